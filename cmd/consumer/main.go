@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
 	"go.uber.org/zap"
+	"net/http"
 	"os"
 	"os/signal"
 	"snowgo/config"
 	"snowgo/internal/worker"
 	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xmetrics"
 	"snowgo/pkg/xmq/rabbitmq"
 	"syscall"
 	"time"
@@ -38,12 +41,23 @@ func main() {
 	//	xlogger.Fatalf("new container failed: %v", err)
 	//}
 
-	consumerCfg := rabbitmq.NewConsumerConnConfig(
-		cfg.RabbitMQ.URL,
+	consumerOpts := []rabbitmq.ConsumerConnOption{
 		rabbitmq.WithConsumerLogger(logger),
 		rabbitmq.WithConsumerReconnectInitialDelay(cfg.RabbitMQ.ReconnectInitialDelayTime),
 		rabbitmq.WithConsumerReconnectMaxDelay(cfg.RabbitMQ.ReconnectMaxDelayTime),
-	)
+	}
+	// 消费指标，消费者没有业务端口，单独监听
+	var metricsServer *http.Server
+	if cfg.Metrics.Enable && cfg.Metrics.ConsumerAddr != "" {
+		consumerOpts = append(consumerOpts, rabbitmq.WithConsumerMetrics(xmetrics.MQ()))
+		metricsServer = xmetrics.NewServer(cfg.Metrics.ConsumerAddr, cfg.Metrics.Path)
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error(context.Background(), "metrics server listen fail", zap.Error(err))
+			}
+		}()
+	}
+	consumerCfg := rabbitmq.NewConsumerConnConfig(cfg.RabbitMQ.URL, consumerOpts...)
 	consumer, err := rabbitmq.NewConsumer(context.Background(), consumerCfg)
 	if err != nil {
 		logger.Error(context.Background(), "failed to create consumer", zap.Error(err))
@@ -76,6 +90,9 @@ func main() {
 	if err := consumer.Stop(shutdownCtx); err != nil {
 		logger.Error(shutdownCtx, "consumer stop fail", zap.Error(err))
 	}
+	if metricsServer != nil {
+		_ = metricsServer.Shutdown(shutdownCtx)
+	}
 	// 注入关闭
 	//if err := container.Close(); err != nil {
 	//	xlogger.Errorf("container close error: %v", err)
@@ -52,6 +52,7 @@ func main() {
 	// 手动注入依赖
 	container, err := di.NewContainer(
 		di.WithJWT(cfg.Jwt),
+		di.WithAuth(cfg.Auth),
 		di.WithMySQL(cfg.Mysql, cfg.OtherDB),
 		di.WithRedis(cfg.Redis),
 		//di.WithProducer(&rabbitmq.ProducerConnConfig{
@@ -63,6 +64,7 @@ func main() {
 		//	ReconnectInitialDelay:       cfg.RabbitMQ.ReconnectInitialDelayTime,
 		//	ReconnectMaxDelay:           cfg.RabbitMQ.ReconnectMaxDelayTime,
 		//	Logger:                      xlogger.NewLogger("./logs", "rabbitmq-producer", xlogger.WithFileMaxAgeDays(30)),
+		//	Metrics:                     xmetrics.MQ(), // 开启 metrics 时采集发布结果
 		//}),
 	)
 	if err != nil {
@@ -70,7 +72,10 @@ func main() {
 	}
 
 	// 启动服务
-	server.StartHttpServer(container)
+	if err := server.StartHttpServer(container); err != nil {
+		xlogger.Fatalf("start http server failed: %v", err)
+	}
+	server.StartMetricsServer()
 
 	// 等待中断信号来优雅地关闭服务器，为关闭服务器操作设置一个超时
 	// kill -2 发送 syscall.SIGINT 信号，用户发送INTR字符(Ctrl+C)触发
@@ -84,6 +89,7 @@ func main() {
 
 	// 关闭服务
 	_ = server.StopHttpServer()
+	server.StopMetricsServer()
 
 	// 注入关闭
 	if err := container.Close(); err != nil {
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"snowgo/config"
+	"snowgo/internal/di"
+	"snowgo/internal/router/admin"
+	"snowgo/pkg/xauth/perm"
+	"snowgo/pkg/xlogger"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 对比路由权限注册表与 sys_menu Btn 节点，存在差异时以非 0 退出，可用于发布前检查
+// -sync -parent <菜单id> 将缺失的权限点补建为该菜单下的 Btn 节点
+func main() {
+	sync := flag.Bool("sync", false, "将缺失的权限点补建为 Btn 节点")
+	parentId := flag.Int("parent", 0, "补建 Btn 节点的父菜单id，-sync 时必填")
+	flag.Parse()
+	if *sync && *parentId < 1 {
+		fmt.Println("-sync 需要通过 -parent 指定父菜单id")
+		os.Exit(2)
+	}
+
+	// 初始化配置文件
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "./config"
+	}
+	config.Init(configPath)
+	logPath := os.Getenv("LOG_PATH")
+	if logPath == "" {
+		logPath = "./logs"
+	}
+	xlogger.Init(logPath)
+	cfg := config.Get()
+
+	// 仅注册路由以登记各接口引用的权限，不启动服务
+	gin.SetMode(gin.ReleaseMode)
+	admin.Register(gin.New().Group("/api"))
+
+	container, err := di.NewContainer(
+		di.WithJWT(cfg.Jwt),
+		di.WithAuth(cfg.Auth),
+		di.WithMySQL(cfg.Mysql, cfg.OtherDB),
+		di.WithRedis(cfg.Redis),
+	)
+	if err != nil {
+		fmt.Printf("new container failed: %v\n", err)
+		os.Exit(1)
+	}
+	code := run(container, *sync, int32(*parentId))
+	if err := container.Close(); err != nil {
+		fmt.Printf("container close error: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+func run(container *di.Container, sync bool, parentId int32) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	menuService := container.AccountContainer.MenuService
+	reg := perm.Default()
+
+	if sync {
+		created, err := menuService.SyncPerms(ctx, reg, parentId)
+		if err != nil {
+			fmt.Printf("同步权限失败: %v\n", err)
+			return 1
+		}
+		for _, m := range created {
+			fmt.Printf("已创建按钮 id=%d perms=%s name=%s\n", m.ID, *m.Perms, m.Name)
+		}
+		fmt.Printf("同步完成，新建按钮 %d 个\n", len(created))
+	}
+
+	drift, err := menuService.DiffPerms(ctx, reg)
+	if err != nil {
+		fmt.Printf("对比权限失败: %v\n", err)
+		return 1
+	}
+	fmt.Print(formatDrift(drift))
+	if !drift.Empty() {
+		return 1
+	}
+	return 0
+}
+
+func formatDrift(drift *perm.Drift) string {
+	if drift.Empty() {
+		return "路由权限与菜单一致\n"
+	}
+	var b strings.Builder
+	if len(drift.Missing) > 0 {
+		b.WriteString("缺失（已定义但 sys_menu 无对应 Btn 节点，可用 -sync 补建）:\n")
+		for _, def := range drift.Missing {
+			_, _ = fmt.Fprintf(&b, "    %s  %s\n", def.Code, def.Name)
+		}
+	}
+	if len(drift.Orphaned) > 0 {
+		b.WriteString("孤立（sys_menu Btn 节点不匹配任何已定义权限）:\n")
+		for _, code := range drift.Orphaned {
+			_, _ = fmt.Fprintf(&b, "    %s\n", code)
+		}
+	}
+	if len(drift.Unused) > 0 {
+		b.WriteString("未使用（已定义但没有路由引用）:\n")
+		for _, def := range drift.Unused {
+			_, _ = fmt.Fprintf(&b, "    %s  %s\n", def.Code, def.Name)
+		}
+	}
+	if len(drift.Undefined) > 0 {
+		b.WriteString("未定义（路由引用了未通过 perm.Define 定义的权限）:\n")
+		for _, code := range drift.Undefined {
+			_, _ = fmt.Fprintf(&b, "    %s\n", code)
+		}
+	}
+	return b.String()
+}
@@ -35,6 +35,10 @@ type Config struct {
 	Jwt         JwtConfig              `mapstructure:"jwt"`
 	OtherDB     OtherDBConfig          `mapstructure:"dbMap"`
 	RabbitMQ    RabbitMQProducerConfig `mapstructure:"rabbitmq"`
+	Auth        AuthConfig             `mapstructure:"auth"`
+	Cors        CorsConfig             `mapstructure:"cors"`
+	Metrics     MetricsConfig          `mapstructure:"metrics"`
+	Idempotency IdempotencyConfig      `mapstructure:"idempotency"`
 }
 
 // ApplicationConfig 应用基础配置
@@ -46,6 +50,35 @@ type ApplicationConfig struct {
 	Server          ServerConfig `mapstructure:"server"`
 }
 
+// CorsConfig 跨域配置
+type CorsConfig struct {
+	Enable             bool          `mapstructure:"enable"`               // 是否启用跨域中间件
+	AllowOrigins       []string      `mapstructure:"allow_origins"`        // 允许的来源：精确匹配 https://a.com，通配子域 https://*.a.com，* 表示任意来源（不可与 allow_credentials 同时使用）
+	AllowOriginRegexps []string      `mapstructure:"allow_origin_regexps"` // 允许来源的正则，按整个来源完整匹配
+	AllowMethods       []string      `mapstructure:"allow_methods"`        // 允许的请求方法，为空时使用默认值
+	AllowHeaders       []string      `mapstructure:"allow_headers"`        // 允许的请求头，为空时使用默认值
+	ExposeHeaders      []string      `mapstructure:"expose_headers"`       // 允许前端读取的响应头
+	MaxAge             time.Duration `mapstructure:"max_age"`              // 预检结果缓存时长
+	AllowCredentials   bool          `mapstructure:"allow_credentials"`    // 是否允许携带 Cookie 等凭证
+}
+
+// MetricsConfig Prometheus 指标配置
+type MetricsConfig struct {
+	Enable     bool     `mapstructure:"enable"`      // 是否开启指标采集与暴露
+	Path       string   `mapstructure:"path"`        // 抓取路径，默认 /metrics
+	Addr       string   `mapstructure:"addr"`        // 独立监听地址，如 0.0.0.0:9091；为空时挂载在业务端口上并按 allow_cidrs 限制访问
+	AllowCidrs []string `mapstructure:"allow_cidrs"` // 挂载在业务端口时允许抓取的网段
+
+	ConsumerAddr string `mapstructure:"consumer_addr"` // 消费者进程的指标监听地址，消费者没有业务端口，为空时不暴露
+}
+
+// IdempotencyConfig 幂等请求配置，携带 Idempotency-Key 的 POST/PUT/DELETE 请求结果会被保存并在重复请求时回放
+type IdempotencyConfig struct {
+	Enable         bool          `mapstructure:"enable"`          // 是否启用
+	ExpirationTime time.Duration `mapstructure:"expiration_time"` // 响应保存时长，超过后同一 key 视为新请求
+	LockTime       time.Duration `mapstructure:"lock_time"`       // 处理中标记的有效期，需覆盖接口最长处理时间
+}
+
 // ServerConfig 服务配置
 type ServerConfig struct {
 	Name         string        `mapstructure:"name"`
@@ -55,6 +88,8 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	MaxHeaderMB  int           `mapstructure:"max_header_mb"`
+
+	TrustedProxies []string `mapstructure:"trusted_proxies"` // 可信反向代理网段，为空时不信任 X-Forwarded-For，客户端 IP 取连接对端地址
 }
 
 // LogConfig 日志配置
@@ -101,6 +136,115 @@ type JwtConfig struct {
 	JwtSecret             string        `mapstructure:"jwt_secret"`
 	AccessExpirationTime  time.Duration `mapstructure:"access_expiration_time"`
 	RefreshExpirationTime time.Duration `mapstructure:"refresh_expiration_time"`
+	// SigningKeys 非对称签名密钥(RS256/EdDSA)，配置后不再使用 jwt_secret 签名
+	SigningKeys []JwtSigningKeyConfig `mapstructure:"signing_keys"`
+}
+
+// JwtSigningKeyConfig JWT签名密钥配置
+type JwtSigningKeyConfig struct {
+	Kid            string `mapstructure:"kid"`              // 密钥ID
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM 私钥文件路径
+	PrivateKey     string `mapstructure:"private_key"`      // PEM 私钥内容，优先于 private_key_file
+	ActiveFrom     string `mapstructure:"active_from"`      // 开始签名时间(RFC3339)，为空表示立即生效
+}
+
+// AuthConfig 登录认证配置
+type AuthConfig struct {
+	Token     TokenConfig     `mapstructure:"token"`
+	Captcha   CaptchaConfig   `mapstructure:"captcha"`
+	Session   SessionConfig   `mapstructure:"session"`
+	Mfa       MfaConfig       `mapstructure:"mfa"`
+	Oidc      OidcConfig      `mapstructure:"oidc"`
+	Password  PasswordConfig  `mapstructure:"password"`
+	PermCache PermCacheConfig `mapstructure:"perm_cache"`
+	RoleGrant RoleGrantConfig `mapstructure:"role_grant"`
+	Lockout   LockoutConfig   `mapstructure:"lockout"`
+	Partner   PartnerConfig   `mapstructure:"partner"`
+}
+
+// CaptchaConfig 登录验证码配置
+type CaptchaConfig struct {
+	Type           string        `mapstructure:"type"`            // image(随机数字) / arithmetic(算术题)，为空时不启用
+	Threshold      int64         `mapstructure:"threshold"`       // 登录失败窗口内尝试次数超过该值后要求验证码，需小于失败锁定阈值
+	Length         int           `mapstructure:"length"`          // image 验证码位数
+	ExpirationTime time.Duration `mapstructure:"expiration_time"` // 验证码有效期
+}
+
+// TokenConfig 登录令牌策略配置
+type TokenConfig struct {
+	Strategy string             `mapstructure:"strategy"` // jwt(默认) / session
+	Session  TokenSessionConfig `mapstructure:"session"`  // strategy 为 session 时生效
+}
+
+// TokenSessionConfig 服务端不透明会话令牌配置
+type TokenSessionConfig struct {
+	IdleTimeout           time.Duration `mapstructure:"idle_timeout"`            // access 空闲超时，每次请求滑动续期
+	AccessMaxLifetime     time.Duration `mapstructure:"access_max_lifetime"`     // access 最长有效期，滑动续期不超过该时长
+	RefreshExpirationTime time.Duration `mapstructure:"refresh_expiration_time"` // refresh token 有效期
+	CookieDomain          string        `mapstructure:"cookie_domain"`           // Cookie 域名，为空表示当前域名
+	CookieSecure          bool          `mapstructure:"cookie_secure"`           // Cookie 仅通过 HTTPS 发送
+	CookieSameSite        string        `mapstructure:"cookie_same_site"`        // lax(默认) / strict / none
+}
+
+// SessionConfig 登录会话配置
+type SessionConfig struct {
+	MaxPerUser     int    `mapstructure:"max_per_user"`    // 每个用户最大并发会话数，0 表示不限制
+	OverflowPolicy string `mapstructure:"overflow_policy"` // 超出上限策略 evict_oldest(踢出最早会话) / reject(拒绝新登录)
+}
+
+// MfaConfig 两步验证配置
+type MfaConfig struct {
+	Issuer                  string        `mapstructure:"issuer"`                    // 验证器 App 中展示的发行方
+	EncryptKey              string        `mapstructure:"encrypt_key"`               // TOTP 密钥加密 key(16/24/32 字节)，为空时不启用两步验证
+	ChallengeExpirationTime time.Duration `mapstructure:"challenge_expiration_time"` // 登录挑战有效期
+}
+
+// OidcConfig OIDC 单点登录配置
+type OidcConfig struct {
+	Issuer              string        `mapstructure:"issuer"`                // IdP issuer，为空时不启用单点登录
+	ClientID            string        `mapstructure:"client_id"`             // 客户端ID
+	ClientSecret        string        `mapstructure:"client_secret"`         // 客户端密钥，公共客户端可为空
+	RedirectURL         string        `mapstructure:"redirect_url"`          // 授权回调地址（前端回调页）
+	Scopes              []string      `mapstructure:"scopes"`                // 授权范围，自动包含 openid
+	AutoProvision       bool          `mapstructure:"auto_provision"`        // 未关联用户首次登录时自动创建
+	DefaultRoleId       int32         `mapstructure:"default_role_id"`       // 自动创建用户绑定的角色ID，0 表示不绑定
+	LinkByEmail         bool          `mapstructure:"link_by_email"`         // 未关联时按已验证邮箱关联唯一的已有用户
+	UsernameClaim       string        `mapstructure:"username_claim"`        // 自动创建用户的用户名来源 preferred_username / email / sub
+	StateExpirationTime time.Duration `mapstructure:"state_expiration_time"` // 授权请求有效期
+	CookieSecure        bool          `mapstructure:"cookie_secure"`         // 浏览器绑定 Cookie 仅通过 HTTPS 发送
+}
+
+// PasswordConfig 密码策略配置
+type PasswordConfig struct {
+	HistoryCount            int           `mapstructure:"history_count"`               // 不允许重复使用最近 N 次的密码，0 表示不校验历史密码
+	MaxAge                  time.Duration `mapstructure:"max_age"`                     // 密码最长使用期限，超过后登录需修改密码，0 表示不过期
+	ForceChangeOnFirstLogin bool          `mapstructure:"force_change_on_first_login"` // 管理员创建的用户首次登录必须修改密码
+	ForceChangeAfterReset   bool          `mapstructure:"force_change_after_reset"`    // 管理员重置密码后下次登录必须修改密码
+	BannedListFile          string        `mapstructure:"banned_list_file"`            // 禁用的常见密码列表文件，每行一个，为空表示不校验
+	ChangeExpirationTime    time.Duration `mapstructure:"change_expiration_time"`      // 强制修改密码凭证有效期
+}
+
+// PermCacheConfig 进程内权限缓存配置
+type PermCacheConfig struct {
+	Size int           `mapstructure:"size"` // 最多缓存的用户数，0 表示关闭进程内缓存
+	TTL  time.Duration `mapstructure:"ttl"`  // 缓存有效期，跨实例失效通知丢失时的兜底
+}
+
+// RoleGrantConfig 限时角色授权配置
+type RoleGrantConfig struct {
+	SweepInterval time.Duration `mapstructure:"sweep_interval"` // 清理过期授权的间隔，0 表示不清理
+}
+
+// LockoutConfig 登录失败锁定配置
+type LockoutConfig struct {
+	Durations      []time.Duration `mapstructure:"durations"`       // 连续第 N 次超出失败限流时的锁定时长，超出列表按最后一项，为空表示只按窗口限流不锁定账号
+	PermanentAfter int             `mapstructure:"permanent_after"` // 连续第 N 次锁定改为永久锁定，需管理员解锁，0 表示不永久锁定
+}
+
+// PartnerConfig 开放接口合作方签名认证配置
+type PartnerConfig struct {
+	EncryptKey string        `mapstructure:"encrypt_key"` // 签名密钥加密 key(16/24/32 字节)，为空时不启用签名认证
+	ClockSkew  time.Duration `mapstructure:"clock_skew"`  // 签名时间戳允许的时钟偏差，nonce 按该窗口防重放
 }
 
 // RabbitMQProducerConfig rabbitmq配置
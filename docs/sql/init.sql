@@ -3,27 +3,110 @@ SET NAMES utf8mb4;
 DROP TABLE IF EXISTS `sys_user`;
 CREATE TABLE `sys_user`
 (
-    `id`         INT(11)      NOT NULL AUTO_INCREMENT,
-    `username`   VARCHAR(64)  NOT NULL COMMENT '登录名，业务唯一',
-    `tel`        VARCHAR(20)  NOT NULL COMMENT '手机号码',
-    `nickname`   VARCHAR(60)           DEFAULT NULL COMMENT '用户昵称',
-    `password`   VARCHAR(255) NOT NULL COMMENT 'pwd',
-    `email`      VARCHAR(100)          DEFAULT NULL COMMENT '邮箱',
-    `remark`     VARCHAR(500)          DEFAULT NULL COMMENT '备注',
-    `status`     TINYINT(4)   NOT NULL DEFAULT 1 COMMENT '用户状态：1 活跃, 2 禁用登录',
-    `created_by` INT(11)               DEFAULT NULL COMMENT '创建人 ID',
-    `updated_by` INT(11)               DEFAULT NULL COMMENT '更新人 ID',
-    `created_at` DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
-    `updated_at` DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    `id`              INT(11)      NOT NULL AUTO_INCREMENT,
+    `username`        VARCHAR(64)  NOT NULL COMMENT '登录名，业务唯一',
+    `tel`             VARCHAR(20)           DEFAULT NULL COMMENT '手机号码，服务账号为空',
+    `nickname`        VARCHAR(60)           DEFAULT NULL COMMENT '用户昵称',
+    `password`        VARCHAR(255) NOT NULL COMMENT 'pwd',
+    `email`           VARCHAR(100)          DEFAULT NULL COMMENT '邮箱',
+    `remark`          VARCHAR(500)          DEFAULT NULL COMMENT '备注',
+    `status`          TINYINT(4)   NOT NULL DEFAULT 1 COMMENT '用户状态：1 活跃, 2 禁用登录',
+    `user_type`       TINYINT(4)   NOT NULL DEFAULT 1 COMMENT '用户类型：1 普通用户, 2 服务账号',
+    `dept_id`         INT(11)               DEFAULT NULL COMMENT '所属部门ID',
+    `pwd_changed_at`  DATETIME(6)           DEFAULT NULL COMMENT '密码最后修改时间',
+    `pwd_must_change` TINYINT(4)   NOT NULL DEFAULT 0 COMMENT '下次登录是否必须修改密码：0 否, 1 是',
+    `lock_status`     TINYINT(4)   NOT NULL DEFAULT 0 COMMENT '登录锁定状态：0 未锁定, 1 临时锁定, 2 永久锁定',
+    `lock_count`      INT(11)      NOT NULL DEFAULT 0 COMMENT '连续锁定次数，登录成功或管理员解锁后清零',
+    `locked_until`    DATETIME(6)           DEFAULT NULL COMMENT '临时锁定截止时间',
+    `created_by`      INT(11)               DEFAULT NULL COMMENT '创建人 ID',
+    `updated_by`      INT(11)               DEFAULT NULL COMMENT '更新人 ID',
+    `created_at`      DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at`      DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
     PRIMARY KEY (`id`),
     UNIQUE KEY uk_username (username),
     UNIQUE KEY uk_tel (tel),
     INDEX idx_created_at (created_at),
-    KEY `idx_status` (`status`)
+    KEY `idx_status` (`status`),
+    KEY `idx_dept_id` (`dept_id`)
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='用户表';
 
+# 用户两步验证表
+DROP TABLE IF EXISTS `sys_user_mfa`;
+CREATE TABLE `sys_user_mfa`
+(
+    `id`             INT(11)      NOT NULL AUTO_INCREMENT,
+    `user_id`        INT(11)      NOT NULL COMMENT '用户ID',
+    `secret`         VARCHAR(255) NOT NULL COMMENT 'TOTP密钥（AES-GCM加密）',
+    `status`         TINYINT(4)   NOT NULL DEFAULT 1 COMMENT '状态：1 待验证, 2 已启用',
+    `recovery_codes` JSON                  DEFAULT NULL COMMENT '恢复码哈希列表',
+    `last_used_step` BIGINT(20)   NOT NULL DEFAULT 0 COMMENT '最近一次使用的时间步，防止验证码重放',
+    `enabled_at`     DATETIME(6)           DEFAULT NULL COMMENT '启用时间',
+    `created_at`     DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at`     DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY uk_user_id (user_id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='用户两步验证表';
+
+# 服务账号 API Key 表
+DROP TABLE IF EXISTS `sys_api_key`;
+CREATE TABLE `sys_api_key`
+(
+    `id`           INT(11)     NOT NULL AUTO_INCREMENT,
+    `user_id`      INT(11)     NOT NULL COMMENT '服务账号用户ID',
+    `name`         VARCHAR(64) NOT NULL COMMENT '名称/用途',
+    `prefix`       VARCHAR(16) NOT NULL COMMENT 'Key 前缀，用于展示识别',
+    `key_hash`     CHAR(64)    NOT NULL COMMENT 'Key SHA-256 摘要',
+    `status`       TINYINT(4)  NOT NULL DEFAULT 1 COMMENT '状态：1 有效, 2 已吊销',
+    `expired_at`   DATETIME(6)          DEFAULT NULL COMMENT '过期时间，为空表示永不过期',
+    `last_used_at` DATETIME(6)          DEFAULT NULL COMMENT '最后使用时间',
+    `revoked_at`   DATETIME(6)          DEFAULT NULL COMMENT '吊销时间',
+    `created_by`   INT(11)              DEFAULT NULL COMMENT '创建人 ID',
+    `created_at`   DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at`   DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY uk_key_hash (key_hash),
+    KEY `idx_user_id` (`user_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='服务账号 API Key 表';
+
+# 用户历史密码表
+DROP TABLE IF EXISTS `sys_user_pwd_history`;
+CREATE TABLE `sys_user_pwd_history`
+(
+    `id`         INT(11)      NOT NULL AUTO_INCREMENT,
+    `user_id`    INT(11)      NOT NULL COMMENT '用户ID',
+    `password`   VARCHAR(255) NOT NULL COMMENT '历史密码 hash',
+    `created_at` DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    KEY `idx_user_id` (`user_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='用户历史密码表';
+
+# 创建 OIDC 身份关联表
+DROP TABLE IF EXISTS `sys_user_oidc`;
+CREATE TABLE `sys_user_oidc`
+(
+    `id`            INT(11)      NOT NULL AUTO_INCREMENT,
+    `user_id`       INT(11)      NOT NULL COMMENT '用户ID',
+    `issuer`        VARCHAR(255) NOT NULL COMMENT 'IdP issuer',
+    `subject`       VARCHAR(255) NOT NULL COMMENT 'IdP 用户唯一标识(sub)',
+    `email`         VARCHAR(100)          DEFAULT NULL COMMENT '关联时 IdP 返回的邮箱',
+    `last_login_at` DATETIME(6)           DEFAULT NULL COMMENT '最后登录时间',
+    `created_at`    DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at`    DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY uk_issuer_subject (issuer, subject),
+    KEY `idx_user_id` (`user_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='OIDC 身份关联表';
+
 # 创建角色表
 DROP TABLE IF EXISTS `sys_role`;
 CREATE TABLE `sys_role`
@@ -32,6 +115,8 @@ CREATE TABLE `sys_role`
     `code`        VARCHAR(64)  NOT NULL COMMENT '角色代码，如 admin、normal',
     `name`        VARCHAR(128) NULL COMMENT '前端展示用名称',
     `description` TEXT         NULL COMMENT '角色描述',
+    `require_mfa` TINYINT(4)   NOT NULL DEFAULT 0 COMMENT '是否强制两步验证：0 否, 1 是',
+    `data_scope`  TINYINT(4)   NOT NULL DEFAULT 1 COMMENT '数据范围：1 全部, 2 本人, 3 本部门, 4 本部门及以下, 5 自定义部门',
     `created_at`  DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
     `updated_at`  DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
     PRIMARY KEY (`id`),
@@ -59,17 +144,36 @@ CREATE TABLE `sys_menu`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='菜单权限表';
 
+# 创建部门表
+DROP TABLE IF EXISTS `sys_dept`;
+CREATE TABLE `sys_dept`
+(
+    `id`         INT(11)     NOT NULL AUTO_INCREMENT,
+    `parent_id`  INT(11)     NOT NULL DEFAULT 0 COMMENT '父级部门，0=根节点',
+    `name`       VARCHAR(64) NOT NULL COMMENT '部门名称',
+    `sort_order` INT         NOT NULL DEFAULT 0 COMMENT '排序号',
+    `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY uk_parent_name (parent_id, name)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='部门表';
+
 # 用户-角色关联表
 DROP TABLE IF EXISTS `sys_user_role`;
 CREATE TABLE `sys_user_role`
 (
-    `id`         BIGINT      NOT NULL AUTO_INCREMENT,
-    `user_id`    INT(11)     NOT NULL COMMENT '用户ID',
-    `role_id`    INT(11)     NOT NULL COMMENT '角色ID',
-    `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
-    `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    `id`          BIGINT      NOT NULL AUTO_INCREMENT,
+    `user_id`     INT(11)     NOT NULL COMMENT '用户ID',
+    `role_id`     INT(11)     NOT NULL COMMENT '角色ID',
+    `valid_from`  DATETIME(6) NULL COMMENT '生效时间，为空表示立即生效',
+    `valid_until` DATETIME(6) NULL COMMENT '失效时间，为空表示永久有效',
+    `created_at`  DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at`  DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
     PRIMARY KEY (`id`),
     KEY `idx_role_id` (`role_id`),
+    KEY `idx_valid_until` (`valid_until`),
     UNIQUE KEY uk_user_role (user_id, role_id)
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4
@@ -91,6 +195,38 @@ CREATE TABLE `sys_role_menu`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='角色-菜单权限表';
 
+# 创建角色-自定义数据范围部门表
+DROP TABLE IF EXISTS `sys_role_dept`;
+CREATE TABLE `sys_role_dept`
+(
+    `id`         BIGINT      NOT NULL AUTO_INCREMENT,
+    `role_id`    INT(11)     NOT NULL COMMENT '角色ID',
+    `dept_id`    INT(11)     NOT NULL COMMENT '部门ID',
+    `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    KEY `idx_dept_id` (`dept_id`),
+    UNIQUE KEY uk_role_dept (role_id, dept_id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='角色-自定义数据范围部门表';
+
+# 创建角色继承关系表
+DROP TABLE IF EXISTS `sys_role_inherit`;
+CREATE TABLE `sys_role_inherit`
+(
+    `id`         BIGINT      NOT NULL AUTO_INCREMENT,
+    `role_id`    INT(11)     NOT NULL COMMENT '角色ID',
+    `parent_id`  INT(11)     NOT NULL COMMENT '继承的父角色ID',
+    `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    KEY `idx_parent_id` (`parent_id`),
+    UNIQUE KEY uk_role_parent (role_id, parent_id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='角色继承关系表';
+
 # 创建操作日志表
 DROP TABLE IF EXISTS `sys_operation_log`;
 CREATE TABLE `sys_operation_log`
@@ -174,12 +310,38 @@ CREATE TABLE `sys_dict_item`
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci COMMENT ='系统字典枚举值表';
 
+# 开放接口合作方凭证表
+DROP TABLE IF EXISTS `sys_partner_credential`;
+CREATE TABLE `sys_partner_credential`
+(
+    `id`           INT(11)      NOT NULL AUTO_INCREMENT,
+    `name`         VARCHAR(64)  NOT NULL COMMENT '合作方名称',
+    `access_key`   VARCHAR(32)  NOT NULL COMMENT '访问密钥ID',
+    `secret`       VARCHAR(255) NOT NULL COMMENT '签名密钥（AES-GCM加密）',
+    `status`       TINYINT(4)   NOT NULL DEFAULT 1 COMMENT '状态：1 启用, 2 禁用',
+    `remark`       VARCHAR(500)          DEFAULT NULL COMMENT '备注',
+    `last_used_at` DATETIME(6)           DEFAULT NULL COMMENT '最后调用时间',
+    `created_by`   INT(11)               DEFAULT NULL COMMENT '创建人 ID',
+    `updated_by`   INT(11)               DEFAULT NULL COMMENT '更新人 ID',
+    `created_at`   DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at`   DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_name` (`name`),
+    UNIQUE KEY `uk_access_key` (`access_key`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci COMMENT ='开放接口合作方凭证表';
+
 # 插入测试数据
+# 部门数据
+INSERT INTO `sys_dept` (`id`, `parent_id`, `name`, `sort_order`)
+VALUES (1, 0, '总公司', 1);
+
 # 用户数据
-INSERT INTO `sys_user` (`username`, `tel`, `nickname`, `password`, `remark`)
-VALUES ('admin', '18712345678', '如何好听', '$2a$10$XqU5GKb6wbGXjckKxQtMF.b8nn6MlC17tk2Y.ap//n8swLOQ4fZwO', '管理员'),
+INSERT INTO `sys_user` (`username`, `tel`, `nickname`, `password`, `remark`, `dept_id`)
+VALUES ('admin', '18712345678', '如何好听', '$2a$10$XqU5GKb6wbGXjckKxQtMF.b8nn6MlC17tk2Y.ap//n8swLOQ4fZwO', '管理员', 1),
        ('test', '18700000001', '只读测试用户', '$2a$10$XqU5GKb6wbGXjckKxQtMF.b8nn6MlC17tk2Y.ap//n8swLOQ4fZwO',
-        '只读测试用户');
+        '只读测试用户', 1);
 
 # 菜单数据
 INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
@@ -240,6 +402,40 @@ INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`,
 VALUES (28, 25, 'Btn', '更新字典', NULL, NULL, 'system:dict:update', 3);
 INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
 VALUES (29, 25, 'Btn', '删除字典', NULL, NULL, 'system:dict:delete', 3);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (30, 2, 'Btn', '在线会话', NULL, NULL, 'account:session:list', 7);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (31, 2, 'Btn', '踢出会话', NULL, NULL, 'account:session:kick', 8);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (32, 2, 'Btn', '重置两步验证', NULL, NULL, 'account:user:reset_mfa', 9);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (33, 2, 'Btn', 'API Key列表', NULL, NULL, 'account:api_key:list', 10);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (34, 2, 'Btn', '创建API Key', NULL, NULL, 'account:api_key:create', 11);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (35, 2, 'Btn', '吊销API Key', NULL, NULL, 'account:api_key:revoke', 12);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (36, 1, 'Menu', '部门管理', '/account/dept', 'OfficeBuilding', NULL, 4);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (37, 36, 'Btn', '部门列表', NULL, NULL, 'account:dept:list', 1);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (38, 36, 'Btn', '添加部门', NULL, NULL, 'account:dept:create', 2);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (39, 36, 'Btn', '更新部门', NULL, NULL, 'account:dept:update', 3);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (40, 36, 'Btn', '删除部门', NULL, NULL, 'account:dept:delete', 4);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (41, 2, 'Btn', '解锁用户', NULL, NULL, 'account:user:unlock', 13);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (42, 20, 'Menu', '开放接口合作方', '/system/partner', 'Connection', NULL, 4);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (43, 42, 'Btn', '合作方列表', NULL, NULL, 'system:partner:list', 1);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (44, 42, 'Btn', '添加合作方', NULL, NULL, 'system:partner:create', 2);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (45, 42, 'Btn', '更新合作方', NULL, NULL, 'system:partner:update', 3);
+INSERT INTO `sys_menu` (`id`, `parent_id`, `menu_type`, `name`, `path`, `icon`, `perms`, `sort_order`)
+VALUES (46, 42, 'Btn', '删除合作方', NULL, NULL, 'system:partner:delete', 4);
 
 # 角色数据
 INSERT INTO `sys_role` (`id`, `code`, `name`, `description`)
@@ -248,35 +444,17 @@ VALUES (1, 'admin', '管理员', '平台管理员角色'),
 
 # 角色菜单关联数据
 INSERT INTO `sys_role_menu` (`role_id`, `menu_id`)
-VALUES (1, 1),
+VALUES # 管理员：接口权限由内置的 * 授权覆盖，只需关联目录与菜单用于前端渲染
+       (1, 1),
        (1, 2),
-       (1, 3),
-       (1, 4),
-       (1, 5),
-       (1, 6),
-       (1, 7),
-       (1, 8),
        (1, 9),
-       (1, 10),
-       (1, 11),
-       (1, 12),
-       (1, 13),
-       (1, 14),
        (1, 15),
-       (1, 16),
-       (1, 17),
-       (1, 18),
-       (1, 19),
+       (1, 36),
        (1, 20),
        (1, 21),
-       (1, 22),
        (1, 23),
-       (1, 24),
        (1, 25),
-       (1, 26),
-       (1, 27),
-       (1, 28),
-       (1, 29),
+       (1, 42),
        # 只读
        (2, 1),
        (2, 2),
@@ -293,7 +471,13 @@ VALUES (1, 1),
        (2, 23),
        (2, 24),
        (2, 25),
-       (2, 26);
+       (2, 26),
+       (2, 30),
+       (2, 33),
+       (2, 36),
+       (2, 37),
+       (2, 42),
+       (2, 43);
 
 # 用户角色关联数据
 INSERT INTO `sys_user_role` (`user_id`, `role_id`)
@@ -0,0 +1,136 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	"snowgo/internal/service/admin/account"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xgin"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+	"strconv"
+	"time"
+)
+
+type ApiKeyInfo struct {
+	ID         int32  `json:"id"`
+	UserID     int32  `json:"user_id"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix"`
+	Status     int8   `json:"status"`
+	ExpiredAt  string `json:"expired_at"`
+	LastUsedAt string `json:"last_used_at"`
+	RevokedAt  string `json:"revoked_at"`
+	CreatedBy  int32  `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// GetUserApiKeyList 服务账号API Key列表，不返回Key明文
+func GetUserApiKeyList(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	keyList, err := container.ApiKeyService.GetApiKeyList(ctx, id)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get user api key list is err: %v", err)
+		xresponse.FailByError(c, e.ApiKeyListError)
+		return
+	}
+	list := make([]*ApiKeyInfo, 0, len(keyList))
+	for _, apiKey := range keyList {
+		list = append(list, toApiKeyInfo(apiKey))
+	}
+	xresponse.Success(c, &gin.H{"list": list, "total": len(list)})
+}
+
+// CreateUserApiKey 为服务账号创建API Key，明文Key仅在此处返回一次
+func CreateUserApiKey(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	var param account.ApiKeyParam
+	if err := c.ShouldBindJSON(&param); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	created, err := container.ApiKeyService.CreateApiKey(ctx, id, &param)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "create user api key is err: %v", err)
+		xresponse.FailByError(c, e.ApiKeyCreateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"api_key": toApiKeyInfo(&created.ApiKeyInfo), "key": created.Key})
+}
+
+// RevokeUserApiKey 吊销服务账号的API Key
+func RevokeUserApiKey(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	keyId, err := strconv.ParseInt(c.Param("key_id"), 10, 32)
+	if err != nil || keyId < 1 {
+		xresponse.FailByError(c, e.ApiKeyNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	if err := container.ApiKeyService.RevokeApiKey(ctx, id, int32(keyId)); err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "revoke user api key is err: %v", err)
+		xresponse.FailByError(c, e.ApiKeyRevokeError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": id, "key_id": keyId})
+}
+
+func toApiKeyInfo(apiKey *account.ApiKeyInfo) *ApiKeyInfo {
+	return &ApiKeyInfo{
+		ID:         apiKey.ID,
+		UserID:     apiKey.UserID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Status:     apiKey.Status,
+		ExpiredAt:  formatOptionalTime(apiKey.ExpiredAt),
+		LastUsedAt: formatOptionalTime(apiKey.LastUsedAt),
+		RevokedAt:  formatOptionalTime(apiKey.RevokedAt),
+		CreatedBy:  apiKey.CreatedBy,
+		CreatedAt:  apiKey.CreatedAt.Format(constant.TimeFmtWithMS),
+	}
+}
+
+// formatOptionalTime 可空时间格式化，为空返回空串
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(constant.TimeFmtWithMS)
+}
@@ -6,9 +6,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"snowgo/internal/constant"
 	"snowgo/internal/di"
+	accountService "snowgo/internal/service/admin/account"
 	systemService "snowgo/internal/service/admin/system"
 	"snowgo/pkg/xauth"
-	"snowgo/pkg/xauth/jwt"
 	e "snowgo/pkg/xerror"
 	"snowgo/pkg/xlimiter"
 	"snowgo/pkg/xlogger"
@@ -20,6 +20,10 @@ func Login(c *gin.Context) {
 	var req struct {
 		Username string `json:"username" binding:"required"`
 		Password string `json:"password" binding:"required"`
+		Device   string `json:"device" binding:"max=64"` // 登录设备名称，为空时根据 User-Agent 识别
+		// 失败次数超过软阈值后必填，通过 /auth/captcha 获取
+		CaptchaId   string `json:"captcha_id" binding:"max=64"`
+		CaptchaCode string `json:"captcha_code" binding:"max=16"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
@@ -28,49 +32,300 @@ func Login(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	container := di.GetContainer(c)
-	cache := container.Cache
+	limiter, attempts, ok := addLoginAttempt(c, container, req.Username)
+	if !ok {
+		return
+	}
+
+	// 失败次数超过软阈值后、账号锁定前需先通过验证码，未携带或错误同样计入失败次数
+	if container.CaptchaService.Required(attempts) {
+		if err := container.CaptchaService.Verify(ctx, req.CaptchaId, req.CaptchaCode); err != nil {
+			var bizErr *e.BizError
+			if errors.As(err, &bizErr) {
+				container.LoginLogService.CreateLoginLog(ctx,
+					&systemService.LoginLogInput{
+						Username:  req.Username,
+						IP:        c.ClientIP(),
+						Status:    false,
+						Message:   bizErr.Code.GetErrMsg(),
+						UserAgent: c.GetHeader("User-Agent"),
+					})
+				xresponse.JsonByError(c, bizErr.Code, gin.H{"captcha_required": true})
+				return
+			}
+			xlogger.ErrorfCtx(ctx, "verify captcha err: %v", err)
+			xresponse.FailByError(c, e.HttpInternalServerError)
+			return
+		}
+	}
+
+	// 验证用户名密码
+	user, err := container.UserService.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			// 记录登录日志
+			container.LoginLogService.CreateLoginLog(ctx,
+				&systemService.LoginLogInput{
+					Username:  req.Username,
+					IP:        c.ClientIP(),
+					Status:    false,
+					Message:   bizErr.Code.GetErrMsg(),
+					UserAgent: c.GetHeader("User-Agent"),
+				})
+			// 下次登录需要验证码时提示前端展示验证码
+			if container.CaptchaService.Required(attempts + 1) {
+				xresponse.JsonByError(c, bizErr.Code, gin.H{"captcha_required": true})
+				return
+			}
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "authenticate err: %v", err)
+		xresponse.FailByError(c, e.AuthError)
+		return
+	}
+
+	device := req.Device
+	if device == "" {
+		device = accountService.DeviceFromUserAgent(c.GetHeader("User-Agent"))
+	}
+
+	// 已开启两步验证或角色强制两步验证时，先返回登录挑战，验证通过后再检查是否需修改密码、签发token
+	if startMfaChallenge(c, container, user.ID, user.Username, device) {
+		return
+	}
+
+	// 首次登录、被管理员重置或密码过期时，返回修改密码凭证，修改成功后需使用新密码重新登录
+	if startPwdChange(c, container, limiter, user.ID, user.Username, nil) {
+		return
+	}
+
+	// 成功登录 → 重置失败计数
+	resetLoginAttempt(c, container, limiter, user.ID)
+
+	issueLoginTokens(c, container, user.ID, user.Username, device, nil)
+}
+
+// startMfaChallenge 用户需要两步验证时返回登录挑战，返回 true 时已写入响应
+func startMfaChallenge(c *gin.Context, container *di.Container, userId int32, username, device string) bool {
+	ctx := c.Request.Context()
+	mfaStatus, err := container.MfaService.GetStatus(ctx, userId)
+	if err != nil {
+		// 需要两步验证但未配置加密 key 时拒绝登录
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return true
+		}
+		xlogger.ErrorfCtx(ctx, "get mfa status err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return true
+	}
+	if !mfaStatus.Required {
+		return false
+	}
+	challenge := &accountService.MfaChallenge{
+		UserId:        userId,
+		Username:      username,
+		Device:        device,
+		SetupRequired: !mfaStatus.Enabled,
+	}
+	challengeToken, err := container.MfaService.CreateChallenge(ctx, challenge)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "create mfa challenge err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return true
+	}
+	xresponse.Success(c, gin.H{
+		"mfa_required":               true,
+		"mfa_setup_required":         challenge.SetupRequired,
+		"challenge_token":            challengeToken,
+		"challenge_expire_timestamp": challenge.ExpireAt.Unix(),
+	})
+	return true
+}
 
-	// 登录失败限流，3分钟内，最多失败5次
-	loginFailKey := fmt.Sprintf("%s%s", constant.CacheLoginFailPrefix, req.Username)
-	limiter, err := xlimiter.NewFixedWindowLimiter(cache, loginFailKey, constant.CacheLoginFailWindowSecond, 5)
+// startPwdChange 用户需要修改密码时返回专用业务码与修改密码凭证，返回 true 时已写入响应
+// 需在两步验证通过后调用，recoveryCodes 为登录挑战内绑定验证器时生成的恢复码，只返回这一次
+func startPwdChange(c *gin.Context, container *di.Container, limiter *xlimiter.FixedWindowLimiter, userId int32, username string, recoveryCodes []string) bool {
+	ctx := c.Request.Context()
+	reason, err := container.UserService.GetPwdChangeReason(ctx, userId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "get pwd change reason err: %v", err)
+		xresponse.FailByError(c, e.AuthError)
+		return true
+	}
+	if reason == "" {
+		return false
+	}
+	ticket := &accountService.PwdChangeTicket{
+		UserId:   userId,
+		Username: username,
+		Reason:   reason,
+	}
+	changeToken, err := container.UserService.CreatePwdChangeTicket(ctx, ticket)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "create pwd change ticket err: %v", err)
+		xresponse.FailByError(c, e.AuthError)
+		return true
+	}
+	// 密码已校验通过，重置失败计数
+	resetLoginAttempt(c, container, limiter, userId)
+	container.LoginLogService.CreateLoginLog(ctx,
+		&systemService.LoginLogInput{
+			UserID:    userId,
+			Username:  username,
+			IP:        c.ClientIP(),
+			Status:    false,
+			Message:   e.PwdChangeRequired.GetErrMsg(),
+			UserAgent: c.GetHeader("User-Agent"),
+		})
+	res := gin.H{
+		"reason":                  reason,
+		"change_token":            changeToken,
+		"change_expire_timestamp": ticket.ExpireAt.Unix(),
+	}
+	if len(recoveryCodes) > 0 {
+		res["recovery_codes"] = recoveryCodes
+	}
+	xresponse.JsonByError(c, e.PwdChangeRequired, res)
+	return true
+}
+
+// addLoginAttempt 登录失败限流，3分钟内最多失败5次，超出后按锁定策略持久锁定账号；密码校验与两步验证共用同一计数
+// 返回计入本次后的窗口内尝试次数，返回 false 时已写入响应
+func addLoginAttempt(c *gin.Context, container *di.Container, username string) (*xlimiter.FixedWindowLimiter, int64, bool) {
+	ctx := c.Request.Context()
+	// 账号处于锁定期内直接拒绝，不再累计失败次数
+	lock, err := container.UserService.GetLoginLock(ctx, username)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "get login lock error: %v", err)
+		xresponse.FailByError(c, e.HttpInternalServerError)
+		return nil, 0, false
+	}
+	if lock != nil {
+		failLockedLogin(c, container, lock)
+		return nil, 0, false
+	}
+
+	loginFailKey := fmt.Sprintf("%s%s", constant.CacheLoginFailPrefix, username)
+	limiter, err := xlimiter.NewFixedWindowLimiter(container.Cache, loginFailKey, constant.CacheLoginFailWindowSecond, constant.CacheLoginFailMaxCount)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "login limiter init error: %v", err)
 		xresponse.FailByError(c, e.HttpInternalServerError)
-		return
+		return nil, 0, false
 	}
 	// 尝试增加失败计数前，先检查限流器
-	allowed, _, ttl, err := limiter.Add(ctx)
+	allowed, count, ttl, err := limiter.Add(ctx)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "login limiter error: %v", err)
 		xresponse.FailByError(c, e.HttpInternalServerError)
-		return
+		return nil, 0, false
 	}
 
 	// 如果不允许，直接返回锁定信息
 	if !allowed {
+		// 当前窗口首次超限时按锁定策略锁定账号，锁定写入数据库后重新开始计数
+		if count == constant.CacheLoginFailMaxCount+1 {
+			lock, err := container.UserService.LockUser(ctx, username)
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "lock user error: %v", err)
+			} else if lock != nil {
+				_ = limiter.Reset(ctx)
+				failLockedLogin(c, container, lock)
+				return nil, 0, false
+			}
+		}
 		// 记录登录日志
 		msg := fmt.Sprintf("登录失败次数过多，请等待%d秒后再试", int(ttl.Seconds()))
 		container.LoginLogService.CreateLoginLog(ctx,
 			&systemService.LoginLogInput{
-				Username:  req.Username,
+				Username:  username,
 				IP:        c.ClientIP(),
 				Status:    false,
 				Message:   msg,
 				UserAgent: c.GetHeader("User-Agent"),
 			})
 		xresponse.FailByError(c, e.LoginLocked)
+		return nil, 0, false
+	}
+	return limiter, count, true
+}
+
+// failLockedLogin 账号被锁定时记录登录日志并返回锁定信息
+func failLockedLogin(c *gin.Context, container *di.Container, lock *accountService.LoginLock) {
+	ctx := c.Request.Context()
+	container.LoginLogService.CreateLoginLog(ctx,
+		&systemService.LoginLogInput{
+			UserID:    lock.UserId,
+			Username:  lock.Username,
+			IP:        c.ClientIP(),
+			Status:    false,
+			Message:   lock.Message(),
+			UserAgent: c.GetHeader("User-Agent"),
+		})
+	xresponse.FailByError(c, lock.Err().Code)
+}
+
+// resetLoginAttempt 校验通过后重置登录失败计数，并清除账号的锁定状态与连续锁定次数
+func resetLoginAttempt(c *gin.Context, container *di.Container, limiter *xlimiter.FixedWindowLimiter, userId int32) {
+	ctx := c.Request.Context()
+	_ = limiter.Reset(ctx)
+	_ = container.UserService.ClearLoginLock(ctx, userId)
+}
+
+// GetCaptcha 获取登录验证码，登录失败次数超过软阈值后登录需携带
+func GetCaptcha(c *gin.Context) {
+	ctx := c.Request.Context()
+	container := di.GetContainer(c)
+	challenge, err := container.CaptchaService.Create(ctx)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "create captcha err: %v", err)
+		xresponse.FailByError(c, e.HttpInternalServerError)
 		return
 	}
+	xresponse.Success(c, gin.H{
+		"captcha_id":       challenge.Id,
+		"captcha_type":     challenge.Type,
+		"captcha_data":     challenge.Data,
+		"expire_timestamp": challenge.ExpireAt.Unix(),
+	})
+}
 
-	// 验证用户名密码
-	user, err := container.UserService.Authenticate(ctx, req.Username, req.Password)
+// issueLoginTokens 签发token、登记会话并记录登录成功日志
+func issueLoginTokens(c *gin.Context, container *di.Container, userId int32, username, device string, recoveryCodes []string) {
+	ctx := c.Request.Context()
+	pair, err := container.TokenStrategy.IssueTokens(ctx, userId, username)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "generate tokens err: %v", err)
+		xresponse.FailByError(c, e.TokenError)
+		return
+	}
+	claims := pair.Refresh
+
+	// 登记会话，超出并发会话上限时按配置踢出最早会话或拒绝登录
+	err = container.SessionService.Register(ctx, &accountService.SessionInput{
+		UserId:    userId,
+		SessionId: claims.TokenId,
+		FamilyId:  claims.FamilyId,
+		Device:    device,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		ExpireAt:  pair.RefreshExpire,
+	})
 	if err != nil {
 		var bizErr *e.BizError
 		if errors.As(err, &bizErr) {
-			// 记录登录日志
 			container.LoginLogService.CreateLoginLog(ctx,
 				&systemService.LoginLogInput{
-					Username:  req.Username,
+					UserID:    userId,
+					Username:  username,
 					IP:        c.ClientIP(),
 					Status:    false,
 					Message:   bizErr.Code.GetErrMsg(),
@@ -79,67 +334,83 @@ func Login(c *gin.Context) {
 			xresponse.FailByError(c, bizErr.Code)
 			return
 		}
-		xlogger.ErrorfCtx(ctx, "authenticate err: %v", err)
-		xresponse.FailByError(c, e.AuthError)
-		return
-	}
-
-	// 成功登录 → 重置失败计数
-	_ = limiter.Reset(ctx)
-
-	jwtMgr := container.JwtManager
-	token, err := jwtMgr.GenerateTokens(user.ID, user.Username)
-	if err != nil {
-		xlogger.ErrorfCtx(ctx, "jwt generate tokens err: %v", err)
-		xresponse.FailByError(c, e.TokenError)
-		return
+		xlogger.ErrorfCtx(ctx, "register session err: %v", err)
 	}
 
 	// 保存 refresh token 的 jti，设置过期时间（防止重放攻击、每个refresh token只能使用一次）
-	if claims, err := jwtMgr.ParseToken(token.RefreshToken); err == nil {
-		jtiKey := constant.CacheRefreshJtiPrefix + claims.ID
-		err = container.Cache.Set(ctx, jtiKey, "1", claims.ExpiresAt.Sub(claims.IssuedAt.Time))
-		if err != nil {
-			xlogger.ErrorfCtx(ctx, "save refresh token jti err: %v", err)
-		}
+	if err = container.SessionService.SaveRefreshJti(ctx, claims.TokenId, claims.ExpireAt.Sub(claims.IssuedAt)); err != nil {
+		xlogger.ErrorfCtx(ctx, "save refresh token jti err: %v", err)
 	}
 
 	// 记录登录成功日志
 	container.LoginLogService.CreateLoginLog(ctx,
 		&systemService.LoginLogInput{
-			UserID:    user.ID,
-			Username:  user.Username,
+			UserID:    userId,
+			Username:  username,
 			IP:        c.ClientIP(),
 			Status:    true,
 			UserAgent: c.GetHeader("User-Agent"),
 		})
 
-	xresponse.Success(c, gin.H{
-		"access_token":             token.AccessToken,
-		"refresh_token":            token.RefreshToken,
-		"access_expire_timestamp":  token.AccessExpire.Unix(),
-		"refresh_expire_timestamp": token.RefreshExpire.Unix(),
-	})
+	res := gin.H{}
+	// 首次绑定验证器时返回恢复码，仅展示一次
+	if len(recoveryCodes) > 0 {
+		res["recovery_codes"] = recoveryCodes
+	}
+	writeTokens(c, container, pair, res)
 }
 
-// RefreshToken 刷新token
-func RefreshToken(c *gin.Context) {
+// writeTokens 返回令牌，会话策略下令牌写入 HttpOnly Cookie，响应体只返回过期时间
+func writeTokens(c *gin.Context, container *di.Container, pair *xauth.TokenPair, res gin.H) {
+	res["access_expire_timestamp"] = pair.AccessExpire.Unix()
+	res["refresh_expire_timestamp"] = pair.RefreshExpire.Unix()
+	if container.TokenCookie != nil {
+		if err := container.TokenCookie.WriteTokens(c.Writer, pair); err != nil {
+			xlogger.ErrorfCtx(c.Request.Context(), "write token cookie err: %v", err)
+			xresponse.FailByError(c, e.TokenError)
+			return
+		}
+	} else {
+		res["access_token"] = pair.AccessToken
+		res["refresh_token"] = pair.RefreshToken
+	}
+	xresponse.Success(c, res)
+}
+
+// refreshTokenFromRequest 读取 refresh token，会话策略下优先读取 Cookie 并校验 CSRF，失败时已写入响应
+func refreshTokenFromRequest(c *gin.Context, container *di.Container) (string, bool) {
+	if container.TokenCookie != nil {
+		if token := container.TokenCookie.RefreshToken(c.Request); token != "" {
+			if !container.TokenCookie.VerifyCSRF(c.Request) {
+				xresponse.Fail(c, e.HttpForbidden.GetErrCode(), e.CsrfTokenInvalid.GetErrMsg())
+				return "", false
+			}
+			return token, true
+		}
+	}
 	var req struct {
 		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
-		return
+		return "", false
 	}
-	ctx := c.Request.Context()
+	return req.RefreshToken, true
+}
 
+// RefreshToken 刷新token
+func RefreshToken(c *gin.Context) {
 	container := di.GetContainer(c)
-	jwtMgr := container.JwtManager
+	refreshToken, ok := refreshTokenFromRequest(c, container)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
 
 	// 检查 jti 是否使用过（防止重放攻击、每个refresh token只能使用一次）
-	claims, err := jwtMgr.ParseToken(req.RefreshToken)
+	claims, err := container.TokenStrategy.ParseRefreshToken(ctx, refreshToken)
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
+		if errors.Is(err, xauth.ErrTokenExpired) {
 			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenExpired.GetErrMsg())
 			return
 		}
@@ -148,17 +419,57 @@ func RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// 先删除旧 JTI，再生成新的token，宁愿用户重新登录，也不允许 refresh token 被并发重复使用，并且生成新token理论上不应该失败
-	// 删除旧 jti（防止重放）
-	jtiKey := constant.CacheRefreshJtiPrefix + claims.ID
-	if del, _ := container.Cache.Delete(ctx, jtiKey); del == 0 {
-		xlogger.ErrorfCtx(ctx, "refresh token reuse attempt: userID=%d, jti=%s", claims.UserId, claims.ID)
+	// 禁用、重置密码、角色变更后，旧 refresh token 不允许再换取新token
+	revoked, err := container.RevocationService.IsRevoked(ctx, &accountService.TokenIdentity{
+		UserId:    claims.UserId,
+		SessionId: claims.SessionId,
+		FamilyId:  claims.FamilyId,
+		IssuedAt:  claims.IssuedAt,
+	})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "check refresh token revoked err: %v", err)
+		xresponse.FailByError(c, e.HttpInternalServerError)
+		return
+	}
+	if revoked {
+		xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenRevoked.GetErrMsg())
+		return
+	}
+
+	// 先标记旧 JTI 已使用，再生成新的token，宁愿用户重新登录，也不允许 refresh token 被并发重复使用，并且生成新token理论上不应该失败
+	state, err := container.SessionService.UseRefreshJti(ctx, claims.TokenId)
+	if err != nil {
+		xresponse.FailByError(c, e.HttpInternalServerError)
+		return
+	}
+	switch state {
+	case accountService.RefreshJtiMissing:
+		// 登出、踢出、会话淘汰或已过期，按无效令牌处理，不吊销令牌族
+		xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenInvalid.GetErrMsg())
+		return
+	case accountService.RefreshJtiReused:
+		// 已使用过的 refresh token 再次出现，说明令牌可能被窃取：吊销整个令牌族，持有新token的一方同样失效
+		xlogger.ErrorfCtx(ctx, "refresh token reuse attempt: userID=%d, jti=%s, family=%s", claims.UserId, claims.TokenId, claims.FamilyId)
+		count, err := container.SessionService.RevokeFamily(ctx, claims.UserId, claims.FamilyId)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "revoke refresh token family err: %v", err)
+		}
+		// 记录安全事件
+		container.LoginLogService.CreateLoginLog(ctx,
+			&systemService.LoginLogInput{
+				UserID:    claims.UserId,
+				Username:  claims.Username,
+				IP:        c.ClientIP(),
+				Status:    false,
+				Message:   fmt.Sprintf("检测到refresh token重复使用，已吊销令牌族%s(结束会话%d个)", claims.FamilyId, count),
+				UserAgent: c.GetHeader("User-Agent"),
+			})
 		xresponse.FailByError(c, e.TokenUsedError)
 		return
 	}
 
 	// 生成新的token
-	token, err := jwtMgr.RefreshTokens(req.RefreshToken)
+	pair, err := container.TokenStrategy.RotateTokens(ctx, claims)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "refresh access token err: %s", err.Error())
 		xresponse.FailByError(c, e.HttpInternalServerError)
@@ -166,22 +477,38 @@ func RefreshToken(c *gin.Context) {
 	}
 
 	// 保存 refresh token 的 jti，设置过期时间（防止重放攻击、每个refresh token只能使用一次）
-	if newClaims, err := jwtMgr.ParseToken(token.RefreshToken); err == nil {
-		jtiKey = constant.CacheRefreshJtiPrefix + newClaims.ID
-		err = container.Cache.Set(ctx, jtiKey, "1", newClaims.ExpiresAt.Sub(newClaims.IssuedAt.Time))
-		if err != nil {
-			xlogger.ErrorfCtx(ctx, "save refresh token jti err: %v", err)
-			xresponse.FailByError(c, e.HttpInternalServerError)
+	newClaims := pair.Refresh
+	if err = container.SessionService.SaveRefreshJti(ctx, newClaims.TokenId, newClaims.ExpireAt.Sub(newClaims.IssuedAt)); err != nil {
+		xresponse.FailByError(c, e.HttpInternalServerError)
+		return
+	}
+	// 会话ID随 refresh token 轮换；会话在刷新期间被踢出或登出时作废刚签发的令牌
+	if err = container.SessionService.Rotate(ctx, claims.UserId, claims.TokenId, newClaims.TokenId, newClaims.FamilyId, pair.RefreshExpire); err != nil {
+		discardRotatedTokens(c, container, newClaims.TokenId)
+		if errors.Is(err, accountService.ErrSessionNotFound) {
+			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenInvalid.GetErrMsg())
 			return
 		}
+		xlogger.ErrorfCtx(ctx, "rotate session err: %v", err)
+		xresponse.FailByError(c, e.HttpInternalServerError)
+		return
 	}
 
-	xresponse.Success(c, gin.H{
-		"access_token":             token.AccessToken,
-		"refresh_token":            token.RefreshToken,
-		"access_expire_timestamp":  token.AccessExpire.Unix(),
-		"refresh_expire_timestamp": token.RefreshExpire.Unix(),
-	})
+	writeTokens(c, container, pair, gin.H{})
+}
+
+// discardRotatedTokens 会话轮换失败时作废刚签发的令牌：删除新 refresh jti 与服务端令牌，吊销新会话下的 access token
+func discardRotatedTokens(c *gin.Context, container *di.Container, sessionId string) {
+	ctx := c.Request.Context()
+	if _, err := container.Cache.Delete(ctx, constant.CacheRefreshJtiPrefix+sessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "delete rotated refresh jti err: %v", err)
+	}
+	if err := container.RevocationService.RevokeSession(ctx, sessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "revoke rotated session err: %v", err)
+	}
+	if err := container.TokenStrategy.RevokeTokens(ctx, "", sessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "revoke rotated tokens err: %v", err)
+	}
 }
 
 func Logout(c *gin.Context) {
@@ -193,11 +520,23 @@ func Logout(c *gin.Context) {
 		return
 	}
 
-	cache := di.GetContainer(c).Cache
+	container := di.GetContainer(c)
 
 	// 根据 sessionId 删除 refresh token
 	jtiKey := constant.CacheRefreshJtiPrefix + userContext.SessionId
-	_, _ = cache.Delete(ctx, jtiKey)
+	_, _ = container.Cache.Delete(ctx, jtiKey)
+
+	// 吊销当前 access token 及同会话下签发的 access token
+	_ = container.RevocationService.RevokeToken(ctx, userContext.TokenId)
+	_ = container.RevocationService.RevokeSession(ctx, userContext.SessionId)
+	_ = container.SessionService.Remove(ctx, userContext.UserId, userContext.SessionId)
+	// 会话策略下删除服务端令牌并清除 Cookie
+	if err := container.TokenStrategy.RevokeTokens(ctx, userContext.TokenId, userContext.SessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "revoke tokens err: %v", err)
+	}
+	if container.TokenCookie != nil {
+		container.TokenCookie.Clear(c.Writer)
+	}
 
 	xresponse.Success(c, gin.H{
 		"user_id": userContext.UserId,
@@ -0,0 +1,149 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/di"
+	"snowgo/internal/service/admin/account"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xgin"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+)
+
+// CreateDept 创建部门
+func CreateDept(c *gin.Context) {
+	var deptParam account.DeptParam
+	if err := c.ShouldBindJSON(&deptParam); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetAccountContainer(c)
+	deptId, err := container.DeptService.CreateDept(ctx, &deptParam)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "create dept is err: %v", err)
+		xresponse.FailByError(c, e.DeptCreateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": deptId})
+}
+
+// UpdateDept 更新部门
+func UpdateDept(c *gin.Context) {
+	var deptParam account.DeptParam
+	if err := c.ShouldBindJSON(&deptParam); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetAccountContainer(c)
+	err := container.DeptService.UpdateDept(ctx, &deptParam)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "update dept is err: %v", err)
+		xresponse.FailByError(c, e.DeptUpdateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": deptParam.ID})
+}
+
+// MoveDept 移动部门到新的父级
+func MoveDept(c *gin.Context) {
+	var moveParam account.DeptMoveParam
+	if err := c.ShouldBindJSON(&moveParam); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetAccountContainer(c)
+	err := container.DeptService.MoveDept(ctx, &moveParam)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "move dept is err: %v", err)
+		xresponse.FailByError(c, e.DeptUpdateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": moveParam.ID})
+}
+
+// SortDept 批量调整部门排序
+func SortDept(c *gin.Context) {
+	var sortParam account.DeptSortParam
+	if err := c.ShouldBindJSON(&sortParam); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetAccountContainer(c)
+	err := container.DeptService.SortDept(ctx, &sortParam)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "sort dept is err: %v", err)
+		xresponse.FailByError(c, e.DeptUpdateError)
+		return
+	}
+	xresponse.Success(c, nil)
+}
+
+// GetDeptList 部门树
+func GetDeptList(c *gin.Context) {
+	container := di.GetAccountContainer(c)
+	ctx := c.Request.Context()
+	res, err := container.DeptService.GetDeptTree(ctx)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get dept list is err: %v", err)
+		xresponse.FailByError(c, e.DeptListError)
+		return
+	}
+	xresponse.Success(c, res)
+}
+
+// DeleteDeptById 部门删除
+func DeleteDeptById(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.DeptNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+	container := di.GetAccountContainer(c)
+	err := container.DeptService.DeleteDeptById(ctx, id)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "delete dept is err: %v", err)
+		xresponse.FailByError(c, e.DeptDeleteError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": id})
+}
@@ -0,0 +1,274 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	accountService "snowgo/internal/service/admin/account"
+	systemService "snowgo/internal/service/admin/system"
+	"snowgo/pkg/xauth"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xgin"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+)
+
+// LoginMfaSetup 登录挑战内绑定验证器（角色强制两步验证但用户尚未绑定）
+func LoginMfaSetup(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	enrollment, err := container.MfaService.SetupChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "login mfa setup is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, enrollment)
+}
+
+// LoginMfaVerify 登录第二步，校验验证码或恢复码后签发token（需修改密码时返回修改密码凭证）
+func LoginMfaVerify(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), "code or recovery_code is required")
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	challenge, err := container.MfaService.GetChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get mfa challenge is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+
+	// 验证码错误与密码错误共用登录失败计数，防止通过反复登录获取新挑战来暴力破解验证码
+	limiter, _, ok := addLoginAttempt(c, container, challenge.Username)
+	if !ok {
+		return
+	}
+
+	verified, recoveryCodes, err := container.MfaService.VerifyChallenge(ctx, req.ChallengeToken, &accountService.MfaVerifyInput{
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+	})
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			container.LoginLogService.CreateLoginLog(ctx,
+				&systemService.LoginLogInput{
+					UserID:    challenge.UserId,
+					Username:  challenge.Username,
+					IP:        c.ClientIP(),
+					Status:    false,
+					Message:   bizErr.Code.GetErrMsg(),
+					UserAgent: c.GetHeader("User-Agent"),
+				})
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "verify mfa challenge is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+
+	// 两步验证期间用户可能已被禁用或删除
+	user, err := container.UserService.GetUserById(ctx, verified.UserId)
+	if err != nil || user.Status == constant.UserStatusDisabled {
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "get user after mfa verify is err: %v", err)
+		}
+		xresponse.FailByError(c, e.AuthError)
+		return
+	}
+
+	// 两步验证通过后仍需修改密码时只返回修改密码凭证，不签发token
+	if startPwdChange(c, container, limiter, verified.UserId, verified.Username, recoveryCodes) {
+		return
+	}
+
+	// 成功登录 → 重置失败计数
+	resetLoginAttempt(c, container, limiter, verified.UserId)
+
+	issueLoginTokens(c, container, verified.UserId, verified.Username, verified.Device, recoveryCodes)
+}
+
+// GetMfaStatus 当前登录用户两步验证状态
+func GetMfaStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		xresponse.FailByError(c, e.HttpUnauthorized)
+		return
+	}
+
+	container := di.GetContainer(c)
+	status, err := container.MfaService.GetStatus(ctx, userContext.UserId)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get mfa status is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, status)
+}
+
+// EnrollMfa 当前登录用户绑定验证器，返回密钥和 otpauth URI
+func EnrollMfa(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	enrollment, err := container.MfaService.Enroll(ctx)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "enroll mfa is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, enrollment)
+}
+
+// ActivateMfa 当前登录用户验证首个验证码，启用两步验证
+func ActivateMfa(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	recoveryCodes, err := container.MfaService.Activate(ctx, req.Code)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "activate mfa is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"recovery_codes": recoveryCodes})
+}
+
+// DisableMfa 当前登录用户关闭两步验证
+func DisableMfa(c *gin.Context) {
+	var req struct {
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), "code or recovery_code is required")
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	err := container.MfaService.Disable(ctx, &accountService.MfaVerifyInput{
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+	})
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "disable mfa is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, nil)
+}
+
+// RegenerateMfaRecoveryCodes 当前登录用户重新生成恢复码
+func RegenerateMfaRecoveryCodes(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	recoveryCodes, err := container.MfaService.RegenerateRecoveryCodes(ctx, req.Code)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "regenerate mfa recovery codes is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"recovery_codes": recoveryCodes})
+}
+
+// ResetUserMfa 管理员重置用户两步验证
+func ResetUserMfa(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	if err := container.MfaService.ResetUserMfa(ctx, id); err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "reset user mfa is err: %v", err)
+		xresponse.FailByError(c, e.MfaError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": id})
+}
@@ -0,0 +1,101 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+	"snowgo/internal/di"
+	accountService "snowgo/internal/service/admin/account"
+	systemService "snowgo/internal/service/admin/system"
+	"snowgo/pkg/xauth/oidc"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+	"time"
+)
+
+// oidcCookiePath 浏览器绑定 Cookie 只发送到 OIDC 接口所在路径（authorize 与 callback 同级）
+func oidcCookiePath(c *gin.Context) string {
+	return path.Dir(c.FullPath())
+}
+
+// OidcAuthorize 发起OIDC单点登录，返回IdP授权地址，由前端跳转
+func OidcAuthorize(c *gin.Context) {
+	var req struct {
+		Device string `form:"device" binding:"max=64"` // 登录设备名称，为空时根据 User-Agent 识别
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	device := req.Device
+	if device == "" {
+		device = accountService.DeviceFromUserAgent(c.GetHeader("User-Agent"))
+	}
+	container := di.GetContainer(c)
+	authorization, err := container.OidcService.Begin(ctx, device)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "oidc authorize err: %v", err)
+		xresponse.FailByError(c, e.OidcLoginError)
+		return
+	}
+	// state 绑定发起登录的浏览器，回调时校验，防止登录 CSRF
+	maxAge := max(int(time.Until(authorization.ExpireAt).Seconds()), 1)
+	http.SetCookie(c.Writer, oidc.BindingCookie(authorization.Binding, oidcCookiePath(c), maxAge, container.OidcService.CookieSecure()))
+	xresponse.Success(c, gin.H{
+		"authorization_url": authorization.AuthorizationURL,
+		"state":             authorization.State,
+		"expire_timestamp":  authorization.ExpireAt.Unix(),
+	})
+}
+
+// OidcCallback OIDC回调，前端回调页携带 code 与 state 换取系统token
+func OidcCallback(c *gin.Context) {
+	var req struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	// 浏览器绑定 Cookie 一次性使用，无论回调成功与否都清除
+	binding, _ := c.Cookie(oidc.BindingCookieName)
+	http.SetCookie(c.Writer, oidc.BindingCookie("", oidcCookiePath(c), -1, container.OidcService.CookieSecure()))
+	result, err := container.OidcService.Callback(ctx, req.Code, req.State, binding)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			// 记录登录日志
+			container.LoginLogService.CreateLoginLog(ctx,
+				&systemService.LoginLogInput{
+					IP:        c.ClientIP(),
+					Status:    false,
+					Message:   "OIDC: " + bizErr.Code.GetErrMsg(),
+					UserAgent: c.GetHeader("User-Agent"),
+				})
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "oidc callback err: %v", err)
+		xresponse.FailByError(c, e.OidcLoginError)
+		return
+	}
+
+	// 本地开启或角色强制两步验证的用户，单点登录同样需要完成两步验证
+	if startMfaChallenge(c, container, result.UserId, result.Username, result.Device) {
+		return
+	}
+	issueLoginTokens(c, container, result.UserId, result.Username, result.Device, nil)
+}
@@ -0,0 +1,39 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/di"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+)
+
+// LoginPwdChange 登录时强制修改密码，凭修改密码凭证访问，修改成功后需使用新密码重新登录
+func LoginPwdChange(c *gin.Context) {
+	var req struct {
+		ChangeToken string `json:"change_token" binding:"required"`
+		Password    string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	ticket, err := container.UserService.ChangePwdByTicket(ctx, req.ChangeToken, req.Password)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "login pwd change is err: %v", err)
+		xresponse.FailByError(c, e.PwdChangeError)
+		return
+	}
+	xresponse.Success(c, gin.H{
+		"user_id": ticket.UserId,
+	})
+}
@@ -0,0 +1,120 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	"snowgo/internal/service/admin/account"
+	"snowgo/pkg/xauth"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+)
+
+// GetProfile 当前登录用户信息
+func GetProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	container := di.GetContainer(c)
+	user, err := container.UserService.GetProfile(ctx)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get profile is err: %v", err)
+		xresponse.FailByError(c, e.UserInfoError)
+		return
+	}
+	roleList := make([]*UserRole, 0, len(user.RoleList))
+	for _, role := range user.RoleList {
+		roleList = append(roleList, &UserRole{
+			ID:         role.ID,
+			Name:       role.Name,
+			Code:       role.Code,
+			ValidFrom:  formatOptionalTime(role.ValidFrom),
+			ValidUntil: formatOptionalTime(role.ValidUntil),
+		})
+	}
+
+	xresponse.Success(c, &UserInfo{
+		ID:        user.ID,
+		Username:  user.Username,
+		Tel:       user.Tel,
+		Nickname:  user.Nickname,
+		Email:     user.Email,
+		Remark:    user.Remark,
+		Status:    user.Status,
+		UserType:  user.UserType,
+		DeptId:    user.DeptId,
+		DeptName:  user.DeptName,
+		CreatedBy: user.CreatedBy,
+		UpdatedBy: user.UpdatedBy,
+		CreatedAt: user.CreatedAt.Format(constant.TimeFmtWithMS),
+		UpdatedAt: user.UpdatedAt.Format(constant.TimeFmtWithMS),
+		RoleList:  roleList,
+	})
+}
+
+// UpdateProfile 当前登录用户修改昵称、邮箱、电话
+func UpdateProfile(c *gin.Context) {
+	var param account.ProfileParam
+	if err := c.ShouldBindJSON(&param); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	err := container.UserService.UpdateProfile(ctx, &param)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "update profile is err: %v", err)
+		xresponse.FailByError(c, e.UserUpdateError)
+		return
+	}
+	xresponse.Success(c, nil)
+}
+
+// ChangePwd 当前登录用户修改密码，成功后需重新登录
+func ChangePwd(c *gin.Context) {
+	var param struct {
+		OldPassword string `json:"old_password" binding:"required"`
+		Password    string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&param); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		xresponse.FailByError(c, e.HttpForbidden)
+		return
+	}
+
+	container := di.GetContainer(c)
+	// 原密码错误与登录失败共用计数，防止会话泄露后暴力破解原密码
+	limiter, _, ok := addLoginAttempt(c, container, userContext.Username)
+	if !ok {
+		return
+	}
+	err = container.UserService.ChangePwd(ctx, param.OldPassword, param.Password)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "change pwd is err: %v", err)
+		xresponse.FailByError(c, e.PwdChangeError)
+		return
+	}
+	resetLoginAttempt(c, container, limiter, userContext.UserId)
+	xresponse.Success(c, &gin.H{"id": userContext.UserId})
+}
@@ -18,6 +18,7 @@ type RoleListInfo struct {
 	Name        string `json:"name"`
 	Code        string `json:"code"`
 	Description string `json:"description"`
+	RequireMfa  bool   `json:"require_mfa"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
@@ -138,6 +139,7 @@ func GetRoleList(c *gin.Context) {
 			Name:        role.Name,
 			Code:        role.Code,
 			Description: role.Description,
+			RequireMfa:  role.RequireMfa,
 			CreatedAt:   role.CreatedAt.Format(constant.TimeFmtWithMS),
 			UpdatedAt:   role.UpdatedAt.Format(constant.TimeFmtWithMS),
 		})
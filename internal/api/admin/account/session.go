@@ -0,0 +1,110 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xgin"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+)
+
+type SessionInfo struct {
+	SessionId  string `json:"session_id"`
+	Device     string `json:"device"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	LoginAt    string `json:"login_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	ExpireAt   string `json:"expire_at"`
+}
+
+// GetUserSessionList 用户在线会话列表
+func GetUserSessionList(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	sessions, err := container.SessionService.ListSessions(ctx, id)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get user session list is err: %v", err)
+		xresponse.FailByError(c, e.SessionListError)
+		return
+	}
+	list := make([]*SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, &SessionInfo{
+			SessionId:  session.SessionId,
+			Device:     session.Device,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			LoginAt:    session.LoginAt.Format(constant.TimeFmtWithMS),
+			LastSeenAt: session.LastSeenAt.Format(constant.TimeFmtWithMS),
+			ExpireAt:   session.ExpireAt.Format(constant.TimeFmtWithMS),
+		})
+	}
+	xresponse.Success(c, &gin.H{"list": list, "total": len(list)})
+}
+
+// KickUserSession 踢出用户的某个会话
+func KickUserSession(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	sessionId := c.Param("session_id")
+	if sessionId == "" {
+		xresponse.FailByError(c, e.SessionNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	if err := container.SessionService.KickSession(ctx, id, sessionId); err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "kick user session is err: %v", err)
+		xresponse.FailByError(c, e.SessionKickError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": id, "session_id": sessionId})
+}
+
+// KickUserAllSessions 踢出用户的全部会话
+func KickUserAllSessions(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetContainer(c)
+	count, err := container.SessionService.KickAllSessions(ctx, id)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "kick user all sessions is err: %v", err)
+		xresponse.FailByError(c, e.SessionKickError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": id, "count": count})
+}
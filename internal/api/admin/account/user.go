@@ -2,10 +2,12 @@ package account
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"snowgo/internal/constant"
 	"snowgo/internal/di"
 	"snowgo/internal/service/admin/account"
+	systemService "snowgo/internal/service/admin/system"
 	"snowgo/pkg/xauth"
 	e "snowgo/pkg/xerror"
 	"snowgo/pkg/xgin"
@@ -14,38 +16,51 @@ import (
 )
 
 type UserListInfo struct {
-	ID        int32  `json:"id"`
-	Username  string `json:"username"`
-	Tel       string `json:"tel"`
-	Nickname  string `json:"nickname"`
-	Email     string `json:"email"`
-	Remark    string `json:"remark"`
-	Status    int8   `json:"status"`
-	CreatedBy int32  `json:"created_by"`
-	UpdatedBy int32  `json:"updated_by"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID          int32  `json:"id"`
+	Username    string `json:"username"`
+	Tel         string `json:"tel"`
+	Nickname    string `json:"nickname"`
+	Email       string `json:"email"`
+	Remark      string `json:"remark"`
+	Status      int8   `json:"status"`
+	UserType    int8   `json:"user_type"`
+	DeptId      int32  `json:"dept_id"`
+	DeptName    string `json:"dept_name"`
+	LockStatus  int8   `json:"lock_status"`  // 登录锁定状态：0 未锁定, 1 临时锁定, 2 永久锁定
+	LockedUntil string `json:"locked_until"` // 临时锁定截止时间
+	CreatedBy   int32  `json:"created_by"`
+	UpdatedBy   int32  `json:"updated_by"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
 }
 
 type UserInfo struct {
-	ID        int32       `json:"id"`
-	Username  string      `json:"username"`
-	Tel       string      `json:"tel"`
-	Nickname  string      `json:"nickname"`
-	Email     string      `json:"email"`
-	Remark    string      `json:"remark"`
-	Status    int8        `json:"status"`
-	CreatedBy int32       `json:"created_by"`
-	UpdatedBy int32       `json:"updated_by"`
-	CreatedAt string      `json:"created_at"`
-	UpdatedAt string      `json:"updated_at"`
-	RoleList  []*UserRole `json:"role_list"`
+	ID          int32       `json:"id"`
+	Username    string      `json:"username"`
+	Tel         string      `json:"tel"`
+	Nickname    string      `json:"nickname"`
+	Email       string      `json:"email"`
+	Remark      string      `json:"remark"`
+	Status      int8        `json:"status"`
+	UserType    int8        `json:"user_type"`
+	DeptId      int32       `json:"dept_id"`
+	DeptName    string      `json:"dept_name"`
+	LockStatus  int8        `json:"lock_status"`  // 登录锁定状态：0 未锁定, 1 临时锁定, 2 永久锁定
+	LockCount   int32       `json:"lock_count"`   // 连续锁定次数，达到上限后永久锁定
+	LockedUntil string      `json:"locked_until"` // 临时锁定截止时间
+	CreatedBy   int32       `json:"created_by"`
+	UpdatedBy   int32       `json:"updated_by"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
+	RoleList    []*UserRole `json:"role_list"`
 }
 
 type UserRole struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Code string `json:"code"`
+	ID         int32  `json:"id"`
+	Name       string `json:"name"`
+	Code       string `json:"code"`
+	ValidFrom  string `json:"valid_from"`  // 限时授权生效时间，为空表示立即生效
+	ValidUntil string `json:"valid_until"` // 限时授权失效时间，为空表示永久有效
 }
 
 type UserList struct {
@@ -61,8 +76,8 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
-	// 可以额外校验
-	if user.Username == "" || user.Tel == "" {
+	// 可以额外校验，服务账号无需电话
+	if user.Username == "" || (user.UserType != constant.UserTypeService && user.Tel == "") {
 		xresponse.FailByError(c, e.UserNameTelEmptyError)
 		return
 	}
@@ -90,8 +105,8 @@ func UpdateUser(c *gin.Context) {
 		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
 		return
 	}
-	// 额外校验
-	if user.Username == "" || user.Tel == "" {
+	// 额外校验，电话是否必填由服务层按用户类型判断
+	if user.Username == "" {
 		xresponse.FailByError(c, e.UserNameTelEmptyError)
 		return
 	}
@@ -136,25 +151,33 @@ func GetUserInfo(c *gin.Context) {
 	roleList := make([]*UserRole, 0, len(user.RoleList))
 	for _, role := range user.RoleList {
 		roleList = append(roleList, &UserRole{
-			ID:   role.ID,
-			Name: role.Name,
-			Code: role.Code,
+			ID:         role.ID,
+			Name:       role.Name,
+			Code:       role.Code,
+			ValidFrom:  formatOptionalTime(role.ValidFrom),
+			ValidUntil: formatOptionalTime(role.ValidUntil),
 		})
 	}
 
 	xresponse.Success(c, &UserInfo{
-		ID:        user.ID,
-		Username:  user.Username,
-		Tel:       user.Tel,
-		Nickname:  user.Nickname,
-		Email:     user.Email,
-		Remark:    user.Remark,
-		Status:    user.Status,
-		CreatedBy: user.CreatedBy,
-		UpdatedBy: user.UpdatedBy,
-		CreatedAt: user.CreatedAt.Format(constant.TimeFmtWithMS),
-		UpdatedAt: user.UpdatedAt.Format(constant.TimeFmtWithMS),
-		RoleList:  roleList,
+		ID:          user.ID,
+		Username:    user.Username,
+		Tel:         user.Tel,
+		Nickname:    user.Nickname,
+		Email:       user.Email,
+		Remark:      user.Remark,
+		Status:      user.Status,
+		UserType:    user.UserType,
+		DeptId:      user.DeptId,
+		DeptName:    user.DeptName,
+		LockStatus:  user.LockStatus,
+		LockCount:   user.LockCount,
+		LockedUntil: formatOptionalTime(user.LockedUntil),
+		CreatedBy:   user.CreatedBy,
+		UpdatedBy:   user.UpdatedBy,
+		CreatedAt:   user.CreatedAt.Format(constant.TimeFmtWithMS),
+		UpdatedAt:   user.UpdatedAt.Format(constant.TimeFmtWithMS),
+		RoleList:    roleList,
 	})
 }
 
@@ -194,17 +217,22 @@ func GetUserList(c *gin.Context) {
 	userList := make([]*UserListInfo, 0, len(res.List))
 	for _, user := range res.List {
 		userList = append(userList, &UserListInfo{
-			ID:        user.ID,
-			Username:  user.Username,
-			Tel:       user.Tel,
-			Nickname:  user.Nickname,
-			Email:     user.Email,
-			Remark:    user.Remark,
-			Status:    user.Status,
-			CreatedBy: user.CreatedBy,
-			UpdatedBy: user.UpdatedBy,
-			CreatedAt: user.CreatedAt.Format(constant.TimeFmtWithMS),
-			UpdatedAt: user.UpdatedAt.Format(constant.TimeFmtWithMS),
+			ID:          user.ID,
+			Username:    user.Username,
+			Tel:         user.Tel,
+			Nickname:    user.Nickname,
+			Email:       user.Email,
+			Remark:      user.Remark,
+			Status:      user.Status,
+			UserType:    user.UserType,
+			DeptId:      user.DeptId,
+			DeptName:    user.DeptName,
+			LockStatus:  user.LockStatus,
+			LockedUntil: formatOptionalTime(user.LockedUntil),
+			CreatedBy:   user.CreatedBy,
+			UpdatedBy:   user.UpdatedBy,
+			CreatedAt:   user.CreatedAt.Format(constant.TimeFmtWithMS),
+			UpdatedAt:   user.UpdatedAt.Format(constant.TimeFmtWithMS),
 		})
 	}
 	xresponse.Success(c, &UserList{
@@ -265,6 +293,45 @@ func ResetPwdById(c *gin.Context) {
 	xresponse.Success(c, &gin.H{"id": param.ID})
 }
 
+// UnlockUser 解锁因登录失败被锁定的用户
+func UnlockUser(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.UserNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		xresponse.FailByError(c, e.HttpForbidden)
+		return
+	}
+
+	container := di.GetContainer(c)
+	lock, err := container.UserService.UnlockUser(ctx, id)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "unlock user is err: %v", err)
+		xresponse.FailByError(c, e.UserUnlockError)
+		return
+	}
+	// 解锁与锁定一样记录到被锁定用户的登录日志
+	container.LoginLogService.CreateLoginLog(ctx,
+		&systemService.LoginLogInput{
+			UserID:    lock.UserId,
+			Username:  lock.Username,
+			IP:        c.ClientIP(),
+			Status:    false,
+			Message:   fmt.Sprintf("账号已被用户(%d-%s)解锁", userContext.UserId, userContext.Username),
+			UserAgent: c.GetHeader("User-Agent"),
+		})
+	xresponse.Success(c, &gin.H{"id": id})
+}
+
 // GetUserPermission 用户权限信息
 func GetUserPermission(c *gin.Context) {
 	// 获取登录ctx
@@ -56,6 +56,20 @@ func GetOperationLogList(c *gin.Context) {
 		logListReq.Limit = constant.MaxLimit
 	}
 
+	// 按当前登录用户的数据范围过滤
+	dataScope, err := di.GetAccountContainer(c).UserService.GetDataScope(ctx)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get data scope is err: %v", err)
+		xresponse.FailByError(c, e.LogListError)
+		return
+	}
+	logListReq.DataScope = dataScope
+
 	container := di.GetSystemContainer(c)
 	res, err := container.OperationLogService.GetOperationLogList(ctx, &logListReq)
 	if err != nil {
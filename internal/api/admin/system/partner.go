@@ -0,0 +1,184 @@
+package system
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	"snowgo/internal/service/admin/system"
+	common "snowgo/pkg"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xgin"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+)
+
+type PartnerInfo struct {
+	ID         int32  `json:"id"`
+	Name       string `json:"name"`
+	AccessKey  string `json:"access_key"`
+	Status     int8   `json:"status"`
+	Remark     string `json:"remark"`
+	LastUsedAt string `json:"last_used_at"`
+	CreatedBy  int32  `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type PartnerList struct {
+	List  []*PartnerInfo `json:"list"`
+	Total int64          `json:"total"`
+}
+
+// GetPartnerList 开放接口合作方列表，不返回签名密钥
+func GetPartnerList(c *gin.Context) {
+	var partnerListReq system.PartnerListCondition
+	if err := c.ShouldBindQuery(&partnerListReq); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	if partnerListReq.Offset < 0 {
+		xresponse.FailByError(c, e.OffsetErrorRequests)
+		return
+	}
+	if partnerListReq.Limit < 0 {
+		xresponse.FailByError(c, e.LimitErrorRequests)
+		return
+	} else if partnerListReq.Limit == 0 {
+		partnerListReq.Limit = constant.DefaultLimit
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetSystemContainer(c)
+	res, err := container.PartnerService.GetPartnerList(ctx, &partnerListReq)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "get partner list is err: %v", err)
+		xresponse.FailByError(c, e.PartnerListError)
+		return
+	}
+	partnerList := make([]*PartnerInfo, 0, len(res.List))
+	for _, partner := range res.List {
+		partnerList = append(partnerList, toPartnerInfo(partner))
+	}
+	xresponse.Success(c, &PartnerList{
+		Total: res.Total,
+		List:  partnerList,
+	})
+}
+
+// CreatePartner 创建合作方，签名密钥明文仅在此处返回一次
+func CreatePartner(c *gin.Context) {
+	var param system.PartnerParam
+	if err := c.ShouldBindJSON(&param); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetSystemContainer(c)
+	created, err := container.PartnerService.CreatePartner(ctx, &param)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "create partner is err: %v", err)
+		xresponse.FailByError(c, e.PartnerCreateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"partner": toPartnerInfo(&created.PartnerInfo), "secret": created.Secret})
+}
+
+// UpdatePartner 更新合作方
+func UpdatePartner(c *gin.Context) {
+	var param system.PartnerParam
+	if err := c.ShouldBindJSON(&param); err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), err.Error())
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetSystemContainer(c)
+	partnerId, err := container.PartnerService.UpdatePartner(ctx, &param)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "update partner is err: %v", err)
+		xresponse.FailByError(c, e.PartnerUpdateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": partnerId})
+}
+
+// RotatePartnerSecret 更换合作方签名密钥，新密钥明文仅在此处返回一次
+func RotatePartnerSecret(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.PartnerNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetSystemContainer(c)
+	rotated, err := container.PartnerService.RotatePartnerSecret(ctx, id)
+	if err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "rotate partner secret is err: %v", err)
+		xresponse.FailByError(c, e.PartnerRotateError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"partner": toPartnerInfo(&rotated.PartnerInfo), "secret": rotated.Secret})
+}
+
+// DeletePartnerById 删除合作方
+func DeletePartnerById(c *gin.Context) {
+	id := xgin.ParsePathID32(c)
+	if id < 1 {
+		xresponse.FailByError(c, e.PartnerNotFound)
+		return
+	}
+	ctx := c.Request.Context()
+
+	container := di.GetSystemContainer(c)
+	if err := container.PartnerService.DeletePartner(ctx, id); err != nil {
+		var bizErr *e.BizError
+		if errors.As(err, &bizErr) {
+			xresponse.FailByError(c, bizErr.Code)
+			return
+		}
+		xlogger.ErrorfCtx(ctx, "delete partner is err: %v", err)
+		xresponse.FailByError(c, e.PartnerDeleteError)
+		return
+	}
+	xresponse.Success(c, &gin.H{"id": id})
+}
+
+func toPartnerInfo(partner *system.PartnerInfo) *PartnerInfo {
+	info := &PartnerInfo{
+		ID:        partner.ID,
+		Name:      partner.Name,
+		AccessKey: partner.AccessKey,
+		Status:    partner.Status,
+		Remark:    common.DerefOrZero(partner.Remark),
+		CreatedBy: partner.CreatedBy,
+		CreatedAt: partner.CreatedAt.Format(constant.TimeFmtWithMS),
+		UpdatedAt: partner.UpdatedAt.Format(constant.TimeFmtWithMS),
+	}
+	if partner.LastUsedAt != nil {
+		info.LastUsedAt = partner.LastUsedAt.Format(constant.TimeFmtWithMS)
+	}
+	return info
+}
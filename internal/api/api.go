@@ -57,6 +57,17 @@ func Readiness(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "ready"})
 }
 
+// JWKS 公布 JWT 验签公钥(RFC 7517)，供网关或下游服务离线验签
+func JWKS(c *gin.Context) {
+	container := di.GetContainer(c)
+	if container.JwtManager == nil {
+		c.JSON(503, gin.H{"error": "jwt not initialized"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(200, container.JwtManager.JWKS())
+}
+
 // PublishMessage 发送消息（已移除路由注册，保留作为 MQ 发布示例参考）
 func PublishMessage(c *gin.Context) {
 	container := di.GetContainer(c)
@@ -0,0 +1,21 @@
+package open
+
+import (
+	"github.com/gin-gonic/gin"
+	"snowgo/pkg/xauth"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xresponse"
+)
+
+// Ping 开放接口连通性检查，返回当前签名认证的合作方，供合作方联调签名
+func Ping(c *gin.Context) {
+	partnerContext, err := xauth.GetPartnerContext(c.Request.Context())
+	if err != nil {
+		xresponse.FailByError(c, e.HttpUnauthorized)
+		return
+	}
+	xresponse.Success(c, &gin.H{
+		"partner_id":   partnerContext.PartnerId,
+		"partner_name": partnerContext.PartnerName,
+	})
+}
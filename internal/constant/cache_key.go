@@ -4,6 +4,8 @@ const (
 	// CacheMenuTree 缓存相关key
 	CacheMenuTree               = "account:menu_data"   // 菜单权限数据缓存key
 	CacheMenuTreeExpirationDay  = 15                    // 菜单权限缓存天数
+	CacheDeptTree               = "account:dept_data"   // 部门树数据缓存key
+	CacheDeptTreeExpirationDay  = 15                    // 部门树缓存天数
 	CacheRolePermsPrefix        = "account:role_perms:" // 角色对应 接口权限key
 	CacheRolePermsExpirationDay = 15                    // 角色对应 接口权限缓存天数
 	CacheRoleMenuPrefix         = "account:role_menu:"  // 角色对应 菜单权限key
@@ -13,10 +15,73 @@ const (
 	CacheRefreshJtiPrefix       = "jwt:refresh:jti:"
 )
 
+const (
+	// CacheRevokedJtiPrefix token吊销相关
+	CacheRevokedJtiPrefix     = "jwt:revoked:jti:"     // 已吊销的 access token jti
+	CacheRevokedSessionPrefix = "jwt:revoked:session:" // 已吊销的会话（refresh jti）
+	CacheRevokedUserPrefix    = "jwt:revoked:user:"    // 用户吊销水位线（unix 毫秒），该时间及之前签发的token失效
+	CacheRevokedFamilyPrefix  = "jwt:revoked:family:"  // 已吊销的 refresh token 族（检测到重复使用）
+)
+
+const (
+	// ChannelPermInvalidate 进程内权限缓存失效广播频道（Redis 发布订阅）
+	ChannelPermInvalidate = "account:perm_invalidate"
+)
+
+const (
+	// LockRoleGrantSweep 清理过期限时角色授权的分布式锁，多实例只有一个执行
+	LockRoleGrantSweep = "lock:account:role_grant_sweep"
+)
+
+const (
+	// CacheOpaqueTokenPrefix 服务端不透明会话令牌（key 为令牌摘要），令牌策略为 session 时使用
+	CacheOpaqueTokenPrefix = "auth:token:"
+)
+
+const (
+	// CacheSessionPrefix 用户登录会话登记（hash，field 为会话ID）
+	CacheSessionPrefix = "account:session:"
+)
+
+const (
+	// CacheMfaChallengePrefix 两步验证登录挑战，密码校验通过后换取 token 前的临时凭证
+	CacheMfaChallengePrefix = "account:mfa:challenge:"
+)
+
+const (
+	// CacheOidcStatePrefix OIDC 单点登录授权请求状态（state -> nonce、PKCE code_verifier），回调时一次性消费
+	CacheOidcStatePrefix = "account:oidc:state:"
+)
+
+const (
+	// CacheCaptchaPrefix 登录验证码答案，校验一次后删除
+	CacheCaptchaPrefix = "account:captcha:"
+)
+
+const (
+	// CachePwdChangePrefix 强制修改密码凭证，登录校验通过但密码需修改时签发，只能用于修改密码
+	CachePwdChangePrefix = "account:pwd_change:"
+)
+
+const (
+	// CacheApiKeyPrefix API Key 认证结果缓存（key 为 API Key 摘要），吊销时删除
+	CacheApiKeyPrefix           = "account:api_key:"
+	CacheApiKeyUserPrefix       = "account:api_key_user:" // 服务账号已缓存的 API Key 摘要（hash），禁用、删除服务账号时据此清除认证缓存
+	CacheApiKeyExpirationSecond = 60                      // 认证结果缓存时长/s
+)
+
+const (
+	// CachePartnerPrefix 开放接口合作方凭证缓存（key 为访问密钥ID），更新、删除时删除
+	CachePartnerPrefix           = "system:partner:"
+	CachePartnerExpirationSecond = 60                 // 合作方凭证缓存时长/s
+	CacheSignatureNoncePrefix    = "open:sign:nonce:" // 签名请求已使用的 nonce，有效期覆盖允许的时钟偏差，防止重放
+)
+
 const (
 	// CacheLoginFailPrefix 用户登录相关
 	CacheLoginFailPrefix       = "login:fail:" // 登录失败key（用户判断用户在xx时间内登录失败的次数）
 	CacheLoginFailWindowSecond = 180           // 登录失败窗口/s
+	CacheLoginFailMaxCount     = 5             // 登录失败窗口内最多失败次数，超出后按锁定策略锁定账号
 )
 
 const (
@@ -24,3 +89,16 @@ const (
 	SystemDictPrefix        = "system:dict:" // 系统字典code缓存key
 	SystemDictExpirationDay = 30             // 系统字典code缓存天数
 )
+
+const (
+	// CacheIdempotencyPrefix 幂等请求的响应结果（key 为调用方、路由与 Idempotency-Key 的摘要）
+	CacheIdempotencyPrefix = "idempotency:"
+	LockIdempotencyPrefix  = "lock:idempotency:" // 幂等请求处理中标记
+)
+
+const (
+	// RateLimitCaptchaPrefix 分布式限流 key（GCRA 理论到达时间），按接口区分
+	RateLimitCaptchaPrefix = "rate:captcha:"
+	RateLimitLoginPrefix   = "rate:login:"
+	RateLimitOpenPrefix    = "rate:open:"
+)
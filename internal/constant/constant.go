@@ -12,6 +12,8 @@ const (
 
 	CONTAINER = "snowgo.internal.di.container" // 注册的container名
 
+	TokenRefreshCookiePath = "/api/admin/auth" // refresh token Cookie 路径，仅发送到刷新与登出接口所在分组
+
 	// ActiveStatus 状态
 	ActiveStatus   = "Active"
 	DisabledStatus = "Disabled"
@@ -28,14 +30,26 @@ const (
 	ResourceUser     = "User"
 	ResourceRole     = "Role"
 	ResourceMenu     = "Menu"
+	ResourceDept     = "Dept"
 	ResourceDict     = "Dict"
 	ResourceDictItem = "DictItem"
+	ResourceSession  = "Session"
+	ResourceMfa      = "Mfa"
+	ResourceApiKey   = "ApiKey"
+	ResourcePartner  = "Partner"
 )
 
 // 用户相关
 const (
 	UserStatusActive   int8 = 1 // 活跃
 	UserStatusDisabled int8 = 2 // 被禁用
+	UserTypeNormal     int8 = 1 // 普通用户，账号密码登录
+	UserTypeService    int8 = 2 // 服务账号，仅能通过 API Key 调用
+	PwdMustChangeNo    int8 = 0 // 无需修改密码
+	PwdMustChangeYes   int8 = 1 // 下次登录必须修改密码（首次登录、被管理员重置）
+	UserLockNone       int8 = 0 // 未锁定
+	UserLockTemporary  int8 = 1 // 临时锁定，到期自动解除
+	UserLockPermanent  int8 = 2 // 永久锁定，需管理员解锁
 
 	// MenuTypeDir 菜单相关
 	MenuTypeDir  = "Dir"
@@ -44,4 +58,24 @@ const (
 
 	// SuperAdminRoleId 角色相关
 	SuperAdminRoleId int32 = 1 // 超级管理员角色 ID，系统内置，不可删除
+	RoleRequireMfa   int8  = 1 // 角色强制两步验证
+
+	// DataScopeAll 角色数据范围，多个角色取并集
+	DataScopeAll       int8 = 1 // 全部数据
+	DataScopeSelf      int8 = 2 // 仅本人创建的数据
+	DataScopeDept      int8 = 3 // 本部门
+	DataScopeDeptBelow int8 = 4 // 本部门及以下
+	DataScopeCustom    int8 = 5 // 自定义部门
+
+	// MfaStatusPending 两步验证相关
+	MfaStatusPending int8 = 1 // 已生成密钥，待验证首个验证码
+	MfaStatusEnabled int8 = 2 // 已启用
+
+	// ApiKeyStatusActive API Key 相关
+	ApiKeyStatusActive  int8 = 1 // 有效
+	ApiKeyStatusRevoked int8 = 2 // 已吊销
+
+	// PartnerStatusEnabled 开放接口合作方相关
+	PartnerStatusEnabled  int8 = 1 // 启用
+	PartnerStatusDisabled int8 = 2 // 禁用，签名请求直接拒绝
 )
@@ -1,35 +1,64 @@
 package constant
 
-const (
+import "snowgo/pkg/xauth/perm"
+
+// PermAll 全部权限，超级管理员角色默认拥有，无需逐个分配菜单
+const PermAll = "*"
+
+// 接口权限点，在全局权限注册表中唯一定义，名称用于同步菜单 Btn 节点（cmd/perm-sync）
+var (
 	// PermAccountUserList 账号管理 - 用户管理
-	PermAccountUserList     = "account:user:list"      // 查看用户列表
-	PermAccountUserDetail   = "account:user:detail"    // 查看用户详情
-	PermAccountUserCreate   = "account:user:create"    // 创建用户
-	PermAccountUserUpdate   = "account:user:update"    // 更新用户信息
-	PermAccountUserDelete   = "account:user:delete"    // 删除用户
-	PermAccountUserResetPwd = "account:user:reset_pwd" // 重置用户密码
+	PermAccountUserList     = perm.Define("account:user:list", "查看用户列表")
+	PermAccountUserDetail   = perm.Define("account:user:detail", "查看用户详情")
+	PermAccountUserCreate   = perm.Define("account:user:create", "创建用户")
+	PermAccountUserUpdate   = perm.Define("account:user:update", "更新用户信息")
+	PermAccountUserDelete   = perm.Define("account:user:delete", "删除用户")
+	PermAccountUserResetPwd = perm.Define("account:user:reset_pwd", "重置用户密码")
+	PermAccountUserResetMfa = perm.Define("account:user:reset_mfa", "重置用户两步验证")
+	PermAccountUserUnlock   = perm.Define("account:user:unlock", "解锁登录锁定的用户")
+
+	// PermAccountSessionList 账号管理 - 用户会话
+	PermAccountSessionList = perm.Define("account:session:list", "查看用户在线会话")
+	PermAccountSessionKick = perm.Define("account:session:kick", "踢出用户会话")
+
+	// PermAccountApiKeyList 账号管理 - 服务账号 API Key
+	PermAccountApiKeyList   = perm.Define("account:api_key:list", "查看服务账号 API Key")
+	PermAccountApiKeyCreate = perm.Define("account:api_key:create", "创建服务账号 API Key")
+	PermAccountApiKeyRevoke = perm.Define("account:api_key:revoke", "吊销服务账号 API Key")
 
 	// PermAccountRoleList 账号管理 - 角色管理
-	PermAccountRoleList   = "account:role:list"   // 查看角色列表
-	PermAccountRoleDetail = "account:role:detail" // 查看角色详情
-	PermAccountRoleCreate = "account:role:create" // 创建角色
-	PermAccountRoleUpdate = "account:role:update" // 更新角色信息
-	PermAccountRoleDelete = "account:role:delete" // 删除角色
+	PermAccountRoleList   = perm.Define("account:role:list", "查看角色列表")
+	PermAccountRoleDetail = perm.Define("account:role:detail", "查看角色详情")
+	PermAccountRoleCreate = perm.Define("account:role:create", "创建角色")
+	PermAccountRoleUpdate = perm.Define("account:role:update", "更新角色信息")
+	PermAccountRoleDelete = perm.Define("account:role:delete", "删除角色")
 
 	// PermAccountMenuList 账号管理 - 菜单管理
-	PermAccountMenuList   = "account:menu:list"   // 查看菜单列表
-	PermAccountMenuCreate = "account:menu:create" // 创建菜单
-	PermAccountMenuUpdate = "account:menu:update" // 更新菜单信息
-	PermAccountMenuDelete = "account:menu:delete" // 删除菜单
+	PermAccountMenuList   = perm.Define("account:menu:list", "查看菜单列表")
+	PermAccountMenuCreate = perm.Define("account:menu:create", "创建菜单")
+	PermAccountMenuUpdate = perm.Define("account:menu:update", "更新菜单信息")
+	PermAccountMenuDelete = perm.Define("account:menu:delete", "删除菜单")
+
+	// PermAccountDeptList 账号管理 - 部门管理
+	PermAccountDeptList   = perm.Define("account:dept:list", "查看部门树")
+	PermAccountDeptCreate = perm.Define("account:dept:create", "创建部门")
+	PermAccountDeptUpdate = perm.Define("account:dept:update", "更新、移动、排序部门")
+	PermAccountDeptDelete = perm.Define("account:dept:delete", "删除部门")
 
 	// PermSystemOperationLogList 系统管理 - 操作日志管理
-	PermSystemOperationLogList = "system:operation-log:list" // 查看操作日志列表
+	PermSystemOperationLogList = perm.Define("system:operation-log:list", "查看操作日志列表")
 	// PermSystemLoginLogList 系统管理 - 登录日志管理
-	PermSystemLoginLogList = "system:login-log:list" // 查看登录日志列表
+	PermSystemLoginLogList = perm.Define("system:login-log:list", "查看登录日志列表")
 
 	// PermSystemDictList 系统管理 - 字典管理
-	PermSystemDictList   = "system:dict:list"
-	PermSystemDictCreate = "system:dict:create"
-	PermSystemDictUpdate = "system:dict:update"
-	PermSystemDictDelete = "system:dict:delete"
+	PermSystemDictList   = perm.Define("system:dict:list", "查看字典列表")
+	PermSystemDictCreate = perm.Define("system:dict:create", "创建字典")
+	PermSystemDictUpdate = perm.Define("system:dict:update", "更新字典")
+	PermSystemDictDelete = perm.Define("system:dict:delete", "删除字典")
+
+	// PermSystemPartnerList 系统管理 - 开放接口合作方
+	PermSystemPartnerList   = perm.Define("system:partner:list", "查看开放接口合作方列表")
+	PermSystemPartnerCreate = perm.Define("system:partner:create", "创建开放接口合作方")
+	PermSystemPartnerUpdate = perm.Define("system:partner:update", "更新开放接口合作方、更换签名密钥")
+	PermSystemPartnerDelete = perm.Define("system:partner:delete", "删除开放接口合作方")
 )
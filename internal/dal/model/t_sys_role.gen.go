@@ -13,9 +13,11 @@ const TableNameSysRole = "sys_role"
 // SysRole 角色表
 type SysRole struct {
 	ID          int32      `gorm:"column:id;type:int(11);primaryKey;autoIncrement:true" json:"id"`
-	Code        string     `gorm:"column:code;type:varchar(64);not null;uniqueIndex:uk_code,priority:1;comment:角色代码，如 admin、normal" json:"code"` // 角色代码，如 admin、normal
-	Name        *string    `gorm:"column:name;type:varchar(128);comment:前端展示用名称" json:"name"`                                                    // 前端展示用名称
-	Description *string    `gorm:"column:description;type:text;comment:角色描述" json:"description"`                                                 // 角色描述
+	Code        string     `gorm:"column:code;type:varchar(64);not null;uniqueIndex:uk_code,priority:1;comment:角色代码，如 admin、normal" json:"code"`             // 角色代码，如 admin、normal
+	Name        *string    `gorm:"column:name;type:varchar(128);comment:前端展示用名称" json:"name"`                                                                // 前端展示用名称
+	Description *string    `gorm:"column:description;type:text;comment:角色描述" json:"description"`                                                             // 角色描述
+	RequireMfa  *int8      `gorm:"column:require_mfa;type:tinyint(4);not null;default:0;comment:是否强制两步验证：0 否, 1 是" json:"require_mfa"`                       // 是否强制两步验证：0 否, 1 是
+	DataScope   *int8      `gorm:"column:data_scope;type:tinyint(4);not null;default:1;comment:数据范围：1 全部, 2 本人, 3 本部门, 4 本部门及以下, 5 自定义部门" json:"data_scope"` // 数据范围：1 全部, 2 本人, 3 本部门, 4 本部门及以下, 5 自定义部门
 	CreatedAt   *time.Time `gorm:"column:created_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
 	UpdatedAt   *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
 }
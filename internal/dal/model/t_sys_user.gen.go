@@ -12,18 +12,25 @@ const TableNameSysUser = "sys_user"
 
 // SysUser 用户表
 type SysUser struct {
-	ID        int32      `gorm:"column:id;type:int(11);primaryKey;autoIncrement:true" json:"id"`
-	Username  string     `gorm:"column:username;type:varchar(64);not null;uniqueIndex:uk_username,priority:1;comment:登录名，业务唯一" json:"username"`        // 登录名，业务唯一
-	Tel       string     `gorm:"column:tel;type:varchar(20);not null;uniqueIndex:uk_tel,priority:1;comment:手机号码" json:"tel"`                           // 手机号码
-	Nickname  *string    `gorm:"column:nickname;type:varchar(60);comment:用户昵称" json:"nickname"`                                                        // 用户昵称
-	Password  string     `gorm:"column:password;type:varchar(255);not null;comment:pwd" json:"password"`                                               // pwd
-	Email     *string    `gorm:"column:email;type:varchar(100);comment:邮箱" json:"email"`                                                               // 邮箱
-	Remark    *string    `gorm:"column:remark;type:varchar(500);comment:备注" json:"remark"`                                                             // 备注
-	Status    *int8      `gorm:"column:status;type:tinyint(4);not null;index:idx_status,priority:1;default:1;comment:用户状态：1 活跃, 2 禁用登录" json:"status"` // 用户状态：1 活跃, 2 禁用登录
-	CreatedBy *int32     `gorm:"column:created_by;type:int(11);comment:创建人 ID" json:"created_by"`                                                      // 创建人 ID
-	UpdatedBy *int32     `gorm:"column:updated_by;type:int(11);comment:更新人 ID" json:"updated_by"`                                                      // 更新人 ID
-	CreatedAt *time.Time `gorm:"column:created_at;type:datetime(6);not null;index:idx_created_at,priority:1;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
-	UpdatedAt *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
+	ID            int32      `gorm:"column:id;type:int(11);primaryKey;autoIncrement:true" json:"id"`
+	Username      string     `gorm:"column:username;type:varchar(64);not null;uniqueIndex:uk_username,priority:1;comment:登录名，业务唯一" json:"username"`        // 登录名，业务唯一
+	Tel           *string    `gorm:"column:tel;type:varchar(20);uniqueIndex:uk_tel,priority:1;comment:手机号码，服务账号为空" json:"tel"`                             // 手机号码，服务账号为空
+	Nickname      *string    `gorm:"column:nickname;type:varchar(60);comment:用户昵称" json:"nickname"`                                                        // 用户昵称
+	Password      string     `gorm:"column:password;type:varchar(255);not null;comment:pwd" json:"password"`                                               // pwd
+	Email         *string    `gorm:"column:email;type:varchar(100);comment:邮箱" json:"email"`                                                               // 邮箱
+	Remark        *string    `gorm:"column:remark;type:varchar(500);comment:备注" json:"remark"`                                                             // 备注
+	Status        *int8      `gorm:"column:status;type:tinyint(4);not null;index:idx_status,priority:1;default:1;comment:用户状态：1 活跃, 2 禁用登录" json:"status"` // 用户状态：1 活跃, 2 禁用登录
+	UserType      *int8      `gorm:"column:user_type;type:tinyint(4);not null;default:1;comment:用户类型：1 普通用户, 2 服务账号" json:"user_type"`                     // 用户类型：1 普通用户, 2 服务账号
+	DeptID        *int32     `gorm:"column:dept_id;type:int(11);index:idx_dept_id,priority:1;comment:所属部门ID" json:"dept_id"`                               // 所属部门ID
+	PwdChangedAt  *time.Time `gorm:"column:pwd_changed_at;type:datetime(6);comment:密码最后修改时间" json:"pwd_changed_at"`                                        // 密码最后修改时间
+	PwdMustChange *int8      `gorm:"column:pwd_must_change;type:tinyint(4);not null;default:0;comment:下次登录是否必须修改密码：0 否, 1 是" json:"pwd_must_change"`       // 下次登录是否必须修改密码：0 否, 1 是
+	LockStatus    *int8      `gorm:"column:lock_status;type:tinyint(4);not null;default:0;comment:登录锁定状态：0 未锁定, 1 临时锁定, 2 永久锁定" json:"lock_status"`        // 登录锁定状态：0 未锁定, 1 临时锁定, 2 永久锁定
+	LockCount     *int32     `gorm:"column:lock_count;type:int(11);not null;default:0;comment:连续锁定次数，登录成功或管理员解锁后清零" json:"lock_count"`                     // 连续锁定次数，登录成功或管理员解锁后清零
+	LockedUntil   *time.Time `gorm:"column:locked_until;type:datetime(6);comment:临时锁定截止时间" json:"locked_until"`                                            // 临时锁定截止时间
+	CreatedBy     *int32     `gorm:"column:created_by;type:int(11);comment:创建人 ID" json:"created_by"`                                                      // 创建人 ID
+	UpdatedBy     *int32     `gorm:"column:updated_by;type:int(11);comment:更新人 ID" json:"updated_by"`                                                      // 更新人 ID
+	CreatedAt     *time.Time `gorm:"column:created_at;type:datetime(6);not null;index:idx_created_at,priority:1;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
 }
 
 // TableName SysUser's table name
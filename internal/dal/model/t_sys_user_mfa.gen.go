@@ -0,0 +1,29 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package model
+
+import (
+	"time"
+)
+
+const TableNameSysUserMfa = "sys_user_mfa"
+
+// SysUserMfa 用户两步验证表
+type SysUserMfa struct {
+	ID            int32      `gorm:"column:id;type:int(11);primaryKey;autoIncrement:true" json:"id"`
+	UserID        int32      `gorm:"column:user_id;type:int(11);not null;uniqueIndex:uk_user_id,priority:1;comment:用户ID" json:"user_id"`        // 用户ID
+	Secret        string     `gorm:"column:secret;type:varchar(255);not null;comment:TOTP密钥（AES-GCM加密）" json:"secret"`                          // TOTP密钥（AES-GCM加密）
+	Status        *int8      `gorm:"column:status;type:tinyint(4);not null;default:1;comment:状态：1 待验证, 2 已启用" json:"status"`                    // 状态：1 待验证, 2 已启用
+	RecoveryCodes *string    `gorm:"column:recovery_codes;type:json;comment:恢复码哈希列表" json:"recovery_codes"`                                     // 恢复码哈希列表
+	LastUsedStep  *int64     `gorm:"column:last_used_step;type:bigint(20);not null;default:0;comment:最近一次使用的时间步，防止验证码重放" json:"last_used_step"` // 最近一次使用的时间步，防止验证码重放
+	EnabledAt     *time.Time `gorm:"column:enabled_at;type:datetime(6);comment:启用时间" json:"enabled_at"`                                         // 启用时间
+	CreatedAt     *time.Time `gorm:"column:created_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
+}
+
+// TableName SysUserMfa's table name
+func (*SysUserMfa) TableName() string {
+	return TableNameSysUserMfa
+}
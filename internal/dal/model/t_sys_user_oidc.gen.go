@@ -0,0 +1,28 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package model
+
+import (
+	"time"
+)
+
+const TableNameSysUserOidc = "sys_user_oidc"
+
+// SysUserOidc 用户 OIDC 身份关联表
+type SysUserOidc struct {
+	ID          int32      `gorm:"column:id;type:int(11);primaryKey;autoIncrement:true" json:"id"`
+	UserID      int32      `gorm:"column:user_id;type:int(11);not null;index:idx_user_id,priority:1;comment:用户ID" json:"user_id"`                             // 用户ID
+	Issuer      string     `gorm:"column:issuer;type:varchar(255);not null;uniqueIndex:uk_issuer_subject,priority:1;comment:IdP issuer" json:"issuer"`        // IdP issuer
+	Subject     string     `gorm:"column:subject;type:varchar(255);not null;uniqueIndex:uk_issuer_subject,priority:2;comment:IdP 用户唯一标识(sub)" json:"subject"` // IdP 用户唯一标识(sub)
+	Email       *string    `gorm:"column:email;type:varchar(100);comment:IdP 邮箱" json:"email"`                                                                // IdP 邮箱
+	LastLoginAt *time.Time `gorm:"column:last_login_at;type:datetime(6);comment:最后登录时间" json:"last_login_at"`                                                 // 最后登录时间
+	CreatedAt   *time.Time `gorm:"column:created_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
+}
+
+// TableName SysUserOidc's table name
+func (*SysUserOidc) TableName() string {
+	return TableNameSysUserOidc
+}
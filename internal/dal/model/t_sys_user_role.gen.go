@@ -12,11 +12,13 @@ const TableNameSysUserRole = "sys_user_role"
 
 // SysUserRole 用户-角色关联表
 type SysUserRole struct {
-	ID        int64      `gorm:"column:id;type:bigint(20);primaryKey;autoIncrement:true" json:"id"`
-	UserID    int32      `gorm:"column:user_id;type:int(11);not null;uniqueIndex:uk_user_role,priority:1;comment:用户ID" json:"user_id"`                              // 用户ID
-	RoleID    int32      `gorm:"column:role_id;type:int(11);not null;uniqueIndex:uk_user_role,priority:2;index:idx_role_id,priority:1;comment:角色ID" json:"role_id"` // 角色ID
-	CreatedAt *time.Time `gorm:"column:created_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
-	UpdatedAt *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
+	ID         int64      `gorm:"column:id;type:bigint(20);primaryKey;autoIncrement:true" json:"id"`
+	UserID     int32      `gorm:"column:user_id;type:int(11);not null;uniqueIndex:uk_user_role,priority:1;comment:用户ID" json:"user_id"`                              // 用户ID
+	RoleID     int32      `gorm:"column:role_id;type:int(11);not null;uniqueIndex:uk_user_role,priority:2;index:idx_role_id,priority:1;comment:角色ID" json:"role_id"` // 角色ID
+	ValidFrom  *time.Time `gorm:"column:valid_from;type:datetime(6);comment:生效时间，为空表示立即生效" json:"valid_from"`                                                        // 生效时间，为空表示立即生效
+	ValidUntil *time.Time `gorm:"column:valid_until;type:datetime(6);index:idx_valid_until,priority:1;comment:失效时间，为空表示永久有效" json:"valid_until"`                     // 失效时间，为空表示永久有效
+	CreatedAt  *time.Time `gorm:"column:created_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"created_at"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at;type:datetime(6);not null;default:CURRENT_TIMESTAMP(6)" json:"updated_at"`
 }
 
 // TableName SysUserRole's table name
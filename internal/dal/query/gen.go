@@ -17,47 +17,71 @@ import (
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:              db,
-		SysDict:         newSysDict(db, opts...),
-		SysDictItem:     newSysDictItem(db, opts...),
-		SysLoginLog:     newSysLoginLog(db, opts...),
-		SysMenu:         newSysMenu(db, opts...),
-		SysOperationLog: newSysOperationLog(db, opts...),
-		SysRole:         newSysRole(db, opts...),
-		SysRoleMenu:     newSysRoleMenu(db, opts...),
-		SysUser:         newSysUser(db, opts...),
-		SysUserRole:     newSysUserRole(db, opts...),
+		db:                   db,
+		SysAPIKey:            newSysAPIKey(db, opts...),
+		SysDept:              newSysDept(db, opts...),
+		SysDict:              newSysDict(db, opts...),
+		SysDictItem:          newSysDictItem(db, opts...),
+		SysLoginLog:          newSysLoginLog(db, opts...),
+		SysMenu:              newSysMenu(db, opts...),
+		SysOperationLog:      newSysOperationLog(db, opts...),
+		SysPartnerCredential: newSysPartnerCredential(db, opts...),
+		SysRole:              newSysRole(db, opts...),
+		SysRoleDept:          newSysRoleDept(db, opts...),
+		SysRoleInherit:       newSysRoleInherit(db, opts...),
+		SysRoleMenu:          newSysRoleMenu(db, opts...),
+		SysUser:              newSysUser(db, opts...),
+		SysUserMfa:           newSysUserMfa(db, opts...),
+		SysUserOidc:          newSysUserOidc(db, opts...),
+		SysUserPwdHistory:    newSysUserPwdHistory(db, opts...),
+		SysUserRole:          newSysUserRole(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	SysDict         sysDict
-	SysDictItem     sysDictItem
-	SysLoginLog     sysLoginLog
-	SysMenu         sysMenu
-	SysOperationLog sysOperationLog
-	SysRole         sysRole
-	SysRoleMenu     sysRoleMenu
-	SysUser         sysUser
-	SysUserRole     sysUserRole
+	SysAPIKey            sysAPIKey
+	SysDept              sysDept
+	SysDict              sysDict
+	SysDictItem          sysDictItem
+	SysLoginLog          sysLoginLog
+	SysMenu              sysMenu
+	SysOperationLog      sysOperationLog
+	SysPartnerCredential sysPartnerCredential
+	SysRole              sysRole
+	SysRoleDept          sysRoleDept
+	SysRoleInherit       sysRoleInherit
+	SysRoleMenu          sysRoleMenu
+	SysUser              sysUser
+	SysUserMfa           sysUserMfa
+	SysUserOidc          sysUserOidc
+	SysUserPwdHistory    sysUserPwdHistory
+	SysUserRole          sysUserRole
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:              db,
-		SysDict:         q.SysDict.clone(db),
-		SysDictItem:     q.SysDictItem.clone(db),
-		SysLoginLog:     q.SysLoginLog.clone(db),
-		SysMenu:         q.SysMenu.clone(db),
-		SysOperationLog: q.SysOperationLog.clone(db),
-		SysRole:         q.SysRole.clone(db),
-		SysRoleMenu:     q.SysRoleMenu.clone(db),
-		SysUser:         q.SysUser.clone(db),
-		SysUserRole:     q.SysUserRole.clone(db),
+		db:                   db,
+		SysAPIKey:            q.SysAPIKey.clone(db),
+		SysDept:              q.SysDept.clone(db),
+		SysDict:              q.SysDict.clone(db),
+		SysDictItem:          q.SysDictItem.clone(db),
+		SysLoginLog:          q.SysLoginLog.clone(db),
+		SysMenu:              q.SysMenu.clone(db),
+		SysOperationLog:      q.SysOperationLog.clone(db),
+		SysPartnerCredential: q.SysPartnerCredential.clone(db),
+		SysRole:              q.SysRole.clone(db),
+		SysRoleDept:          q.SysRoleDept.clone(db),
+		SysRoleInherit:       q.SysRoleInherit.clone(db),
+		SysRoleMenu:          q.SysRoleMenu.clone(db),
+		SysUser:              q.SysUser.clone(db),
+		SysUserMfa:           q.SysUserMfa.clone(db),
+		SysUserOidc:          q.SysUserOidc.clone(db),
+		SysUserPwdHistory:    q.SysUserPwdHistory.clone(db),
+		SysUserRole:          q.SysUserRole.clone(db),
 	}
 }
 
@@ -71,42 +95,66 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:              db,
-		SysDict:         q.SysDict.replaceDB(db),
-		SysDictItem:     q.SysDictItem.replaceDB(db),
-		SysLoginLog:     q.SysLoginLog.replaceDB(db),
-		SysMenu:         q.SysMenu.replaceDB(db),
-		SysOperationLog: q.SysOperationLog.replaceDB(db),
-		SysRole:         q.SysRole.replaceDB(db),
-		SysRoleMenu:     q.SysRoleMenu.replaceDB(db),
-		SysUser:         q.SysUser.replaceDB(db),
-		SysUserRole:     q.SysUserRole.replaceDB(db),
+		db:                   db,
+		SysAPIKey:            q.SysAPIKey.replaceDB(db),
+		SysDept:              q.SysDept.replaceDB(db),
+		SysDict:              q.SysDict.replaceDB(db),
+		SysDictItem:          q.SysDictItem.replaceDB(db),
+		SysLoginLog:          q.SysLoginLog.replaceDB(db),
+		SysMenu:              q.SysMenu.replaceDB(db),
+		SysOperationLog:      q.SysOperationLog.replaceDB(db),
+		SysPartnerCredential: q.SysPartnerCredential.replaceDB(db),
+		SysRole:              q.SysRole.replaceDB(db),
+		SysRoleDept:          q.SysRoleDept.replaceDB(db),
+		SysRoleInherit:       q.SysRoleInherit.replaceDB(db),
+		SysRoleMenu:          q.SysRoleMenu.replaceDB(db),
+		SysUser:              q.SysUser.replaceDB(db),
+		SysUserMfa:           q.SysUserMfa.replaceDB(db),
+		SysUserOidc:          q.SysUserOidc.replaceDB(db),
+		SysUserPwdHistory:    q.SysUserPwdHistory.replaceDB(db),
+		SysUserRole:          q.SysUserRole.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	SysDict         *sysDictDo
-	SysDictItem     *sysDictItemDo
-	SysLoginLog     *sysLoginLogDo
-	SysMenu         *sysMenuDo
-	SysOperationLog *sysOperationLogDo
-	SysRole         *sysRoleDo
-	SysRoleMenu     *sysRoleMenuDo
-	SysUser         *sysUserDo
-	SysUserRole     *sysUserRoleDo
+	SysAPIKey            *sysAPIKeyDo
+	SysDept              *sysDeptDo
+	SysDict              *sysDictDo
+	SysDictItem          *sysDictItemDo
+	SysLoginLog          *sysLoginLogDo
+	SysMenu              *sysMenuDo
+	SysOperationLog      *sysOperationLogDo
+	SysPartnerCredential *sysPartnerCredentialDo
+	SysRole              *sysRoleDo
+	SysRoleDept          *sysRoleDeptDo
+	SysRoleInherit       *sysRoleInheritDo
+	SysRoleMenu          *sysRoleMenuDo
+	SysUser              *sysUserDo
+	SysUserMfa           *sysUserMfaDo
+	SysUserOidc          *sysUserOidcDo
+	SysUserPwdHistory    *sysUserPwdHistoryDo
+	SysUserRole          *sysUserRoleDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		SysDict:         q.SysDict.WithContext(ctx),
-		SysDictItem:     q.SysDictItem.WithContext(ctx),
-		SysLoginLog:     q.SysLoginLog.WithContext(ctx),
-		SysMenu:         q.SysMenu.WithContext(ctx),
-		SysOperationLog: q.SysOperationLog.WithContext(ctx),
-		SysRole:         q.SysRole.WithContext(ctx),
-		SysRoleMenu:     q.SysRoleMenu.WithContext(ctx),
-		SysUser:         q.SysUser.WithContext(ctx),
-		SysUserRole:     q.SysUserRole.WithContext(ctx),
+		SysAPIKey:            q.SysAPIKey.WithContext(ctx),
+		SysDept:              q.SysDept.WithContext(ctx),
+		SysDict:              q.SysDict.WithContext(ctx),
+		SysDictItem:          q.SysDictItem.WithContext(ctx),
+		SysLoginLog:          q.SysLoginLog.WithContext(ctx),
+		SysMenu:              q.SysMenu.WithContext(ctx),
+		SysOperationLog:      q.SysOperationLog.WithContext(ctx),
+		SysPartnerCredential: q.SysPartnerCredential.WithContext(ctx),
+		SysRole:              q.SysRole.WithContext(ctx),
+		SysRoleDept:          q.SysRoleDept.WithContext(ctx),
+		SysRoleInherit:       q.SysRoleInherit.WithContext(ctx),
+		SysRoleMenu:          q.SysRoleMenu.WithContext(ctx),
+		SysUser:              q.SysUser.WithContext(ctx),
+		SysUserMfa:           q.SysUserMfa.WithContext(ctx),
+		SysUserOidc:          q.SysUserOidc.WithContext(ctx),
+		SysUserPwdHistory:    q.SysUserPwdHistory.WithContext(ctx),
+		SysUserRole:          q.SysUserRole.WithContext(ctx),
 	}
 }
 
@@ -0,0 +1,372 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"snowgo/internal/dal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newSysAPIKey(db *gorm.DB, opts ...gen.DOOption) sysAPIKey {
+	_sysAPIKey := sysAPIKey{}
+
+	_sysAPIKey.sysAPIKeyDo.UseDB(db, opts...)
+	_sysAPIKey.sysAPIKeyDo.UseModel(&model.SysAPIKey{})
+
+	tableName := _sysAPIKey.sysAPIKeyDo.TableName()
+	_sysAPIKey.ALL = field.NewAsterisk(tableName)
+	_sysAPIKey.ID = field.NewInt32(tableName, "id")
+	_sysAPIKey.UserID = field.NewInt32(tableName, "user_id")
+	_sysAPIKey.Name = field.NewString(tableName, "name")
+	_sysAPIKey.Prefix = field.NewString(tableName, "prefix")
+	_sysAPIKey.KeyHash = field.NewString(tableName, "key_hash")
+	_sysAPIKey.Status = field.NewInt8(tableName, "status")
+	_sysAPIKey.ExpiredAt = field.NewTime(tableName, "expired_at")
+	_sysAPIKey.LastUsedAt = field.NewTime(tableName, "last_used_at")
+	_sysAPIKey.RevokedAt = field.NewTime(tableName, "revoked_at")
+	_sysAPIKey.CreatedBy = field.NewInt32(tableName, "created_by")
+	_sysAPIKey.CreatedAt = field.NewTime(tableName, "created_at")
+	_sysAPIKey.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_sysAPIKey.fillFieldMap()
+
+	return _sysAPIKey
+}
+
+type sysAPIKey struct {
+	sysAPIKeyDo sysAPIKeyDo
+
+	ALL        field.Asterisk
+	ID         field.Int32
+	UserID     field.Int32  // 服务账号用户ID
+	Name       field.String // 名称/用途
+	Prefix     field.String // Key 前缀，用于展示识别
+	KeyHash    field.String // Key SHA-256 摘要
+	Status     field.Int8   // 状态：1 有效, 2 已吊销
+	ExpiredAt  field.Time   // 过期时间，为空表示永不过期
+	LastUsedAt field.Time   // 最后使用时间
+	RevokedAt  field.Time   // 吊销时间
+	CreatedBy  field.Int32  // 创建人 ID
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sysAPIKey) Table(newTableName string) *sysAPIKey {
+	s.sysAPIKeyDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sysAPIKey) As(alias string) *sysAPIKey {
+	s.sysAPIKeyDo.DO = *(s.sysAPIKeyDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sysAPIKey) updateTableName(table string) *sysAPIKey {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewInt32(table, "id")
+	s.UserID = field.NewInt32(table, "user_id")
+	s.Name = field.NewString(table, "name")
+	s.Prefix = field.NewString(table, "prefix")
+	s.KeyHash = field.NewString(table, "key_hash")
+	s.Status = field.NewInt8(table, "status")
+	s.ExpiredAt = field.NewTime(table, "expired_at")
+	s.LastUsedAt = field.NewTime(table, "last_used_at")
+	s.RevokedAt = field.NewTime(table, "revoked_at")
+	s.CreatedBy = field.NewInt32(table, "created_by")
+	s.CreatedAt = field.NewTime(table, "created_at")
+	s.UpdatedAt = field.NewTime(table, "updated_at")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sysAPIKey) WithContext(ctx context.Context) *sysAPIKeyDo {
+	return s.sysAPIKeyDo.WithContext(ctx)
+}
+
+func (s sysAPIKey) TableName() string { return s.sysAPIKeyDo.TableName() }
+
+func (s sysAPIKey) Alias() string { return s.sysAPIKeyDo.Alias() }
+
+func (s sysAPIKey) Columns(cols ...field.Expr) gen.Columns { return s.sysAPIKeyDo.Columns(cols...) }
+
+func (s *sysAPIKey) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sysAPIKey) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 12)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["user_id"] = s.UserID
+	s.fieldMap["name"] = s.Name
+	s.fieldMap["prefix"] = s.Prefix
+	s.fieldMap["key_hash"] = s.KeyHash
+	s.fieldMap["status"] = s.Status
+	s.fieldMap["expired_at"] = s.ExpiredAt
+	s.fieldMap["last_used_at"] = s.LastUsedAt
+	s.fieldMap["revoked_at"] = s.RevokedAt
+	s.fieldMap["created_by"] = s.CreatedBy
+	s.fieldMap["created_at"] = s.CreatedAt
+	s.fieldMap["updated_at"] = s.UpdatedAt
+}
+
+func (s sysAPIKey) clone(db *gorm.DB) sysAPIKey {
+	s.sysAPIKeyDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sysAPIKey) replaceDB(db *gorm.DB) sysAPIKey {
+	s.sysAPIKeyDo.ReplaceDB(db)
+	return s
+}
+
+type sysAPIKeyDo struct{ gen.DO }
+
+func (s sysAPIKeyDo) Debug() *sysAPIKeyDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sysAPIKeyDo) WithContext(ctx context.Context) *sysAPIKeyDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sysAPIKeyDo) ReadDB() *sysAPIKeyDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sysAPIKeyDo) WriteDB() *sysAPIKeyDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sysAPIKeyDo) Session(config *gorm.Session) *sysAPIKeyDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sysAPIKeyDo) Clauses(conds ...clause.Expression) *sysAPIKeyDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sysAPIKeyDo) Returning(value interface{}, columns ...string) *sysAPIKeyDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sysAPIKeyDo) Not(conds ...gen.Condition) *sysAPIKeyDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sysAPIKeyDo) Or(conds ...gen.Condition) *sysAPIKeyDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sysAPIKeyDo) Select(conds ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sysAPIKeyDo) Where(conds ...gen.Condition) *sysAPIKeyDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sysAPIKeyDo) Order(conds ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sysAPIKeyDo) Distinct(cols ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sysAPIKeyDo) Omit(cols ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sysAPIKeyDo) Join(table schema.Tabler, on ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sysAPIKeyDo) LeftJoin(table schema.Tabler, on ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sysAPIKeyDo) RightJoin(table schema.Tabler, on ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sysAPIKeyDo) Group(cols ...field.Expr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sysAPIKeyDo) Having(conds ...gen.Condition) *sysAPIKeyDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sysAPIKeyDo) Limit(limit int) *sysAPIKeyDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sysAPIKeyDo) Offset(offset int) *sysAPIKeyDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sysAPIKeyDo) Scopes(funcs ...func(gen.Dao) gen.Dao) *sysAPIKeyDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sysAPIKeyDo) Unscoped() *sysAPIKeyDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sysAPIKeyDo) Create(values ...*model.SysAPIKey) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sysAPIKeyDo) CreateInBatches(values []*model.SysAPIKey, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sysAPIKeyDo) Save(values ...*model.SysAPIKey) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sysAPIKeyDo) First() (*model.SysAPIKey, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysAPIKey), nil
+	}
+}
+
+func (s sysAPIKeyDo) Take() (*model.SysAPIKey, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysAPIKey), nil
+	}
+}
+
+func (s sysAPIKeyDo) Last() (*model.SysAPIKey, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysAPIKey), nil
+	}
+}
+
+func (s sysAPIKeyDo) Find() ([]*model.SysAPIKey, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SysAPIKey), err
+}
+
+func (s sysAPIKeyDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SysAPIKey, err error) {
+	buf := make([]*model.SysAPIKey, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sysAPIKeyDo) FindInBatches(result *[]*model.SysAPIKey, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sysAPIKeyDo) Attrs(attrs ...field.AssignExpr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sysAPIKeyDo) Assign(attrs ...field.AssignExpr) *sysAPIKeyDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sysAPIKeyDo) Joins(fields ...field.RelationField) *sysAPIKeyDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sysAPIKeyDo) Preload(fields ...field.RelationField) *sysAPIKeyDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sysAPIKeyDo) FirstOrInit() (*model.SysAPIKey, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysAPIKey), nil
+	}
+}
+
+func (s sysAPIKeyDo) FirstOrCreate() (*model.SysAPIKey, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysAPIKey), nil
+	}
+}
+
+func (s sysAPIKeyDo) FindByPage(offset int, limit int) (result []*model.SysAPIKey, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sysAPIKeyDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sysAPIKeyDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sysAPIKeyDo) Delete(models ...*model.SysAPIKey) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sysAPIKeyDo) withDO(do gen.Dao) *sysAPIKeyDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
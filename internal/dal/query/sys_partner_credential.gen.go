@@ -0,0 +1,370 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"snowgo/internal/dal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newSysPartnerCredential(db *gorm.DB, opts ...gen.DOOption) sysPartnerCredential {
+	_sysPartnerCredential := sysPartnerCredential{}
+
+	_sysPartnerCredential.sysPartnerCredentialDo.UseDB(db, opts...)
+	_sysPartnerCredential.sysPartnerCredentialDo.UseModel(&model.SysPartnerCredential{})
+
+	tableName := _sysPartnerCredential.sysPartnerCredentialDo.TableName()
+	_sysPartnerCredential.ALL = field.NewAsterisk(tableName)
+	_sysPartnerCredential.ID = field.NewInt32(tableName, "id")
+	_sysPartnerCredential.Name = field.NewString(tableName, "name")
+	_sysPartnerCredential.AccessKey = field.NewString(tableName, "access_key")
+	_sysPartnerCredential.Secret = field.NewString(tableName, "secret")
+	_sysPartnerCredential.Status = field.NewInt8(tableName, "status")
+	_sysPartnerCredential.Remark = field.NewString(tableName, "remark")
+	_sysPartnerCredential.LastUsedAt = field.NewTime(tableName, "last_used_at")
+	_sysPartnerCredential.CreatedBy = field.NewInt32(tableName, "created_by")
+	_sysPartnerCredential.UpdatedBy = field.NewInt32(tableName, "updated_by")
+	_sysPartnerCredential.CreatedAt = field.NewTime(tableName, "created_at")
+	_sysPartnerCredential.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_sysPartnerCredential.fillFieldMap()
+
+	return _sysPartnerCredential
+}
+
+type sysPartnerCredential struct {
+	sysPartnerCredentialDo sysPartnerCredentialDo
+
+	ALL        field.Asterisk
+	ID         field.Int32
+	Name       field.String // 合作方名称
+	AccessKey  field.String // 访问密钥ID
+	Secret     field.String // 签名密钥（AES-GCM加密）
+	Status     field.Int8   // 状态：1 启用, 2 禁用
+	Remark     field.String // 备注
+	LastUsedAt field.Time   // 最后调用时间
+	CreatedBy  field.Int32  // 创建人 ID
+	UpdatedBy  field.Int32  // 更新人 ID
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sysPartnerCredential) Table(newTableName string) *sysPartnerCredential {
+	s.sysPartnerCredentialDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sysPartnerCredential) As(alias string) *sysPartnerCredential {
+	s.sysPartnerCredentialDo.DO = *(s.sysPartnerCredentialDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sysPartnerCredential) updateTableName(table string) *sysPartnerCredential {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewInt32(table, "id")
+	s.Name = field.NewString(table, "name")
+	s.AccessKey = field.NewString(table, "access_key")
+	s.Secret = field.NewString(table, "secret")
+	s.Status = field.NewInt8(table, "status")
+	s.Remark = field.NewString(table, "remark")
+	s.LastUsedAt = field.NewTime(table, "last_used_at")
+	s.CreatedBy = field.NewInt32(table, "created_by")
+	s.UpdatedBy = field.NewInt32(table, "updated_by")
+	s.CreatedAt = field.NewTime(table, "created_at")
+	s.UpdatedAt = field.NewTime(table, "updated_at")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sysPartnerCredential) WithContext(ctx context.Context) *sysPartnerCredentialDo {
+	return s.sysPartnerCredentialDo.WithContext(ctx)
+}
+
+func (s sysPartnerCredential) TableName() string { return s.sysPartnerCredentialDo.TableName() }
+
+func (s sysPartnerCredential) Alias() string { return s.sysPartnerCredentialDo.Alias() }
+
+func (s sysPartnerCredential) Columns(cols ...field.Expr) gen.Columns {
+	return s.sysPartnerCredentialDo.Columns(cols...)
+}
+
+func (s *sysPartnerCredential) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sysPartnerCredential) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 11)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["name"] = s.Name
+	s.fieldMap["access_key"] = s.AccessKey
+	s.fieldMap["secret"] = s.Secret
+	s.fieldMap["status"] = s.Status
+	s.fieldMap["remark"] = s.Remark
+	s.fieldMap["last_used_at"] = s.LastUsedAt
+	s.fieldMap["created_by"] = s.CreatedBy
+	s.fieldMap["updated_by"] = s.UpdatedBy
+	s.fieldMap["created_at"] = s.CreatedAt
+	s.fieldMap["updated_at"] = s.UpdatedAt
+}
+
+func (s sysPartnerCredential) clone(db *gorm.DB) sysPartnerCredential {
+	s.sysPartnerCredentialDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sysPartnerCredential) replaceDB(db *gorm.DB) sysPartnerCredential {
+	s.sysPartnerCredentialDo.ReplaceDB(db)
+	return s
+}
+
+type sysPartnerCredentialDo struct{ gen.DO }
+
+func (s sysPartnerCredentialDo) Debug() *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sysPartnerCredentialDo) WithContext(ctx context.Context) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sysPartnerCredentialDo) ReadDB() *sysPartnerCredentialDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sysPartnerCredentialDo) WriteDB() *sysPartnerCredentialDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sysPartnerCredentialDo) Session(config *gorm.Session) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sysPartnerCredentialDo) Clauses(conds ...clause.Expression) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sysPartnerCredentialDo) Returning(value interface{}, columns ...string) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sysPartnerCredentialDo) Not(conds ...gen.Condition) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sysPartnerCredentialDo) Or(conds ...gen.Condition) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sysPartnerCredentialDo) Select(conds ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sysPartnerCredentialDo) Where(conds ...gen.Condition) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sysPartnerCredentialDo) Order(conds ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sysPartnerCredentialDo) Distinct(cols ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sysPartnerCredentialDo) Omit(cols ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sysPartnerCredentialDo) Join(table schema.Tabler, on ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sysPartnerCredentialDo) LeftJoin(table schema.Tabler, on ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sysPartnerCredentialDo) RightJoin(table schema.Tabler, on ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sysPartnerCredentialDo) Group(cols ...field.Expr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sysPartnerCredentialDo) Having(conds ...gen.Condition) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sysPartnerCredentialDo) Limit(limit int) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sysPartnerCredentialDo) Offset(offset int) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sysPartnerCredentialDo) Scopes(funcs ...func(gen.Dao) gen.Dao) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sysPartnerCredentialDo) Unscoped() *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sysPartnerCredentialDo) Create(values ...*model.SysPartnerCredential) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sysPartnerCredentialDo) CreateInBatches(values []*model.SysPartnerCredential, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sysPartnerCredentialDo) Save(values ...*model.SysPartnerCredential) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sysPartnerCredentialDo) First() (*model.SysPartnerCredential, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysPartnerCredential), nil
+	}
+}
+
+func (s sysPartnerCredentialDo) Take() (*model.SysPartnerCredential, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysPartnerCredential), nil
+	}
+}
+
+func (s sysPartnerCredentialDo) Last() (*model.SysPartnerCredential, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysPartnerCredential), nil
+	}
+}
+
+func (s sysPartnerCredentialDo) Find() ([]*model.SysPartnerCredential, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SysPartnerCredential), err
+}
+
+func (s sysPartnerCredentialDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SysPartnerCredential, err error) {
+	buf := make([]*model.SysPartnerCredential, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sysPartnerCredentialDo) FindInBatches(result *[]*model.SysPartnerCredential, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sysPartnerCredentialDo) Attrs(attrs ...field.AssignExpr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sysPartnerCredentialDo) Assign(attrs ...field.AssignExpr) *sysPartnerCredentialDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sysPartnerCredentialDo) Joins(fields ...field.RelationField) *sysPartnerCredentialDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sysPartnerCredentialDo) Preload(fields ...field.RelationField) *sysPartnerCredentialDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sysPartnerCredentialDo) FirstOrInit() (*model.SysPartnerCredential, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysPartnerCredential), nil
+	}
+}
+
+func (s sysPartnerCredentialDo) FirstOrCreate() (*model.SysPartnerCredential, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysPartnerCredential), nil
+	}
+}
+
+func (s sysPartnerCredentialDo) FindByPage(offset int, limit int) (result []*model.SysPartnerCredential, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sysPartnerCredentialDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sysPartnerCredentialDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sysPartnerCredentialDo) Delete(models ...*model.SysPartnerCredential) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sysPartnerCredentialDo) withDO(do gen.Dao) *sysPartnerCredentialDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
@@ -30,6 +30,8 @@ func newSysRole(db *gorm.DB, opts ...gen.DOOption) sysRole {
 	_sysRole.Code = field.NewString(tableName, "code")
 	_sysRole.Name = field.NewString(tableName, "name")
 	_sysRole.Description = field.NewString(tableName, "description")
+	_sysRole.RequireMfa = field.NewInt8(tableName, "require_mfa")
+	_sysRole.DataScope = field.NewInt8(tableName, "data_scope")
 	_sysRole.CreatedAt = field.NewTime(tableName, "created_at")
 	_sysRole.UpdatedAt = field.NewTime(tableName, "updated_at")
 
@@ -46,6 +48,8 @@ type sysRole struct {
 	Code        field.String // 角色代码，如 admin、normal
 	Name        field.String // 前端展示用名称
 	Description field.String // 角色描述
+	RequireMfa  field.Int8   // 是否强制两步验证：0 否, 1 是
+	DataScope   field.Int8   // 数据范围：1 全部, 2 本人, 3 本部门, 4 本部门及以下, 5 自定义部门
 	CreatedAt   field.Time
 	UpdatedAt   field.Time
 
@@ -68,6 +72,8 @@ func (s *sysRole) updateTableName(table string) *sysRole {
 	s.Code = field.NewString(table, "code")
 	s.Name = field.NewString(table, "name")
 	s.Description = field.NewString(table, "description")
+	s.RequireMfa = field.NewInt8(table, "require_mfa")
+	s.DataScope = field.NewInt8(table, "data_scope")
 	s.CreatedAt = field.NewTime(table, "created_at")
 	s.UpdatedAt = field.NewTime(table, "updated_at")
 
@@ -94,11 +100,13 @@ func (s *sysRole) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *sysRole) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 6)
+	s.fieldMap = make(map[string]field.Expr, 8)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["code"] = s.Code
 	s.fieldMap["name"] = s.Name
 	s.fieldMap["description"] = s.Description
+	s.fieldMap["require_mfa"] = s.RequireMfa
+	s.fieldMap["data_scope"] = s.DataScope
 	s.fieldMap["created_at"] = s.CreatedAt
 	s.fieldMap["updated_at"] = s.UpdatedAt
 }
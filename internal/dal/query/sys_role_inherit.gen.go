@@ -0,0 +1,346 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"snowgo/internal/dal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newSysRoleInherit(db *gorm.DB, opts ...gen.DOOption) sysRoleInherit {
+	_sysRoleInherit := sysRoleInherit{}
+
+	_sysRoleInherit.sysRoleInheritDo.UseDB(db, opts...)
+	_sysRoleInherit.sysRoleInheritDo.UseModel(&model.SysRoleInherit{})
+
+	tableName := _sysRoleInherit.sysRoleInheritDo.TableName()
+	_sysRoleInherit.ALL = field.NewAsterisk(tableName)
+	_sysRoleInherit.ID = field.NewInt64(tableName, "id")
+	_sysRoleInherit.RoleID = field.NewInt32(tableName, "role_id")
+	_sysRoleInherit.ParentID = field.NewInt32(tableName, "parent_id")
+	_sysRoleInherit.CreatedAt = field.NewTime(tableName, "created_at")
+	_sysRoleInherit.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_sysRoleInherit.fillFieldMap()
+
+	return _sysRoleInherit
+}
+
+type sysRoleInherit struct {
+	sysRoleInheritDo sysRoleInheritDo
+
+	ALL       field.Asterisk
+	ID        field.Int64
+	RoleID    field.Int32 // 角色ID
+	ParentID  field.Int32 // 继承的父角色ID
+	CreatedAt field.Time
+	UpdatedAt field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sysRoleInherit) Table(newTableName string) *sysRoleInherit {
+	s.sysRoleInheritDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sysRoleInherit) As(alias string) *sysRoleInherit {
+	s.sysRoleInheritDo.DO = *(s.sysRoleInheritDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sysRoleInherit) updateTableName(table string) *sysRoleInherit {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewInt64(table, "id")
+	s.RoleID = field.NewInt32(table, "role_id")
+	s.ParentID = field.NewInt32(table, "parent_id")
+	s.CreatedAt = field.NewTime(table, "created_at")
+	s.UpdatedAt = field.NewTime(table, "updated_at")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sysRoleInherit) WithContext(ctx context.Context) *sysRoleInheritDo {
+	return s.sysRoleInheritDo.WithContext(ctx)
+}
+
+func (s sysRoleInherit) TableName() string { return s.sysRoleInheritDo.TableName() }
+
+func (s sysRoleInherit) Alias() string { return s.sysRoleInheritDo.Alias() }
+
+func (s sysRoleInherit) Columns(cols ...field.Expr) gen.Columns {
+	return s.sysRoleInheritDo.Columns(cols...)
+}
+
+func (s *sysRoleInherit) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sysRoleInherit) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 5)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["role_id"] = s.RoleID
+	s.fieldMap["parent_id"] = s.ParentID
+	s.fieldMap["created_at"] = s.CreatedAt
+	s.fieldMap["updated_at"] = s.UpdatedAt
+}
+
+func (s sysRoleInherit) clone(db *gorm.DB) sysRoleInherit {
+	s.sysRoleInheritDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sysRoleInherit) replaceDB(db *gorm.DB) sysRoleInherit {
+	s.sysRoleInheritDo.ReplaceDB(db)
+	return s
+}
+
+type sysRoleInheritDo struct{ gen.DO }
+
+func (s sysRoleInheritDo) Debug() *sysRoleInheritDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sysRoleInheritDo) WithContext(ctx context.Context) *sysRoleInheritDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sysRoleInheritDo) ReadDB() *sysRoleInheritDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sysRoleInheritDo) WriteDB() *sysRoleInheritDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sysRoleInheritDo) Session(config *gorm.Session) *sysRoleInheritDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sysRoleInheritDo) Clauses(conds ...clause.Expression) *sysRoleInheritDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sysRoleInheritDo) Returning(value interface{}, columns ...string) *sysRoleInheritDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sysRoleInheritDo) Not(conds ...gen.Condition) *sysRoleInheritDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sysRoleInheritDo) Or(conds ...gen.Condition) *sysRoleInheritDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sysRoleInheritDo) Select(conds ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sysRoleInheritDo) Where(conds ...gen.Condition) *sysRoleInheritDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sysRoleInheritDo) Order(conds ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sysRoleInheritDo) Distinct(cols ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sysRoleInheritDo) Omit(cols ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sysRoleInheritDo) Join(table schema.Tabler, on ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sysRoleInheritDo) LeftJoin(table schema.Tabler, on ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sysRoleInheritDo) RightJoin(table schema.Tabler, on ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sysRoleInheritDo) Group(cols ...field.Expr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sysRoleInheritDo) Having(conds ...gen.Condition) *sysRoleInheritDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sysRoleInheritDo) Limit(limit int) *sysRoleInheritDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sysRoleInheritDo) Offset(offset int) *sysRoleInheritDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sysRoleInheritDo) Scopes(funcs ...func(gen.Dao) gen.Dao) *sysRoleInheritDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sysRoleInheritDo) Unscoped() *sysRoleInheritDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sysRoleInheritDo) Create(values ...*model.SysRoleInherit) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sysRoleInheritDo) CreateInBatches(values []*model.SysRoleInherit, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sysRoleInheritDo) Save(values ...*model.SysRoleInherit) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sysRoleInheritDo) First() (*model.SysRoleInherit, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysRoleInherit), nil
+	}
+}
+
+func (s sysRoleInheritDo) Take() (*model.SysRoleInherit, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysRoleInherit), nil
+	}
+}
+
+func (s sysRoleInheritDo) Last() (*model.SysRoleInherit, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysRoleInherit), nil
+	}
+}
+
+func (s sysRoleInheritDo) Find() ([]*model.SysRoleInherit, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SysRoleInherit), err
+}
+
+func (s sysRoleInheritDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SysRoleInherit, err error) {
+	buf := make([]*model.SysRoleInherit, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sysRoleInheritDo) FindInBatches(result *[]*model.SysRoleInherit, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sysRoleInheritDo) Attrs(attrs ...field.AssignExpr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sysRoleInheritDo) Assign(attrs ...field.AssignExpr) *sysRoleInheritDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sysRoleInheritDo) Joins(fields ...field.RelationField) *sysRoleInheritDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sysRoleInheritDo) Preload(fields ...field.RelationField) *sysRoleInheritDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sysRoleInheritDo) FirstOrInit() (*model.SysRoleInherit, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysRoleInherit), nil
+	}
+}
+
+func (s sysRoleInheritDo) FirstOrCreate() (*model.SysRoleInherit, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysRoleInherit), nil
+	}
+}
+
+func (s sysRoleInheritDo) FindByPage(offset int, limit int) (result []*model.SysRoleInherit, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sysRoleInheritDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sysRoleInheritDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sysRoleInheritDo) Delete(models ...*model.SysRoleInherit) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sysRoleInheritDo) withDO(do gen.Dao) *sysRoleInheritDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
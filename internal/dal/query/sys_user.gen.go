@@ -34,6 +34,13 @@ func newSysUser(db *gorm.DB, opts ...gen.DOOption) sysUser {
 	_sysUser.Email = field.NewString(tableName, "email")
 	_sysUser.Remark = field.NewString(tableName, "remark")
 	_sysUser.Status = field.NewInt8(tableName, "status")
+	_sysUser.UserType = field.NewInt8(tableName, "user_type")
+	_sysUser.DeptID = field.NewInt32(tableName, "dept_id")
+	_sysUser.PwdChangedAt = field.NewTime(tableName, "pwd_changed_at")
+	_sysUser.PwdMustChange = field.NewInt8(tableName, "pwd_must_change")
+	_sysUser.LockStatus = field.NewInt8(tableName, "lock_status")
+	_sysUser.LockCount = field.NewInt32(tableName, "lock_count")
+	_sysUser.LockedUntil = field.NewTime(tableName, "locked_until")
 	_sysUser.CreatedBy = field.NewInt32(tableName, "created_by")
 	_sysUser.UpdatedBy = field.NewInt32(tableName, "updated_by")
 	_sysUser.CreatedAt = field.NewTime(tableName, "created_at")
@@ -47,19 +54,26 @@ func newSysUser(db *gorm.DB, opts ...gen.DOOption) sysUser {
 type sysUser struct {
 	sysUserDo sysUserDo
 
-	ALL       field.Asterisk
-	ID        field.Int32
-	Username  field.String // 登录名，业务唯一
-	Tel       field.String // 手机号码
-	Nickname  field.String // 用户昵称
-	Password  field.String // pwd
-	Email     field.String // 邮箱
-	Remark    field.String // 备注
-	Status    field.Int8   // 用户状态：1 活跃, 2 禁用登录
-	CreatedBy field.Int32  // 创建人 ID
-	UpdatedBy field.Int32  // 更新人 ID
-	CreatedAt field.Time
-	UpdatedAt field.Time
+	ALL           field.Asterisk
+	ID            field.Int32
+	Username      field.String // 登录名，业务唯一
+	Tel           field.String // 手机号码，服务账号为空
+	Nickname      field.String // 用户昵称
+	Password      field.String // pwd
+	Email         field.String // 邮箱
+	Remark        field.String // 备注
+	Status        field.Int8   // 用户状态：1 活跃, 2 禁用登录
+	UserType      field.Int8   // 用户类型：1 普通用户, 2 服务账号
+	DeptID        field.Int32  // 所属部门ID
+	PwdChangedAt  field.Time   // 密码最后修改时间
+	PwdMustChange field.Int8   // 下次登录是否必须修改密码：0 否, 1 是
+	LockStatus    field.Int8   // 登录锁定状态：0 未锁定, 1 临时锁定, 2 永久锁定
+	LockCount     field.Int32  // 连续锁定次数，登录成功或管理员解锁后清零
+	LockedUntil   field.Time   // 临时锁定截止时间
+	CreatedBy     field.Int32  // 创建人 ID
+	UpdatedBy     field.Int32  // 更新人 ID
+	CreatedAt     field.Time
+	UpdatedAt     field.Time
 
 	fieldMap map[string]field.Expr
 }
@@ -84,6 +98,13 @@ func (s *sysUser) updateTableName(table string) *sysUser {
 	s.Email = field.NewString(table, "email")
 	s.Remark = field.NewString(table, "remark")
 	s.Status = field.NewInt8(table, "status")
+	s.UserType = field.NewInt8(table, "user_type")
+	s.DeptID = field.NewInt32(table, "dept_id")
+	s.PwdChangedAt = field.NewTime(table, "pwd_changed_at")
+	s.PwdMustChange = field.NewInt8(table, "pwd_must_change")
+	s.LockStatus = field.NewInt8(table, "lock_status")
+	s.LockCount = field.NewInt32(table, "lock_count")
+	s.LockedUntil = field.NewTime(table, "locked_until")
 	s.CreatedBy = field.NewInt32(table, "created_by")
 	s.UpdatedBy = field.NewInt32(table, "updated_by")
 	s.CreatedAt = field.NewTime(table, "created_at")
@@ -112,7 +133,7 @@ func (s *sysUser) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *sysUser) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 12)
+	s.fieldMap = make(map[string]field.Expr, 19)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["username"] = s.Username
 	s.fieldMap["tel"] = s.Tel
@@ -121,6 +142,13 @@ func (s *sysUser) fillFieldMap() {
 	s.fieldMap["email"] = s.Email
 	s.fieldMap["remark"] = s.Remark
 	s.fieldMap["status"] = s.Status
+	s.fieldMap["user_type"] = s.UserType
+	s.fieldMap["dept_id"] = s.DeptID
+	s.fieldMap["pwd_changed_at"] = s.PwdChangedAt
+	s.fieldMap["pwd_must_change"] = s.PwdMustChange
+	s.fieldMap["lock_status"] = s.LockStatus
+	s.fieldMap["lock_count"] = s.LockCount
+	s.fieldMap["locked_until"] = s.LockedUntil
 	s.fieldMap["created_by"] = s.CreatedBy
 	s.fieldMap["updated_by"] = s.UpdatedBy
 	s.fieldMap["created_at"] = s.CreatedAt
@@ -0,0 +1,360 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"snowgo/internal/dal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newSysUserMfa(db *gorm.DB, opts ...gen.DOOption) sysUserMfa {
+	_sysUserMfa := sysUserMfa{}
+
+	_sysUserMfa.sysUserMfaDo.UseDB(db, opts...)
+	_sysUserMfa.sysUserMfaDo.UseModel(&model.SysUserMfa{})
+
+	tableName := _sysUserMfa.sysUserMfaDo.TableName()
+	_sysUserMfa.ALL = field.NewAsterisk(tableName)
+	_sysUserMfa.ID = field.NewInt32(tableName, "id")
+	_sysUserMfa.UserID = field.NewInt32(tableName, "user_id")
+	_sysUserMfa.Secret = field.NewString(tableName, "secret")
+	_sysUserMfa.Status = field.NewInt8(tableName, "status")
+	_sysUserMfa.RecoveryCodes = field.NewString(tableName, "recovery_codes")
+	_sysUserMfa.LastUsedStep = field.NewInt64(tableName, "last_used_step")
+	_sysUserMfa.EnabledAt = field.NewTime(tableName, "enabled_at")
+	_sysUserMfa.CreatedAt = field.NewTime(tableName, "created_at")
+	_sysUserMfa.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_sysUserMfa.fillFieldMap()
+
+	return _sysUserMfa
+}
+
+type sysUserMfa struct {
+	sysUserMfaDo sysUserMfaDo
+
+	ALL           field.Asterisk
+	ID            field.Int32
+	UserID        field.Int32  // 用户ID
+	Secret        field.String // TOTP密钥（AES-GCM加密）
+	Status        field.Int8   // 状态：1 待验证, 2 已启用
+	RecoveryCodes field.String // 恢复码哈希列表
+	LastUsedStep  field.Int64  // 最近一次使用的时间步，防止验证码重放
+	EnabledAt     field.Time   // 启用时间
+	CreatedAt     field.Time
+	UpdatedAt     field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sysUserMfa) Table(newTableName string) *sysUserMfa {
+	s.sysUserMfaDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sysUserMfa) As(alias string) *sysUserMfa {
+	s.sysUserMfaDo.DO = *(s.sysUserMfaDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sysUserMfa) updateTableName(table string) *sysUserMfa {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewInt32(table, "id")
+	s.UserID = field.NewInt32(table, "user_id")
+	s.Secret = field.NewString(table, "secret")
+	s.Status = field.NewInt8(table, "status")
+	s.RecoveryCodes = field.NewString(table, "recovery_codes")
+	s.LastUsedStep = field.NewInt64(table, "last_used_step")
+	s.EnabledAt = field.NewTime(table, "enabled_at")
+	s.CreatedAt = field.NewTime(table, "created_at")
+	s.UpdatedAt = field.NewTime(table, "updated_at")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sysUserMfa) WithContext(ctx context.Context) *sysUserMfaDo {
+	return s.sysUserMfaDo.WithContext(ctx)
+}
+
+func (s sysUserMfa) TableName() string { return s.sysUserMfaDo.TableName() }
+
+func (s sysUserMfa) Alias() string { return s.sysUserMfaDo.Alias() }
+
+func (s sysUserMfa) Columns(cols ...field.Expr) gen.Columns { return s.sysUserMfaDo.Columns(cols...) }
+
+func (s *sysUserMfa) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sysUserMfa) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 9)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["user_id"] = s.UserID
+	s.fieldMap["secret"] = s.Secret
+	s.fieldMap["status"] = s.Status
+	s.fieldMap["recovery_codes"] = s.RecoveryCodes
+	s.fieldMap["last_used_step"] = s.LastUsedStep
+	s.fieldMap["enabled_at"] = s.EnabledAt
+	s.fieldMap["created_at"] = s.CreatedAt
+	s.fieldMap["updated_at"] = s.UpdatedAt
+}
+
+func (s sysUserMfa) clone(db *gorm.DB) sysUserMfa {
+	s.sysUserMfaDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sysUserMfa) replaceDB(db *gorm.DB) sysUserMfa {
+	s.sysUserMfaDo.ReplaceDB(db)
+	return s
+}
+
+type sysUserMfaDo struct{ gen.DO }
+
+func (s sysUserMfaDo) Debug() *sysUserMfaDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sysUserMfaDo) WithContext(ctx context.Context) *sysUserMfaDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sysUserMfaDo) ReadDB() *sysUserMfaDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sysUserMfaDo) WriteDB() *sysUserMfaDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sysUserMfaDo) Session(config *gorm.Session) *sysUserMfaDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sysUserMfaDo) Clauses(conds ...clause.Expression) *sysUserMfaDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sysUserMfaDo) Returning(value interface{}, columns ...string) *sysUserMfaDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sysUserMfaDo) Not(conds ...gen.Condition) *sysUserMfaDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sysUserMfaDo) Or(conds ...gen.Condition) *sysUserMfaDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sysUserMfaDo) Select(conds ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sysUserMfaDo) Where(conds ...gen.Condition) *sysUserMfaDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sysUserMfaDo) Order(conds ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sysUserMfaDo) Distinct(cols ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sysUserMfaDo) Omit(cols ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sysUserMfaDo) Join(table schema.Tabler, on ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sysUserMfaDo) LeftJoin(table schema.Tabler, on ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sysUserMfaDo) RightJoin(table schema.Tabler, on ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sysUserMfaDo) Group(cols ...field.Expr) *sysUserMfaDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sysUserMfaDo) Having(conds ...gen.Condition) *sysUserMfaDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sysUserMfaDo) Limit(limit int) *sysUserMfaDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sysUserMfaDo) Offset(offset int) *sysUserMfaDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sysUserMfaDo) Scopes(funcs ...func(gen.Dao) gen.Dao) *sysUserMfaDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sysUserMfaDo) Unscoped() *sysUserMfaDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sysUserMfaDo) Create(values ...*model.SysUserMfa) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sysUserMfaDo) CreateInBatches(values []*model.SysUserMfa, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sysUserMfaDo) Save(values ...*model.SysUserMfa) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sysUserMfaDo) First() (*model.SysUserMfa, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserMfa), nil
+	}
+}
+
+func (s sysUserMfaDo) Take() (*model.SysUserMfa, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserMfa), nil
+	}
+}
+
+func (s sysUserMfaDo) Last() (*model.SysUserMfa, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserMfa), nil
+	}
+}
+
+func (s sysUserMfaDo) Find() ([]*model.SysUserMfa, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SysUserMfa), err
+}
+
+func (s sysUserMfaDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SysUserMfa, err error) {
+	buf := make([]*model.SysUserMfa, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sysUserMfaDo) FindInBatches(result *[]*model.SysUserMfa, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sysUserMfaDo) Attrs(attrs ...field.AssignExpr) *sysUserMfaDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sysUserMfaDo) Assign(attrs ...field.AssignExpr) *sysUserMfaDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sysUserMfaDo) Joins(fields ...field.RelationField) *sysUserMfaDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sysUserMfaDo) Preload(fields ...field.RelationField) *sysUserMfaDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sysUserMfaDo) FirstOrInit() (*model.SysUserMfa, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserMfa), nil
+	}
+}
+
+func (s sysUserMfaDo) FirstOrCreate() (*model.SysUserMfa, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserMfa), nil
+	}
+}
+
+func (s sysUserMfaDo) FindByPage(offset int, limit int) (result []*model.SysUserMfa, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sysUserMfaDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sysUserMfaDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sysUserMfaDo) Delete(models ...*model.SysUserMfa) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sysUserMfaDo) withDO(do gen.Dao) *sysUserMfaDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
@@ -0,0 +1,356 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"snowgo/internal/dal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newSysUserOidc(db *gorm.DB, opts ...gen.DOOption) sysUserOidc {
+	_sysUserOidc := sysUserOidc{}
+
+	_sysUserOidc.sysUserOidcDo.UseDB(db, opts...)
+	_sysUserOidc.sysUserOidcDo.UseModel(&model.SysUserOidc{})
+
+	tableName := _sysUserOidc.sysUserOidcDo.TableName()
+	_sysUserOidc.ALL = field.NewAsterisk(tableName)
+	_sysUserOidc.ID = field.NewInt32(tableName, "id")
+	_sysUserOidc.UserID = field.NewInt32(tableName, "user_id")
+	_sysUserOidc.Issuer = field.NewString(tableName, "issuer")
+	_sysUserOidc.Subject = field.NewString(tableName, "subject")
+	_sysUserOidc.Email = field.NewString(tableName, "email")
+	_sysUserOidc.LastLoginAt = field.NewTime(tableName, "last_login_at")
+	_sysUserOidc.CreatedAt = field.NewTime(tableName, "created_at")
+	_sysUserOidc.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_sysUserOidc.fillFieldMap()
+
+	return _sysUserOidc
+}
+
+type sysUserOidc struct {
+	sysUserOidcDo sysUserOidcDo
+
+	ALL         field.Asterisk
+	ID          field.Int32
+	UserID      field.Int32  // 用户ID
+	Issuer      field.String // IdP issuer
+	Subject     field.String // IdP 用户唯一标识(sub)
+	Email       field.String // IdP 邮箱
+	LastLoginAt field.Time   // 最后登录时间
+	CreatedAt   field.Time
+	UpdatedAt   field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sysUserOidc) Table(newTableName string) *sysUserOidc {
+	s.sysUserOidcDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sysUserOidc) As(alias string) *sysUserOidc {
+	s.sysUserOidcDo.DO = *(s.sysUserOidcDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sysUserOidc) updateTableName(table string) *sysUserOidc {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewInt32(table, "id")
+	s.UserID = field.NewInt32(table, "user_id")
+	s.Issuer = field.NewString(table, "issuer")
+	s.Subject = field.NewString(table, "subject")
+	s.Email = field.NewString(table, "email")
+	s.LastLoginAt = field.NewTime(table, "last_login_at")
+	s.CreatedAt = field.NewTime(table, "created_at")
+	s.UpdatedAt = field.NewTime(table, "updated_at")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sysUserOidc) WithContext(ctx context.Context) *sysUserOidcDo {
+	return s.sysUserOidcDo.WithContext(ctx)
+}
+
+func (s sysUserOidc) TableName() string { return s.sysUserOidcDo.TableName() }
+
+func (s sysUserOidc) Alias() string { return s.sysUserOidcDo.Alias() }
+
+func (s sysUserOidc) Columns(cols ...field.Expr) gen.Columns { return s.sysUserOidcDo.Columns(cols...) }
+
+func (s *sysUserOidc) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sysUserOidc) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 8)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["user_id"] = s.UserID
+	s.fieldMap["issuer"] = s.Issuer
+	s.fieldMap["subject"] = s.Subject
+	s.fieldMap["email"] = s.Email
+	s.fieldMap["last_login_at"] = s.LastLoginAt
+	s.fieldMap["created_at"] = s.CreatedAt
+	s.fieldMap["updated_at"] = s.UpdatedAt
+}
+
+func (s sysUserOidc) clone(db *gorm.DB) sysUserOidc {
+	s.sysUserOidcDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sysUserOidc) replaceDB(db *gorm.DB) sysUserOidc {
+	s.sysUserOidcDo.ReplaceDB(db)
+	return s
+}
+
+type sysUserOidcDo struct{ gen.DO }
+
+func (s sysUserOidcDo) Debug() *sysUserOidcDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sysUserOidcDo) WithContext(ctx context.Context) *sysUserOidcDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sysUserOidcDo) ReadDB() *sysUserOidcDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sysUserOidcDo) WriteDB() *sysUserOidcDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sysUserOidcDo) Session(config *gorm.Session) *sysUserOidcDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sysUserOidcDo) Clauses(conds ...clause.Expression) *sysUserOidcDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sysUserOidcDo) Returning(value interface{}, columns ...string) *sysUserOidcDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sysUserOidcDo) Not(conds ...gen.Condition) *sysUserOidcDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sysUserOidcDo) Or(conds ...gen.Condition) *sysUserOidcDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sysUserOidcDo) Select(conds ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sysUserOidcDo) Where(conds ...gen.Condition) *sysUserOidcDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sysUserOidcDo) Order(conds ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sysUserOidcDo) Distinct(cols ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sysUserOidcDo) Omit(cols ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sysUserOidcDo) Join(table schema.Tabler, on ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sysUserOidcDo) LeftJoin(table schema.Tabler, on ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sysUserOidcDo) RightJoin(table schema.Tabler, on ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sysUserOidcDo) Group(cols ...field.Expr) *sysUserOidcDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sysUserOidcDo) Having(conds ...gen.Condition) *sysUserOidcDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sysUserOidcDo) Limit(limit int) *sysUserOidcDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sysUserOidcDo) Offset(offset int) *sysUserOidcDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sysUserOidcDo) Scopes(funcs ...func(gen.Dao) gen.Dao) *sysUserOidcDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sysUserOidcDo) Unscoped() *sysUserOidcDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sysUserOidcDo) Create(values ...*model.SysUserOidc) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sysUserOidcDo) CreateInBatches(values []*model.SysUserOidc, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sysUserOidcDo) Save(values ...*model.SysUserOidc) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sysUserOidcDo) First() (*model.SysUserOidc, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserOidc), nil
+	}
+}
+
+func (s sysUserOidcDo) Take() (*model.SysUserOidc, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserOidc), nil
+	}
+}
+
+func (s sysUserOidcDo) Last() (*model.SysUserOidc, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserOidc), nil
+	}
+}
+
+func (s sysUserOidcDo) Find() ([]*model.SysUserOidc, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SysUserOidc), err
+}
+
+func (s sysUserOidcDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SysUserOidc, err error) {
+	buf := make([]*model.SysUserOidc, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sysUserOidcDo) FindInBatches(result *[]*model.SysUserOidc, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sysUserOidcDo) Attrs(attrs ...field.AssignExpr) *sysUserOidcDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sysUserOidcDo) Assign(attrs ...field.AssignExpr) *sysUserOidcDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sysUserOidcDo) Joins(fields ...field.RelationField) *sysUserOidcDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sysUserOidcDo) Preload(fields ...field.RelationField) *sysUserOidcDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sysUserOidcDo) FirstOrInit() (*model.SysUserOidc, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserOidc), nil
+	}
+}
+
+func (s sysUserOidcDo) FirstOrCreate() (*model.SysUserOidc, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserOidc), nil
+	}
+}
+
+func (s sysUserOidcDo) FindByPage(offset int, limit int) (result []*model.SysUserOidc, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sysUserOidcDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sysUserOidcDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sysUserOidcDo) Delete(models ...*model.SysUserOidc) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sysUserOidcDo) withDO(do gen.Dao) *sysUserOidcDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
@@ -0,0 +1,342 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"context"
+	"snowgo/internal/dal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newSysUserPwdHistory(db *gorm.DB, opts ...gen.DOOption) sysUserPwdHistory {
+	_sysUserPwdHistory := sysUserPwdHistory{}
+
+	_sysUserPwdHistory.sysUserPwdHistoryDo.UseDB(db, opts...)
+	_sysUserPwdHistory.sysUserPwdHistoryDo.UseModel(&model.SysUserPwdHistory{})
+
+	tableName := _sysUserPwdHistory.sysUserPwdHistoryDo.TableName()
+	_sysUserPwdHistory.ALL = field.NewAsterisk(tableName)
+	_sysUserPwdHistory.ID = field.NewInt32(tableName, "id")
+	_sysUserPwdHistory.UserID = field.NewInt32(tableName, "user_id")
+	_sysUserPwdHistory.Password = field.NewString(tableName, "password")
+	_sysUserPwdHistory.CreatedAt = field.NewTime(tableName, "created_at")
+
+	_sysUserPwdHistory.fillFieldMap()
+
+	return _sysUserPwdHistory
+}
+
+type sysUserPwdHistory struct {
+	sysUserPwdHistoryDo sysUserPwdHistoryDo
+
+	ALL       field.Asterisk
+	ID        field.Int32
+	UserID    field.Int32  // 用户ID
+	Password  field.String // 历史密码 hash
+	CreatedAt field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (s sysUserPwdHistory) Table(newTableName string) *sysUserPwdHistory {
+	s.sysUserPwdHistoryDo.UseTable(newTableName)
+	return s.updateTableName(newTableName)
+}
+
+func (s sysUserPwdHistory) As(alias string) *sysUserPwdHistory {
+	s.sysUserPwdHistoryDo.DO = *(s.sysUserPwdHistoryDo.As(alias).(*gen.DO))
+	return s.updateTableName(alias)
+}
+
+func (s *sysUserPwdHistory) updateTableName(table string) *sysUserPwdHistory {
+	s.ALL = field.NewAsterisk(table)
+	s.ID = field.NewInt32(table, "id")
+	s.UserID = field.NewInt32(table, "user_id")
+	s.Password = field.NewString(table, "password")
+	s.CreatedAt = field.NewTime(table, "created_at")
+
+	s.fillFieldMap()
+
+	return s
+}
+
+func (s *sysUserPwdHistory) WithContext(ctx context.Context) *sysUserPwdHistoryDo {
+	return s.sysUserPwdHistoryDo.WithContext(ctx)
+}
+
+func (s sysUserPwdHistory) TableName() string { return s.sysUserPwdHistoryDo.TableName() }
+
+func (s sysUserPwdHistory) Alias() string { return s.sysUserPwdHistoryDo.Alias() }
+
+func (s sysUserPwdHistory) Columns(cols ...field.Expr) gen.Columns {
+	return s.sysUserPwdHistoryDo.Columns(cols...)
+}
+
+func (s *sysUserPwdHistory) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := s.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (s *sysUserPwdHistory) fillFieldMap() {
+	s.fieldMap = make(map[string]field.Expr, 4)
+	s.fieldMap["id"] = s.ID
+	s.fieldMap["user_id"] = s.UserID
+	s.fieldMap["password"] = s.Password
+	s.fieldMap["created_at"] = s.CreatedAt
+}
+
+func (s sysUserPwdHistory) clone(db *gorm.DB) sysUserPwdHistory {
+	s.sysUserPwdHistoryDo.ReplaceConnPool(db.Statement.ConnPool)
+	return s
+}
+
+func (s sysUserPwdHistory) replaceDB(db *gorm.DB) sysUserPwdHistory {
+	s.sysUserPwdHistoryDo.ReplaceDB(db)
+	return s
+}
+
+type sysUserPwdHistoryDo struct{ gen.DO }
+
+func (s sysUserPwdHistoryDo) Debug() *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Debug())
+}
+
+func (s sysUserPwdHistoryDo) WithContext(ctx context.Context) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.WithContext(ctx))
+}
+
+func (s sysUserPwdHistoryDo) ReadDB() *sysUserPwdHistoryDo {
+	return s.Clauses(dbresolver.Read)
+}
+
+func (s sysUserPwdHistoryDo) WriteDB() *sysUserPwdHistoryDo {
+	return s.Clauses(dbresolver.Write)
+}
+
+func (s sysUserPwdHistoryDo) Session(config *gorm.Session) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Session(config))
+}
+
+func (s sysUserPwdHistoryDo) Clauses(conds ...clause.Expression) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Clauses(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Returning(value interface{}, columns ...string) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Returning(value, columns...))
+}
+
+func (s sysUserPwdHistoryDo) Not(conds ...gen.Condition) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Not(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Or(conds ...gen.Condition) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Or(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Select(conds ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Select(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Where(conds ...gen.Condition) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Where(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Order(conds ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Order(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Distinct(cols ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Distinct(cols...))
+}
+
+func (s sysUserPwdHistoryDo) Omit(cols ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Omit(cols...))
+}
+
+func (s sysUserPwdHistoryDo) Join(table schema.Tabler, on ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Join(table, on...))
+}
+
+func (s sysUserPwdHistoryDo) LeftJoin(table schema.Tabler, on ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.LeftJoin(table, on...))
+}
+
+func (s sysUserPwdHistoryDo) RightJoin(table schema.Tabler, on ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.RightJoin(table, on...))
+}
+
+func (s sysUserPwdHistoryDo) Group(cols ...field.Expr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Group(cols...))
+}
+
+func (s sysUserPwdHistoryDo) Having(conds ...gen.Condition) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Having(conds...))
+}
+
+func (s sysUserPwdHistoryDo) Limit(limit int) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Limit(limit))
+}
+
+func (s sysUserPwdHistoryDo) Offset(offset int) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Offset(offset))
+}
+
+func (s sysUserPwdHistoryDo) Scopes(funcs ...func(gen.Dao) gen.Dao) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Scopes(funcs...))
+}
+
+func (s sysUserPwdHistoryDo) Unscoped() *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Unscoped())
+}
+
+func (s sysUserPwdHistoryDo) Create(values ...*model.SysUserPwdHistory) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Create(values)
+}
+
+func (s sysUserPwdHistoryDo) CreateInBatches(values []*model.SysUserPwdHistory, batchSize int) error {
+	return s.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (s sysUserPwdHistoryDo) Save(values ...*model.SysUserPwdHistory) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.DO.Save(values)
+}
+
+func (s sysUserPwdHistoryDo) First() (*model.SysUserPwdHistory, error) {
+	if result, err := s.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserPwdHistory), nil
+	}
+}
+
+func (s sysUserPwdHistoryDo) Take() (*model.SysUserPwdHistory, error) {
+	if result, err := s.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserPwdHistory), nil
+	}
+}
+
+func (s sysUserPwdHistoryDo) Last() (*model.SysUserPwdHistory, error) {
+	if result, err := s.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserPwdHistory), nil
+	}
+}
+
+func (s sysUserPwdHistoryDo) Find() ([]*model.SysUserPwdHistory, error) {
+	result, err := s.DO.Find()
+	return result.([]*model.SysUserPwdHistory), err
+}
+
+func (s sysUserPwdHistoryDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.SysUserPwdHistory, err error) {
+	buf := make([]*model.SysUserPwdHistory, 0, batchSize)
+	err = s.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (s sysUserPwdHistoryDo) FindInBatches(result *[]*model.SysUserPwdHistory, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return s.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (s sysUserPwdHistoryDo) Attrs(attrs ...field.AssignExpr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Attrs(attrs...))
+}
+
+func (s sysUserPwdHistoryDo) Assign(attrs ...field.AssignExpr) *sysUserPwdHistoryDo {
+	return s.withDO(s.DO.Assign(attrs...))
+}
+
+func (s sysUserPwdHistoryDo) Joins(fields ...field.RelationField) *sysUserPwdHistoryDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Joins(_f))
+	}
+	return &s
+}
+
+func (s sysUserPwdHistoryDo) Preload(fields ...field.RelationField) *sysUserPwdHistoryDo {
+	for _, _f := range fields {
+		s = *s.withDO(s.DO.Preload(_f))
+	}
+	return &s
+}
+
+func (s sysUserPwdHistoryDo) FirstOrInit() (*model.SysUserPwdHistory, error) {
+	if result, err := s.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserPwdHistory), nil
+	}
+}
+
+func (s sysUserPwdHistoryDo) FirstOrCreate() (*model.SysUserPwdHistory, error) {
+	if result, err := s.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.SysUserPwdHistory), nil
+	}
+}
+
+func (s sysUserPwdHistoryDo) FindByPage(offset int, limit int) (result []*model.SysUserPwdHistory, count int64, err error) {
+	result, err = s.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = s.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (s sysUserPwdHistoryDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = s.Count()
+	if err != nil {
+		return
+	}
+
+	err = s.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (s sysUserPwdHistoryDo) Scan(result interface{}) (err error) {
+	return s.DO.Scan(result)
+}
+
+func (s sysUserPwdHistoryDo) Delete(models ...*model.SysUserPwdHistory) (result gen.ResultInfo, err error) {
+	return s.DO.Delete(models)
+}
+
+func (s *sysUserPwdHistoryDo) withDO(do gen.Dao) *sysUserPwdHistoryDo {
+	s.DO = *do.(*gen.DO)
+	return s
+}
@@ -29,6 +29,8 @@ func newSysUserRole(db *gorm.DB, opts ...gen.DOOption) sysUserRole {
 	_sysUserRole.ID = field.NewInt64(tableName, "id")
 	_sysUserRole.UserID = field.NewInt32(tableName, "user_id")
 	_sysUserRole.RoleID = field.NewInt32(tableName, "role_id")
+	_sysUserRole.ValidFrom = field.NewTime(tableName, "valid_from")
+	_sysUserRole.ValidUntil = field.NewTime(tableName, "valid_until")
 	_sysUserRole.CreatedAt = field.NewTime(tableName, "created_at")
 	_sysUserRole.UpdatedAt = field.NewTime(tableName, "updated_at")
 
@@ -40,12 +42,14 @@ func newSysUserRole(db *gorm.DB, opts ...gen.DOOption) sysUserRole {
 type sysUserRole struct {
 	sysUserRoleDo sysUserRoleDo
 
-	ALL       field.Asterisk
-	ID        field.Int64
-	UserID    field.Int32 // 用户ID
-	RoleID    field.Int32 // 角色ID
-	CreatedAt field.Time
-	UpdatedAt field.Time
+	ALL        field.Asterisk
+	ID         field.Int64
+	UserID     field.Int32 // 用户ID
+	RoleID     field.Int32 // 角色ID
+	ValidFrom  field.Time  // 生效时间，为空表示立即生效
+	ValidUntil field.Time  // 失效时间，为空表示永久有效
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
 
 	fieldMap map[string]field.Expr
 }
@@ -65,6 +69,8 @@ func (s *sysUserRole) updateTableName(table string) *sysUserRole {
 	s.ID = field.NewInt64(table, "id")
 	s.UserID = field.NewInt32(table, "user_id")
 	s.RoleID = field.NewInt32(table, "role_id")
+	s.ValidFrom = field.NewTime(table, "valid_from")
+	s.ValidUntil = field.NewTime(table, "valid_until")
 	s.CreatedAt = field.NewTime(table, "created_at")
 	s.UpdatedAt = field.NewTime(table, "updated_at")
 
@@ -93,10 +99,12 @@ func (s *sysUserRole) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *sysUserRole) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 5)
+	s.fieldMap = make(map[string]field.Expr, 7)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["user_id"] = s.UserID
 	s.fieldMap["role_id"] = s.RoleID
+	s.fieldMap["valid_from"] = s.ValidFrom
+	s.fieldMap["valid_until"] = s.ValidUntil
 	s.fieldMap["created_at"] = s.CreatedAt
 	s.fieldMap["updated_at"] = s.UpdatedAt
 }
@@ -6,12 +6,20 @@ func GetQueryModels() []interface{} {
 	return []interface{}{
 		&model.SysDictItem{},
 		&model.SysDict{},
+		&model.SysDept{},
 		&model.SysLoginLog{},
 		&model.SysMenu{},
 		&model.SysOperationLog{},
 		&model.SysRoleMenu{},
+		&model.SysRoleDept{},
+		&model.SysRoleInherit{},
 		&model.SysRole{},
 		&model.SysUserRole{},
 		&model.SysUser{},
+		&model.SysUserMfa{},
+		&model.SysAPIKey{},
+		&model.SysUserOidc{},
+		&model.SysUserPwdHistory{},
+		&model.SysPartnerCredential{},
 	}
 }
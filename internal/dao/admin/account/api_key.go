@@ -0,0 +1,83 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"time"
+)
+
+// ApiKeyDao ApiKeyRepo接口实现
+type ApiKeyDao struct {
+	repo *repo.Repository
+}
+
+func NewApiKeyDao(repo *repo.Repository) *ApiKeyDao {
+	return &ApiKeyDao{repo: repo}
+}
+
+// CreateApiKey 创建API Key
+func (a *ApiKeyDao) CreateApiKey(ctx context.Context, q *query.Query, apiKey *model.SysAPIKey) (*model.SysAPIKey, error) {
+	if apiKey == nil {
+		return nil, errors.New("API Key信息不能为空")
+	}
+	if err := q.WithContext(ctx).SysAPIKey.Create(apiKey); err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// GetApiKeyList 查询服务账号的API Key列表，按创建时间倒序
+func (a *ApiKeyDao) GetApiKeyList(ctx context.Context, userId int32) ([]*model.SysAPIKey, error) {
+	if userId <= 0 {
+		return nil, errors.New("用户id不存在")
+	}
+	m := a.repo.Query().SysAPIKey
+	return m.WithContext(ctx).Where(m.UserID.Eq(userId)).Order(m.ID.Desc()).Find()
+}
+
+// GetApiKeyById 根据id查询API Key
+func (a *ApiKeyDao) GetApiKeyById(ctx context.Context, q *query.Query, keyId int32) (*model.SysAPIKey, error) {
+	if keyId <= 0 {
+		return nil, errors.New("API Key id不存在")
+	}
+	m := q.SysAPIKey
+	return m.WithContext(ctx).Where(m.ID.Eq(keyId)).First()
+}
+
+// GetApiKeyByHash 根据Key摘要查询API Key，认证使用，读取主库避免刚创建的Key查不到
+func (a *ApiKeyDao) GetApiKeyByHash(ctx context.Context, keyHash string) (*model.SysAPIKey, error) {
+	if keyHash == "" {
+		return nil, errors.New("API Key摘要不能为空")
+	}
+	m := a.repo.WriteQuery().SysAPIKey
+	return m.WithContext(ctx).Where(m.KeyHash.Eq(keyHash)).First()
+}
+
+// RevokeApiKey 吊销API Key，仅有效状态的Key会被更新，返回是否吊销成功
+func (a *ApiKeyDao) RevokeApiKey(ctx context.Context, q *query.Query, keyId int32) (bool, error) {
+	if keyId <= 0 {
+		return false, errors.New("API Key id不存在")
+	}
+	m := q.SysAPIKey
+	info, err := m.WithContext(ctx).
+		Where(m.ID.Eq(keyId), m.Status.Eq(constant.ApiKeyStatusActive)).
+		UpdateSimple(m.Status.Value(constant.ApiKeyStatusRevoked), m.RevokedAt.Value(time.Now()))
+	if err != nil {
+		return false, err
+	}
+	return info.RowsAffected > 0, nil
+}
+
+// UpdateLastUsedAt 更新API Key最后使用时间
+func (a *ApiKeyDao) UpdateLastUsedAt(ctx context.Context, keyId int32, usedAt time.Time) error {
+	if keyId <= 0 {
+		return errors.New("API Key id不存在")
+	}
+	m := a.repo.WriteQuery().SysAPIKey
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(keyId)).UpdateSimple(m.LastUsedAt.Value(usedAt))
+	return err
+}
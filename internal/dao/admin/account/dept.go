@@ -0,0 +1,142 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+)
+
+type DeptDao struct {
+	repo *repo.Repository
+}
+
+func NewDeptDao(repo *repo.Repository) *DeptDao {
+	return &DeptDao{repo: repo}
+}
+
+// CreateDept 创建部门
+func (d *DeptDao) CreateDept(ctx context.Context, q *query.Query, dept *model.SysDept) (*model.SysDept, error) {
+	err := q.WithContext(ctx).SysDept.Create(dept)
+	if err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// UpdateDept 更新部门
+func (d *DeptDao) UpdateDept(ctx context.Context, q *query.Query, dept *model.SysDept) (*model.SysDept, error) {
+	if dept.ID <= 0 {
+		return nil, errors.New("部门ID无效")
+	}
+	err := q.WithContext(ctx).SysDept.Where(q.SysDept.ID.Eq(dept.ID)).Save(dept)
+	if err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// UpdateParent 移动部门到新的父级并设置排序号
+func (d *DeptDao) UpdateParent(ctx context.Context, q *query.Query, id, parentId, sortOrder int32) error {
+	m := q.SysDept
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(id)).UpdateSimple(m.ParentID.Value(parentId), m.SortOrder.Value(sortOrder))
+	return err
+}
+
+// UpdateSortOrder 更新部门排序号
+func (d *DeptDao) UpdateSortOrder(ctx context.Context, q *query.Query, id, sortOrder int32) error {
+	m := q.SysDept
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(id)).UpdateSimple(m.SortOrder.Value(sortOrder))
+	return err
+}
+
+// DeleteById 删除部门
+func (d *DeptDao) DeleteById(ctx context.Context, q *query.Query, id int32) error {
+	if id <= 0 {
+		return errors.New("部门ID无效")
+	}
+	_, err := q.WithContext(ctx).SysDept.Where(q.SysDept.ID.Eq(id)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetById 查询单个部门
+func (d *DeptDao) GetById(ctx context.Context, q *query.Query, id int32) (*model.SysDept, error) {
+	if id <= 0 {
+		return nil, errors.New("部门ID无效")
+	}
+	dept, err := q.WithContext(ctx).SysDept.Where(q.SysDept.ID.Eq(id)).First()
+	if err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// LockDepts 事务内加锁读取全部部门，串行化并发的部门树修改，避免各自校验通过后形成环或挂到已删除的部门下
+func (d *DeptDao) LockDepts(ctx context.Context, q *query.Query) ([]*model.SysDept, error) {
+	return q.WithContext(ctx).SysDept.Clauses(clause.Locking{Strength: "UPDATE"}).Find()
+}
+
+// CountByIds 根据部门ids，获取数量
+func (d *DeptDao) CountByIds(ctx context.Context, q *query.Query, ids []int32) (int64, error) {
+	m := q.SysDept
+	return m.WithContext(ctx).Where(m.ID.In(ids...)).Count()
+}
+
+// GetByParentId 根据parentId获取下级部门
+func (d *DeptDao) GetByParentId(ctx context.Context, q *query.Query, parentId int32) ([]*model.SysDept, error) {
+	depts, err := q.WithContext(ctx).SysDept.Where(q.SysDept.ParentID.Eq(parentId)).Find()
+	if err != nil {
+		return nil, err
+	}
+	return depts, nil
+}
+
+// GetAllDepts 获取所有部门（不分页）
+func (d *DeptDao) GetAllDepts(ctx context.Context) ([]*model.SysDept, error) {
+	depts, err := d.repo.Query().WithContext(ctx).SysDept.Find()
+	if err != nil {
+		return nil, err
+	}
+	return depts, nil
+}
+
+// IsNameExists 检查同级部门名称是否已存在，excludeId 用于排除自身（更新场景）
+func (d *DeptDao) IsNameExists(ctx context.Context, q *query.Query, parentId int32, name string, excludeId int32) (bool, error) {
+	stmt := q.WithContext(ctx).SysDept.Select(q.SysDept.ID).Where(q.SysDept.ParentID.Eq(parentId), q.SysDept.Name.Eq(name))
+	if excludeId > 0 {
+		stmt = stmt.Where(q.SysDept.ID.Neq(excludeId))
+	}
+	_, err := stmt.First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// IsUsedByUser 判断部门下是否存在用户
+func (d *DeptDao) IsUsedByUser(ctx context.Context, q *query.Query, id int32) (bool, error) {
+	m := q.SysUser
+	_, err := m.WithContext(ctx).Select(m.ID).Where(m.DeptID.Eq(id)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// DeleteRoleDept 删除角色自定义数据范围中的该部门
+func (d *DeptDao) DeleteRoleDept(ctx context.Context, q *query.Query, id int32) error {
+	_, err := q.WithContext(ctx).SysRoleDept.Where(q.SysRoleDept.DeptID.Eq(id)).Delete()
+	return err
+}
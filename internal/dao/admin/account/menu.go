@@ -147,3 +147,8 @@ func (d *MenuDao) GetRoleIdsByIds(ctx context.Context, menuId int32) ([]int32, e
 	}
 	return roleIds, nil
 }
+
+// GetAllRoleInherits 获取全部角色继承关系，菜单变更时级联清理下级角色缓存
+func (d *MenuDao) GetAllRoleInherits(ctx context.Context) ([]*model.SysRoleInherit, error) {
+	return d.repo.Query().WithContext(ctx).SysRoleInherit.Find()
+}
@@ -0,0 +1,123 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm/clause"
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"time"
+)
+
+// MfaDao MfaRepo接口实现
+type MfaDao struct {
+	repo *repo.Repository
+}
+
+func NewMfaDao(repo *repo.Repository) *MfaDao {
+	return &MfaDao{repo: repo}
+}
+
+// GetMfaByUserId 查询用户两步验证信息
+func (m *MfaDao) GetMfaByUserId(ctx context.Context, q *query.Query, userId int32) (*model.SysUserMfa, error) {
+	if userId <= 0 {
+		return nil, errors.New("用户id不存在")
+	}
+	mfa := q.SysUserMfa
+	return mfa.WithContext(ctx).Where(mfa.UserID.Eq(userId)).First()
+}
+
+// LockMfaByUserId 事务内加行锁查询用户两步验证信息
+func (m *MfaDao) LockMfaByUserId(ctx context.Context, q *query.Query, userId int32) (*model.SysUserMfa, error) {
+	if userId <= 0 {
+		return nil, errors.New("用户id不存在")
+	}
+	mfa := q.SysUserMfa
+	return mfa.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where(mfa.UserID.Eq(userId)).First()
+}
+
+// SavePendingMfa 写入待验证的密钥，已存在则覆盖并重置为待验证状态
+func (m *MfaDao) SavePendingMfa(ctx context.Context, q *query.Query, userId int32, secret string) error {
+	if userId <= 0 {
+		return errors.New("用户id不存在")
+	}
+	status := constant.MfaStatusPending
+	var lastUsedStep int64
+	return q.WithContext(ctx).SysUserMfa.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "status", "recovery_codes", "last_used_step", "enabled_at"}),
+	}).Create(&model.SysUserMfa{
+		UserID:       userId,
+		Secret:       secret,
+		Status:       &status,
+		LastUsedStep: &lastUsedStep,
+	})
+}
+
+// EnableMfa 启用两步验证
+func (m *MfaDao) EnableMfa(ctx context.Context, q *query.Query, userId int32, recoveryCodes string) error {
+	if userId <= 0 {
+		return errors.New("用户id不存在")
+	}
+	mfa := q.SysUserMfa
+	_, err := mfa.WithContext(ctx).Where(mfa.UserID.Eq(userId)).UpdateSimple(
+		mfa.Status.Value(constant.MfaStatusEnabled),
+		mfa.RecoveryCodes.Value(recoveryCodes),
+		mfa.EnabledAt.Value(time.Now()),
+	)
+	return err
+}
+
+// ConsumeStep 记录已使用的时间步，时间步不大于已记录值时视为重放，返回 false
+func (m *MfaDao) ConsumeStep(ctx context.Context, q *query.Query, userId int32, step int64) (bool, error) {
+	if userId <= 0 {
+		return false, errors.New("用户id不存在")
+	}
+	mfa := q.SysUserMfa
+	info, err := mfa.WithContext(ctx).
+		Where(mfa.UserID.Eq(userId), mfa.LastUsedStep.Lt(step)).
+		UpdateSimple(mfa.LastUsedStep.Value(step))
+	if err != nil {
+		return false, err
+	}
+	return info.RowsAffected > 0, nil
+}
+
+// UpdateRecoveryCodes 更新恢复码
+func (m *MfaDao) UpdateRecoveryCodes(ctx context.Context, q *query.Query, userId int32, recoveryCodes string) error {
+	if userId <= 0 {
+		return errors.New("用户id不存在")
+	}
+	mfa := q.SysUserMfa
+	_, err := mfa.WithContext(ctx).Where(mfa.UserID.Eq(userId)).UpdateSimple(mfa.RecoveryCodes.Value(recoveryCodes))
+	return err
+}
+
+// DeleteMfa 删除用户两步验证信息
+func (m *MfaDao) DeleteMfa(ctx context.Context, q *query.Query, userId int32) error {
+	if userId <= 0 {
+		return errors.New("用户id不存在")
+	}
+	_, err := q.WithContext(ctx).SysUserMfa.Where(q.SysUserMfa.UserID.Eq(userId)).Delete()
+	return err
+}
+
+// IsMfaRequiredByRole 用户所属角色是否强制两步验证
+func (m *MfaDao) IsMfaRequiredByRole(ctx context.Context, q *query.Query, userId int32) (bool, error) {
+	if userId <= 0 {
+		return false, errors.New("用户id不存在")
+	}
+	ur := q.SysUserRole
+	role := q.SysRole
+	count, err := ur.WithContext(ctx).
+		Join(role, ur.RoleID.EqCol(role.ID)).
+		Where(ur.UserID.Eq(userId), role.RequireMfa.Eq(constant.RoleRequireMfa)).
+		Where(userRoleActiveConds(q, time.Now())...).
+		Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
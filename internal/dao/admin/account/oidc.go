@@ -0,0 +1,58 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"time"
+)
+
+// OidcDao OidcRepo接口实现
+type OidcDao struct {
+	repo *repo.Repository
+}
+
+func NewOidcDao(repo *repo.Repository) *OidcDao {
+	return &OidcDao{repo: repo}
+}
+
+// GetLinkBySubject 根据 IdP issuer 与 subject 查询关联，登录使用，读取主库
+func (o *OidcDao) GetLinkBySubject(ctx context.Context, issuer, subject string) (*model.SysUserOidc, error) {
+	if issuer == "" || subject == "" {
+		return nil, errors.New("issuer或subject不能为空")
+	}
+	m := o.repo.WriteQuery().SysUserOidc
+	return m.WithContext(ctx).Where(m.Issuer.Eq(issuer), m.Subject.Eq(subject)).First()
+}
+
+// CreateLink 创建用户 OIDC 身份关联
+func (o *OidcDao) CreateLink(ctx context.Context, q *query.Query, link *model.SysUserOidc) (*model.SysUserOidc, error) {
+	if link == nil || link.UserID <= 0 {
+		return nil, errors.New("用户id不存在")
+	}
+	if err := q.WithContext(ctx).SysUserOidc.Create(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// UpdateLastLoginAt 更新最后登录时间
+func (o *OidcDao) UpdateLastLoginAt(ctx context.Context, linkId int32, loginAt time.Time) error {
+	if linkId <= 0 {
+		return errors.New("关联id不存在")
+	}
+	m := o.repo.WriteQuery().SysUserOidc
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(linkId)).UpdateSimple(m.LastLoginAt.Value(loginAt))
+	return err
+}
+
+// GetUsersByEmail 根据邮箱查询用户，最多返回2条用于判断是否唯一
+func (o *OidcDao) GetUsersByEmail(ctx context.Context, email string) ([]*model.SysUser, error) {
+	if email == "" {
+		return nil, errors.New("邮箱不能为空")
+	}
+	m := o.repo.WriteQuery().SysUser
+	return m.WithContext(ctx).Where(m.Email.Eq(email)).Limit(2).Find()
+}
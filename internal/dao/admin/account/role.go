@@ -5,6 +5,7 @@ import (
 	"errors"
 	"gorm.io/gen"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"snowgo/internal/constant"
 	"snowgo/internal/dal/model"
 	"snowgo/internal/dal/query"
@@ -173,6 +174,38 @@ func (r *RoleDao) DeleteRoleMenu(ctx context.Context, q *query.Query, roleId int
 	return nil
 }
 
+// CreateRoleDept 创建角色与自定义数据范围部门关联关系
+func (r *RoleDao) CreateRoleDept(ctx context.Context, q *query.Query, roleDeptList []*model.SysRoleDept) error {
+	err := q.WithContext(ctx).SysRoleDept.CreateInBatches(roleDeptList, 1000)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteRoleDept 删除角色与自定义数据范围部门关联关系
+func (r *RoleDao) DeleteRoleDept(ctx context.Context, q *query.Query, roleId int32) error {
+	_, err := q.WithContext(ctx).SysRoleDept.Where(q.SysRoleDept.RoleID.Eq(roleId)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDeptIdsByRoleIds 批量获取角色自定义数据范围的部门id
+func (r *RoleDao) GetDeptIdsByRoleIds(ctx context.Context, roleIds []int32) ([]int32, error) {
+	if len(roleIds) == 0 {
+		return nil, nil
+	}
+	m := r.repo.Query().SysRoleDept
+	deptIds := make([]int32, 0, 10)
+	err := m.WithContext(ctx).Distinct(m.DeptID).Where(m.RoleID.In(roleIds...)).Pluck(m.DeptID, &deptIds)
+	if err != nil {
+		return nil, err
+	}
+	return deptIds, nil
+}
+
 // IsUsedUserByIds 判断角色是否被使用过
 func (r *RoleDao) IsUsedUserByIds(ctx context.Context, q *query.Query, roleId int32) (bool, error) {
 	m := q.SysUserRole
@@ -219,14 +252,17 @@ func (r *RoleDao) GetMenuPermsByRoleId(ctx context.Context, roleId int32) ([]str
 	return menuPermsList, nil
 }
 
-// GetMenuListByRoleId 根据roleId 获取关联的菜单
-func (r *RoleDao) GetMenuListByRoleId(ctx context.Context, roleId int32) ([]*model.SysMenu, error) {
+// GetMenuListByRoleIds 根据roleIds 获取关联的菜单，多个角色关联同一菜单时会重复返回
+func (r *RoleDao) GetMenuListByRoleIds(ctx context.Context, roleIds []int32) ([]*model.SysMenu, error) {
+	if len(roleIds) == 0 {
+		return nil, nil
+	}
 	m := r.repo.Query().SysRoleMenu
 	menu := r.repo.Query().SysMenu
 	menuList := make([]*model.SysMenu, 0, 10)
 	err := m.WithContext(ctx).
 		Join(menu, m.MenuID.EqCol(menu.ID)).
-		Where(m.RoleID.Eq(roleId)).
+		Where(m.RoleID.In(roleIds...)).
 		Select(menu.ALL).
 		Scan(&menuList)
 	if err != nil {
@@ -243,6 +279,7 @@ func (r *RoleDao) IsSuperAdmin(ctx context.Context, q *query.Query, userId int32
 	ur := q.SysUserRole
 	_, err := ur.WithContext(ctx).
 		Where(ur.UserID.Eq(userId), ur.RoleID.Eq(constant.SuperAdminRoleId)).
+		Where(userRoleActiveConds(q, time.Now())...).
 		Select(ur.ID).
 		First()
 	if err != nil {
@@ -254,22 +291,81 @@ func (r *RoleDao) IsSuperAdmin(ctx context.Context, q *query.Query, userId int32
 	return true, nil
 }
 
-// GetUserMenuIds 获取用户所有角色关联的菜单ID集合
-func (r *RoleDao) GetUserMenuIds(ctx context.Context, q *query.Query, userId int32) ([]int32, error) {
+// GetRoleIdsByUserId 获取用户直接关联且当前生效的角色ID
+func (r *RoleDao) GetRoleIdsByUserId(ctx context.Context, q *query.Query, userId int32) ([]int32, error) {
 	ur := q.SysUserRole
-	rm := q.SysRoleMenu
-	menuIds := make([]int32, 0, 20)
+	roleIds := make([]int32, 0, 5)
 	err := ur.WithContext(ctx).
-		Join(rm, ur.RoleID.EqCol(rm.RoleID)).
 		Where(ur.UserID.Eq(userId)).
-		Select(rm.MenuID).
-		Pluck(rm.MenuID, &menuIds)
+		Where(userRoleActiveConds(q, time.Now())...).
+		Pluck(ur.RoleID, &roleIds)
+	if err != nil {
+		return nil, err
+	}
+	return roleIds, nil
+}
+
+// GetMenuIdsByRoleIds 获取多个角色关联的菜单ID集合
+func (r *RoleDao) GetMenuIdsByRoleIds(ctx context.Context, q *query.Query, roleIds []int32) ([]int32, error) {
+	if len(roleIds) == 0 {
+		return nil, nil
+	}
+	rm := q.SysRoleMenu
+	menuIds := make([]int32, 0, 20)
+	err := rm.WithContext(ctx).Distinct(rm.MenuID).Where(rm.RoleID.In(roleIds...)).Pluck(rm.MenuID, &menuIds)
 	if err != nil {
 		return nil, err
 	}
 	return menuIds, nil
 }
 
+// CountRoleByIds 根据角色ids，获取数量
+func (r *RoleDao) CountRoleByIds(ctx context.Context, q *query.Query, ids []int32) (int64, error) {
+	m := q.SysRole
+	return m.WithContext(ctx).Where(m.ID.In(ids...)).Count()
+}
+
+// CreateRoleInherit 创建角色继承关系
+func (r *RoleDao) CreateRoleInherit(ctx context.Context, q *query.Query, inheritList []*model.SysRoleInherit) error {
+	err := q.WithContext(ctx).SysRoleInherit.CreateInBatches(inheritList, 1000)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteRoleInherit 删除角色继承的父角色关系
+func (r *RoleDao) DeleteRoleInherit(ctx context.Context, q *query.Query, roleId int32) error {
+	_, err := q.WithContext(ctx).SysRoleInherit.Where(q.SysRoleInherit.RoleID.Eq(roleId)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsInherited 判断角色是否被其他角色继承
+func (r *RoleDao) IsInherited(ctx context.Context, q *query.Query, roleId int32) (bool, error) {
+	m := q.SysRoleInherit
+	_, err := m.WithContext(ctx).Select(m.ID).Where(m.ParentID.Eq(roleId)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// GetAllRoleInherits 获取全部角色继承关系（不分页，角色数量有限）
+func (r *RoleDao) GetAllRoleInherits(ctx context.Context) ([]*model.SysRoleInherit, error) {
+	return r.repo.Query().WithContext(ctx).SysRoleInherit.Find()
+}
+
+// LockRoleInherits 事务内加锁读取全部角色继承关系，串行化并发的继承关系修改，避免各自校验通过后形成循环
+func (r *RoleDao) LockRoleInherits(ctx context.Context, q *query.Query) ([]*model.SysRoleInherit, error) {
+	return q.WithContext(ctx).SysRoleInherit.Clauses(clause.Locking{Strength: "UPDATE"}).Find()
+}
+
 // GetMenuPermsByRoleIds 批量获取多个角色的菜单 perms 列表
 func (r *RoleDao) GetMenuPermsByRoleIds(ctx context.Context, roleIds []int32) ([]string, error) {
 	if len(roleIds) == 0 {
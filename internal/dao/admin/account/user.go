@@ -6,9 +6,13 @@ import (
 	"gorm.io/gen"
 	"gorm.io/gen/field"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"snowgo/internal/constant"
 	"snowgo/internal/dal/model"
 	"snowgo/internal/dal/query"
 	"snowgo/internal/dal/repo"
+	"snowgo/internal/dao"
+	"time"
 )
 
 // UserDao UserRepo接口实现
@@ -28,8 +32,12 @@ type UserListCondition struct {
 	Tel      string  `json:"tel"`
 	Nickname string  `json:"nickname"`
 	Status   *int8   `json:"status"`
+	UserType *int8   `json:"user_type"`
+	DeptIds  []int32 `json:"dept_ids"`
 	Offset   int32   `json:"offset"`
 	Limit    int32   `json:"limit"`
+
+	DataScope *dao.DataScope `json:"-"` // 数据范围，nil 不限制
 }
 
 // CreateUser 创建用户
@@ -50,7 +58,9 @@ func (u *UserDao) UpdateUser(ctx context.Context, q *query.Query, user *model.Sy
 	m := q.WithContext(ctx).SysUser.Where(q.SysUser.ID.Eq(user.ID))
 	clauses := []field.AssignExpr{
 		q.SysUser.Username.Value(user.Username),
-		q.SysUser.Tel.Value(user.Tel),
+	}
+	if user.Tel != nil {
+		clauses = append(clauses, q.SysUser.Tel.Value(*user.Tel))
 	}
 	if user.Nickname != nil {
 		clauses = append(clauses, q.SysUser.Nickname.Value(*user.Nickname))
@@ -64,6 +74,14 @@ func (u *UserDao) UpdateUser(ctx context.Context, q *query.Query, user *model.Sy
 	if user.Status != nil {
 		clauses = append(clauses, q.SysUser.Status.Value(*user.Status))
 	}
+	if user.DeptID != nil {
+		// 部门id为0表示清空所属部门
+		if *user.DeptID == 0 {
+			clauses = append(clauses, q.SysUser.DeptID.Null())
+		} else {
+			clauses = append(clauses, q.SysUser.DeptID.Value(*user.DeptID))
+		}
+	}
 	if user.UpdatedBy != nil {
 		clauses = append(clauses, q.SysUser.UpdatedBy.Value(*user.UpdatedBy))
 	}
@@ -101,6 +119,70 @@ func (u *UserDao) DeleteUserRole(ctx context.Context, q *query.Query, userId int
 	return nil
 }
 
+// DeleteUserRoleByIds 按关联id删除用户角色授权，用于清理过期授权
+func (u *UserDao) DeleteUserRoleByIds(ctx context.Context, q *query.Query, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := q.WithContext(ctx).SysUserRole.Where(q.SysUserRole.ID.In(ids...)).Delete()
+	return err
+}
+
+// GetExpiredUserRoles 获取 now 时刻已失效的用户角色授权，按id升序最多返回 limit 条
+func (u *UserDao) GetExpiredUserRoles(ctx context.Context, q *query.Query, now time.Time, limit int) ([]*model.SysUserRole, error) {
+	m := q.SysUserRole
+	return m.WithContext(ctx).
+		Where(m.ValidUntil.IsNotNull(), m.ValidUntil.Lte(now)).
+		Order(m.ID).
+		Limit(limit).
+		Find()
+}
+
+// userRoleActiveConds 用户角色授权在 now 时刻生效：已到生效时间且未到失效时间
+func userRoleActiveConds(q *query.Query, now time.Time) []gen.Condition {
+	m := q.SysUserRole
+	return []gen.Condition{
+		field.Or(m.ValidFrom.IsNull(), m.ValidFrom.Lte(now)),
+		field.Or(m.ValidUntil.IsNull(), m.ValidUntil.Gt(now)),
+	}
+}
+
+// DeleteUserMfa 删除用户两步验证信息
+func (u *UserDao) DeleteUserMfa(ctx context.Context, q *query.Query, userId int32) error {
+	_, err := q.WithContext(ctx).SysUserMfa.Where(q.SysUserMfa.UserID.Eq(userId)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUserOidc 删除用户 OIDC 身份关联
+func (u *UserDao) DeleteUserOidc(ctx context.Context, q *query.Query, userId int32) error {
+	_, err := q.WithContext(ctx).SysUserOidc.Where(q.SysUserOidc.UserID.Eq(userId)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUserPwdHistory 删除用户历史密码
+func (u *UserDao) DeleteUserPwdHistory(ctx context.Context, q *query.Query, userId int32) error {
+	_, err := q.WithContext(ctx).SysUserPwdHistory.Where(q.SysUserPwdHistory.UserID.Eq(userId)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUserApiKeys 删除服务账号的全部 API Key
+func (u *UserDao) DeleteUserApiKeys(ctx context.Context, q *query.Query, userId int32) error {
+	_, err := q.WithContext(ctx).SysAPIKey.Where(q.SysAPIKey.UserID.Eq(userId)).Delete()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // DeleteById 删除用户by id（硬删除）
 func (u *UserDao) DeleteById(ctx context.Context, q *query.Query, userId int32) error {
 	if userId <= 0 {
@@ -113,22 +195,59 @@ func (u *UserDao) DeleteById(ctx context.Context, q *query.Query, userId int32)
 	return nil
 }
 
+// GetRoleListByUserId 获取用户当前生效的角色
 func (u *UserDao) GetRoleListByUserId(ctx context.Context, userId int32) ([]*model.SysRole, error) {
 	if userId <= 0 {
 		return nil, errors.New("用户id不存在")
 	}
-	m := u.repo.Query().SysUserRole
-	role := u.repo.Query().SysRole
+	q := u.repo.Query()
+	m := q.SysUserRole
+	role := q.SysRole
 
 	var userRoles []*model.SysRole
 	err := m.WithContext(ctx).
 		Where(m.UserID.Eq(userId)).
+		Where(userRoleActiveConds(q, time.Now())...).
 		LeftJoin(role, m.RoleID.EqCol(role.ID)).
 		Select(role.ALL).
 		Scan(&userRoles)
 	return userRoles, err
 }
 
+// UserRoleGrant 用户角色授权及其有效期
+type UserRoleGrant struct {
+	model.SysRole
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+}
+
+// GetRoleGrantListByUserId 获取用户全部角色授权（含未生效、已过期的限时授权），用于展示
+func (u *UserDao) GetRoleGrantListByUserId(ctx context.Context, userId int32) ([]*UserRoleGrant, error) {
+	if userId <= 0 {
+		return nil, errors.New("用户id不存在")
+	}
+	m := u.repo.Query().SysUserRole
+	role := u.repo.Query().SysRole
+
+	var grants []*UserRoleGrant
+	err := m.WithContext(ctx).
+		Where(m.UserID.Eq(userId)).
+		Join(role, m.RoleID.EqCol(role.ID)).
+		Select(role.ALL, m.ValidFrom, m.ValidUntil).
+		Order(m.ID).
+		Scan(&grants)
+	return grants, err
+}
+
+// GetUserRoleGrants 获取用户全部角色授权（含未生效、已过期的限时授权）
+func (u *UserDao) GetUserRoleGrants(ctx context.Context, userId int32) ([]*model.SysUserRole, error) {
+	if userId <= 0 {
+		return nil, errors.New("用户 id 不合法")
+	}
+	m := u.repo.Query().SysUserRole
+	return m.WithContext(ctx).Where(m.UserID.Eq(userId)).Order(m.ID).Find()
+}
+
 // GetRoleIdsByUserId 只返回 roleId，如果没找到记录则返回 0
 func (u *UserDao) GetRoleIdsByUserId(ctx context.Context, userId int32) ([]int32, error) {
 	var roleIds []int32
@@ -144,12 +263,16 @@ func (u *UserDao) GetRoleIdsByUserId(ctx context.Context, userId int32) ([]int32
 	return roleIds, nil
 }
 
-// IsNameTelDuplicate 用户名或者电话是否存在,如果有用户id应该排除
+// IsNameTelDuplicate 用户名或者电话是否存在,如果有用户id应该排除；服务账号电话为空时只校验用户名
 func (u *UserDao) IsNameTelDuplicate(ctx context.Context, username, tel string, userId int32) (bool, error) {
 	m := u.repo.Query().SysUser
+	cond := m.WithContext(ctx).Or(m.Username.Eq(username))
+	if tel != "" {
+		cond = cond.Or(m.Tel.Eq(tel))
+	}
 	userQuery := m.WithContext(ctx).
 		Select(m.ID).
-		Where(m.WithContext(ctx).Or(m.Username.Eq(username)).Or(m.Tel.Eq(tel)))
+		Where(cond)
 	if userId > 0 {
 		userQuery = userQuery.Where(m.ID.Neq(userId))
 	}
@@ -185,6 +308,16 @@ func (u *UserDao) CountRoleByIds(ctx context.Context, q *query.Query, roleIds []
 	return m.WithContext(ctx).Select(m.ID).Where(m.ID.In(roleIds...)).Count()
 }
 
+// IsDeptExists 部门是否存在
+func (u *UserDao) IsDeptExists(ctx context.Context, q *query.Query, deptId int32) (bool, error) {
+	m := q.SysDept
+	count, err := m.WithContext(ctx).Where(m.ID.Eq(deptId)).Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // GetUserById 查询用户by id
 func (u *UserDao) GetUserById(ctx context.Context, userId int32) (*model.SysUser, error) {
 	if userId <= 0 {
@@ -211,6 +344,47 @@ func (u *UserDao) GetUserByUsername(ctx context.Context, q *query.Query, usernam
 	return user, nil
 }
 
+// LockUserByUsername 事务内加行锁按用户名查询用户
+func (u *UserDao) LockUserByUsername(ctx context.Context, q *query.Query, username string) (*model.SysUser, error) {
+	if len(username) <= 0 {
+		return nil, errors.New("用户username不存在")
+	}
+	m := q.SysUser
+	return m.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where(m.Username.Eq(username)).First()
+}
+
+// SaveUserLock 更新用户登录锁定状态，until 为空时清空锁定截止时间
+func (u *UserDao) SaveUserLock(ctx context.Context, q *query.Query, userId int32, status int8, count int32, until *time.Time) error {
+	if userId <= 0 {
+		return errors.New("用户id不存在")
+	}
+	m := q.SysUser
+	lockedUntil := m.LockedUntil.Null()
+	if until != nil {
+		lockedUntil = m.LockedUntil.Value(*until)
+	}
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(userId)).UpdateSimple(
+		m.LockStatus.Value(status),
+		m.LockCount.Value(count),
+		lockedUntil,
+	)
+	return err
+}
+
+// ClearUserLock 清除用户登录锁定状态与连续锁定次数，未锁定时不更新
+func (u *UserDao) ClearUserLock(ctx context.Context, q *query.Query, userId int32) error {
+	if userId <= 0 {
+		return errors.New("用户id不存在")
+	}
+	m := q.SysUser
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(userId), field.Or(m.LockStatus.Neq(constant.UserLockNone), m.LockCount.Neq(0))).UpdateSimple(
+		m.LockStatus.Value(constant.UserLockNone),
+		m.LockCount.Value(0),
+		m.LockedUntil.Null(),
+	)
+	return err
+}
+
 // GetUserList 获取用户列表
 func (u *UserDao) GetUserList(ctx context.Context, condition *UserListCondition) ([]*model.SysUser, int64, error) {
 	m := u.repo.Query().SysUser
@@ -220,7 +394,10 @@ func (u *UserDao) GetUserList(ctx context.Context, condition *UserListCondition)
 			u.UserNameScope(condition.Username),
 			u.TelScope(condition.Tel),
 			u.StatusScope(condition.Status),
+			u.UserTypeScope(condition.UserType),
+			u.DeptIdsScope(condition.DeptIds),
 			u.NickNameScope(condition.Nickname),
+			u.DataScopeScope(condition.DataScope),
 		).
 		FindByPage(int(condition.Offset), int(condition.Limit))
 	if err != nil {
@@ -240,6 +417,22 @@ func (u *UserDao) UserIdsScope(userIds []int32) func(tx gen.Dao) gen.Dao {
 	}
 }
 
+// DataScopeScope 按数据范围过滤：本人创建的用户、本人、可见部门下的用户
+func (u *UserDao) DataScopeScope(scope *dao.DataScope) func(tx gen.Dao) gen.Dao {
+	return func(tx gen.Dao) gen.Dao {
+		if scope.IsAll() {
+			return tx
+		}
+		m := u.repo.Query().SysUser
+		conds := []field.Expr{m.CreatedBy.Eq(scope.UserId), m.ID.Eq(scope.UserId)}
+		if len(scope.DeptIds) > 0 {
+			conds = append(conds, m.DeptID.In(scope.DeptIds...))
+		}
+		tx = tx.Where(field.Or(conds...))
+		return tx
+	}
+}
+
 func (u *UserDao) UserNameScope(username string) func(tx gen.Dao) gen.Dao {
 	return func(tx gen.Dao) gen.Dao {
 		if len(username) == 0 {
@@ -273,6 +466,28 @@ func (u *UserDao) StatusScope(status *int8) func(tx gen.Dao) gen.Dao {
 	}
 }
 
+func (u *UserDao) UserTypeScope(userType *int8) func(tx gen.Dao) gen.Dao {
+	return func(tx gen.Dao) gen.Dao {
+		if userType == nil {
+			return tx
+		}
+		m := u.repo.Query().SysUser
+		tx = tx.Where(m.UserType.Eq(*userType))
+		return tx
+	}
+}
+
+func (u *UserDao) DeptIdsScope(deptIds []int32) func(tx gen.Dao) gen.Dao {
+	return func(tx gen.Dao) gen.Dao {
+		if len(deptIds) == 0 {
+			return tx
+		}
+		m := u.repo.Query().SysUser
+		tx = tx.Where(m.DeptID.In(deptIds...))
+		return tx
+	}
+}
+
 func (u *UserDao) NickNameScope(nickname string) func(tx gen.Dao) gen.Dao {
 	return func(tx gen.Dao) gen.Dao {
 		if len(nickname) == 0 {
@@ -284,14 +499,41 @@ func (u *UserDao) NickNameScope(nickname string) func(tx gen.Dao) gen.Dao {
 	}
 }
 
-// ResetPwdById 重置密码
-func (u *UserDao) ResetPwdById(ctx context.Context, q *query.Query, userId int32, password string) error {
+// ResetPwdById 重置密码，同时记录修改时间与下次登录是否必须修改密码
+func (u *UserDao) ResetPwdById(ctx context.Context, q *query.Query, userId int32, password string, mustChange int8) error {
 	if userId <= 0 {
 		return errors.New("用户id不存在")
 	}
-	_, err := q.WithContext(ctx).SysUser.Where(q.SysUser.ID.Eq(userId)).UpdateSimple(q.SysUser.Password.Value(password))
+	_, err := q.WithContext(ctx).SysUser.Where(q.SysUser.ID.Eq(userId)).UpdateSimple(
+		q.SysUser.Password.Value(password),
+		q.SysUser.PwdChangedAt.Value(time.Now()),
+		q.SysUser.PwdMustChange.Value(mustChange),
+	)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// GetPwdHistory 查询用户最近 limit 条历史密码，按时间倒序
+func (u *UserDao) GetPwdHistory(ctx context.Context, q *query.Query, userId int32, limit int) ([]*model.SysUserPwdHistory, error) {
+	m := q.SysUserPwdHistory
+	return m.WithContext(ctx).Where(m.UserID.Eq(userId)).Order(m.ID.Desc()).Limit(limit).Find()
+}
+
+// CreatePwdHistory 记录历史密码，并只保留最近 keep 条
+func (u *UserDao) CreatePwdHistory(ctx context.Context, q *query.Query, history *model.SysUserPwdHistory, keep int) error {
+	m := q.SysUserPwdHistory
+	if err := m.WithContext(ctx).Create(history); err != nil {
+		return err
+	}
+	kept, err := u.GetPwdHistory(ctx, q, history.UserID, keep)
+	if err != nil {
+		return err
+	}
+	if len(kept) < keep {
+		return nil
+	}
+	_, err = m.WithContext(ctx).Where(m.UserID.Eq(history.UserID), m.ID.Lt(kept[len(kept)-1].ID)).Delete()
+	return err
+}
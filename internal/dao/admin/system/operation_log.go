@@ -3,9 +3,11 @@ package system
 import (
 	"context"
 	"gorm.io/gen"
+	"gorm.io/gen/field"
 	"snowgo/internal/dal/model"
 	"snowgo/internal/dal/query"
 	"snowgo/internal/dal/repo"
+	"snowgo/internal/dao"
 	"time"
 )
 
@@ -30,6 +32,8 @@ type OperationLogCondition struct {
 	EndTime      *time.Time `json:"end_time" form:"end_time"`
 	Offset       int32      `json:"offset" form:"offset"`
 	Limit        int32      `json:"limit" form:"limit"`
+
+	DataScope *dao.DataScope `json:"-" form:"-"` // 数据范围，nil 不限制
 }
 
 // Create 创建操作日志
@@ -53,6 +57,7 @@ func (o *OperationLogDao) GetOperationLogList(ctx context.Context, condition *Op
 			o.ActionScope(condition.Action),
 			o.StartTimeScope(condition.StartTime),
 			o.EndTimeScope(condition.EndTime),
+			o.DataScopeScope(condition.DataScope),
 		).
 		Order(m.ID.Desc()).
 		FindByPage(int(condition.Offset), int(condition.Limit))
@@ -138,3 +143,22 @@ func (o *OperationLogDao) EndTimeScope(endTime *time.Time) func(tx gen.Dao) gen.
 		return tx
 	}
 }
+
+// DataScopeScope 按数据范围过滤：本人的操作、可见部门下用户的操作
+func (o *OperationLogDao) DataScopeScope(scope *dao.DataScope) func(tx gen.Dao) gen.Dao {
+	return func(tx gen.Dao) gen.Dao {
+		if scope.IsAll() {
+			return tx
+		}
+		m := o.repo.Query().SysOperationLog
+		if len(scope.DeptIds) == 0 {
+			tx = tx.Where(m.OperatorID.Eq(scope.UserId))
+			return tx
+		}
+		// 子查询只参与 SQL 拼接，执行时使用外层查询的 ctx
+		u := o.repo.Query().SysUser
+		deptUsers := u.WithContext(context.Background()).Select(u.ID).Where(u.DeptID.In(scope.DeptIds...))
+		tx = tx.Where(field.Or(m.OperatorID.Eq(scope.UserId), gen.Columns{m.OperatorID}.In(deptUsers)))
+		return tx
+	}
+}
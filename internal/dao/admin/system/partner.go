@@ -0,0 +1,154 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+)
+
+// PartnerDao 开放接口合作方凭证
+type PartnerDao struct {
+	repo *repo.Repository
+}
+
+func NewPartnerDao(repo *repo.Repository) *PartnerDao {
+	return &PartnerDao{
+		repo: repo,
+	}
+}
+
+type PartnerListCondition struct {
+	Name   string `json:"name" form:"name"`
+	Status int8   `json:"status" form:"status"`
+	Offset int32  `json:"offset" form:"offset"`
+	Limit  int32  `json:"limit" form:"limit"`
+}
+
+// GetPartnerById 查询合作方 by id
+func (p *PartnerDao) GetPartnerById(ctx context.Context, q *query.Query, partnerId int32) (*model.SysPartnerCredential, error) {
+	if partnerId <= 0 {
+		return nil, errors.New("合作方id不存在")
+	}
+	m := q.SysPartnerCredential
+	return m.WithContext(ctx).Where(m.ID.Eq(partnerId)).First()
+}
+
+// GetPartnerByAccessKey 根据访问密钥ID查询合作方，验签使用，读取主库避免刚创建的凭证查不到
+func (p *PartnerDao) GetPartnerByAccessKey(ctx context.Context, accessKey string) (*model.SysPartnerCredential, error) {
+	if accessKey == "" {
+		return nil, errors.New("访问密钥ID不能为空")
+	}
+	m := p.repo.WriteQuery().SysPartnerCredential
+	return m.WithContext(ctx).Where(m.AccessKey.Eq(accessKey)).First()
+}
+
+// GetPartnerList 合作方列表
+func (p *PartnerDao) GetPartnerList(ctx context.Context, condition *PartnerListCondition) ([]*model.SysPartnerCredential, int64, error) {
+	m := p.repo.Query().SysPartnerCredential
+	return m.WithContext(ctx).
+		Scopes(
+			p.NameScope(condition.Name),
+			p.StatusScope(condition.Status),
+		).
+		Order(m.ID.Desc()).
+		FindByPage(int(condition.Offset), int(condition.Limit))
+}
+
+func (p *PartnerDao) NameScope(name string) func(tx gen.Dao) gen.Dao {
+	return func(tx gen.Dao) gen.Dao {
+		if len(name) == 0 {
+			return tx
+		}
+		m := p.repo.Query().SysPartnerCredential
+		return tx.Where(m.Name.Like("%" + name + "%"))
+	}
+}
+
+func (p *PartnerDao) StatusScope(status int8) func(tx gen.Dao) gen.Dao {
+	return func(tx gen.Dao) gen.Dao {
+		if status <= 0 {
+			return tx
+		}
+		m := p.repo.Query().SysPartnerCredential
+		return tx.Where(m.Status.Eq(status))
+	}
+}
+
+// IsNameDuplicate 合作方名称是否重复，partnerId 大于 0 时排除自身
+func (p *PartnerDao) IsNameDuplicate(ctx context.Context, name string, partnerId int32) (bool, error) {
+	m := p.repo.Query().SysPartnerCredential
+	partnerQuery := m.WithContext(ctx).
+		Select(m.ID).
+		Where(m.Name.Eq(name))
+	if partnerId > 0 {
+		partnerQuery = partnerQuery.Where(m.ID.Neq(partnerId))
+	}
+	_, err := partnerQuery.First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// CreatePartner 创建合作方
+func (p *PartnerDao) CreatePartner(ctx context.Context, q *query.Query, partner *model.SysPartnerCredential) (*model.SysPartnerCredential, error) {
+	if partner == nil {
+		return nil, errors.New("合作方信息不能为空")
+	}
+	if err := q.WithContext(ctx).SysPartnerCredential.Create(partner); err != nil {
+		return nil, err
+	}
+	return partner, nil
+}
+
+// UpdatePartner 更新合作方名称、状态、备注
+func (p *PartnerDao) UpdatePartner(ctx context.Context, q *query.Query, partner *model.SysPartnerCredential) error {
+	if partner.ID <= 0 {
+		return errors.New("合作方id不存在")
+	}
+	m := q.SysPartnerCredential
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(partner.ID)).
+		Select(m.Name, m.Status, m.Remark, m.UpdatedBy).
+		Updates(partner)
+	return err
+}
+
+// UpdatePartnerSecret 更换合作方签名密钥
+func (p *PartnerDao) UpdatePartnerSecret(ctx context.Context, q *query.Query, partnerId int32, secret string, updatedBy int32) error {
+	if partnerId <= 0 {
+		return errors.New("合作方id不存在")
+	}
+	m := q.SysPartnerCredential
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(partnerId)).
+		UpdateSimple(m.Secret.Value(secret), m.UpdatedBy.Value(updatedBy))
+	return err
+}
+
+// DeletePartner 删除合作方
+func (p *PartnerDao) DeletePartner(ctx context.Context, q *query.Query, partnerId int32) error {
+	if partnerId <= 0 {
+		return errors.New("合作方id不存在")
+	}
+	m := q.SysPartnerCredential
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(partnerId)).Delete()
+	return err
+}
+
+// UpdateLastUsedAt 更新合作方最后调用时间
+func (p *PartnerDao) UpdateLastUsedAt(ctx context.Context, partnerId int32, usedAt time.Time) error {
+	if partnerId <= 0 {
+		return errors.New("合作方id不存在")
+	}
+	m := p.repo.WriteQuery().SysPartnerCredential
+	_, err := m.WithContext(ctx).Where(m.ID.Eq(partnerId)).UpdateSimple(m.LastUsedAt.Value(usedAt))
+	return err
+}
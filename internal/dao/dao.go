@@ -1,3 +1,15 @@
 package dao
 
 // 零值更新问题，model字段用指针、updates里面用map等方式解决
+
+// DataScope 行级数据范围，由当前用户角色解析得到，供各列表查询拼接条件
+type DataScope struct {
+	All     bool    // 全部数据，不做限制
+	UserId  int32   // 当前用户，本人创建的数据始终可见
+	DeptIds []int32 // 可见的部门
+}
+
+// IsAll nil 表示内部调用，不做限制
+func (d *DataScope) IsAll() bool {
+	return d == nil || d.All
+}
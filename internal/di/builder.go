@@ -14,6 +14,7 @@ type Option func(*containerOptions)
 
 type containerOptions struct {
 	jwtCfg       *config.JwtConfig
+	authCfg      *config.AuthConfig
 	mysqlCfg     *config.MysqlConfig
 	otherDBCfg   *config.OtherDBConfig
 	redisCfg     *config.RedisConfig
@@ -31,6 +32,10 @@ func WithJWT(c config.JwtConfig) Option {
 	return func(o *containerOptions) { o.jwtCfg = &c }
 }
 
+func WithAuth(c config.AuthConfig) Option {
+	return func(o *containerOptions) { o.authCfg = &c }
+}
+
 func WithMySQL(mysqlCfg config.MysqlConfig, otherCfg config.OtherDBConfig) Option {
 	return func(o *containerOptions) {
 		o.mysqlCfg = &mysqlCfg
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -18,7 +20,11 @@ import (
 	systemDao "snowgo/internal/dao/admin/system"
 	accountService "snowgo/internal/service/admin/account"
 	systemService "snowgo/internal/service/admin/system"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/captcha"
 	"snowgo/pkg/xauth/jwt"
+	"snowgo/pkg/xauth/oidc"
+	"snowgo/pkg/xauth/session"
 	"snowgo/pkg/xcache"
 	"snowgo/pkg/xdatabase/mysql"
 	xredis "snowgo/pkg/xdatabase/redis"
@@ -34,10 +40,12 @@ type Container struct {
 		MyDB *mysql.MyDB
 		RDB  *redis.Client
 	}
-	Cache      xcache.Cache
-	JwtManager *jwt.Manager
-	Lock       xlock.Lock
-	Producer   xmq.Producer
+	Cache         xcache.Cache
+	JwtManager    *jwt.Manager
+	TokenStrategy xauth.TokenStrategy      // 登录令牌策略，按 auth.token.strategy 选择 JWT 或不透明会话
+	TokenCookie   *session.CookieTransport // 令牌 Cookie 传输，仅不透明会话策略下不为 nil
+	Lock          xlock.Lock
+	Producer      xmq.Producer
 
 	// 这里只提供对api使用的service，不提供dao操作
 	AccountContainer
@@ -51,34 +59,265 @@ type Container struct {
 }
 
 type AccountContainer struct {
-	UserService *accountService.UserService
-	MenuService *accountService.MenuService
-	RoleService *accountService.RoleService
+	UserService       *accountService.UserService
+	MenuService       *accountService.MenuService
+	DeptService       *accountService.DeptService
+	RoleService       *accountService.RoleService
+	RevocationService *accountService.RevocationService
+	SessionService    *accountService.SessionService
+	MfaService        *accountService.MfaService
+	ApiKeyService     *accountService.ApiKeyService
+	OidcService       *accountService.OidcService
+	CaptchaService    *accountService.CaptchaService
 }
 
 type SystemContainer struct {
 	OperationLogService *systemService.OperationLogService
 	DictService         *systemService.DictService
 	LoginLogService     *systemService.LoginLogService
+	PartnerService      *systemService.PartnerService
 }
 
 // BuildJwtManager 构建jwt操作
 func BuildJwtManager(config config.JwtConfig) (*jwt.Manager, error) {
-	if len(config.JwtSecret) == 0 ||
+	if (len(config.JwtSecret) == 0 && len(config.SigningKeys) == 0) ||
 		len(config.Issuer) == 0 ||
 		config.AccessExpirationTime == 0 ||
 		config.RefreshExpirationTime == 0 {
 		return nil, errors.New("please initialize jwt config first, jwt config is empty")
 	}
+	signingKeys, err := buildJwtSigningKeys(config.SigningKeys)
+	if err != nil {
+		return nil, err
+	}
 	jwtManager, err := jwt.NewJwtManager(&jwt.Config{
 		JwtSecret:             config.JwtSecret,
 		Issuer:                config.Issuer,
 		AccessExpirationTime:  config.AccessExpirationTime,
 		RefreshExpirationTime: config.RefreshExpirationTime,
+		SigningKeys:           signingKeys,
 	})
 	return jwtManager, err
 }
 
+// buildTokenStrategy 按配置选择登录令牌策略，不透明会话策略同时构造 Cookie 传输
+func buildTokenStrategy(cfg config.TokenConfig, jwtManager *jwt.Manager, cache xcache.Cache) (xauth.TokenStrategy, *session.CookieTransport, error) {
+	switch cfg.Strategy {
+	case "", xauth.TokenStrategyJwt:
+		if jwtManager == nil {
+			return nil, nil, nil
+		}
+		return jwtManager, nil, nil
+	case xauth.TokenStrategySession:
+		manager, err := session.NewManager(cache, session.Config{
+			KeyPrefix:             constant.CacheOpaqueTokenPrefix,
+			IdleTimeout:           cfg.Session.IdleTimeout,
+			AccessMaxLifetime:     cfg.Session.AccessMaxLifetime,
+			RefreshExpirationTime: cfg.Session.RefreshExpirationTime,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sameSite, err := session.ParseSameSite(cfg.Session.CookieSameSite)
+		if err != nil {
+			return nil, nil, err
+		}
+		transport, err := session.NewCookieTransport(session.CookieConfig{
+			Domain:      cfg.Session.CookieDomain,
+			Secure:      cfg.Session.CookieSecure,
+			SameSite:    sameSite,
+			RefreshPath: constant.TokenRefreshCookiePath,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if !cfg.Session.CookieSecure {
+			zap.S().Warn("auth.token.session.cookie_secure is false, session cookies are sent over plain HTTP")
+		}
+		return manager, transport, nil
+	default:
+		return nil, nil, fmt.Errorf("auth.token.strategy must be jwt or session, got %q", cfg.Strategy)
+	}
+}
+
+// buildJwtSigningKeys 读取签名私钥并解析生效时间
+func buildJwtSigningKeys(keys []config.JwtSigningKeyConfig) ([]jwt.SigningKeyConfig, error) {
+	signingKeys := make([]jwt.SigningKeyConfig, 0, len(keys))
+	for _, key := range keys {
+		pemBytes := []byte(key.PrivateKey)
+		if len(pemBytes) == 0 {
+			if key.PrivateKeyFile == "" {
+				return nil, fmt.Errorf("jwt signing key %s: private_key or private_key_file required", key.Kid)
+			}
+			raw, err := os.ReadFile(filepath.Clean(key.PrivateKeyFile))
+			if err != nil {
+				return nil, fmt.Errorf("jwt signing key %s: read private key file err: %w", key.Kid, err)
+			}
+			pemBytes = raw
+		}
+		var activeFrom time.Time
+		if key.ActiveFrom != "" {
+			t, err := time.Parse(time.RFC3339, key.ActiveFrom)
+			if err != nil {
+				return nil, fmt.Errorf("jwt signing key %s: invalid active_from: %w", key.Kid, err)
+			}
+			activeFrom = t
+		}
+		signingKeys = append(signingKeys, jwt.SigningKeyConfig{
+			Kid:           key.Kid,
+			PrivateKeyPEM: pemBytes,
+			ActiveFrom:    activeFrom,
+		})
+	}
+	return signingKeys, nil
+}
+
+// buildOidcProvider 构造 OIDC 客户端，未配置 issuer 时单点登录不可用
+func buildOidcProvider(cfg config.OidcConfig) (accountService.OidcProvider, accountService.OidcConfig, error) {
+	conf := accountService.OidcConfig{
+		AutoProvision: cfg.AutoProvision,
+		DefaultRoleId: cfg.DefaultRoleId,
+		LinkByEmail:   cfg.LinkByEmail,
+		UsernameClaim: cfg.UsernameClaim,
+		StateTTL:      cfg.StateExpirationTime,
+		CookieSecure:  cfg.CookieSecure,
+	}
+	if cfg.Issuer == "" {
+		return nil, conf, nil
+	}
+	switch cfg.UsernameClaim {
+	case "", "preferred_username", "email", "sub":
+	default:
+		return nil, conf, fmt.Errorf("auth.oidc.username_claim must be preferred_username, email or sub, got %s", cfg.UsernameClaim)
+	}
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
+	})
+	if err != nil {
+		return nil, conf, err
+	}
+	return provider, conf, nil
+}
+
+// buildMfaConfig 校验两步验证加密 key，未配置时两步验证不可用
+func buildMfaConfig(cfg config.MfaConfig) (accountService.MfaConfig, error) {
+	if cfg.EncryptKey == "" {
+		zap.S().Warn("auth.mfa.encrypt_key is empty, two-factor authentication is disabled")
+	} else if l := len(cfg.EncryptKey); l != 16 && l != 24 && l != 32 {
+		return accountService.MfaConfig{}, fmt.Errorf("auth.mfa.encrypt_key must be 16, 24 or 32 bytes, got %d", l)
+	}
+	return accountService.MfaConfig{
+		Issuer:       cfg.Issuer,
+		EncryptKey:   cfg.EncryptKey,
+		ChallengeTTL: cfg.ChallengeExpirationTime,
+	}, nil
+}
+
+// buildCaptchaProvider 按配置构造登录验证码，未配置类型时不启用
+func buildCaptchaProvider(cfg config.CaptchaConfig, cache xcache.Cache) (accountService.CaptchaProvider, accountService.CaptchaConfig, error) {
+	var generator captcha.Generator
+	switch cfg.Type {
+	case "":
+		zap.S().Warn("auth.captcha.type is empty, login captcha is disabled")
+		return nil, accountService.CaptchaConfig{}, nil
+	case captcha.TypeImage:
+		generator = captcha.DigitGenerator{Length: cfg.Length}
+	case captcha.TypeArithmetic:
+		generator = captcha.ArithmeticGenerator{}
+	default:
+		return nil, accountService.CaptchaConfig{}, fmt.Errorf("auth.captcha.type must be image or arithmetic, got %q", cfg.Type)
+	}
+	if cfg.Threshold <= 0 || cfg.Threshold >= constant.CacheLoginFailMaxCount {
+		return nil, accountService.CaptchaConfig{}, fmt.Errorf("auth.captcha.threshold must be between 1 and %d, got %d", constant.CacheLoginFailMaxCount-1, cfg.Threshold)
+	}
+	store, err := captcha.NewStore(cache, generator, captcha.Config{
+		KeyPrefix:  constant.CacheCaptchaPrefix,
+		Expiration: cfg.ExpirationTime,
+	})
+	if err != nil {
+		return nil, accountService.CaptchaConfig{}, err
+	}
+	return store, accountService.CaptchaConfig{Threshold: cfg.Threshold}, nil
+}
+
+// buildPartnerConfig 校验开放接口签名密钥加密 key，未配置时签名认证不可用
+func buildPartnerConfig(cfg config.PartnerConfig) (systemService.PartnerConfig, error) {
+	if cfg.EncryptKey == "" {
+		zap.S().Warn("auth.partner.encrypt_key is empty, open api signature authentication is disabled")
+	} else if l := len(cfg.EncryptKey); l != 16 && l != 24 && l != 32 {
+		return systemService.PartnerConfig{}, fmt.Errorf("auth.partner.encrypt_key must be 16, 24 or 32 bytes, got %d", l)
+	} else if cfg.ClockSkew <= 0 {
+		return systemService.PartnerConfig{}, fmt.Errorf("auth.partner.clock_skew must be > 0, got %s", cfg.ClockSkew)
+	}
+	return systemService.PartnerConfig{
+		EncryptKey: cfg.EncryptKey,
+		ClockSkew:  cfg.ClockSkew,
+	}, nil
+}
+
+// buildPasswordPolicy 构造密码策略，配置了禁用密码列表文件时加载列表
+func buildPasswordPolicy(cfg config.PasswordConfig) (accountService.PasswordPolicy, error) {
+	if cfg.HistoryCount < 0 {
+		return accountService.PasswordPolicy{}, fmt.Errorf("auth.password.history_count must be >= 0, got %d", cfg.HistoryCount)
+	}
+	policy := accountService.PasswordPolicy{
+		HistoryCount:            cfg.HistoryCount,
+		MaxAge:                  cfg.MaxAge,
+		ForceChangeOnFirstLogin: cfg.ForceChangeOnFirstLogin,
+		ForceChangeAfterReset:   cfg.ForceChangeAfterReset,
+		ChangeTTL:               cfg.ChangeExpirationTime,
+	}
+	if cfg.BannedListFile != "" {
+		banned, err := accountService.LoadBannedPasswords(cfg.BannedListFile)
+		if err != nil {
+			return accountService.PasswordPolicy{}, err
+		}
+		policy.BannedPasswords = banned
+	}
+	return policy, nil
+}
+
+// buildLockoutPolicy 构造登录失败锁定策略，未配置锁定时长时不锁定账号
+func buildLockoutPolicy(cfg config.LockoutConfig) (accountService.LockoutPolicy, error) {
+	for _, d := range cfg.Durations {
+		if d <= 0 {
+			return accountService.LockoutPolicy{}, fmt.Errorf("auth.lockout.durations must be > 0, got %s", d)
+		}
+	}
+	if cfg.PermanentAfter < 0 {
+		return accountService.LockoutPolicy{}, fmt.Errorf("auth.lockout.permanent_after must be >= 0, got %d", cfg.PermanentAfter)
+	}
+	if cfg.PermanentAfter > 0 && len(cfg.Durations) == 0 {
+		return accountService.LockoutPolicy{}, errors.New("auth.lockout.durations must be set when permanent_after is set")
+	}
+	return accountService.LockoutPolicy{
+		Durations:      cfg.Durations,
+		PermanentAfter: cfg.PermanentAfter,
+	}, nil
+}
+
+// buildPermCache 构造进程内权限缓存，未配置或 size 为 0 时关闭
+func buildPermCache(authCfg *config.AuthConfig, rdb *redis.Client) (*accountService.PermCache, error) {
+	if authCfg == nil || authCfg.PermCache.Size <= 0 {
+		return nil, nil
+	}
+	if authCfg.PermCache.TTL <= 0 {
+		return nil, fmt.Errorf("auth.perm_cache.ttl must be > 0 when size is set, got %s", authCfg.PermCache.TTL)
+	}
+	notifier, err := xcache.NewRedisNotifier(rdb)
+	if err != nil {
+		return nil, err
+	}
+	return accountService.NewPermCache(accountService.PermCacheConfig{
+		Size: authCfg.PermCache.Size,
+		TTL:  authCfg.PermCache.TTL,
+	}, notifier), nil
+}
+
 // BuildRepository 构建db操作
 func BuildRepository(db *gorm.DB, dbMap map[string]*gorm.DB) (*repo.Repository, error) {
 	if db == nil {
@@ -197,30 +436,155 @@ func NewContainer(opts ...Option) (container *Container, err error) {
 	// 构造Dao
 	userDao := accountDao.NewUserDao(repository)
 	menuDao := accountDao.NewMenuDao(repository)
+	deptDao := accountDao.NewDeptDao(repository)
 	roleDao := accountDao.NewRoleDao(repository)
+	mfaDao := accountDao.NewMfaDao(repository)
+	apiKeyDao := accountDao.NewApiKeyDao(repository)
+	oidcDao := accountDao.NewOidcDao(repository)
 	operationLogDao := systemDao.NewOperationLogDao(repository)
 	dictDao := systemDao.NewDictDao(repository)
 	loginLogDao := systemDao.NewLoginLogDao(repository)
+	partnerDao := systemDao.NewPartnerDao(repository)
 
 	// 构造Service依赖
 	operationLogService := systemService.NewOperationLogService(repository, operationLogDao)
 	dictService := systemService.NewDictService(repository, redisCache, dictDao, operationLogService)
 	loginLogService := systemService.NewLoginLogService(repository, loginLogDao)
-	menuService := accountService.NewMenuService(repository, redisCache, menuDao, operationLogService)
-	roleService := accountService.NewRoleService(repository, roleDao, redisCache, operationLogService)
-	userService := accountService.NewUserService(repository, userDao, redisCache, roleService, operationLogService)
+	permCache, err := buildPermCache(opt.authCfg, rdb)
+	if err != nil {
+		return nil, fmt.Errorf("perm cache init err: %w", err)
+	}
+	if permCache != nil {
+		listenCtx, cancelListen := context.WithCancel(context.Background())
+		listenDone := make(chan struct{})
+		go func() {
+			defer close(listenDone)
+			if err := permCache.Listen(listenCtx); err != nil {
+				zap.S().Errorf("perm cache listen err: %v", err)
+			}
+		}()
+		// 先于 redis 关闭（LIFO）
+		container.closeMgr.RegisterFunc(func(ctx context.Context) error {
+			cancelListen()
+			select {
+			case <-listenDone:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+	menuService := accountService.NewMenuService(repository, redisCache, menuDao, operationLogService, permCache)
+	deptService := accountService.NewDeptService(repository, redisCache, deptDao, operationLogService)
+	roleService := accountService.NewRoleService(repository, roleDao, redisCache, operationLogService, permCache)
+	var tokenCfg config.TokenConfig
+	if opt.authCfg != nil {
+		tokenCfg = opt.authCfg.Token
+	}
+	container.TokenStrategy, container.TokenCookie, err = buildTokenStrategy(tokenCfg, container.JwtManager, redisCache)
+	if err != nil {
+		return nil, fmt.Errorf("token strategy init err: %w", err)
+	}
+	var accessTTL, refreshTTL time.Duration
+	if container.TokenStrategy != nil {
+		accessTTL, refreshTTL = container.TokenStrategy.AccessExpiration(), container.TokenStrategy.RefreshExpiration()
+	}
+	revocationService := accountService.NewRevocationService(redisCache, accessTTL, refreshTTL)
+	var pwdPolicy accountService.PasswordPolicy
+	var lockoutPolicy accountService.LockoutPolicy
+	if opt.authCfg != nil {
+		pwdPolicy, err = buildPasswordPolicy(opt.authCfg.Password)
+		if err != nil {
+			return nil, fmt.Errorf("password policy init err: %w", err)
+		}
+		lockoutPolicy, err = buildLockoutPolicy(opt.authCfg.Lockout)
+		if err != nil {
+			return nil, fmt.Errorf("lockout policy init err: %w", err)
+		}
+	}
+	userService := accountService.NewUserService(repository, userDao, redisCache, roleService, deptService, revocationService, operationLogService,
+		accountService.UserServiceOptions{PwdPolicy: pwdPolicy, Lockout: lockoutPolicy, PermCache: permCache})
+	if opt.authCfg != nil && opt.authCfg.RoleGrant.SweepInterval > 0 {
+		sweepCtx, cancelSweep := context.WithCancel(context.Background())
+		sweepDone := make(chan struct{})
+		go func() {
+			defer close(sweepDone)
+			userService.RunRoleGrantSweep(sweepCtx, lock, opt.authCfg.RoleGrant.SweepInterval)
+		}()
+		// 先于 mysql、redis 关闭（LIFO）
+		container.closeMgr.RegisterFunc(func(ctx context.Context) error {
+			cancelSweep()
+			select {
+			case <-sweepDone:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+	var sessionConf accountService.SessionConfig
+	if opt.authCfg != nil {
+		sessionConf = accountService.SessionConfig{
+			MaxPerUser:     opt.authCfg.Session.MaxPerUser,
+			OverflowPolicy: opt.authCfg.Session.OverflowPolicy,
+		}
+	}
+	sessionService := accountService.NewSessionService(repository, redisCache, revocationService, operationLogService, sessionConf, refreshTTL)
+	var mfaConf accountService.MfaConfig
+	if opt.authCfg != nil {
+		mfaConf, err = buildMfaConfig(opt.authCfg.Mfa)
+		if err != nil {
+			return nil, fmt.Errorf("mfa init err: %w", err)
+		}
+	}
+	mfaService := accountService.NewMfaService(repository, mfaDao, redisCache, operationLogService, mfaConf)
+	apiKeyService := accountService.NewApiKeyService(repository, apiKeyDao, userDao, redisCache, operationLogService)
+	var oidcProvider accountService.OidcProvider
+	var oidcConf accountService.OidcConfig
+	if opt.authCfg != nil {
+		oidcProvider, oidcConf, err = buildOidcProvider(opt.authCfg.Oidc)
+		if err != nil {
+			return nil, fmt.Errorf("oidc init err: %w", err)
+		}
+	}
+	oidcService := accountService.NewOidcService(repository, oidcDao, userDao, redisCache, operationLogService, oidcProvider, oidcConf)
+	var captchaProvider accountService.CaptchaProvider
+	var captchaConf accountService.CaptchaConfig
+	if opt.authCfg != nil {
+		captchaProvider, captchaConf, err = buildCaptchaProvider(opt.authCfg.Captcha, redisCache)
+		if err != nil {
+			return nil, fmt.Errorf("captcha init err: %w", err)
+		}
+	}
+	captchaService := accountService.NewCaptchaService(captchaProvider, captchaConf)
+	var partnerConf systemService.PartnerConfig
+	if opt.authCfg != nil {
+		partnerConf, err = buildPartnerConfig(opt.authCfg.Partner)
+		if err != nil {
+			return nil, fmt.Errorf("partner init err: %w", err)
+		}
+	}
+	partnerService := systemService.NewPartnerService(repository, redisCache, partnerDao, operationLogService, partnerConf)
 
 	// account
 	container.AccountContainer = AccountContainer{
-		UserService: userService,
-		MenuService: menuService,
-		RoleService: roleService,
+		UserService:       userService,
+		MenuService:       menuService,
+		DeptService:       deptService,
+		RoleService:       roleService,
+		RevocationService: revocationService,
+		SessionService:    sessionService,
+		MfaService:        mfaService,
+		ApiKeyService:     apiKeyService,
+		OidcService:       oidcService,
+		CaptchaService:    captchaService,
 	}
 	// system
 	container.SystemContainer = SystemContainer{
 		OperationLogService: operationLogService,
 		DictService:         dictService,
 		LoginLogService:     loginLogService,
+		PartnerService:      partnerService,
 	}
 	return container, nil
 }
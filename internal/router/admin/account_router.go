@@ -7,29 +7,57 @@ import (
 	"snowgo/internal/router/middleware"
 )
 
-// 用户相关路由
-func accountRouters(r *gin.RouterGroup) {
+// 用户相关路由，apiKey 分组下的路由允许服务账号通过 X-Api-Key 调用
+func accountRouters(r, apiKey *gin.RouterGroup) {
 	accountGroup := r.Group("/account")
+	apiKeyGroup := apiKey.Group("/account")
 	{
 		// 用户
-		accountGroup.GET("/user", middleware.PermissionAuth(constant.PermAccountUserList), account.GetUserList)
-		accountGroup.POST("/user", middleware.PermissionAuth(constant.PermAccountUserCreate), account.CreateUser)
-		accountGroup.PUT("/user", middleware.PermissionAuth(constant.PermAccountUserUpdate), account.UpdateUser)
+		apiKeyGroup.GET("/user", middleware.PermissionAuth(constant.PermAccountUserList), account.GetUserList)
+		apiKeyGroup.POST("/user", middleware.PermissionAuth(constant.PermAccountUserCreate), account.CreateUser)
+		apiKeyGroup.PUT("/user", middleware.PermissionAuth(constant.PermAccountUserUpdate), account.UpdateUser)
 		// 当前登录用户权限（仅需 JWTAuth，不需要 PermissionAuth）
 		accountGroup.GET("/user/permission", account.GetUserPermission)
 		accountGroup.POST("/user/pwd", middleware.PermissionAuth(constant.PermAccountUserResetPwd), account.ResetPwdById)
-		accountGroup.DELETE("/user/:id", middleware.PermissionAuth(constant.PermAccountUserDelete), account.DeleteUserById)
-		accountGroup.GET("/user/:id", middleware.PermissionAuth(constant.PermAccountUserDetail), account.GetUserInfo)
+		apiKeyGroup.DELETE("/user/:id", middleware.PermissionAuth(constant.PermAccountUserDelete), account.DeleteUserById)
+		apiKeyGroup.GET("/user/:id", middleware.PermissionAuth(constant.PermAccountUserDetail), account.GetUserInfo)
+		accountGroup.DELETE("/user/:id/mfa", middleware.PermissionAuth(constant.PermAccountUserResetMfa), account.ResetUserMfa)
+		accountGroup.POST("/user/:id/unlock", middleware.PermissionAuth(constant.PermAccountUserUnlock), account.UnlockUser)
+		// 当前登录用户个人资料与密码（仅需 JWTAuth，只操作自己的账号，不需要 PermissionAuth）
+		accountGroup.GET("/me", account.GetProfile)
+		accountGroup.PUT("/me", account.UpdateProfile)
+		accountGroup.POST("/me/pwd", account.ChangePwd)
+		// 当前登录用户两步验证（仅需 JWTAuth，只操作自己的验证器，不需要 PermissionAuth）；返回密钥、恢复码的接口不保存幂等响应
+		accountGroup.GET("/mfa", account.GetMfaStatus)
+		accountGroup.POST("/mfa/enroll", middleware.SensitiveResponse(), account.EnrollMfa)
+		accountGroup.POST("/mfa/activate", middleware.SensitiveResponse(), account.ActivateMfa)
+		accountGroup.POST("/mfa/disable", account.DisableMfa)
+		accountGroup.POST("/mfa/recovery-codes", middleware.SensitiveResponse(), account.RegenerateMfaRecoveryCodes)
+		// 用户会话
+		accountGroup.GET("/user/:id/session", middleware.PermissionAuth(constant.PermAccountSessionList), account.GetUserSessionList)
+		accountGroup.DELETE("/user/:id/session", middleware.PermissionAuth(constant.PermAccountSessionKick), account.KickUserAllSessions)
+		accountGroup.DELETE("/user/:id/session/:session_id", middleware.PermissionAuth(constant.PermAccountSessionKick), account.KickUserSession)
+		// 服务账号 API Key，创建时返回的明文 Key 不保存幂等响应
+		accountGroup.GET("/user/:id/api-key", middleware.PermissionAuth(constant.PermAccountApiKeyList), account.GetUserApiKeyList)
+		accountGroup.POST("/user/:id/api-key", middleware.SensitiveResponse(), middleware.PermissionAuth(constant.PermAccountApiKeyCreate), account.CreateUserApiKey)
+		accountGroup.DELETE("/user/:id/api-key/:key_id", middleware.PermissionAuth(constant.PermAccountApiKeyRevoke), account.RevokeUserApiKey)
 		// 菜单权限
-		accountGroup.GET("/menu", middleware.PermissionAuth(constant.PermAccountMenuList), account.GetMenuList)
+		apiKeyGroup.GET("/menu", middleware.PermissionAuth(constant.PermAccountMenuList), account.GetMenuList)
 		accountGroup.POST("/menu", middleware.PermissionAuth(constant.PermAccountMenuCreate), account.CreateMenu)
 		accountGroup.PUT("/menu", middleware.PermissionAuth(constant.PermAccountMenuUpdate), account.UpdateMenu)
 		accountGroup.DELETE("/menu/:id", middleware.PermissionAuth(constant.PermAccountMenuDelete), account.DeleteMenuById)
+		// 部门管理
+		apiKeyGroup.GET("/dept", middleware.PermissionAuth(constant.PermAccountDeptList), account.GetDeptList)
+		apiKeyGroup.POST("/dept", middleware.PermissionAuth(constant.PermAccountDeptCreate), account.CreateDept)
+		apiKeyGroup.PUT("/dept", middleware.PermissionAuth(constant.PermAccountDeptUpdate), account.UpdateDept)
+		apiKeyGroup.PUT("/dept/move", middleware.PermissionAuth(constant.PermAccountDeptUpdate), account.MoveDept)
+		apiKeyGroup.PUT("/dept/sort", middleware.PermissionAuth(constant.PermAccountDeptUpdate), account.SortDept)
+		apiKeyGroup.DELETE("/dept/:id", middleware.PermissionAuth(constant.PermAccountDeptDelete), account.DeleteDeptById)
 		// 角色管理
-		accountGroup.GET("/role", middleware.PermissionAuth(constant.PermAccountRoleList), account.GetRoleList)
+		apiKeyGroup.GET("/role", middleware.PermissionAuth(constant.PermAccountRoleList), account.GetRoleList)
 		accountGroup.POST("/role", middleware.PermissionAuth(constant.PermAccountRoleCreate), account.CreateRole)
 		accountGroup.PUT("/role", middleware.PermissionAuth(constant.PermAccountRoleUpdate), account.UpdateRole)
-		accountGroup.GET("/role/:id", middleware.PermissionAuth(constant.PermAccountRoleDetail), account.GetRoleById)
+		apiKeyGroup.GET("/role/:id", middleware.PermissionAuth(constant.PermAccountRoleDetail), account.GetRoleById)
 		accountGroup.DELETE("/role/:id", middleware.PermissionAuth(constant.PermAccountRoleDelete), account.DeleteRole)
 	}
 }
@@ -1,9 +1,20 @@
 package admin
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"snowgo/internal/api/admin/account"
+	"snowgo/internal/constant"
 	"snowgo/internal/router/middleware"
+	"snowgo/pkg/xlimiter"
+)
+
+const (
+	captchaRateInterval = time.Second // 同一 IP 获取登录验证码的平均间隔
+	captchaRateBurst    = 10          // 同一 IP 获取登录验证码的最大突发次数
+	loginRateInterval   = time.Second // 同一 IP 登录的平均间隔
+	loginRateBurst      = 20          // 同一 IP 登录的最大突发次数
 )
 
 // Register 路由配置
@@ -13,15 +24,31 @@ func Register(r *gin.RouterGroup) {
 	// 登录认证相关
 	auth := admin.Group("/auth")
 	{
-		auth.POST("/login", account.Login)
+		// 登录与验证码按 IP 限流，多副本共享计数
+		auth.POST("/login", middleware.KeyLimiter(constant.RateLimitLoginPrefix,
+			xlimiter.BucketEvery(loginRateInterval), loginRateBurst, middleware.LimitByIP), account.Login)
+		// 登录验证码，登录失败次数超过软阈值后登录需携带；每次生成都会写入 Redis
+		auth.GET("/captcha", middleware.KeyLimiter(constant.RateLimitCaptchaPrefix,
+			xlimiter.BucketEvery(captchaRateInterval), captchaRateBurst, middleware.LimitByIP), account.GetCaptcha)
 		auth.POST("/refresh-token", account.RefreshToken)
 		auth.POST("/logout", middleware.JWTAuth(), account.Logout)
+		// 两步验证登录第二步，凭登录挑战令牌访问，无需 JWTAuth
+		auth.POST("/mfa/setup", account.LoginMfaSetup)
+		auth.POST("/mfa/verify", account.LoginMfaVerify)
+		// 强制修改密码，凭登录返回的修改密码凭证访问，无需 JWTAuth
+		auth.POST("/password/change", account.LoginPwdChange)
+		// OIDC 单点登录（授权码 + PKCE），登录前访问，无需 JWTAuth
+		auth.GET("/oidc/authorize", account.OidcAuthorize)
+		auth.POST("/oidc/callback", account.OidcCallback)
 	}
 
-	// 受保护接口（必须登录）
-	protected := admin.Group("", middleware.JWTAuth())
+	// 受保护接口（必须登录），写操作支持 Idempotency-Key
+	protected := admin.Group("", middleware.JWTAuth(), middleware.Idempotency())
+	// 服务账号可通过 X-Api-Key 调用的接口，仅登记在该分组下的路由开放给 API Key
+	// 个人资料、两步验证、会话与凭证管理等交互式登录接口不得注册到该分组
+	apiKeyAllowed := admin.Group("", middleware.JWTOrApiKeyAuth(), middleware.Idempotency())
 	{
-		accountRouters(protected) // 账户相关
-		systemRouters(protected)  // 系统设置相关
+		accountRouters(protected, apiKeyAllowed) // 账户相关
+		systemRouters(protected, apiKeyAllowed)  // 系统设置相关
 	}
 }
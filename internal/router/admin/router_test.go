@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	accountService "snowgo/internal/service/admin/account"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/perm"
+	e "snowgo/pkg/xerror"
+)
+
+// initSqlBtnPerms 匹配 init.sql 中 Btn 节点的 perms 列
+var initSqlBtnPerms = regexp.MustCompile(`'Btn',\s*'[^']*',\s*(?:NULL|'[^']*'),\s*(?:NULL|'[^']*'),\s*'([^']+)'`)
+
+// TestRoutePermsMatchSeed 路由引用的权限、权限定义、初始化 SQL 的 Btn 节点三者保持一致
+func TestRoutePermsMatchSeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Register(gin.New().Group("/api"))
+
+	content, err := os.ReadFile("../../../docs/sql/init.sql")
+	if err != nil {
+		t.Fatalf("read init.sql: %v", err)
+	}
+	var seedPerms []string
+	for _, m := range initSqlBtnPerms.FindAllStringSubmatch(string(content), -1) {
+		seedPerms = append(seedPerms, m[1])
+	}
+	if len(seedPerms) == 0 {
+		t.Fatalf("no Btn perms found in init.sql")
+	}
+
+	if drift := perm.Default().Diff(seedPerms); !drift.Empty() {
+		t.Fatalf("route perms drift from init.sql: %+v", drift)
+	}
+}
+
+// TestApiKeyOnlyOnAllowedRoutes 只有白名单分组接受 X-Api-Key，交互式登录接口仍要求登录令牌
+func TestApiKeyOnlyOnAllowedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set(constant.CONTAINER, &di.Container{})
+	})
+	Register(engine.Group("/api"))
+
+	tests := []struct {
+		method, path string
+		wantMsg      string
+	}{
+		{http.MethodGet, "/api/admin/account/user", e.ApiKeyInvalid.GetErrMsg()},
+		{http.MethodGet, "/api/admin/system/dict", e.ApiKeyInvalid.GetErrMsg()},
+		{http.MethodGet, "/api/admin/account/me", e.TokenNotFound.GetErrMsg()},
+		{http.MethodPost, "/api/admin/account/me/pwd", e.TokenNotFound.GetErrMsg()},
+		{http.MethodPost, "/api/admin/account/mfa/enroll", e.TokenNotFound.GetErrMsg()},
+		{http.MethodDelete, "/api/admin/account/user/2/session", e.TokenNotFound.GetErrMsg()},
+		{http.MethodPost, "/api/admin/account/user/2/api-key", e.TokenNotFound.GetErrMsg()},
+		{http.MethodPost, "/api/admin/system/partner", e.TokenNotFound.GetErrMsg()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(xauth.XApiKeyHeader, "sk_invalid")
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			if !strings.Contains(w.Body.String(), tt.wantMsg) {
+				t.Fatalf("expected %q, got %s", tt.wantMsg, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestCaptchaLimitedByIP 获取验证码按 IP 限流，不同 IP 互不影响
+func TestCaptchaLimitedByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	container := &di.Container{}
+	container.CaptchaService = accountService.NewCaptchaService(nil, accountService.CaptchaConfig{})
+	engine.Use(func(c *gin.Context) {
+		c.Set(constant.CONTAINER, container)
+	})
+	Register(engine.Group("/api"))
+
+	get := func(remoteAddr string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/auth/captcha", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	for i := 0; i < captchaRateBurst; i++ {
+		if body := get("10.0.0.1:1234"); !strings.Contains(body, e.CaptchaUnavailable.GetErrMsg()) {
+			t.Fatalf("request %d expected to reach handler, got %s", i+1, body)
+		}
+	}
+	if body := get("10.0.0.1:1234"); !strings.Contains(body, e.KeyTooManyRequests.GetErrMsg()) {
+		t.Fatalf("expected captcha rate limited, got %s", body)
+	}
+	if body := get("10.0.0.2:1234"); !strings.Contains(body, e.CaptchaUnavailable.GetErrMsg()) {
+		t.Fatalf("expected other ip not limited, got %s", body)
+	}
+}
@@ -7,14 +7,15 @@ import (
 	"snowgo/internal/router/middleware"
 )
 
-// 系统相关路由
-func systemRouters(r *gin.RouterGroup) {
+// 系统相关路由，apiKey 分组下的路由允许服务账号通过 X-Api-Key 调用
+func systemRouters(r, apiKey *gin.RouterGroup) {
 	systemGroup := r.Group("/system")
+	apiKeyGroup := apiKey.Group("/system")
 
 	// 服务信息（仅需 JWTAuth，不需要权限校验）
-	systemGroup.GET("/info", system.GetServerInfo)
+	apiKeyGroup.GET("/info", system.GetServerInfo)
 
-	logGroup := systemGroup.Group("/log")
+	logGroup := apiKeyGroup.Group("/log")
 	{
 		// 操作日志
 		logGroup.GET("/operation", middleware.PermissionAuth(constant.PermSystemOperationLogList), system.GetOperationLogList)
@@ -22,7 +23,7 @@ func systemRouters(r *gin.RouterGroup) {
 		logGroup.GET("/login", middleware.PermissionAuth(constant.PermSystemLoginLogList), system.GetLoginLogList)
 	}
 
-	dictGroup := systemGroup.Group("/dict")
+	dictGroup := apiKeyGroup.Group("/dict")
 	{
 		// 字典管理
 		dictGroup.GET("", middleware.PermissionAuth(constant.PermSystemDictList), system.GetDictList)
@@ -36,4 +37,15 @@ func systemRouters(r *gin.RouterGroup) {
 		dictGroup.GET("/item/:code", system.GetItemListByDictCode)
 		dictGroup.DELETE("/item/:id", middleware.PermissionAuth(constant.PermSystemDictDelete), system.DeleteDictItem)
 	}
+
+	partnerGroup := systemGroup.Group("/partner")
+	{
+		// 开放接口合作方凭证，创建与更换密钥时返回的明文密钥不保存幂等响应
+		partnerGroup.GET("", middleware.PermissionAuth(constant.PermSystemPartnerList), system.GetPartnerList)
+		partnerGroup.POST("", middleware.SensitiveResponse(), middleware.PermissionAuth(constant.PermSystemPartnerCreate), system.CreatePartner)
+		partnerGroup.PUT("", middleware.PermissionAuth(constant.PermSystemPartnerUpdate), system.UpdatePartner)
+		// 更换签名密钥
+		partnerGroup.POST("/:id/secret", middleware.SensitiveResponse(), middleware.PermissionAuth(constant.PermSystemPartnerUpdate), system.RotatePartnerSecret)
+		partnerGroup.DELETE("/:id", middleware.PermissionAuth(constant.PermSystemPartnerDelete), system.DeletePartnerById)
+	}
 }
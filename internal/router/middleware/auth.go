@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"snowgo/internal/di"
+	accountService "snowgo/internal/service/admin/account"
 	"snowgo/pkg/xauth"
-	"snowgo/pkg/xauth/jwt"
+	"snowgo/pkg/xauth/perm"
+	"snowgo/pkg/xauth/session"
 	e "snowgo/pkg/xerror"
 	"snowgo/pkg/xlogger"
 	"snowgo/pkg/xresponse"
@@ -14,55 +18,70 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// JWTAuth 基于JWT的认证中间件
+// maxSignedBodyBytes 签名请求体上限，验签需要完整读取请求体
+const maxSignedBodyBytes = 10 << 20
+
+// JWTAuth 登录令牌认证中间件，按配置的令牌策略校验 JWT 或不透明会话令牌
 func JWTAuth() func(c *gin.Context) {
 	return func(c *gin.Context) {
-		// 客户端携带Token有三种方式 1.放在请求头 2.放在请求体 3.放在URI
-		// 假设Token放在Header的Authorization中，并使用Bearer开头
-		authHeader := c.Request.Header.Get("Authorization")
-		if authHeader == "" {
-			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenNotFound.GetErrMsg())
-			c.Abort()
-			return
-		}
-		// 按空格分割
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenIncorrectFormat.GetErrMsg())
+		container := di.GetContainer(c)
+		// 优先读取 Authorization 请求头（Bearer），会话策略下未携带请求头时读取 Cookie
+		token, ok := accessToken(c, container.TokenCookie)
+		if !ok {
 			c.Abort()
 			return
 		}
-		// parts[1]是获取到的tokenString，我们使用之前定义好的解析JWT的函数来解析它
-		container := di.GetContainer(c)
-		jwtManager := container.JwtManager
-		mc, err := jwtManager.ParseToken(parts[1])
+
+		mc, err := container.TokenStrategy.ParseAccessToken(c.Request.Context(), token)
 		if err != nil {
-			if errors.Is(err, jwt.ErrTokenExpired) {
+			switch {
+			case errors.Is(err, xauth.ErrTokenExpired):
 				xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenExpired.GetErrMsg())
-				c.Abort()
-				return
+			case errors.Is(err, xauth.ErrInvalidTokenType):
+				xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenTypeError.GetErrMsg())
+			default:
+				xlogger.ErrorfCtx(c.Request.Context(), "parse token err: %v", err)
+				xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenInvalid.GetErrMsg())
 			}
-			xlogger.ErrorfCtx(c.Request.Context(), "parse token err: %v", err)
-			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenInvalid.GetErrMsg())
 			c.Abort()
 			return
 		}
 
-		// 检查token的type
-		if err := mc.ValidAccessToken(); err != nil {
-			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenTypeError.GetErrMsg())
+		// 检查token是否已被吊销（登出、禁用、重置密码、角色变更）
+		revoked, err := container.RevocationService.IsRevoked(c.Request.Context(), &accountService.TokenIdentity{
+			UserId:    mc.UserId,
+			TokenId:   mc.TokenId,
+			SessionId: mc.SessionId,
+			FamilyId:  mc.FamilyId,
+			IssuedAt:  mc.IssuedAt,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(c.Request.Context(), "check token revoked err: %v", err)
+			xresponse.FailByError(c, e.HttpInternalServerError)
+			c.Abort()
+			return
+		}
+		if revoked {
+			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenRevoked.GetErrMsg())
 			c.Abort()
 			return
 		}
 
+		// 刷新会话最后活跃时间，失败不影响请求
+		if err := container.SessionService.Touch(c.Request.Context(), mc.UserId, mc.SessionId, c.ClientIP()); err != nil {
+			xlogger.ErrorfCtx(c.Request.Context(), "touch session err: %v", err)
+		}
+
 		// 将当前请求的username信息保存到请求的上下文c上
 		c.Set(xauth.XUserId, mc.UserId)
 		c.Set(xauth.XUserName, mc.Username)
 		c.Set(xauth.XSessionId, mc.SessionId)
+		c.Set(xauth.XTokenId, mc.TokenId)
 		// 标准 context.Context 注入用户信息，用于后续GetUserContext获取
 		ctx := context.WithValue(c.Request.Context(), xauth.XUserId, mc.UserId)
 		ctx = context.WithValue(ctx, xauth.XUserName, mc.Username)
 		ctx = context.WithValue(ctx, xauth.XSessionId, mc.SessionId)
+		ctx = context.WithValue(ctx, xauth.XTokenId, mc.TokenId)
 
 		// 更新请求的 Context
 		c.Request = c.Request.WithContext(ctx)
@@ -70,8 +89,168 @@ func JWTAuth() func(c *gin.Context) {
 	}
 }
 
-// PermissionAuth 接口权限校验
+// accessToken 读取请求携带的 access token，失败时已写入响应
+func accessToken(c *gin.Context, cookie *session.CookieTransport) (string, bool) {
+	// 假设Token放在Header的Authorization中，并使用Bearer开头
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader != "" {
+		// 按空格分割
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenIncorrectFormat.GetErrMsg())
+			return "", false
+		}
+		return parts[1], true
+	}
+	if cookie != nil {
+		if token := cookie.AccessToken(c.Request); token != "" {
+			// Cookie 由浏览器自动携带，需校验双重提交的 CSRF 令牌
+			if !cookie.VerifyCSRF(c.Request) {
+				xresponse.Fail(c, e.HttpForbidden.GetErrCode(), e.CsrfTokenInvalid.GetErrMsg())
+				return "", false
+			}
+			return token, true
+		}
+	}
+	xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.TokenNotFound.GetErrMsg())
+	return "", false
+}
+
+// ApiKeyAuth 基于服务账号 API Key 的认证中间件，与 JWTAuth 注入相同的用户上下文，PermissionAuth 可直接复用
+func ApiKeyAuth() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		rawKey := c.Request.Header.Get(xauth.XApiKeyHeader)
+		if rawKey == "" {
+			xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), e.ApiKeyInvalid.GetErrMsg())
+			c.Abort()
+			return
+		}
+		container := di.GetContainer(c)
+		principal, err := container.ApiKeyService.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			var bizErr *e.BizError
+			if errors.As(err, &bizErr) {
+				xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), bizErr.Code.GetErrMsg())
+				c.Abort()
+				return
+			}
+			xlogger.ErrorfCtx(c.Request.Context(), "authenticate api key err: %v", err)
+			xresponse.FailByError(c, e.HttpInternalServerError)
+			c.Abort()
+			return
+		}
+
+		// 注入服务账号信息，XApiKeyId 用于操作日志区分调用方式
+		c.Set(xauth.XUserId, principal.UserId)
+		c.Set(xauth.XUserName, principal.Username)
+		c.Set(xauth.XApiKeyId, principal.KeyId)
+		ctx := context.WithValue(c.Request.Context(), xauth.XUserId, principal.UserId)
+		ctx = context.WithValue(ctx, xauth.XUserName, principal.Username)
+		ctx = context.WithValue(ctx, xauth.XApiKeyId, principal.KeyId)
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// SignatureAuth 开放接口 HMAC-SHA256 签名认证中间件，认证通过后注入合作方上下文
+func SignatureAuth() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		container := di.GetContainer(c)
+
+		// 先校验签名头、时间戳与合作方，未通过的请求不读取请求体
+		signedReq, err := container.PartnerService.Precheck(ctx, c.Request)
+		if err != nil {
+			abortSignatureErr(c, err)
+			return
+		}
+		if c.Request.ContentLength > maxSignedBodyBytes {
+			xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), "请求体过大")
+			c.Abort()
+			return
+		}
+
+		// 签名覆盖请求体，读取后回填供后续 handler 绑定
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSignedBodyBytes+1))
+		if err != nil {
+			xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), "读取请求体失败")
+			c.Abort()
+			return
+		}
+		if len(body) > maxSignedBodyBytes {
+			xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), "请求体过大")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		principal, err := container.PartnerService.Verify(ctx, signedReq, c.Request, body)
+		if err != nil {
+			abortSignatureErr(c, err)
+			return
+		}
+
+		c.Set(xauth.XPartnerId, principal.PartnerId)
+		c.Set(xauth.XPartnerName, principal.Name)
+		ctx = context.WithValue(ctx, xauth.XPartnerId, principal.PartnerId)
+		ctx = context.WithValue(ctx, xauth.XPartnerName, principal.Name)
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// abortSignatureErr 签名认证失败时按业务错误返回 401，其余错误返回 500
+func abortSignatureErr(c *gin.Context, err error) {
+	var bizErr *e.BizError
+	if errors.As(err, &bizErr) {
+		xresponse.Fail(c, e.HttpUnauthorized.GetErrCode(), bizErr.Code.GetErrMsg())
+		c.Abort()
+		return
+	}
+	xlogger.ErrorfCtx(c.Request.Context(), "authenticate signature err: %v", err)
+	xresponse.FailByError(c, e.HttpInternalServerError)
+	c.Abort()
+}
+
+// JWTOrApiKeyAuth 携带 X-Api-Key 请求头时按 API Key 认证，否则按 JWT 认证
+func JWTOrApiKeyAuth() func(c *gin.Context) {
+	jwtAuth := JWTAuth()
+	apiKeyAuth := ApiKeyAuth()
+	return func(c *gin.Context) {
+		if c.Request.Header.Get(xauth.XApiKeyHeader) != "" {
+			apiKeyAuth(c)
+			return
+		}
+		jwtAuth(c)
+	}
+}
+
+// PermissionAuth 接口权限校验，用户授权支持通配（account:user:*、account:*、*）
+// 权限点需在 internal/constant 通过 perm.Define 定义，注册路由时登记引用，供 cmd/perm-sync 检测与菜单的差异
 func PermissionAuth(requiredPerm string) gin.HandlerFunc {
+	return PermissionAll(requiredPerm)
+}
+
+// PermissionAny 拥有任意一个权限即可访问
+func PermissionAny(perms ...string) gin.HandlerFunc {
+	perm.Use(perms...)
+	return permissionCheck(func(m *perm.Matcher) bool {
+		return m.MatchAny(perms...)
+	})
+}
+
+// PermissionAll 必须拥有全部权限才可访问
+func PermissionAll(perms ...string) gin.HandlerFunc {
+	perm.Use(perms...)
+	return permissionCheck(func(m *perm.Matcher) bool {
+		return m.MatchAll(perms...)
+	})
+}
+
+// permissionCheck 获取用户预编译的权限树并校验
+func permissionCheck(allow func(m *perm.Matcher) bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 拿到 userId
 		uidIfc, exists := c.Get(xauth.XUserId)
@@ -89,24 +268,17 @@ func PermissionAuth(requiredPerm string) gin.HandlerFunc {
 		}
 
 		container := di.GetContainer(c)
-		// 拿该用户的perms列表
-		perms, err := container.UserService.GetPermsListById(c, userId)
+		// 拿该用户的权限树
+		matcher, err := container.UserService.GetPermMatcherById(c, userId)
 		if err != nil {
-			xlogger.ErrorfCtx(c.Request.Context(), "get user(%d) perms list is err: %v", userId, err)
+			xlogger.ErrorfCtx(c.Request.Context(), "get user(%d) perms matcher is err: %v", userId, err)
 			xresponse.FailByError(c, e.HttpInternalServerError)
 			c.Abort()
 			return
 		}
 
 		// 校验是否有接口权限
-		allowed := false
-		for _, p := range perms {
-			if p == requiredPerm {
-				allowed = true
-				break
-			}
-		}
-		if !allowed {
+		if !allow(matcher) {
 			xresponse.FailByError(c, e.HttpForbidden)
 			c.Abort()
 			return
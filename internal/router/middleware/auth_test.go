@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"snowgo/internal/di"
+	systemService "snowgo/internal/service/admin/system"
+	"snowgo/pkg/xauth/signature"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trackedBody 记录请求体是否被读取
+type trackedBody struct {
+	*strings.Reader
+	read bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *trackedBody) Close() error { return nil }
+
+func TestSignatureAuthRejectsBeforeReadingBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	partnerService := systemService.NewPartnerService(nil, nil, nil, nil,
+		systemService.PartnerConfig{EncryptKey: "0123456789abcdef0123456789abcdef", ClockSkew: 5 * time.Minute})
+	r := gin.New()
+	r.Use(InjectContainerMiddleware(&di.Container{SystemContainer: di.SystemContainer{PartnerService: partnerService}}), SignatureAuth())
+	r.POST("/open/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		header func(h http.Header)
+	}{
+		{"missing headers", func(h http.Header) {}},
+		{"expired timestamp", func(h http.Header) {
+			h.Set(signature.HeaderAccessKey, "ak_test")
+			h.Set(signature.HeaderTimestamp, strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+			h.Set(signature.HeaderNonce, "0123456789abcdef")
+			h.Set(signature.HeaderSignature, "deadbeef")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &trackedBody{Reader: strings.NewReader(`{"order_no":"A1001"}`)}
+			req := httptest.NewRequest(http.MethodPost, "/open/ping", body)
+			tt.header(req.Header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if !strings.Contains(w.Body.String(), `"code":401`) {
+				t.Fatalf("expected 401 response, got %s", w.Body.String())
+			}
+			if body.read {
+				t.Fatalf("expected body not read before header checks pass")
+			}
+		})
+	}
+}
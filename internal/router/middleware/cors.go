@@ -0,0 +1,258 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"snowgo/config"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/session"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	defaultCorsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCorsHeaders = []string{"Authorization", "Content-Type", session.CsrfHeader, xauth.XApiKeyHeader, xauth.XTraceIDHeader}
+)
+
+// CorsPolicy 预编译的跨域策略
+type CorsPolicy struct {
+	anyOrigin        bool
+	origins          map[string]struct{}
+	wildcards        []wildcardOrigin
+	regexps          []*regexp.Regexp
+	methods          map[string]struct{}
+	headers          map[string]struct{}
+	allowMethods     string
+	allowHeaders     string
+	exposeHeaders    string
+	maxAge           string
+	allowCredentials bool
+}
+
+// wildcardOrigin 通配子域来源 https://*.a.com，拆分为 prefix "https://" 与 suffix ".a.com"
+type wildcardOrigin struct {
+	prefix string
+	suffix string
+}
+
+func (w wildcardOrigin) match(origin string) bool {
+	if len(origin) <= len(w.prefix)+len(w.suffix) || !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+		return false
+	}
+	// 通配部分只能是子域名，不能包含端口、路径或用户信息
+	sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+	return !strings.ContainsAny(sub, "/:@?#") && !strings.HasPrefix(sub, ".") && !strings.HasSuffix(sub, ".")
+}
+
+// NewCorsPolicy 校验并编译跨域配置
+func NewCorsPolicy(conf config.CorsConfig) (*CorsPolicy, error) {
+	p := &CorsPolicy{
+		origins:          make(map[string]struct{}),
+		methods:          make(map[string]struct{}),
+		headers:          make(map[string]struct{}),
+		allowCredentials: conf.AllowCredentials,
+	}
+	for _, origin := range conf.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			scheme, host, ok := strings.Cut(origin, "://*.")
+			if !ok || scheme == "" || host == "" || strings.Contains(host, "*") {
+				return nil, fmt.Errorf("cors.allow_origins wildcard must look like https://*.example.com, got %q", origin)
+			}
+			p.wildcards = append(p.wildcards, wildcardOrigin{prefix: scheme + "://", suffix: "." + host})
+		default:
+			if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+				return nil, fmt.Errorf("cors.allow_origins must be scheme://host[:port], got %q", origin)
+			}
+			p.origins[origin] = struct{}{}
+		}
+	}
+	for _, expr := range conf.AllowOriginRegexps {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("cors.allow_origin_regexps %q: %w", expr, err)
+		}
+		p.regexps = append(p.regexps, re)
+	}
+	if p.anyOrigin && p.allowCredentials {
+		return nil, errors.New("cors.allow_origins * cannot be used with allow_credentials")
+	}
+	if conf.MaxAge < 0 {
+		return nil, fmt.Errorf("cors.max_age must be >= 0, got %s", conf.MaxAge)
+	}
+
+	configMethods := conf.AllowMethods
+	if len(configMethods) == 0 {
+		configMethods = defaultCorsMethods
+	}
+	methods := make([]string, 0, len(configMethods))
+	for _, m := range configMethods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		p.methods[m] = struct{}{}
+		methods = append(methods, m)
+	}
+	configHeaders := conf.AllowHeaders
+	if len(configHeaders) == 0 {
+		configHeaders = defaultCorsHeaders
+	}
+	headers := make([]string, 0, len(configHeaders))
+	for _, h := range configHeaders {
+		h = http.CanonicalHeaderKey(strings.TrimSpace(h))
+		p.headers[h] = struct{}{}
+		headers = append(headers, h)
+	}
+	p.allowMethods = strings.Join(methods, ", ")
+	p.allowHeaders = strings.Join(headers, ", ")
+	p.exposeHeaders = strings.Join(conf.ExposeHeaders, ", ")
+	if conf.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(conf.MaxAge.Seconds()))
+	}
+	return p, nil
+}
+
+// AllowOrigin 来源是否在允许列表中
+func (p *CorsPolicy) AllowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+	for _, w := range p.wildcards {
+		if w.match(origin) {
+			return true
+		}
+	}
+	for _, re := range p.regexps {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowPreflight 预检请求的方法与请求头是否允许，不允许时返回原因
+func (p *CorsPolicy) allowPreflight(method, headers string) (bool, string) {
+	if _, ok := p.methods[strings.ToUpper(method)]; !ok {
+		return false, "method " + method
+	}
+	for _, h := range strings.Split(headers, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if _, ok := p.headers[http.CanonicalHeaderKey(h)]; !ok {
+			return false, "header " + h
+		}
+	}
+	return true, ""
+}
+
+// Cors 按来源白名单处理跨域请求，拒绝的请求记录日志并返回 403；策略由 NewCorsPolicy 在启动时校验生成
+func Cors(policy *CorsPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		// 非跨域请求（无 Origin 或与当前请求同源）不处理
+		if origin == "" || sameOrigin(origin, c.Request) {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.Request.Header.Get("Access-Control-Request-Method") != ""
+
+		if !policy.AllowOrigin(origin) {
+			xlogger.InfofCtx(c.Request.Context(), "cors rejected: origin=%s method=%s path=%s preflight=%t",
+				origin, c.Request.Method, c.Request.URL.Path, preflight)
+			xresponse.FailByError(c, e.HttpForbidden)
+			c.Abort()
+			return
+		}
+
+		h := c.Writer.Header()
+		if policy.anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if policy.allowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			reqMethod := c.Request.Header.Get("Access-Control-Request-Method")
+			if ok, reason := policy.allowPreflight(reqMethod, c.Request.Header.Get("Access-Control-Request-Headers")); !ok {
+				xlogger.InfofCtx(c.Request.Context(), "cors preflight rejected: origin=%s path=%s %s not allowed",
+					origin, c.Request.URL.Path, reason)
+				h.Del("Access-Control-Allow-Origin")
+				h.Del("Access-Control-Allow-Credentials")
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			h.Set("Access-Control-Allow-Methods", policy.allowMethods)
+			h.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+			if policy.maxAge != "" {
+				h.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if policy.exposeHeaders != "" {
+			h.Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+		}
+		c.Next()
+	}
+}
+
+// sameOrigin Origin 与当前请求的 scheme、host、port 均一致时视为同源，默认端口（http 80、https 443）可省略
+func sameOrigin(origin string, r *http.Request) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	scheme := requestScheme(r)
+	if !strings.EqualFold(u.Scheme, scheme) {
+		return false
+	}
+	return strings.EqualFold(hostWithPort(u.Host, scheme), hostWithPort(r.Host, scheme))
+}
+
+// requestScheme 当前请求的 scheme，TLS 在反向代理终止时取 X-Forwarded-Proto
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	if proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ","); strings.TrimSpace(proto) != "" {
+		return strings.ToLower(strings.TrimSpace(proto))
+	}
+	return "http"
+}
+
+// hostWithPort 补全默认端口，便于比较 host:port
+func hostWithPort(host, scheme string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	if scheme == "https" {
+		return net.JoinHostPort(strings.Trim(host, "[]"), "443")
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), "80")
+}
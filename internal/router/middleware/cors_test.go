@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"snowgo/config"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCorsEngine(t *testing.T, conf config.CorsConfig) *gin.Engine {
+	t.Helper()
+	policy, err := NewCorsPolicy(conf)
+	if err != nil {
+		t.Fatalf("NewCorsPolicy error: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Cors(policy))
+	r.POST("/api/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	return r
+}
+
+func TestCorsPolicyAllowOrigin(t *testing.T) {
+	policy, err := NewCorsPolicy(config.CorsConfig{
+		AllowOrigins:       []string{"https://admin.example.com/", "https://*.example.org"},
+		AllowOriginRegexps: []string{`http://localhost(:\d+)?`},
+	})
+	if err != nil {
+		t.Fatalf("NewCorsPolicy error: %v", err)
+	}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://admin.example.com", true},
+		{"HTTPS://Admin.Example.com", true},
+		{"https://evil.example.com", false},
+		{"https://admin.example.com.evil.com", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"http://a.example.org", false},
+		{"https://evil.com/.example.org", false},
+		{"https://a.example.org:8443", false},
+		{"http://localhost", true},
+		{"http://localhost:3000", true},
+		{"http://localhost.evil.com", false},
+	}
+	for _, tt := range tests {
+		if got := policy.AllowOrigin(tt.origin); got != tt.want {
+			t.Errorf("AllowOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestNewCorsPolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.CorsConfig
+	}{
+		{"any origin with credentials", config.CorsConfig{AllowOrigins: []string{"*"}, AllowCredentials: true}},
+		{"bad wildcard", config.CorsConfig{AllowOrigins: []string{"https://a.*.com"}}},
+		{"origin with path", config.CorsConfig{AllowOrigins: []string{"https://a.com/app"}}},
+		{"bad regexp", config.CorsConfig{AllowOriginRegexps: []string{"("}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCorsPolicy(tt.conf); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestCorsPreflight(t *testing.T) {
+	r := newCorsEngine(t, config.CorsConfig{
+		AllowOrigins:     []string{"https://admin.example.com"},
+		AllowHeaders:     []string{"authorization", "Content-Type"},
+		MaxAge:           10 * time.Minute,
+		AllowCredentials: true,
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/ping", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "content-type, Authorization")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") != "https://admin.example.com" || h.Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("unexpected allow origin headers: %v", h)
+	}
+	if h.Get("Access-Control-Max-Age") != "600" || h.Get("Access-Control-Allow-Headers") != "Authorization, Content-Type" {
+		t.Fatalf("unexpected preflight headers: %v", h)
+	}
+
+	// 未允许的请求头
+	req = httptest.NewRequest(http.MethodOptions, "/api/ping", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected preflight rejected, got %d %v", w.Code, w.Header())
+	}
+
+	// 未允许的方法
+	req = httptest.NewRequest(http.MethodOptions, "/api/ping", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	req.Header.Set("Access-Control-Request-Method", "TRACE")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected preflight method rejected, got %d", w.Code)
+	}
+}
+
+func TestCorsActualRequest(t *testing.T) {
+	r := newCorsEngine(t, config.CorsConfig{
+		AllowOrigins:  []string{"https://admin.example.com"},
+		ExposeHeaders: []string{"X-Trace-Id"},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ping", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "pong" {
+		t.Fatalf("expected request passed, got %d %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://admin.example.com" || w.Header().Get("Access-Control-Expose-Headers") != "X-Trace-Id" {
+		t.Fatalf("unexpected cors headers: %v", w.Header())
+	}
+	if w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Fatalf("expected credentials not allowed")
+	}
+
+	// 未允许的来源直接拒绝，不回显 Origin
+	req = httptest.NewRequest(http.MethodPost, "/api/ping", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() == "pong" || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected request rejected, got %d %s %v", w.Code, w.Body.String(), w.Header())
+	}
+
+	// 同源请求不受白名单限制
+	req = httptest.NewRequest(http.MethodPost, "http://api.example.com/api/ping", nil)
+	req.Header.Set("Origin", "http://api.example.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != "pong" {
+		t.Fatalf("expected same-origin request passed, got %s", w.Body.String())
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		target string
+		tls    bool
+		proto  string
+		want   bool
+	}{
+		{"same http", "http://api.example.com", "http://api.example.com/api/ping", false, "", true},
+		{"default port omitted", "http://api.example.com:80", "http://api.example.com/api/ping", false, "", true},
+		{"https behind tls", "https://api.example.com", "https://api.example.com/api/ping", true, "", true},
+		{"https behind proxy", "https://api.example.com", "http://api.example.com/api/ping", false, "https", true},
+		{"scheme mismatch", "https://api.example.com", "http://api.example.com/api/ping", false, "", false},
+		{"http origin to https", "http://api.example.com", "https://api.example.com/api/ping", true, "", false},
+		{"port mismatch", "http://api.example.com:8080", "http://api.example.com/api/ping", false, "", false},
+		{"host mismatch", "http://evil.com", "http://api.example.com/api/ping", false, "", false},
+		{"opaque origin", "null", "http://api.example.com/api/ping", false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if !tt.tls {
+				req.TLS = nil
+			}
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			if got := sameOrigin(tt.origin, req); got != tt.want {
+				t.Fatalf("sameOrigin(%q, %s) = %v, want %v", tt.origin, tt.target, got, tt.want)
+			}
+		})
+	}
+}
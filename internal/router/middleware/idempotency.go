@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"snowgo/config"
+	"snowgo/internal/constant"
+	"snowgo/internal/di"
+	"snowgo/pkg/xauth"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlock"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed" // 回放的响应携带该响应头
+	maxIdempotencyKeyLen      = 128
+	defaultIdempotencyExpire  = 24 * time.Hour
+	defaultIdempotencyLockTTL = 30 * time.Second
+	idempotencyStoreTimeout   = 3 * time.Second      // 保存响应的超时，与请求 ctx 解耦，客户端断开后仍保存
+	sensitiveResponseKey      = "sensitive_response" // 响应包含一次性密钥等敏感数据，幂等中间件不保存
+)
+
+// idempotentMethods 需要幂等保护的请求方法
+var idempotentMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+}
+
+// idempotencyRecord 保存在 Redis 中的首次响应
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"` // 请求指纹：方法、URI 与请求体的摘要
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	BizCode     int    `json:"biz_code"`
+	Body        []byte `json:"body"`
+}
+
+// idempotencyWriter 记录 handler 写出的响应体
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// SensitiveResponse 标记路由响应包含只返回一次的密钥、恢复码等敏感数据，幂等中间件不会将其保存到 Redis，
+// 携带相同 Idempotency-Key 的重试会重新执行
+func SensitiveResponse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(sensitiveResponseKey, true)
+		c.Next()
+	}
+}
+
+// Idempotency 幂等请求，需放在认证中间件之后，按 config.idempotency 启用
+// 携带 Idempotency-Key 的 POST/PUT/DELETE 请求：处理期间通过分布式锁标记处理中，重复请求直接拒绝；
+// 处理完成后保存响应，相同 key 的重复请求回放首次响应，请求内容不同则拒绝；key 按调用方与路由隔离
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handleIdempotency(c, config.Get().Idempotency)
+	}
+}
+
+// handleIdempotency 每次请求读取配置，配置变更无需重建路由
+func handleIdempotency(c *gin.Context, conf config.IdempotencyConfig) {
+	if !conf.Enable || !idempotentMethods[c.Request.Method] {
+		c.Next()
+		return
+	}
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	if !validIdempotencyKey(key) {
+		xresponse.FailByError(c, e.IdempotencyKeyError)
+		c.Abort()
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		xresponse.Fail(c, e.HttpBadRequest.GetErrCode(), "读取请求体失败")
+		c.Abort()
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	ctx := c.Request.Context()
+	container := di.GetContainer(c)
+	digest := idempotencyDigest(idempotencyScope(c), c.Request.Method, c.FullPath(), key)
+	cacheKey := constant.CacheIdempotencyPrefix + digest
+	fingerprint := idempotencyDigest(c.Request.Method, c.Request.URL.RequestURI(), string(body))
+
+	// Redis 不可用时不做幂等保护，避免影响正常请求
+	if done, err := replayIdempotent(c, container, cacheKey, fingerprint); err != nil {
+		xlogger.ErrorfCtx(ctx, "idempotency get record err: key=%s err=%v", cacheKey, err)
+		c.Next()
+		return
+	} else if done {
+		return
+	}
+
+	expire := conf.ExpirationTime
+	if expire <= 0 {
+		expire = defaultIdempotencyExpire
+	}
+	lockTTL := conf.LockTime
+	if lockTTL <= 0 {
+		lockTTL = defaultIdempotencyLockTTL
+	}
+	err = container.Lock.TryLock(ctx, constant.LockIdempotencyPrefix+digest, int64(lockTTL/time.Second), func(isLock bool, _ xlock.LockContext) error {
+		if !isLock {
+			xresponse.FailByError(c, e.IdempotencyBusy)
+			c.Abort()
+			return nil
+		}
+		// 拿到锁后再确认一次，避免等锁期间首个请求已完成
+		if done, err := replayIdempotent(c, container, cacheKey, fingerprint); err != nil || done {
+			return err
+		}
+
+		w := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		bizCode, ok := c.Get(xresponse.BizCode)
+		code, _ := bizCode.(int)
+		// 只保存经 xresponse 输出的响应；服务端异常允许客户端使用相同 key 重试；敏感响应不落盘
+		if !ok || c.Writer.Status() >= http.StatusInternalServerError || code == e.HttpInternalServerError.GetErrCode() || c.GetBool(sensitiveResponseKey) {
+			return nil
+		}
+		record, err := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      c.Writer.Status(),
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			BizCode:     code,
+			Body:        w.body.Bytes(),
+		})
+		if err != nil {
+			return err
+		}
+		// 请求 ctx 可能已因客户端断开而取消，保存失败会导致重试再次执行
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+		defer cancel()
+		return container.Cache.Set(storeCtx, cacheKey, string(record), expire)
+	})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "idempotency handle err: key=%s err=%v", cacheKey, err)
+	}
+}
+
+// replayIdempotent 已有首次响应时回放，请求指纹不一致时拒绝；返回是否已处理
+func replayIdempotent(c *gin.Context, container *di.Container, cacheKey, fingerprint string) (bool, error) {
+	val, ok, err := container.Cache.Get(c.Request.Context(), cacheKey)
+	if err != nil || !ok {
+		return false, err
+	}
+	var record idempotencyRecord
+	if err := json.Unmarshal([]byte(val), &record); err != nil {
+		return false, err
+	}
+	if record.Fingerprint != fingerprint {
+		xresponse.FailByError(c, e.IdempotencyKeyReuse)
+		c.Abort()
+		return true, nil
+	}
+	c.Set(xresponse.BizCode, record.BizCode)
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+	return true, nil
+}
+
+// idempotencyScope 幂等 key 的隔离范围：登录用户、开放接口合作方，否则按客户端 IP
+func idempotencyScope(c *gin.Context) string {
+	if userId := c.GetInt32(xauth.XUserId); userId > 0 {
+		return "user:" + strconv.Itoa(int(userId))
+	}
+	if partnerId := c.GetInt32(xauth.XPartnerId); partnerId > 0 {
+		return "partner:" + strconv.Itoa(int(partnerId))
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func idempotencyDigest(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// validIdempotencyKey key 为 1-128 位可见 ASCII 字符
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
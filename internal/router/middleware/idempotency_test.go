@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"snowgo/config"
+	"snowgo/internal/di"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlock"
+	"snowgo/pkg/xresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memCache 只实现幂等中间件用到的方法
+type memCache struct {
+	xcache.Cache
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (m *memCache) Get(_ context.Context, key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[key]
+	return v, ok, nil
+}
+
+func (m *memCache) Set(ctx context.Context, key string, value string, _ time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+// memLock 进程内 TryLock，被占用时直接失败
+type memLock struct {
+	xlock.Lock
+	mu     sync.Mutex
+	locked map[string]bool
+}
+
+func (l *memLock) TryLock(_ context.Context, key string, _ int64, fn func(isLock bool, lc xlock.LockContext) error) error {
+	l.mu.Lock()
+	if l.locked[key] {
+		l.mu.Unlock()
+		return fn(false, nil)
+	}
+	l.locked[key] = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.locked, key)
+		l.mu.Unlock()
+	}()
+	return fn(true, nil)
+}
+
+func newIdempotencyEngine(handler gin.HandlerFunc) (*gin.Engine, *memCache) {
+	gin.SetMode(gin.TestMode)
+	cache := &memCache{values: make(map[string]string)}
+	container := &di.Container{Cache: cache, Lock: &memLock{locked: make(map[string]bool)}}
+	conf := config.IdempotencyConfig{Enable: true, ExpirationTime: time.Hour, LockTime: time.Second}
+	r := gin.New()
+	r.Use(InjectContainerMiddleware(container), func(c *gin.Context) {
+		c.Set(xauth.XUserId, int32(1))
+		handleIdempotency(c, conf)
+	})
+	r.POST("/api/user", handler)
+	r.POST("/api/secret", SensitiveResponse(), handler)
+	r.PUT("/api/user", handler)
+	r.GET("/api/user", handler)
+	return r, cache
+}
+
+func doIdempotent(r *gin.Engine, method, key, body string) *httptest.ResponseRecorder {
+	return doIdempotentPath(r, method, "/api/user", key, body)
+}
+
+func doIdempotentPath(r *gin.Engine, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	calls := 0
+	r, _ := newIdempotencyEngine(func(c *gin.Context) {
+		calls++
+		xresponse.Success(c, gin.H{"id": calls})
+	})
+
+	first := doIdempotent(r, http.MethodPost, "key-1", `{"name":"a"}`)
+	second := doIdempotent(r, http.MethodPost, "key-1", `{"name":"a"}`)
+	if calls != 1 {
+		t.Fatalf("expected handler executed once, got %d", calls)
+	}
+	if second.Body.String() != first.Body.String() || second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected replayed response, got %s", second.Body.String())
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("first response should not be marked replayed")
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Fatalf("expected content type replayed")
+	}
+
+	// 同一 key 不同请求体
+	w := doIdempotent(r, http.MethodPost, "key-1", `{"name":"b"}`)
+	if calls != 1 || !strings.Contains(w.Body.String(), `"code":20108`) {
+		t.Fatalf("expected reused key rejected, got %s", w.Body.String())
+	}
+
+	// 不同 key、未携带 key、非写操作均正常执行
+	doIdempotent(r, http.MethodPost, "key-2", `{"name":"a"}`)
+	doIdempotent(r, http.MethodPost, "", `{"name":"a"}`)
+	doIdempotent(r, http.MethodGet, "key-1", "")
+	if calls != 4 {
+		t.Fatalf("expected 4 executions, got %d", calls)
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	r, _ := newIdempotencyEngine(func(c *gin.Context) {
+		close(entered)
+		<-release
+		xresponse.Success(c, nil)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- doIdempotent(r, http.MethodPut, "key-busy", `{}`) }()
+	<-entered
+	w := doIdempotent(r, http.MethodPut, "key-busy", `{}`)
+	if !strings.Contains(w.Body.String(), `"code":20107`) {
+		t.Fatalf("expected in-progress rejected, got %s", w.Body.String())
+	}
+	close(release)
+	if first := <-done; !strings.Contains(first.Body.String(), `"code":0`) {
+		t.Fatalf("expected first request succeeded, got %s", first.Body.String())
+	}
+}
+
+func TestIdempotencySkipStore(t *testing.T) {
+	calls := 0
+	r, cache := newIdempotencyEngine(func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			xresponse.FailByError(c, e.HttpInternalServerError)
+			return
+		}
+		xresponse.Success(c, nil)
+	})
+
+	// 服务端异常不保存，允许相同 key 重试
+	doIdempotent(r, http.MethodPost, "key-retry", `{}`)
+	if len(cache.values) != 0 {
+		t.Fatalf("expected server error not stored")
+	}
+	doIdempotent(r, http.MethodPost, "key-retry", `{}`)
+	if calls != 2 || len(cache.values) != 1 {
+		t.Fatalf("expected retry executed and stored, calls=%d stored=%d", calls, len(cache.values))
+	}
+
+	w := doIdempotent(r, http.MethodPost, strings.Repeat("k", maxIdempotencyKeyLen+1), `{}`)
+	if !strings.Contains(w.Body.String(), `"code":20106`) || calls != 2 {
+		t.Fatalf("expected invalid key rejected, got %s", w.Body.String())
+	}
+}
+
+func TestIdempotencyStoreAfterClientCancel(t *testing.T) {
+	calls := 0
+	r, cache := newIdempotencyEngine(func(c *gin.Context) {
+		calls++
+		xresponse.Success(c, gin.H{"id": calls})
+	})
+
+	// 客户端在处理完成前断开，请求 ctx 已取消，响应仍需保存
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/api/user", strings.NewReader(`{}`)).WithContext(ctx)
+	req.Header.Set(IdempotencyKeyHeader, "key-cancel")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if len(cache.values) != 1 {
+		t.Fatalf("expected record stored after cancel, stored=%d", len(cache.values))
+	}
+
+	w := doIdempotent(r, http.MethodPost, "key-cancel", `{}`)
+	if calls != 1 || w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected retry replayed, calls=%d body=%s", calls, w.Body.String())
+	}
+}
+
+func TestIdempotencySensitiveResponseNotStored(t *testing.T) {
+	calls := 0
+	r, cache := newIdempotencyEngine(func(c *gin.Context) {
+		calls++
+		xresponse.Success(c, gin.H{"secret": "s-" + strconv.Itoa(calls)})
+	})
+
+	first := doIdempotentPath(r, http.MethodPost, "/api/secret", "key-secret", `{}`)
+	if len(cache.values) != 0 {
+		t.Fatalf("expected sensitive response not stored, got %v", cache.values)
+	}
+	second := doIdempotentPath(r, http.MethodPost, "/api/secret", "key-secret", `{}`)
+	if calls != 2 || second.Header().Get(IdempotentReplayedHeader) != "" || second.Body.String() == first.Body.String() {
+		t.Fatalf("expected sensitive request executed again, calls=%d", calls)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// IPWhiteList 返回一个中间件，仅允许白名单内 IP 访问
+// IPWhiteList 返回一个中间件，仅允许白名单内 IP 访问；客户端 IP 由引擎的可信代理配置决定，不可信来源的 X-Forwarded-For 不生效
 func IPWhiteList(whiteList []string) gin.HandlerFunc {
 	var cidrs []*net.IPNet
 	for _, cidr := range whiteList {
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIPWhiteListIgnoresUntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatalf("SetTrustedProxies error: %v", err)
+	}
+	r.GET("/metrics", IPWhiteList([]string{"127.0.0.1/32"}), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	// 非可信代理伪造 X-Forwarded-For 不能绕过白名单
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.RemoteAddr = "203.0.113.7:40000"
+	req.Header.Set("X-Forwarded-For", "127.0.0.1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() == "ok" {
+		t.Fatalf("expected spoofed forwarded ip rejected")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.RemoteAddr = "127.0.0.1:40000"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != "ok" {
+		t.Fatalf("expected loopback allowed, got %s", w.Body.String())
+	}
+}
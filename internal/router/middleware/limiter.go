@@ -2,11 +2,20 @@ package middleware
 
 import (
 	"context"
-	"github.com/gin-gonic/gin"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"snowgo/internal/di"
+	"snowgo/pkg/xauth"
 	e "snowgo/pkg/xerror"
 	"snowgo/pkg/xlimiter"
+	"snowgo/pkg/xlogger"
 	"snowgo/pkg/xresponse"
-	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 // AccessLimiter 可用于路由访问限流，可设置等待时间
@@ -25,14 +34,82 @@ func AccessLimiter(key string, r xlimiter.BucketLimit, b int, waitTime time.Dura
 	}
 }
 
-// KeyLimiter 可用于ip，用户等标示性限流
-func KeyLimiter(r xlimiter.BucketLimit, b int) gin.HandlerFunc {
+// KeyLimiter 按 keyFunc 选取的维度做多副本共享的限流（ip、用户等），prefix 区分不同业务的限流 key；
+// 首次请求时用容器中的 Redis 创建 xlimiter.RedisLimiter，容器未配置 Redis 时退化为本地令牌桶
+func KeyLimiter(prefix string, r xlimiter.BucketLimit, b int, keyFunc LimitKeyFunc) gin.HandlerFunc {
+	var (
+		once    sync.Once
+		handler gin.HandlerFunc
+	)
 	return func(c *gin.Context) {
-		// key 除了ip 之外也可以是其他的，例如user name等根据需求调整
-		key := c.ClientIP()
+		once.Do(func() {
+			var limiter xlimiter.Limiter = xlimiter.NewLocalLimiter(prefix, r, b)
+			if container, ok := di.GetContainerSafe(c); ok && container.Cache != nil {
+				redisLimiter, err := xlimiter.NewRedisLimiter(container.Cache, prefix, r, b)
+				if err != nil {
+					xlogger.ErrorfCtx(c.Request.Context(), "new redis limiter failed, use local limiter: prefix=%s err=%v", prefix, err)
+				} else {
+					limiter = redisLimiter
+				}
+			}
+			handler = RateLimiter(limiter, keyFunc)
+		})
+		handler(c)
+	}
+}
+
+// LimitKeyFunc 限流维度，返回空字符串时不限流
+type LimitKeyFunc func(c *gin.Context) string
+
+// LimitByIP 按客户端 IP 限流
+func LimitByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// LimitByUser 按登录用户限流，需放在 JWTAuth 之后；未登录时退化为按 IP
+func LimitByUser(c *gin.Context) string {
+	if userId := c.GetInt32(xauth.XUserId); userId > 0 {
+		return "user:" + strconv.Itoa(int(userId))
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// LimitByRoute 按路由模板限流，所有调用方共享同一额度
+func LimitByRoute(c *gin.Context) string {
+	return c.Request.Method + ":" + c.FullPath()
+}
 
-		bucketLimiter, _ := xlimiter.NewTokenBucket(key, r, b)
-		if !bucketLimiter.Allow() {
+// RateLimiter 按 keyFunc 选取的维度限流，limiter 可选本地令牌桶(xlimiter.LocalLimiter)或 Redis 分布式限流(xlimiter.RedisLimiter)；
+// 响应携带 X-RateLimit-Limit / X-RateLimit-Remaining / X-RateLimit-Reset，被拒绝时额外携带 Retry-After
+func RateLimiter(limiter xlimiter.Limiter, keyFunc LimitKeyFunc) gin.HandlerFunc {
+	// 降级状态只在切换时记录日志，Redis 故障期间不会每个请求都打一条
+	var degraded atomic.Bool
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		res, err := limiter.Take(c.Request.Context(), key)
+		if err != nil {
+			// 限流器故障时放行，避免影响业务
+			xlogger.ErrorfCtx(c.Request.Context(), "rate limiter take failed: key=%s err=%v", key, err)
+			c.Next()
+			return
+		}
+		if degraded.CompareAndSwap(!res.Degraded, res.Degraded) {
+			if res.Degraded {
+				xlogger.ErrorfCtx(c.Request.Context(), "rate limiter degraded to local: key=%s", key)
+			} else {
+				xlogger.InfofCtx(c.Request.Context(), "rate limiter recovered from local: key=%s", key)
+			}
+		}
+		h := c.Writer.Header()
+		h.Set("X-RateLimit-Limit", strconv.FormatInt(res.Limit, 10))
+		h.Set("X-RateLimit-Remaining", strconv.FormatInt(res.Remaining, 10))
+		h.Set("X-RateLimit-Reset", strconv.FormatInt(ceilSeconds(res.ResetAfter), 10))
+		if !res.Allowed {
+			h.Set("Retry-After", strconv.FormatInt(max(ceilSeconds(res.RetryAfter), 1), 10))
 			xresponse.FailByError(c, e.KeyTooManyRequests)
 			c.Abort()
 			return
@@ -40,3 +117,8 @@ func KeyLimiter(r xlimiter.BucketLimit, b int) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ceilSeconds 时长向上取整为秒
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
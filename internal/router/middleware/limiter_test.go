@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"snowgo/internal/di"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xlimiter"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RateLimiter(xlimiter.NewLocalLimiter("test:mw:ip:", 0.001, 2), LimitByIP))
+	r.GET("/api/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	for i := 2; i > 0; i-- {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+		if w.Body.String() != "pong" {
+			t.Fatalf("expected request passed, got %s", w.Body.String())
+		}
+		if w.Header().Get("X-RateLimit-Limit") != "2" || w.Header().Get("X-RateLimit-Remaining") != strconv.Itoa(i-1) {
+			t.Fatalf("unexpected rate limit headers: %v", w.Header())
+		}
+		if w.Header().Get("Retry-After") != "" {
+			t.Fatalf("unexpected Retry-After on allowed request")
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	if w.Body.String() == "pong" {
+		t.Fatalf("expected request limited")
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get("X-RateLimit-Remaining") != "0" || w.Header().Get("X-RateLimit-Reset") == "" {
+		t.Fatalf("unexpected limited headers: %v", w.Header())
+	}
+}
+
+func TestLimitKeyFunc(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var keys []string
+	r := gin.New()
+	r.GET("/api/user/:id", func(c *gin.Context) {
+		keys = append(keys, LimitByUser(c))
+		c.Set(xauth.XUserId, int32(7))
+		keys = append(keys, LimitByUser(c), LimitByRoute(c))
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/user/1", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := []string{"ip:10.0.0.1", "user:7", "GET:/api/user/:id"}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("key %d = %q, want %q", i, keys[i], want[i])
+		}
+	}
+}
+
+// evalCache 只实现限流用到的 Eval，err 不为空时模拟 Redis 不可用
+type evalCache struct {
+	xcache.Cache
+	keys []string
+	err  error
+}
+
+func (c *evalCache) Eval(_ context.Context, _ string, keys []string, _ ...any) (any, error) {
+	c.keys = append(c.keys, keys...)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []any{int64(1), int64(4), int64(0), int64(200000)}, nil
+}
+
+func TestKeyLimiterUsesContainerRedis(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := &evalCache{}
+	r := gin.New()
+	r.Use(InjectContainerMiddleware(&di.Container{Cache: cache}))
+	r.GET("/api/ping", KeyLimiter("test:rate:", 5, 5, LimitByIP), func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != "pong" || w.Header().Get("X-RateLimit-Remaining") != "4" {
+		t.Fatalf("expected redis result used, got %s %v", w.Body.String(), w.Header())
+	}
+	if len(cache.keys) != 1 || cache.keys[0] != "test:rate:10.0.0.1" {
+		t.Fatalf("expected redis key with prefix, got %v", cache.keys)
+	}
+}
+
+func TestKeyLimiterDegradedStillLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := &evalCache{err: errors.New("redis down")}
+	r := gin.New()
+	r.Use(InjectContainerMiddleware(&di.Container{Cache: cache}))
+	r.GET("/api/ping", KeyLimiter("test:rate:degraded:", 0.001, 2, LimitByIP), func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	// Redis 不可用时降级为本地令牌桶，仍然限流
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+		bodies = append(bodies, w.Body.String())
+	}
+	if bodies[0] != "pong" || bodies[1] != "pong" || bodies[2] == "pong" {
+		t.Fatalf("expected local fallback to limit after burst, got %v", bodies)
+	}
+}
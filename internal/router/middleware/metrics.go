@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"snowgo/pkg/xmetrics"
+	"snowgo/pkg/xresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedPath 未匹配到路由的请求统一归为一类，避免扫描请求撑爆 path 标签
+const unmatchedPath = "unmatched"
+
+// Metrics 记录 HTTP 请求数与耗时，path 取路由模板（如 /api/admin/user/:id）
+func Metrics(m *xmetrics.HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = unmatchedPath
+		}
+		// 未经过 xresponse 输出的响应（如 pprof、文件下载）没有业务码
+		bizCode := ""
+		if code, ok := c.Get(xresponse.BizCode); ok {
+			if v, ok := code.(int); ok {
+				bizCode = strconv.Itoa(v)
+			}
+		}
+		m.Observe(c.Request.Method, path, strconv.Itoa(c.Writer.Status()), bizCode, time.Since(start))
+	}
+}
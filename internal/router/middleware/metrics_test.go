@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"snowgo/pkg/xmetrics"
+	"snowgo/pkg/xresponse"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// counterByLabels 按 path|status|biz_code 汇总请求计数
+func counterByLabels(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather error: %v", err)
+	}
+	got := map[string]float64{}
+	for _, mf := range mfs {
+		if mf.GetName() != "snowgo_http_requests_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			got[labels["path"]+"|"+labels["status"]+"|"+labels["biz_code"]] = m.GetCounter().GetValue()
+		}
+	}
+	return got
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+	r := gin.New()
+	r.Use(Metrics(xmetrics.NewHTTPMetrics(reg)))
+	r.GET("/api/user/:id", func(c *gin.Context) {
+		xresponse.Json(c, 10001, "fail", nil)
+	})
+
+	for _, path := range []string{"/api/user/1", "/api/user/2", "/not-found"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	got := counterByLabels(t, reg)
+	if got["/api/user/:id|200|10001"] != 2 {
+		t.Fatalf("expected route template label, got %v", got)
+	}
+	if got["unmatched|404|"] != 1 {
+		t.Fatalf("expected unmatched path without biz code, got %v", got)
+	}
+}
+
+func TestMetricsCountsPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+	r := gin.New()
+	// 与 loadMiddleWare 顺序一致：Metrics 在外层，panic 由 Recovery 转换后再统计
+	r.Use(Metrics(xmetrics.NewHTTPMetrics(reg)), Recovery())
+	r.GET("/api/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/panic", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected recovered response, got %d", w.Code)
+	}
+	if got := counterByLabels(t, reg); got["/api/panic|200|500"] != 1 {
+		t.Fatalf("expected panic counted with biz code 500, got %v", got)
+	}
+}
@@ -284,35 +284,6 @@ func Recovery() gin.HandlerFunc {
 	}
 }
 
-// Cors 前后端跨域设置
-func Cors() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		method := c.Request.Method
-		origin := c.Request.Header.Get("Origin")
-		if origin == "" {
-			c.Next()
-			return
-		}
-
-		// 服务端允许的地址
-		c.Header("Access-Control-Allow-Origin", origin)
-		// 服务端支持的所有跨域请求的方法
-		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, DELETE, PUT, PATCH")
-		//允许跨域设置可以返回其他子段(可根据需求添加)
-		c.Header("Access-Control-Allow-Headers", "Authorization, Content-Length, X-CSRF-Token, Token, Session, X_Requested_With, Accept, Origin, Host, Connection, Accept-Encoding, Accept-Language, DNT, X-CustomHeader, Keep-Alive, User-Agent, X-Requested-With, If-Modified-Since, Cache-Control, Content-Type, Pragma")
-		// 允许浏览器（客户端）可以解析的头部
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Content-Type")
-		//允许客户端传递校验信息比如 cookie
-		c.Header("Access-Control-Allow-Credentials", "true")
-
-		if method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-		}
-
-		c.Next()
-	}
-}
-
 // InjectContainerMiddleware 注入container
 func InjectContainerMiddleware(container *di.Container) gin.HandlerFunc {
 	return func(c *gin.Context) {
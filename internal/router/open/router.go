@@ -0,0 +1,27 @@
+package open
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"snowgo/internal/api/open"
+	"snowgo/internal/constant"
+	"snowgo/internal/router/middleware"
+	"snowgo/pkg/xlimiter"
+)
+
+const (
+	openRateInterval = 20 * time.Millisecond // 同一 IP 调用开放接口的平均间隔（50 次/秒）
+	openRateBurst    = 100                   // 同一 IP 调用开放接口的最大突发次数
+)
+
+// Register 开放接口路由，供合作方以 HMAC-SHA256 签名调用；验签前先按 IP 限流
+func Register(r *gin.RouterGroup) {
+	openGroup := r.Group("/open",
+		middleware.KeyLimiter(constant.RateLimitOpenPrefix, xlimiter.BucketEvery(openRateInterval), openRateBurst, middleware.LimitByIP),
+		middleware.SignatureAuth(), middleware.Idempotency())
+	{
+		openGroup.GET("/ping", open.Ping)
+		openGroup.POST("/ping", open.Ping)
+	}
+}
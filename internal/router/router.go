@@ -1,17 +1,23 @@
 package router
 
 import (
+	"fmt"
 	"github.com/gin-contrib/pprof"
 	"snowgo/config"
 	"snowgo/internal/api"
 	"snowgo/internal/di"
 	"snowgo/internal/router/admin"
 	"snowgo/internal/router/middleware"
+	"snowgo/internal/router/open"
 	"snowgo/pkg/xenv"
 	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xmetrics"
 	"snowgo/pkg/xresponse"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type option func(*gin.RouterGroup)
@@ -27,9 +33,14 @@ func setMode() {
 	}
 }
 
-// 中间件注册使用
-func loadMiddleWare(router *gin.Engine, container *di.Container) {
+// 中间件注册使用，配置无效时返回错误
+func loadMiddleWare(router *gin.Engine, container *di.Container) error {
 	cfg := config.Get()
+	// 请求指标，放在 Recovery 之前，panic 由内层 Recovery 转换为 500 后再统计
+	if cfg.Metrics.Enable {
+		router.Use(middleware.Metrics(xmetrics.HTTP()))
+	}
+
 	router.Use(middleware.Recovery())
 
 	// 链路追踪
@@ -46,7 +57,16 @@ func loadMiddleWare(router *gin.Engine, container *di.Container) {
 
 	// 注入客户端 IP、User-Agent 到 Gin/标准 Context，供登录日志、操作日志及业务层读取
 	router.Use(middleware.AccessLogger())
-	//router.Use(middleware.Cors())
+
+	// 跨域，按环境配置来源白名单
+	if cfg.Cors.Enable {
+		policy, err := middleware.NewCorsPolicy(cfg.Cors)
+		if err != nil {
+			return fmt.Errorf("cors: %w", err)
+		}
+		router.Use(middleware.Cors(policy))
+	}
+	return nil
 }
 
 // 注册所有路由
@@ -65,16 +85,26 @@ func loadRouter(router *gin.Engine) {
 		pprof.Register(pprofGroup)
 	}
 
+	// 指标抓取接口，未配置独立监听地址时挂载在业务端口上(白名单访问)
+	if cfg.Metrics.Enable && cfg.Metrics.Addr == "" {
+		metricsGroup := router.Group("", middleware.IPWhiteList(cfg.Metrics.AllowCidrs))
+		metricsGroup.GET(xmetrics.PathOrDefault(cfg.Metrics.Path), gin.WrapH(xmetrics.Handler()))
+	}
+
 	// 注册健康检查
 	router.GET("/healthz", api.Liveness)
 	router.GET("/readyz", api.Readiness)
 
+	// JWT 验签公钥，公开访问(仅包含公钥)，路径遵循 RFC 8615 .well-known 约定，不加 /api 前缀
+	router.GET("/.well-known/jwks.json", api.JWKS)
+
 	// 创建根路由组，并添加前缀
 	apiGroup := router.Group("/api")
 
 	rootRouters(apiGroup) // 根目录下路由
 	options := []option{  // 根据不同分组注册路由
 		admin.Register, // 后台管理相关路由
+		open.Register,  // 开放接口路由（签名认证）
 	}
 
 	// 注册其他分组下的路由
@@ -83,15 +113,57 @@ func loadRouter(router *gin.Engine) {
 	}
 }
 
-// InitRouter 初始化路由
-func InitRouter(container *di.Container) *gin.Engine {
+// registerCollectors 注册连接池等依赖的指标采集器
+func registerCollectors(container *di.Container) {
+	var cs []prometheus.Collector
+	if myDB := container.GetMyDB(); myDB != nil {
+		dbCollectors, err := xmetrics.NewDBCollectors(myDB.DbMap)
+		if err != nil {
+			xlogger.Errorf("new db metrics collector failed: %v", err)
+		}
+		cs = append(cs, dbCollectors...)
+	}
+	if rdb := container.GetRDB(); rdb != nil {
+		cs = append(cs, xmetrics.NewRedisCollector("default", rdb))
+	}
+	if producer, ok := container.Producer.(xmetrics.ProducerStater); ok {
+		cs = append(cs, xmetrics.NewProducerCollector(producer))
+	}
+	if err := xmetrics.Register(cs...); err != nil {
+		xlogger.Errorf("register metrics collector failed: %v", err)
+	}
+}
+
+// trustedProxies 去掉空项，环境变量未设置时配置中可能出现空字符串
+func trustedProxies(list []string) []string {
+	proxies := make([]string, 0, len(list))
+	for _, p := range list {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// InitRouter 初始化路由，中间件配置无效时返回错误
+func InitRouter(container *di.Container) (*gin.Engine, error) {
 	// 设置模式
 	setMode()
 	// 创建引擎
 	router := gin.New()
+	// 只信任配置的反向代理转发的客户端 IP，IP 白名单、限流与日志都依赖 ClientIP
+	if err := router.SetTrustedProxies(trustedProxies(config.Get().Application.Server.TrustedProxies)); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	// 指标采集器注册
+	if config.Get().Metrics.Enable {
+		registerCollectors(container)
+	}
 	// 中间件注册
-	loadMiddleWare(router, container)
+	if err := loadMiddleWare(router, container); err != nil {
+		return nil, err
+	}
 	// 路由注册
 	loadRouter(router)
-	return router
+	return router, nil
 }
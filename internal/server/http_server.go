@@ -19,12 +19,15 @@ var (
 	HttpServer *http.Server
 )
 
-// StartHttpServer 初始化路由，开启http服务
-func StartHttpServer(container *di.Container) {
+// StartHttpServer 初始化路由，开启http服务；路由初始化失败时返回错误
+func StartHttpServer(container *di.Container) error {
 	// 记录启动时间
 	xruntime.SetStartTime()
 	// 初始化路由
-	r := router.InitRouter(container)
+	r, err := router.InitRouter(container)
+	if err != nil {
+		return fmt.Errorf("init router: %w", err)
+	}
 	cfg := config.Get()
 	HttpServer = &http.Server{
 		Addr:           fmt.Sprintf("%s:%d", cfg.Application.Server.Addr, cfg.Application.Server.Port),
@@ -56,6 +59,7 @@ func StartHttpServer(container *di.Container) {
 			xlogger.Panicf("Server Listen: %s\n", err)
 		}
 	}()
+	return nil
 }
 
 // StopHttpServer 停止服务
@@ -74,7 +78,7 @@ func StopHttpServer() (err error) {
 func RestartHttpServer(container *di.Container) (err error) {
 	err = StopHttpServer()
 	if err == nil {
-		StartHttpServer(container)
+		err = StartHttpServer(container)
 	}
 	return
 }
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"snowgo/config"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xmetrics"
+	"time"
+)
+
+var (
+	MetricsServer *http.Server
+)
+
+// StartMetricsServer 配置了独立监听地址时开启指标服务，与业务端口隔离
+func StartMetricsServer() {
+	cfg := config.Get()
+	if !cfg.Metrics.Enable || cfg.Metrics.Addr == "" {
+		return
+	}
+	MetricsServer = xmetrics.NewServer(cfg.Metrics.Addr, cfg.Metrics.Path)
+	go func() {
+		xlogger.Infof("Metrics server is running on http://%s%s", MetricsServer.Addr, xmetrics.PathOrDefault(cfg.Metrics.Path))
+		if err := MetricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			xlogger.Panicf("Metrics Server Listen: %s\n", err)
+		}
+	}()
+}
+
+// StopMetricsServer 停止指标服务
+func StopMetricsServer() {
+	if MetricsServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := MetricsServer.Shutdown(ctx); err != nil {
+		xlogger.Errorf("Metrics Server Shutdown: %s", err.Error())
+	}
+}
@@ -0,0 +1,377 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xcryption"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+const (
+	apiKeyScheme      = "sk_" // API Key 固定前缀，便于日志、代码扫描识别
+	apiKeyPrefixBytes = 4     // 展示前缀随机部分字节数（8位十六进制）
+	apiKeySecretBytes = 24    // 密钥随机部分字节数（48位十六进制）
+)
+
+var (
+	ErrApiKeyInvalid        = e.NewBizError(e.ApiKeyInvalid)
+	ErrApiKeyNotFound       = e.NewBizError(e.ApiKeyNotFound)
+	ErrApiKeyExpireInvalid  = e.NewBizError(e.ApiKeyExpireInvalid)
+	ErrApiKeyUserNotService = e.NewBizError(e.ApiKeyUserNotService)
+	ErrApiKeyTimeFormat     = e.NewBizError(e.TimeFormatError)
+)
+
+// ApiKeyRepo 定义API Key相关db操作接口
+type ApiKeyRepo interface {
+	CreateApiKey(ctx context.Context, q *query.Query, apiKey *model.SysAPIKey) (*model.SysAPIKey, error)
+	GetApiKeyList(ctx context.Context, userId int32) ([]*model.SysAPIKey, error)
+	GetApiKeyById(ctx context.Context, q *query.Query, keyId int32) (*model.SysAPIKey, error)
+	GetApiKeyByHash(ctx context.Context, keyHash string) (*model.SysAPIKey, error)
+	RevokeApiKey(ctx context.Context, q *query.Query, keyId int32) (bool, error)
+	UpdateLastUsedAt(ctx context.Context, keyId int32, usedAt time.Time) error
+}
+
+// ApiKeyService 服务账号 API Key，供机器对机器调用
+type ApiKeyService struct {
+	db         *repo.Repository
+	apiKeyDao  ApiKeyRepo
+	userDao    UserRepo
+	cache      xcache.Cache
+	logService contract.OperationLogWriter
+}
+
+func NewApiKeyService(db *repo.Repository, apiKeyDao ApiKeyRepo, userDao UserRepo, cache xcache.Cache,
+	logService contract.OperationLogWriter) *ApiKeyService {
+	return &ApiKeyService{
+		db:         db,
+		apiKeyDao:  apiKeyDao,
+		userDao:    userDao,
+		cache:      cache,
+		logService: logService,
+	}
+}
+
+type ApiKeyParam struct {
+	Name      string `json:"name" binding:"required,max=64"`
+	ExpiredAt string `json:"expired_at"` // 过期时间 yyyy-MM-dd HH:mm:ss，为空表示永不过期
+}
+
+type ApiKeyInfo struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	Prefix     string
+	Status     int8
+	ExpiredAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedBy  int32
+	CreatedAt  time.Time
+}
+
+// ApiKeyCreated 新建的API Key，明文仅在创建时返回一次
+type ApiKeyCreated struct {
+	ApiKeyInfo
+	Key string
+}
+
+// ApiKeyPrincipal API Key 认证通过后的调用方身份
+type ApiKeyPrincipal struct {
+	KeyId     int32      `json:"key_id"`
+	UserId    int32      `json:"user_id"`
+	Username  string     `json:"username"`
+	ExpiredAt *time.Time `json:"expired_at"`
+}
+
+// CreateApiKey 为服务账号创建API Key，返回的明文Key不落库，丢失只能重新创建
+func (s *ApiKeyService) CreateApiKey(ctx context.Context, userId int32, param *ApiKeyParam) (*ApiKeyCreated, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userId <= 0 {
+		return nil, ErrUserNotFound
+	}
+	var expiredAt *time.Time
+	if param.ExpiredAt != "" {
+		t, err := time.ParseInLocation(constant.TimeFmtWithS, param.ExpiredAt, time.Local)
+		if err != nil {
+			return nil, ErrApiKeyTimeFormat
+		}
+		if !t.After(time.Now()) {
+			return nil, ErrApiKeyExpireInvalid
+		}
+		expiredAt = &t
+	}
+	user, err := s.userDao.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userId, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	if !isServiceAccount(user) {
+		return nil, ErrApiKeyUserNotService
+	}
+
+	rawKey, prefix, err := generateApiKey()
+	if err != nil {
+		return nil, err
+	}
+	activeStatus := constant.ApiKeyStatusActive
+	var apiKey *model.SysAPIKey
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		apiKey, err = s.apiKeyDao.CreateApiKey(ctx, tx, &model.SysAPIKey{
+			UserID:    userId,
+			Name:      param.Name,
+			Prefix:    prefix,
+			KeyHash:   xcryption.Sha256(rawKey),
+			Status:    &activeStatus,
+			ExpiredAt: expiredAt,
+			CreatedBy: &userContext.UserId,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "API Key创建失败 user_id=%d err: %v", userId, err)
+			return fmt.Errorf("API Key创建失败: %w", err)
+		}
+		return s.writeLog(ctx, tx, userContext, apiKey.ID, constant.ActionCreate, nil, apiKey,
+			fmt.Sprintf("用户(%d-%s)为服务账号(%d-%s)创建了API Key(%d-%s)",
+				userContext.UserId, userContext.Username, userId, user.Username, apiKey.ID, prefix))
+	})
+	if err != nil {
+		return nil, err
+	}
+	xlogger.InfofCtx(ctx, "API Key创建成功 user_id=%d key_id=%d prefix=%s", userId, apiKey.ID, prefix)
+	return &ApiKeyCreated{ApiKeyInfo: *toApiKeyInfo(apiKey), Key: rawKey}, nil
+}
+
+// GetApiKeyList 查询服务账号的API Key列表
+func (s *ApiKeyService) GetApiKeyList(ctx context.Context, userId int32) ([]*ApiKeyInfo, error) {
+	if userId <= 0 {
+		return nil, ErrUserNotFound
+	}
+	keyList, err := s.apiKeyDao.GetApiKeyList(ctx, userId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询服务账号(%d)API Key列表异常: %v", userId, err)
+		return nil, fmt.Errorf("API Key列表查询失败: %w", err)
+	}
+	infoList := make([]*ApiKeyInfo, 0, len(keyList))
+	for _, apiKey := range keyList {
+		infoList = append(infoList, toApiKeyInfo(apiKey))
+	}
+	return infoList, nil
+}
+
+// RevokeApiKey 吊销服务账号的API Key，立即失效
+func (s *ApiKeyService) RevokeApiKey(ctx context.Context, userId, keyId int32) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	if userId <= 0 || keyId <= 0 {
+		return ErrApiKeyNotFound
+	}
+	var keyHash string
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		oldKey, err := s.apiKeyDao.GetApiKeyById(ctx, tx, keyId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrApiKeyNotFound
+			}
+			xlogger.ErrorfCtx(ctx, "查询API Key(%d)异常: %v", keyId, err)
+			return fmt.Errorf("API Key查询失败: %w", err)
+		}
+		// 不属于该服务账号或已吊销的 Key 均视为不存在
+		if oldKey.UserID != userId {
+			return ErrApiKeyNotFound
+		}
+		revoked, err := s.apiKeyDao.RevokeApiKey(ctx, tx, keyId)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "API Key(%d)吊销异常: %v", keyId, err)
+			return fmt.Errorf("API Key吊销失败: %w", err)
+		}
+		if !revoked {
+			return ErrApiKeyNotFound
+		}
+		keyHash = oldKey.KeyHash
+		return s.writeLog(ctx, tx, userContext, keyId, constant.ActionDelete, oldKey, nil,
+			fmt.Sprintf("用户(%d-%s)吊销了服务账号(%d)的API Key(%d-%s)",
+				userContext.UserId, userContext.Username, userId, keyId, oldKey.Prefix))
+	})
+	if err != nil {
+		return err
+	}
+	xlogger.InfofCtx(ctx, "API Key吊销成功 user_id=%d key_id=%d", userId, keyId)
+
+	// 清除认证缓存
+	if _, cacheErr := s.cache.Delete(ctx, constant.CacheApiKeyPrefix+keyHash); cacheErr != nil {
+		xlogger.ErrorfCtx(ctx, "清除API Key认证缓存失败 key_id=%d: %v", keyId, cacheErr)
+	}
+	return nil
+}
+
+// Authenticate 校验API Key，认证结果短暂缓存，缓存未命中时校验Key状态、有效期及服务账号状态
+func (s *ApiKeyService) Authenticate(ctx context.Context, rawKey string) (*ApiKeyPrincipal, error) {
+	if !strings.HasPrefix(rawKey, apiKeyScheme) ||
+		len(rawKey) != len(apiKeyScheme)+apiKeyPrefixBytes*2+1+apiKeySecretBytes*2 {
+		return nil, ErrApiKeyInvalid
+	}
+	keyHash := xcryption.Sha256(rawKey)
+	cacheKey := constant.CacheApiKeyPrefix + keyHash
+	now := time.Now()
+
+	raw, ok, err := s.cache.Get(ctx, cacheKey)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询API Key认证缓存失败: %v", err)
+	}
+	if ok {
+		var principal ApiKeyPrincipal
+		if err := json.Unmarshal([]byte(raw), &principal); err == nil {
+			if principal.ExpiredAt != nil && !principal.ExpiredAt.After(now) {
+				return nil, ErrApiKeyInvalid
+			}
+			return &principal, nil
+		}
+	}
+
+	apiKey, err := s.apiKeyDao.GetApiKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApiKeyInvalid
+		}
+		xlogger.ErrorfCtx(ctx, "查询API Key异常: %v", err)
+		return nil, fmt.Errorf("API Key查询失败: %w", err)
+	}
+	if common.DerefOrZero(apiKey.Status) != constant.ApiKeyStatusActive ||
+		(apiKey.ExpiredAt != nil && !apiKey.ExpiredAt.After(now)) {
+		return nil, ErrApiKeyInvalid
+	}
+	user, err := s.userDao.GetUserById(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApiKeyInvalid
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", apiKey.UserID, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	if !isServiceAccount(user) || common.DerefOrZero(user.Status) != constant.UserStatusActive {
+		return nil, ErrApiKeyInvalid
+	}
+
+	principal := &ApiKeyPrincipal{
+		KeyId:     apiKey.ID,
+		UserId:    user.ID,
+		Username:  user.Username,
+		ExpiredAt: apiKey.ExpiredAt,
+	}
+	// 最后使用时间随缓存未命中更新，精度为缓存时长
+	if err := s.apiKeyDao.UpdateLastUsedAt(ctx, apiKey.ID, now); err != nil {
+		xlogger.ErrorfCtx(ctx, "更新API Key(%d)最后使用时间失败: %v", apiKey.ID, err)
+	}
+	ttl := constant.CacheApiKeyExpirationSecond * time.Second
+	if apiKey.ExpiredAt != nil && apiKey.ExpiredAt.Sub(now) < ttl {
+		ttl = apiKey.ExpiredAt.Sub(now)
+	}
+	if data, err := json.Marshal(principal); err == nil {
+		// 先登记到服务账号索引，保证禁用、删除服务账号时能找到全部缓存
+		userKey := fmt.Sprintf("%s%d", constant.CacheApiKeyUserPrefix, user.ID)
+		if err := s.cache.HSet(ctx, userKey, keyHash, "1"); err != nil {
+			xlogger.ErrorfCtx(ctx, "登记API Key认证缓存索引失败 key_id=%d: %v", apiKey.ID, err)
+			return principal, nil
+		}
+		_ = s.cache.Expire(ctx, userKey, 2*constant.CacheApiKeyExpirationSecond*time.Second)
+		if err := s.cache.Set(ctx, cacheKey, string(data), ttl); err != nil {
+			xlogger.ErrorfCtx(ctx, "写入API Key认证缓存失败 key_id=%d: %v", apiKey.ID, err)
+		}
+	}
+	return principal, nil
+}
+
+// clearUserApiKeyCache 清除服务账号全部 API Key 的认证缓存，服务账号禁用、删除后立即生效
+func clearUserApiKeyCache(ctx context.Context, cache xcache.Cache, userId int32) {
+	userKey := fmt.Sprintf("%s%d", constant.CacheApiKeyUserPrefix, userId)
+	hashes, err := cache.HGetAll(ctx, userKey)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询API Key认证缓存索引失败 uid=%d: %v", userId, err)
+		return
+	}
+	keys := make([]string, 0, len(hashes)+1)
+	for keyHash := range hashes {
+		keys = append(keys, constant.CacheApiKeyPrefix+keyHash)
+	}
+	keys = append(keys, userKey)
+	if _, err := cache.Delete(ctx, keys...); err != nil {
+		xlogger.ErrorfCtx(ctx, "清除API Key认证缓存失败 uid=%d: %v", userId, err)
+	}
+}
+
+func (s *ApiKeyService) writeLog(ctx context.Context, tx *query.Query, operator *xauth.UserContext,
+	keyId int32, action string, before, after *model.SysAPIKey, description string) error {
+	input := &contract.OperationLogInput{
+		OperatorID:   operator.UserId,
+		OperatorName: operator.Username,
+		OperatorType: constant.OperatorUser,
+		Resource:     constant.ResourceApiKey,
+		ResourceID:   int64(keyId),
+		TraceID:      operator.TraceId,
+		Action:       action,
+		Description:  description,
+		IP:           operator.IP,
+	}
+	// 避免 typed nil 写入 "null"
+	if before != nil {
+		input.BeforeData = before
+	}
+	if after != nil {
+		input.AfterData = after
+	}
+	if err := s.logService.CreateOperationLog(ctx, tx, input); err != nil {
+		xlogger.ErrorfCtx(ctx, "操作日志创建失败 resource=api_key resource_id=%d err: %v", keyId, err)
+		return fmt.Errorf("操作日志创建失败: %w", err)
+	}
+	return nil
+}
+
+// generateApiKey 生成API Key，格式 sk_<8位前缀>_<48位密钥>，返回明文及展示前缀
+func generateApiKey() (string, string, error) {
+	buf := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("生成API Key失败: %w", err)
+	}
+	prefix := apiKeyScheme + hex.EncodeToString(buf[:apiKeyPrefixBytes])
+	return prefix + "_" + hex.EncodeToString(buf[apiKeyPrefixBytes:]), prefix, nil
+}
+
+func toApiKeyInfo(apiKey *model.SysAPIKey) *ApiKeyInfo {
+	return &ApiKeyInfo{
+		ID:         apiKey.ID,
+		UserID:     apiKey.UserID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Status:     common.DerefOrZero(apiKey.Status),
+		ExpiredAt:  apiKey.ExpiredAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedBy:  common.DerefOrZero(apiKey.CreatedBy),
+		CreatedAt:  common.DerefOrZero(apiKey.CreatedAt),
+	}
+}
@@ -0,0 +1,67 @@
+//go:build integration
+
+package account
+
+import (
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+func TestApiKeyServiceLifecycleIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	operator := insertIntegrationUser(t, db, "api_key_operator", "18100000201")
+	ctx := mfaUserCtx(operator)
+	userService := newIntegrationUserService(deps)
+	service := newIntegrationApiKeyService(deps)
+
+	serviceUserId, err := userService.CreateUser(ctx, &UserParam{Username: "svc-ci", UserType: constant.UserTypeService})
+	if err != nil {
+		t.Fatalf("CreateUser(service account) error: %v", err)
+	}
+	if _, err := userService.Authenticate(ctx, "svc-ci", ""); err != ErrAuth {
+		t.Fatalf("service account must not log in with password, got %v", err)
+	}
+	if _, err := service.CreateApiKey(ctx, operator.ID, &ApiKeyParam{Name: "ci"}); err != ErrApiKeyUserNotService {
+		t.Fatalf("expected ErrApiKeyUserNotService for normal user, got %v", err)
+	}
+
+	created, err := service.CreateApiKey(ctx, serviceUserId, &ApiKeyParam{Name: "ci"})
+	if err != nil {
+		t.Fatalf("CreateApiKey error: %v", err)
+	}
+	if countRows(t, db, model.TableNameSysAPIKey, "key_hash = ?", created.Key) != 0 {
+		t.Fatal("plaintext api key must not be stored")
+	}
+	queryOperationLog(t, db, constant.ResourceApiKey, int64(created.ID), constant.ActionCreate)
+
+	principal, err := service.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("Authenticate error: %v", err)
+	}
+	if principal.UserId != serviceUserId || principal.KeyId != created.ID {
+		t.Fatalf("unexpected principal: %+v", principal)
+	}
+	list, err := service.GetApiKeyList(ctx, serviceUserId)
+	if err != nil || len(list) != 1 || list[0].LastUsedAt == nil || list[0].Prefix != created.Prefix {
+		t.Fatalf("unexpected api key list: %+v %v", list, err)
+	}
+
+	if err := service.RevokeApiKey(ctx, operator.ID, created.ID); err != ErrApiKeyNotFound {
+		t.Fatalf("expected ErrApiKeyNotFound for other user, got %v", err)
+	}
+	if err := service.RevokeApiKey(ctx, serviceUserId, created.ID); err != nil {
+		t.Fatalf("RevokeApiKey error: %v", err)
+	}
+	queryOperationLog(t, db, constant.ResourceApiKey, int64(created.ID), constant.ActionDelete)
+	if _, err := service.Authenticate(ctx, created.Key); err != ErrApiKeyInvalid {
+		t.Fatalf("expected revoked key to be rejected immediately, got %v", err)
+	}
+	if err := service.RevokeApiKey(ctx, serviceUserId, created.ID); err != ErrApiKeyNotFound {
+		t.Fatalf("expected ErrApiKeyNotFound for revoked key, got %v", err)
+	}
+}
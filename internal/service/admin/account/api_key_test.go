@@ -0,0 +1,227 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/pkg/xcryption"
+)
+
+type fakeApiKeyRepo struct {
+	keysByHash     map[string]*model.SysAPIKey
+	getByHashCalls int
+	lastUsed       map[int32]time.Time
+}
+
+func (f *fakeApiKeyRepo) CreateApiKey(context.Context, *query.Query, *model.SysAPIKey) (*model.SysAPIKey, error) {
+	panic("not implemented")
+}
+
+func (f *fakeApiKeyRepo) GetApiKeyList(context.Context, int32) ([]*model.SysAPIKey, error) {
+	panic("not implemented")
+}
+
+func (f *fakeApiKeyRepo) GetApiKeyById(context.Context, *query.Query, int32) (*model.SysAPIKey, error) {
+	panic("not implemented")
+}
+
+func (f *fakeApiKeyRepo) GetApiKeyByHash(_ context.Context, keyHash string) (*model.SysAPIKey, error) {
+	f.getByHashCalls++
+	apiKey, ok := f.keysByHash[keyHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return apiKey, nil
+}
+
+func (f *fakeApiKeyRepo) RevokeApiKey(context.Context, *query.Query, int32) (bool, error) {
+	panic("not implemented")
+}
+
+func (f *fakeApiKeyRepo) UpdateLastUsedAt(_ context.Context, keyId int32, usedAt time.Time) error {
+	f.lastUsed[keyId] = usedAt
+	return nil
+}
+
+func newTestApiKeyService(apiKey *model.SysAPIKey, user *model.SysUser) (*ApiKeyService, *fakeApiKeyRepo, *fakeCache, string) {
+	rawKey, prefix, _ := generateApiKey()
+	keyRepo := &fakeApiKeyRepo{keysByHash: map[string]*model.SysAPIKey{}, lastUsed: map[int32]time.Time{}}
+	if apiKey != nil {
+		apiKey.Prefix = prefix
+		apiKey.KeyHash = xcryption.Sha256(rawKey)
+		keyRepo.keysByHash[apiKey.KeyHash] = apiKey
+	}
+	cache := newFakeCache()
+	service := NewApiKeyService(&repo.Repository{}, keyRepo, &fakeUserRepo{userById: user}, cache, &fakeOperationLogWriter{})
+	return service, keyRepo, cache, rawKey
+}
+
+func testApiKey(status int8, expiredAt *time.Time) *model.SysAPIKey {
+	return &model.SysAPIKey{ID: 5, UserID: 9, Name: "ci", Status: &status, ExpiredAt: expiredAt}
+}
+
+func testServiceUser(status int8) *model.SysUser {
+	userType := constant.UserTypeService
+	return &model.SysUser{ID: 9, Username: "svc-ci", Status: &status, UserType: &userType}
+}
+
+func TestGenerateApiKey_Format(t *testing.T) {
+	rawKey, prefix, err := generateApiKey()
+	if err != nil {
+		t.Fatalf("generateApiKey error: %v", err)
+	}
+	if !strings.HasPrefix(rawKey, prefix+"_") {
+		t.Fatalf("key %q does not start with prefix %q", rawKey, prefix)
+	}
+	if len(prefix) != len(apiKeyScheme)+apiKeyPrefixBytes*2 {
+		t.Fatalf("unexpected prefix length: %q", prefix)
+	}
+	other, _, _ := generateApiKey()
+	if other == rawKey {
+		t.Fatal("expected distinct api keys")
+	}
+}
+
+func TestApiKeyAuthenticate_SuccessCachesPrincipal(t *testing.T) {
+	service, keyRepo, cache, rawKey := newTestApiKeyService(
+		testApiKey(constant.ApiKeyStatusActive, nil), testServiceUser(constant.UserStatusActive))
+
+	principal, err := service.Authenticate(context.Background(), rawKey)
+	if err != nil {
+		t.Fatalf("Authenticate error: %v", err)
+	}
+	if principal.KeyId != 5 || principal.UserId != 9 || principal.Username != "svc-ci" {
+		t.Fatalf("unexpected principal: %+v", principal)
+	}
+	if _, ok := keyRepo.lastUsed[5]; !ok {
+		t.Fatal("expected last_used_at to be updated")
+	}
+	cacheKey := constant.CacheApiKeyPrefix + xcryption.Sha256(rawKey)
+	if got := cache.expirations[cacheKey]; got != constant.CacheApiKeyExpirationSecond*time.Second {
+		t.Fatalf("cache ttl = %s", got)
+	}
+
+	if _, err := service.Authenticate(context.Background(), rawKey); err != nil {
+		t.Fatalf("cached Authenticate error: %v", err)
+	}
+	if keyRepo.getByHashCalls != 1 {
+		t.Fatalf("expected cached lookup, db calls = %d", keyRepo.getByHashCalls)
+	}
+}
+
+func TestApiKeyAuthenticate_CacheTTLCappedByExpiry(t *testing.T) {
+	expiredAt := time.Now().Add(10 * time.Second)
+	service, _, cache, rawKey := newTestApiKeyService(
+		testApiKey(constant.ApiKeyStatusActive, &expiredAt), testServiceUser(constant.UserStatusActive))
+
+	if _, err := service.Authenticate(context.Background(), rawKey); err != nil {
+		t.Fatalf("Authenticate error: %v", err)
+	}
+	cacheKey := constant.CacheApiKeyPrefix + xcryption.Sha256(rawKey)
+	if got := cache.expirations[cacheKey]; got <= 0 || got > 10*time.Second {
+		t.Fatalf("cache ttl should be capped by expiry, got %s", got)
+	}
+}
+
+func TestClearUserApiKeyCache(t *testing.T) {
+	user := testServiceUser(constant.UserStatusActive)
+	service, keyRepo, cache, rawKey := newTestApiKeyService(testApiKey(constant.ApiKeyStatusActive, nil), user)
+	ctx := context.Background()
+
+	if _, err := service.Authenticate(ctx, rawKey); err != nil {
+		t.Fatalf("Authenticate error: %v", err)
+	}
+	if _, ok := cache.hashes[constant.CacheApiKeyUserPrefix+"9"][xcryption.Sha256(rawKey)]; !ok {
+		t.Fatal("expected cached key indexed by service account")
+	}
+
+	// 服务账号禁用后清除缓存，下一次认证立即按数据库状态拒绝
+	disabled := constant.UserStatusDisabled
+	user.Status = &disabled
+	clearUserApiKeyCache(ctx, cache, 9)
+	if _, ok := cache.values[constant.CacheApiKeyPrefix+xcryption.Sha256(rawKey)]; ok {
+		t.Fatal("expected cached principal deleted")
+	}
+	if _, err := service.Authenticate(ctx, rawKey); !errors.Is(err, ErrApiKeyInvalid) {
+		t.Fatalf("expected ErrApiKeyInvalid after disable, got %v", err)
+	}
+	if keyRepo.getByHashCalls != 2 {
+		t.Fatalf("expected db lookup after cache cleared, db calls = %d", keyRepo.getByHashCalls)
+	}
+}
+
+func TestApiKeyAuthenticate_Rejects(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	normalUser := testServiceUser(constant.UserStatusActive)
+	normalType := constant.UserTypeNormal
+	normalUser.UserType = &normalType
+
+	tests := []struct {
+		name   string
+		apiKey *model.SysAPIKey
+		user   *model.SysUser
+	}{
+		{"unknown key", nil, testServiceUser(constant.UserStatusActive)},
+		{"revoked key", testApiKey(constant.ApiKeyStatusRevoked, nil), testServiceUser(constant.UserStatusActive)},
+		{"expired key", testApiKey(constant.ApiKeyStatusActive, &past), testServiceUser(constant.UserStatusActive)},
+		{"disabled account", testApiKey(constant.ApiKeyStatusActive, nil), testServiceUser(constant.UserStatusDisabled)},
+		{"deleted account", testApiKey(constant.ApiKeyStatusActive, nil), nil},
+		{"normal user", testApiKey(constant.ApiKeyStatusActive, nil), normalUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, cache, rawKey := newTestApiKeyService(tt.apiKey, tt.user)
+			_, err := service.Authenticate(context.Background(), rawKey)
+			if !errors.Is(err, ErrApiKeyInvalid) {
+				t.Fatalf("expected ErrApiKeyInvalid, got %v", err)
+			}
+			if len(cache.sets) != 0 {
+				t.Fatalf("rejected key must not be cached: %v", cache.sets)
+			}
+		})
+	}
+}
+
+func TestApiKeyAuthenticate_MalformedKeySkipsLookup(t *testing.T) {
+	service, keyRepo, _, _ := newTestApiKeyService(nil, nil)
+	for _, rawKey := range []string{"", "sk_short", "Bearer abc", strings.Repeat("x", 60)} {
+		if _, err := service.Authenticate(context.Background(), rawKey); !errors.Is(err, ErrApiKeyInvalid) {
+			t.Fatalf("key %q: expected ErrApiKeyInvalid, got %v", rawKey, err)
+		}
+	}
+	if keyRepo.getByHashCalls != 0 {
+		t.Fatalf("malformed keys should not hit db, calls = %d", keyRepo.getByHashCalls)
+	}
+}
+
+func TestCreateApiKey_Validation(t *testing.T) {
+	normalType := constant.UserTypeNormal
+	tests := []struct {
+		name    string
+		user    *model.SysUser
+		param   *ApiKeyParam
+		wantErr error
+	}{
+		{"bad time format", testServiceUser(constant.UserStatusActive), &ApiKeyParam{Name: "ci", ExpiredAt: "2030/01/01"}, ErrApiKeyTimeFormat},
+		{"expired in past", testServiceUser(constant.UserStatusActive), &ApiKeyParam{Name: "ci", ExpiredAt: "2000-01-01 00:00:00"}, ErrApiKeyExpireInvalid},
+		{"user not found", nil, &ApiKeyParam{Name: "ci"}, ErrUserNotFound},
+		{"normal user", &model.SysUser{ID: 9, UserType: &normalType}, &ApiKeyParam{Name: "ci"}, ErrApiKeyUserNotService},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, _, _ := newTestApiKeyService(nil, tt.user)
+			if _, err := service.CreateApiKey(testUserCtx(), 9, tt.param); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"snowgo/pkg/xauth/captcha"
+	e "snowgo/pkg/xerror"
+)
+
+var (
+	ErrCaptchaRequired    = e.NewBizError(e.CaptchaRequired)
+	ErrCaptchaInvalid     = e.NewBizError(e.CaptchaInvalid)
+	ErrCaptchaUnavailable = e.NewBizError(e.CaptchaUnavailable)
+)
+
+// CaptchaProvider 验证码提供方，内置实现为 pkg/xauth/captcha 的 Redis 存储，可替换为第三方验证码服务
+type CaptchaProvider interface {
+	Create(ctx context.Context) (*captcha.Challenge, error)
+	Verify(ctx context.Context, id, code string) (bool, error)
+}
+
+// CaptchaConfig 登录验证码配置
+type CaptchaConfig struct {
+	Threshold int64 // 登录失败窗口内尝试次数超过该值后要求验证码，需小于硬性锁定阈值
+}
+
+// CaptchaService 登录验证码，失败次数达到软阈值后、账号锁定前要求人机校验
+type CaptchaService struct {
+	provider CaptchaProvider
+	conf     CaptchaConfig
+}
+
+func NewCaptchaService(provider CaptchaProvider, conf CaptchaConfig) *CaptchaService {
+	return &CaptchaService{provider: provider, conf: conf}
+}
+
+// Enabled 是否启用登录验证码
+func (s *CaptchaService) Enabled() bool {
+	return s.provider != nil && s.conf.Threshold > 0
+}
+
+// Required 本次登录尝试是否需要验证码，attempts 为计入本次后的窗口内尝试次数
+func (s *CaptchaService) Required(attempts int64) bool {
+	return s.Enabled() && attempts > s.conf.Threshold
+}
+
+// Create 生成验证码
+func (s *CaptchaService) Create(ctx context.Context) (*captcha.Challenge, error) {
+	if !s.Enabled() {
+		return nil, ErrCaptchaUnavailable
+	}
+	challenge, err := s.provider.Create(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create captcha error: %w", err)
+	}
+	return challenge, nil
+}
+
+// Verify 校验验证码，验证码一次性有效，未携带时返回 ErrCaptchaRequired
+func (s *CaptchaService) Verify(ctx context.Context, id, code string) error {
+	if id == "" || code == "" {
+		return ErrCaptchaRequired
+	}
+	ok, err := s.provider.Verify(ctx, id, code)
+	if err != nil {
+		return fmt.Errorf("verify captcha error: %w", err)
+	}
+	if !ok {
+		return ErrCaptchaInvalid
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"snowgo/pkg/xauth/captcha"
+)
+
+type fakeCaptchaProvider struct {
+	answers map[string]string
+}
+
+func (f *fakeCaptchaProvider) Create(context.Context) (*captcha.Challenge, error) {
+	f.answers["id"] = "1234"
+	return &captcha.Challenge{Id: "id", Type: captcha.TypeImage}, nil
+}
+
+func (f *fakeCaptchaProvider) Verify(_ context.Context, id, code string) (bool, error) {
+	answer, ok := f.answers[id]
+	delete(f.answers, id)
+	return ok && answer == code, nil
+}
+
+func TestCaptchaServiceRequired(t *testing.T) {
+	s := NewCaptchaService(&fakeCaptchaProvider{answers: map[string]string{}}, CaptchaConfig{Threshold: 3})
+	if s.Required(3) {
+		t.Fatalf("expected captcha not required at threshold")
+	}
+	if !s.Required(4) {
+		t.Fatalf("expected captcha required above threshold")
+	}
+
+	disabled := NewCaptchaService(nil, CaptchaConfig{Threshold: 3})
+	if disabled.Required(10) {
+		t.Fatalf("expected disabled captcha never required")
+	}
+	if _, err := disabled.Create(context.Background()); !errors.Is(err, ErrCaptchaUnavailable) {
+		t.Fatalf("expected ErrCaptchaUnavailable, got %v", err)
+	}
+}
+
+func TestCaptchaServiceVerify(t *testing.T) {
+	ctx := context.Background()
+	s := NewCaptchaService(&fakeCaptchaProvider{answers: map[string]string{}}, CaptchaConfig{Threshold: 3})
+	if err := s.Verify(ctx, "", ""); !errors.Is(err, ErrCaptchaRequired) {
+		t.Fatalf("expected ErrCaptchaRequired, got %v", err)
+	}
+	challenge, err := s.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if err := s.Verify(ctx, challenge.Id, "0000"); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("expected ErrCaptchaInvalid, got %v", err)
+	}
+	challenge, _ = s.Create(ctx)
+	if err := s.Verify(ctx, challenge.Id, "1234"); err != nil {
+		t.Fatalf("expected captcha accepted, got %v", err)
+	}
+	if err := s.Verify(ctx, challenge.Id, "1234"); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("expected used captcha rejected, got %v", err)
+	}
+}
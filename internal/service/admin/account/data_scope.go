@@ -0,0 +1,84 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dao"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xlogger"
+)
+
+// GetDataScope 获取当前登录用户的数据范围
+func (u *UserService) GetDataScope(ctx context.Context) (*dao.DataScope, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetDataScopeById(ctx, userContext.UserId)
+}
+
+// GetDataScopeById 按用户全部角色的数据范围取并集，任一角色为全部数据即不限制
+func (u *UserService) GetDataScopeById(ctx context.Context, userId int32) (*dao.DataScope, error) {
+	user, err := u.userDao.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userId, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	roleList, err := u.userDao.GetRoleListByUserId(ctx, userId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)角色异常: %v", userId, err)
+		return nil, fmt.Errorf("用户角色查询失败: %w", err)
+	}
+
+	scope := &dao.DataScope{UserId: userId}
+	customRoleIds := make([]int32, 0, len(roleList))
+	for _, role := range roleList {
+		if role.ID == constant.SuperAdminRoleId {
+			scope.All = true
+			return scope, nil
+		}
+		switch common.DerefOrZero(role.DataScope) {
+		case constant.DataScopeAll:
+			scope.All = true
+			return scope, nil
+		case constant.DataScopeDept:
+			if user.DeptID != nil {
+				scope.DeptIds = append(scope.DeptIds, *user.DeptID)
+			}
+		case constant.DataScopeDeptBelow:
+			if user.DeptID != nil {
+				deptIds, err := u.deptService.GetDeptAndChildIds(ctx, *user.DeptID)
+				if err != nil {
+					xlogger.ErrorfCtx(ctx, "获取部门(%d)下级部门异常: %v", *user.DeptID, err)
+					return nil, fmt.Errorf("下级部门查询失败: %w", err)
+				}
+				scope.DeptIds = append(scope.DeptIds, deptIds...)
+			}
+		case constant.DataScopeCustom:
+			customRoleIds = append(customRoleIds, role.ID)
+		}
+		// 仅本人及未知取值只保留本人数据
+	}
+
+	if len(customRoleIds) > 0 {
+		deptIds, err := u.roleService.GetRoleDeptIdsByRuleIds(ctx, customRoleIds)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "获取角色自定义数据范围异常: %v", err)
+			return nil, fmt.Errorf("角色数据范围查询失败: %w", err)
+		}
+		scope.DeptIds = append(scope.DeptIds, deptIds...)
+	}
+	slices.Sort(scope.DeptIds)
+	scope.DeptIds = slices.Compact(scope.DeptIds)
+	return scope, nil
+}
@@ -0,0 +1,101 @@
+//go:build integration
+
+package account
+
+import (
+	"slices"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+func TestUserServiceDataScopeIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+	roleId, err := newIntegrationRoleService(deps).CreateRole(testUserCtx(), &RoleParam{
+		Name:      "部门经理",
+		Code:      "it_dept_manager",
+		DataScope: constant.DataScopeCustom,
+		DeptIds:   []int32{20},
+	})
+	if err != nil {
+		t.Fatalf("CreateRole error: %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysRoleDept, "role_id = ? AND dept_id = ?", roleId, 20); count != 1 {
+		t.Fatalf("expected custom role dept relation, got %d", count)
+	}
+
+	operator := insertIntegrationUser(t, db, "scope_operator", "18100000401", roleId)
+	inDept := insertIntegrationUser(t, db, "scope_in_dept", "18100000402")
+	outDept := insertIntegrationUser(t, db, "scope_out_dept", "18100000403")
+	created := insertIntegrationUser(t, db, "scope_created", "18100000404")
+	db.Model(&model.SysUser{}).Where("id = ?", inDept.ID).Update("dept_id", 20)
+	db.Model(&model.SysUser{}).Where("id = ?", outDept.ID).Update("dept_id", 30)
+	db.Model(&model.SysUser{}).Where("id = ?", created.ID).Update("created_by", operator.ID)
+
+	service := newIntegrationUserService(deps)
+	list, err := service.GetUserList(profileUserCtx(operator), &UserListCondition{Limit: 20})
+	if err != nil {
+		t.Fatalf("GetUserList error: %v", err)
+	}
+	ids := make([]int32, 0, len(list.List))
+	for _, user := range list.List {
+		ids = append(ids, user.ID)
+	}
+	slices.Sort(ids)
+	want := []int32{operator.ID, inDept.ID, created.ID}
+	slices.Sort(want)
+	if !slices.Equal(ids, want) || list.Total != 3 {
+		t.Fatalf("expected scoped users %v, got %v total %d", want, ids, list.Total)
+	}
+
+	// 超级管理员不受数据范围限制
+	all, err := service.GetUserList(testUserCtx(), &UserListCondition{Limit: 20})
+	if err != nil || all.Total != 5 {
+		t.Fatalf("expected super admin to see all 5 users, got %+v %v", all, err)
+	}
+}
+
+func TestRoleServiceDataScopeDefaultsIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+	service := newIntegrationRoleService(deps)
+
+	// 创建时不传数据范围默认仅本人
+	plainId, err := service.CreateRole(testUserCtx(), &RoleParam{Name: "普通角色", Code: "it_scope_plain"})
+	if err != nil {
+		t.Fatalf("CreateRole error: %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysRole, "id = ? AND data_scope = ?", plainId, constant.DataScopeSelf); count != 1 {
+		t.Fatalf("expected new role to default to self scope, got %d", count)
+	}
+
+	// 更新时不传数据范围保持原范围与自定义部门
+	customId, err := service.CreateRole(testUserCtx(), &RoleParam{
+		Name:      "部门经理",
+		Code:      "it_scope_custom",
+		DataScope: constant.DataScopeCustom,
+		DeptIds:   []int32{20},
+	})
+	if err != nil {
+		t.Fatalf("CreateRole error: %v", err)
+	}
+	if err := service.UpdateRole(testUserCtx(), &RoleParam{ID: customId, Name: "部门经理2", Code: "it_scope_custom"}); err != nil {
+		t.Fatalf("UpdateRole error: %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysRole, "id = ? AND data_scope = ?", customId, constant.DataScopeCustom); count != 1 {
+		t.Fatalf("expected custom scope kept, got %d", count)
+	}
+	if count := countRows(t, db, model.TableNameSysRoleDept, "role_id = ? AND dept_id = ?", customId, 20); count != 1 {
+		t.Fatalf("expected custom role dept kept, got %d", count)
+	}
+}
@@ -0,0 +1,82 @@
+package account
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	common "snowgo/pkg"
+)
+
+func TestUserServiceGetDataScopeById(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []*model.SysRole
+		deptIds []int32
+		wantAll bool
+		want    []int32
+	}{
+		{"no role only self", nil, nil, false, nil},
+		{"super admin", []*model.SysRole{{ID: constant.SuperAdminRoleId}}, nil, true, nil},
+		{"all wins union", []*model.SysRole{
+			{ID: 2, DataScope: common.PtrIfNonZero(constant.DataScopeSelf)},
+			{ID: 3, DataScope: common.PtrIfNonZero(constant.DataScopeAll)},
+		}, nil, true, nil},
+		{"self", []*model.SysRole{{ID: 2, DataScope: common.PtrIfNonZero(constant.DataScopeSelf)}}, nil, false, nil},
+		{"own dept", []*model.SysRole{{ID: 2, DataScope: common.PtrIfNonZero(constant.DataScopeDept)}}, nil, false, []int32{10}},
+		{"dept below includes children", []*model.SysRole{{ID: 2, DataScope: common.PtrIfNonZero(constant.DataScopeDeptBelow)}}, nil, false, []int32{10, 11}},
+		{"dept and custom merged", []*model.SysRole{
+			{ID: 2, DataScope: common.PtrIfNonZero(constant.DataScopeDeptBelow)},
+			{ID: 3, DataScope: common.PtrIfNonZero(constant.DataScopeCustom)},
+		}, []int32{20, 10}, false, []int32{10, 11, 20}},
+		{"unknown value only self", []*model.SysRole{{ID: 2}}, nil, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userDao: &fakeUserRepo{
+					userById: &model.SysUser{ID: 5, DeptID: common.PtrIfNonZero[int32](10)},
+					roleList: tt.roles,
+				},
+				roleService: &RoleService{roleDao: &fakeRoleRepo{deptIds: tt.deptIds}},
+				deptService: &DeptService{deptDao: &fakeDeptRepo{allDepts: []*model.SysDept{
+					{ID: 10, Name: "研发"},
+					{ID: 11, ParentID: 10, Name: "后端"},
+				}}, cache: newFakeCache()},
+			}
+			scope, err := service.GetDataScopeById(testUserCtx(), 5)
+			if err != nil {
+				t.Fatalf("GetDataScopeById error: %v", err)
+			}
+			if scope.UserId != 5 || scope.IsAll() != tt.wantAll {
+				t.Fatalf("expected user 5 all=%v, got %+v", tt.wantAll, scope)
+			}
+			if !tt.wantAll && !slices.Equal(scope.DeptIds, tt.want) {
+				t.Fatalf("expected dept ids %v, got %v", tt.want, scope.DeptIds)
+			}
+		})
+	}
+}
+
+func TestUserServiceGetDataScopeUserNotFound(t *testing.T) {
+	service := &UserService{userDao: &fakeUserRepo{}}
+	if _, err := service.GetDataScope(testUserCtx()); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestRoleDataScopeValue(t *testing.T) {
+	scope, deptIds, err := roleDataScopeValue(&RoleParam{DeptIds: []int32{1}}, constant.DataScopeSelf)
+	if err != nil || scope != constant.DataScopeSelf || deptIds != nil {
+		t.Fatalf("expected default self without depts, got %d %v %v", scope, deptIds, err)
+	}
+	if _, _, err := roleDataScopeValue(&RoleParam{DataScope: constant.DataScopeCustom}, constant.DataScopeSelf); !errors.Is(err, ErrRoleDataScopeInvalid) {
+		t.Fatalf("expected ErrRoleDataScopeInvalid, got %v", err)
+	}
+	scope, deptIds, err = roleDataScopeValue(&RoleParam{DataScope: constant.DataScopeCustom, DeptIds: []int32{3, 1, 3}}, constant.DataScopeSelf)
+	if err != nil || scope != constant.DataScopeCustom || !slices.Equal(deptIds, []int32{1, 3}) {
+		t.Fatalf("expected deduplicated custom depts, got %d %v %v", scope, deptIds, err)
+	}
+}
@@ -0,0 +1,649 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xdatabase/mysql"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// deptMaxDepth 部门最大层级，向上查找父级时防止脏数据成环导致死循环
+const deptMaxDepth = 32
+
+type DeptRepo interface {
+	CreateDept(ctx context.Context, q *query.Query, dept *model.SysDept) (*model.SysDept, error)
+	UpdateDept(ctx context.Context, q *query.Query, dept *model.SysDept) (*model.SysDept, error)
+	UpdateParent(ctx context.Context, q *query.Query, id, parentId, sortOrder int32) error
+	UpdateSortOrder(ctx context.Context, q *query.Query, id, sortOrder int32) error
+	DeleteById(ctx context.Context, q *query.Query, id int32) error
+	GetById(ctx context.Context, q *query.Query, id int32) (*model.SysDept, error)
+	LockDepts(ctx context.Context, q *query.Query) ([]*model.SysDept, error)
+	CountByIds(ctx context.Context, q *query.Query, ids []int32) (int64, error)
+	GetByParentId(ctx context.Context, q *query.Query, parentId int32) ([]*model.SysDept, error)
+	GetAllDepts(ctx context.Context) ([]*model.SysDept, error)
+	IsNameExists(ctx context.Context, q *query.Query, parentId int32, name string, excludeId int32) (bool, error)
+	IsUsedByUser(ctx context.Context, q *query.Query, id int32) (bool, error)
+	DeleteRoleDept(ctx context.Context, q *query.Query, id int32) error
+}
+
+type DeptService struct {
+	db         *repo.Repository
+	deptDao    DeptRepo
+	cache      xcache.Cache
+	logService contract.OperationLogWriter
+}
+
+func NewDeptService(db *repo.Repository, cache xcache.Cache, deptDao DeptRepo, logService contract.OperationLogWriter) *DeptService {
+	return &DeptService{
+		db:         db,
+		cache:      cache,
+		deptDao:    deptDao,
+		logService: logService,
+	}
+}
+
+type DeptParam struct {
+	ID        int32  `json:"id"`
+	ParentID  int32  `json:"parent_id" binding:"gte=0"`
+	Name      string `json:"name" binding:"required,max=64"`
+	SortOrder int32  `json:"sort_order" binding:"gte=0"`
+}
+
+// DeptMoveParam 移动部门到新的父级
+type DeptMoveParam struct {
+	ID        int32 `json:"id" binding:"required"`
+	ParentID  int32 `json:"parent_id" binding:"gte=0"`
+	SortOrder int32 `json:"sort_order" binding:"gte=0"`
+}
+
+// DeptSortParam 批量调整部门排序号
+type DeptSortParam struct {
+	Items []*DeptSortItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type DeptSortItem struct {
+	ID        int32 `json:"id" binding:"required"`
+	SortOrder int32 `json:"sort_order" binding:"gte=0"`
+}
+
+// DeptInfo 返回给前端的部门树节点
+type DeptInfo struct {
+	ID        int32       `json:"id"`
+	ParentID  int32       `json:"parent_id"`
+	Name      string      `json:"name"`
+	SortOrder int32       `json:"sort_order"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Children  []*DeptInfo `json:"children"`
+}
+
+var (
+	ErrDeptNotFound      = e.NewBizError(e.DeptNotFound)
+	ErrDeptNameExist     = e.NewBizError(e.DeptNameExist)
+	ErrDeptParentInvalid = e.NewBizError(e.DeptParentInvalid)
+	ErrDeptParentCycle   = e.NewBizError(e.DeptParentCycle)
+	ErrDeptHasChildren   = e.NewBizError(e.DeptHasChildren)
+	ErrDeptHasUsers      = e.NewBizError(e.DeptHasUsers)
+	ErrDeptIDInvalid     = e.NewBizError(e.DeptIDInvalid)
+)
+
+// CreateDept 创建部门
+func (s *DeptService) CreateDept(ctx context.Context, p *DeptParam) (int32, error) {
+	// 获取登录ctx
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	dept := &model.SysDept{
+		ParentID:  p.ParentID,
+		Name:      p.Name,
+		SortOrder: p.SortOrder,
+	}
+	var deptObj *model.SysDept
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		// 校验父节点存在（事务内加锁，防止并发删除父部门）
+		if err := s.lockDepts(ctx, tx); err != nil {
+			return err
+		}
+		if err := s.checkParent(ctx, tx, 0, p.ParentID); err != nil {
+			return err
+		}
+		if err := s.checkName(ctx, tx, p.ParentID, p.Name, 0); err != nil {
+			return err
+		}
+
+		deptObj, err = s.deptDao.CreateDept(ctx, tx, dept)
+		if err != nil {
+			// 唯一索引冲突兜底
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrDeptNameExist
+			}
+			xlogger.ErrorfCtx(ctx, "创建部门失败: %v", err)
+			return fmt.Errorf("创建部门失败: %w", err)
+		}
+
+		// 创建操作日志
+		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceDept,
+			ResourceID:   int64(deptObj.ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionCreate,
+			BeforeData:   nil,
+			AfterData:    deptObj,
+			Description: fmt.Sprintf("用户(%d-%s)创建了部门(%d-%s)",
+				userContext.UserId, userContext.Username, deptObj.ID, deptObj.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v err: %v", deptObj, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	xlogger.InfofCtx(ctx, "部门创建成功: %v", deptObj)
+	s.clearTreeCache(ctx)
+	return deptObj.ID, nil
+}
+
+// UpdateDept 更新部门名称、父级与排序号
+func (s *DeptService) UpdateDept(ctx context.Context, p *DeptParam) error {
+	// 获取登录ctx
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if p.ID <= 0 {
+		return ErrDeptIDInvalid
+	}
+	oldDept, err := s.getDept(ctx, s.db.Query(), p.ID)
+	if err != nil {
+		return err
+	}
+
+	dept := &model.SysDept{
+		ID:        p.ID,
+		ParentID:  p.ParentID,
+		Name:      p.Name,
+		SortOrder: p.SortOrder,
+		CreatedAt: oldDept.CreatedAt,
+	}
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		// 校验父节点（事务内加锁，防止并发移动形成环）
+		if err := s.lockDepts(ctx, tx); err != nil {
+			return err
+		}
+		if err := s.checkParent(ctx, tx, p.ID, p.ParentID); err != nil {
+			return err
+		}
+		if err := s.checkName(ctx, tx, p.ParentID, p.Name, p.ID); err != nil {
+			return err
+		}
+
+		deptObj, err := s.deptDao.UpdateDept(ctx, tx, dept)
+		if err != nil {
+			// 唯一索引冲突兜底
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrDeptNameExist
+			}
+			xlogger.ErrorfCtx(ctx, "更新部门失败: %v", err)
+			return fmt.Errorf("更新部门失败: %w", err)
+		}
+
+		// 创建操作日志
+		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceDept,
+			ResourceID:   int64(p.ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   oldDept,
+			AfterData:    deptObj,
+			Description: fmt.Sprintf("用户(%d-%s)修改了部门(%d-%s)信息",
+				userContext.UserId, userContext.Username, p.ID, p.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v err: %v", p, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	xlogger.InfofCtx(ctx, "部门更新成功: old=%+v new=%+v", oldDept, dept)
+	s.clearTreeCache(ctx)
+	return nil
+}
+
+// MoveDept 移动部门（连同下级部门）到新的父级
+func (s *DeptService) MoveDept(ctx context.Context, p *DeptMoveParam) error {
+	// 获取登录ctx
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if p.ID <= 0 {
+		return ErrDeptIDInvalid
+	}
+	oldDept, err := s.getDept(ctx, s.db.Query(), p.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		// 校验父节点（事务内加锁，防止并发移动形成环）
+		if err := s.lockDepts(ctx, tx); err != nil {
+			return err
+		}
+		if err := s.checkParent(ctx, tx, p.ID, p.ParentID); err != nil {
+			return err
+		}
+		if err := s.checkName(ctx, tx, p.ParentID, oldDept.Name, p.ID); err != nil {
+			return err
+		}
+
+		err := s.deptDao.UpdateParent(ctx, tx, p.ID, p.ParentID, p.SortOrder)
+		if err != nil {
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrDeptNameExist
+			}
+			xlogger.ErrorfCtx(ctx, "移动部门失败: %v", err)
+			return fmt.Errorf("移动部门失败: %w", err)
+		}
+
+		// 创建操作日志
+		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceDept,
+			ResourceID:   int64(p.ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   oldDept,
+			AfterData:    p,
+			Description: fmt.Sprintf("用户(%d-%s)将部门(%d-%s)从父级(%d)移动到父级(%d)",
+				userContext.UserId, userContext.Username, p.ID, oldDept.Name, oldDept.ParentID, p.ParentID),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v err: %v", p, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	xlogger.InfofCtx(ctx, "部门移动成功: id=%d parent %d -> %d", p.ID, oldDept.ParentID, p.ParentID)
+	s.clearTreeCache(ctx)
+	return nil
+}
+
+// SortDept 批量调整部门排序号
+func (s *DeptService) SortDept(ctx context.Context, p *DeptSortParam) error {
+	// 获取登录ctx
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(p.Items) == 0 {
+		return nil
+	}
+
+	ids := make([]int32, 0, len(p.Items))
+	for _, item := range p.Items {
+		if item.ID <= 0 {
+			return ErrDeptIDInvalid
+		}
+		ids = append(ids, item.ID)
+	}
+
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		count, err := s.deptDao.CountByIds(ctx, tx, ids)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "获取部门数量异常: %v", err)
+			return fmt.Errorf("校验部门失败: %w", err)
+		}
+		uniqIds := slices.Clone(ids)
+		slices.Sort(uniqIds)
+		if count != int64(len(slices.Compact(uniqIds))) {
+			return ErrDeptNotFound
+		}
+
+		for _, item := range p.Items {
+			if err := s.deptDao.UpdateSortOrder(ctx, tx, item.ID, item.SortOrder); err != nil {
+				xlogger.ErrorfCtx(ctx, "更新部门排序失败 dept_id=%d err: %v", item.ID, err)
+				return fmt.Errorf("更新部门排序失败: %w", err)
+			}
+		}
+
+		// 创建操作日志
+		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceDept,
+			ResourceID:   int64(p.Items[0].ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   nil,
+			AfterData:    p,
+			Description: fmt.Sprintf("用户(%d-%s)调整了部门(%v)的排序",
+				userContext.UserId, userContext.Username, ids),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v err: %v", p, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	xlogger.InfofCtx(ctx, "部门排序成功: %v", ids)
+	s.clearTreeCache(ctx)
+	return nil
+}
+
+// DeleteDeptById 删除部门，存在下级部门或用户时拒绝删除
+func (s *DeptService) DeleteDeptById(ctx context.Context, id int32) error {
+	// 获取登录ctx
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if id <= 0 {
+		return ErrDeptIDInvalid
+	}
+	// 查询被删除部门信息，用于操作日志记录
+	oldDept, err := s.getDept(ctx, s.db.Query(), id)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		// 检查是否有下级部门（事务内加锁，防止并发创建或移入下级部门）
+		if err := s.lockDepts(ctx, tx); err != nil {
+			return err
+		}
+		children, err := s.deptDao.GetByParentId(ctx, tx, id)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "查询下级部门失败 dept_id=%d err: %v", id, err)
+			return fmt.Errorf("查询下级部门失败: %w", err)
+		}
+		if len(children) > 0 {
+			return ErrDeptHasChildren
+		}
+
+		// 检查部门下是否还有用户（事务内，防止并发分配用户）
+		isUsed, err := s.deptDao.IsUsedByUser(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("查询部门下用户失败: %w", err)
+		}
+		if isUsed {
+			return ErrDeptHasUsers
+		}
+
+		if err = s.deptDao.DeleteById(ctx, tx, id); err != nil {
+			xlogger.ErrorfCtx(ctx, "删除部门失败: %v", err)
+			return fmt.Errorf("删除部门失败: %w", err)
+		}
+		// 从角色自定义数据范围中移除该部门
+		if err = s.deptDao.DeleteRoleDept(ctx, tx, id); err != nil {
+			xlogger.ErrorfCtx(ctx, "角色与部门关联关系删除失败: %v", err)
+			return fmt.Errorf("角色与部门关联关系删除失败: %w", err)
+		}
+
+		// 创建操作日志
+		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceDept,
+			ResourceID:   int64(id),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionDelete,
+			BeforeData:   oldDept,
+			AfterData:    nil,
+			Description: fmt.Sprintf("用户(%d-%s)删除了部门(%d-%s)",
+				userContext.UserId, userContext.Username, id, oldDept.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v", err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	xlogger.InfofCtx(ctx, "部门删除成功: %d", id)
+	s.clearTreeCache(ctx)
+	return nil
+}
+
+// GetDeptTree 获取部门树
+func (s *DeptService) GetDeptTree(ctx context.Context) ([]*DeptInfo, error) {
+	// 尝试缓存
+	if data, ok, _ := s.cache.Get(ctx, constant.CacheDeptTree); ok {
+		var tree []*DeptInfo
+		if err := json.Unmarshal([]byte(data), &tree); err == nil {
+			return tree, nil
+		}
+	}
+
+	depts, err := s.deptDao.GetAllDepts(ctx)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取全部部门失败: %v", err)
+		return nil, fmt.Errorf("获取全部部门失败: %w", err)
+	}
+
+	// 构造 map[id]DeptInfo
+	nodeMap := make(map[int32]*DeptInfo, len(depts))
+	for _, d := range depts {
+		nodeMap[d.ID] = &DeptInfo{
+			ID:        d.ID,
+			ParentID:  d.ParentID,
+			Name:      d.Name,
+			SortOrder: d.SortOrder,
+			CreatedAt: common.DerefOrZero(d.CreatedAt),
+			UpdatedAt: common.DerefOrZero(d.UpdatedAt),
+			Children:  []*DeptInfo{},
+		}
+	}
+
+	// 构建树结构
+	var roots []*DeptInfo
+	for _, node := range nodeMap {
+		if node.ParentID == 0 {
+			roots = append(roots, node)
+		} else if parent, ok := nodeMap[node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			xlogger.ErrorfCtx(ctx, "部门[%d] 的父节点 [%d] 不存在，挂到根节点", node.ID, node.ParentID)
+			roots = append(roots, node)
+		}
+	}
+
+	// 递归排序，排序号相同按id保证稳定
+	var sortNodes func(nodes []*DeptInfo)
+	sortNodes = func(nodes []*DeptInfo) {
+		sort.Slice(nodes, func(i, j int) bool {
+			if nodes[i].SortOrder != nodes[j].SortOrder {
+				return nodes[i].SortOrder < nodes[j].SortOrder
+			}
+			return nodes[i].ID < nodes[j].ID
+		})
+		for _, n := range nodes {
+			sortNodes(n.Children)
+		}
+	}
+	sortNodes(roots)
+
+	// 缓存结果 15天
+	if bs, err := json.Marshal(roots); err == nil {
+		if err := s.cache.Set(ctx, constant.CacheDeptTree, string(bs), constant.CacheDeptTreeExpirationDay*24*time.Hour); err != nil {
+			xlogger.ErrorfCtx(ctx, "缓存部门树失败: %v", err)
+		}
+	}
+	return roots, nil
+}
+
+// GetDeptAndChildIds 获取部门及其全部下级部门id，部门不存在时返回空
+func (s *DeptService) GetDeptAndChildIds(ctx context.Context, deptId int32) ([]int32, error) {
+	tree, err := s.GetDeptTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	node := findDept(tree, deptId)
+	if node == nil {
+		return nil, nil
+	}
+	ids := make([]int32, 0, 8)
+	var collect func(n *DeptInfo)
+	collect = func(n *DeptInfo) {
+		ids = append(ids, n.ID)
+		for _, c := range n.Children {
+			collect(c)
+		}
+	}
+	collect(node)
+	return ids, nil
+}
+
+// GetDeptNameMap 获取部门id与名称的映射，用于列表展示
+func (s *DeptService) GetDeptNameMap(ctx context.Context) (map[int32]string, error) {
+	tree, err := s.GetDeptTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int32]string)
+	var walk func(nodes []*DeptInfo)
+	walk = func(nodes []*DeptInfo) {
+		for _, n := range nodes {
+			names[n.ID] = n.Name
+			walk(n.Children)
+		}
+	}
+	walk(tree)
+	return names, nil
+}
+
+// getDept 查询部门，不存在时返回 ErrDeptNotFound
+func (s *DeptService) getDept(ctx context.Context, q *query.Query, id int32) (*model.SysDept, error) {
+	dept, err := s.deptDao.GetById(ctx, q, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeptNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取部门(%d)异常: %v", id, err)
+		return nil, fmt.Errorf("获取部门失败: %w", err)
+	}
+	return dept, nil
+}
+
+// lockDepts 加锁读取全部部门，需在校验部门树之前调用
+func (s *DeptService) lockDepts(ctx context.Context, tx *query.Query) error {
+	if _, err := s.deptDao.LockDepts(ctx, tx); err != nil {
+		xlogger.ErrorfCtx(ctx, "加锁读取部门异常: %v", err)
+		return fmt.Errorf("加锁读取部门失败: %w", err)
+	}
+	return nil
+}
+
+// checkParent 校验父级部门存在，且不是自己或自己的下级部门；创建时 id 为 0
+func (s *DeptService) checkParent(ctx context.Context, tx *query.Query, id, parentId int32) error {
+	if parentId == 0 {
+		return nil
+	}
+	if parentId == id {
+		return ErrDeptParentCycle
+	}
+	cur := parentId
+	for depth := 0; cur != 0; depth++ {
+		if depth >= deptMaxDepth {
+			return ErrDeptParentCycle
+		}
+		dept, err := s.deptDao.GetById(ctx, tx, cur)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDeptParentInvalid
+			}
+			return fmt.Errorf("校验父级部门失败: %w", err)
+		}
+		if id > 0 && dept.ParentID == id {
+			return ErrDeptParentCycle
+		}
+		cur = dept.ParentID
+	}
+	return nil
+}
+
+// checkName 校验同级部门名称唯一（事务内，唯一索引兜底）
+func (s *DeptService) checkName(ctx context.Context, tx *query.Query, parentId int32, name string, excludeId int32) error {
+	exists, err := s.deptDao.IsNameExists(ctx, tx, parentId, name, excludeId)
+	if err != nil {
+		return fmt.Errorf("校验部门名称失败: %w", err)
+	}
+	if exists {
+		return ErrDeptNameExist
+	}
+	return nil
+}
+
+// clearTreeCache 清理部门树缓存
+func (s *DeptService) clearTreeCache(ctx context.Context) {
+	if _, err := s.cache.Delete(ctx, constant.CacheDeptTree); err != nil {
+		xlogger.ErrorfCtx(ctx, "清理部门树缓存失败: %v", err)
+	}
+}
+
+// findDept 在部门树中查找节点
+func findDept(nodes []*DeptInfo, id int32) *DeptInfo {
+	for _, n := range nodes {
+		if n.ID == id {
+			return n
+		}
+		if found := findDept(n.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
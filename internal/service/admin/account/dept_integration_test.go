@@ -0,0 +1,192 @@
+//go:build integration
+
+package account
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+func TestDeptServiceCreateAndMoveIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	root := insertIntegrationDept(t, db, 0, "总公司")
+	if err := deps.cache.Set(testUserCtx(), constant.CacheDeptTree, `[{"id":1}]`, time.Hour); err != nil {
+		t.Fatalf("prime dept tree cache: %v", err)
+	}
+
+	service := newIntegrationDeptService(deps)
+	deptID, err := service.CreateDept(testUserCtx(), &DeptParam{ParentID: root.ID, Name: "研发", SortOrder: 1})
+	if err != nil {
+		t.Fatalf("CreateDept expected success, got %v", err)
+	}
+	if countRows(t, db, model.TableNameSysDept, "id = ? AND parent_id = ? AND name = ?", deptID, root.ID, "研发") != 1 {
+		t.Fatalf("expected dept to be created")
+	}
+	queryOperationLog(t, db, constant.ResourceDept, int64(deptID), constant.ActionCreate)
+	if _, ok, err := deps.cache.Get(testUserCtx(), constant.CacheDeptTree); err != nil {
+		t.Fatalf("get dept tree cache: %v", err)
+	} else if ok {
+		t.Fatalf("expected dept tree cache to be invalidated")
+	}
+
+	// 同级重名
+	if _, err := service.CreateDept(testUserCtx(), &DeptParam{ParentID: root.ID, Name: "研发"}); !errors.Is(err, ErrDeptNameExist) {
+		t.Fatalf("CreateDept duplicate expected ErrDeptNameExist, got %v", err)
+	}
+	// 父级不存在
+	if _, err := service.CreateDept(testUserCtx(), &DeptParam{ParentID: 999, Name: "市场"}); !errors.Is(err, ErrDeptParentInvalid) {
+		t.Fatalf("CreateDept missing parent expected ErrDeptParentInvalid, got %v", err)
+	}
+	// 移动到自己的下级形成环
+	if err := service.MoveDept(testUserCtx(), &DeptMoveParam{ID: root.ID, ParentID: deptID}); !errors.Is(err, ErrDeptParentCycle) {
+		t.Fatalf("MoveDept to child expected ErrDeptParentCycle, got %v", err)
+	}
+
+	if err := service.MoveDept(testUserCtx(), &DeptMoveParam{ID: deptID, ParentID: 0, SortOrder: 3}); err != nil {
+		t.Fatalf("MoveDept expected success, got %v", err)
+	}
+	if countRows(t, db, model.TableNameSysDept, "id = ? AND parent_id = 0 AND sort_order = 3", deptID) != 1 {
+		t.Fatalf("expected dept to be moved to root")
+	}
+	queryOperationLog(t, db, constant.ResourceDept, int64(deptID), constant.ActionUpdate)
+
+	if err := service.SortDept(testUserCtx(), &DeptSortParam{Items: []*DeptSortItem{{ID: root.ID, SortOrder: 5}, {ID: deptID, SortOrder: 4}}}); err != nil {
+		t.Fatalf("SortDept expected success, got %v", err)
+	}
+	tree, err := service.GetDeptTree(testUserCtx())
+	if err != nil {
+		t.Fatalf("GetDeptTree expected success, got %v", err)
+	}
+	if len(tree) != 2 || tree[0].ID != deptID || tree[1].ID != root.ID {
+		t.Fatalf("expected roots sorted by sort_order, got %+v", tree)
+	}
+}
+
+func TestDeptServiceDeleteGuardsIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	root := insertIntegrationDept(t, db, 0, "总公司")
+	child := insertIntegrationDept(t, db, root.ID, "研发")
+	user := insertIntegrationUser(t, db, "dept_member", "18100000301")
+	if err := db.Model(&model.SysUser{}).Where("id = ?", user.ID).Update("dept_id", child.ID).Error; err != nil {
+		t.Fatalf("assign user dept: %v", err)
+	}
+	role := insertIntegrationRole(t, db, "dept_role", "部门角色")
+	if err := db.Create(&model.SysRoleDept{RoleID: role.ID, DeptID: child.ID}).Error; err != nil {
+		t.Fatalf("insert role dept: %v", err)
+	}
+
+	service := newIntegrationDeptService(deps)
+	if err := service.DeleteDeptById(testUserCtx(), root.ID); !errors.Is(err, ErrDeptHasChildren) {
+		t.Fatalf("DeleteDeptById with children expected ErrDeptHasChildren, got %v", err)
+	}
+	if err := service.DeleteDeptById(testUserCtx(), child.ID); !errors.Is(err, ErrDeptHasUsers) {
+		t.Fatalf("DeleteDeptById with users expected ErrDeptHasUsers, got %v", err)
+	}
+
+	if err := db.Model(&model.SysUser{}).Where("id = ?", user.ID).Update("dept_id", nil).Error; err != nil {
+		t.Fatalf("clear user dept: %v", err)
+	}
+	if err := service.DeleteDeptById(testUserCtx(), child.ID); err != nil {
+		t.Fatalf("DeleteDeptById expected success, got %v", err)
+	}
+	if countRows(t, db, model.TableNameSysDept, "id = ?", child.ID) != 0 {
+		t.Fatalf("expected dept to be deleted")
+	}
+	if countRows(t, db, model.TableNameSysRoleDept, "dept_id = ?", child.ID) != 0 {
+		t.Fatalf("expected role dept relation to be deleted")
+	}
+	queryOperationLog(t, db, constant.ResourceDept, int64(child.ID), constant.ActionDelete)
+}
+
+func TestDeptServiceConcurrentMoveIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	a := insertIntegrationDept(t, db, 0, "部门A")
+	b := insertIntegrationDept(t, db, 0, "部门B")
+	service := newIntegrationDeptService(deps)
+
+	// 并发互相移动到对方下级，加锁串行化后只能有一个成功，不会形成环
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, p := range []*DeptMoveParam{{ID: a.ID, ParentID: b.ID}, {ID: b.ID, ParentID: a.ID}} {
+		wg.Add(1)
+		go func(i int, p *DeptMoveParam) {
+			defer wg.Done()
+			errs[i] = service.MoveDept(testUserCtx(), p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	if (errs[0] == nil) == (errs[1] == nil) {
+		t.Fatalf("expected exactly one move to succeed, got %v", errs)
+	}
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, ErrDeptParentCycle) {
+			t.Fatalf("expected the losing move to fail with ErrDeptParentCycle, got %v", err)
+		}
+	}
+	if countRows(t, db, model.TableNameSysDept, "parent_id = 0") != 1 {
+		t.Fatalf("expected exactly one root dept after concurrent moves")
+	}
+}
+
+func TestUserServiceDeptAssignmentIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+	root := insertIntegrationDept(t, db, 0, "总公司")
+	child := insertIntegrationDept(t, db, root.ID, "研发")
+	service := newIntegrationUserService(deps)
+
+	missing := int32(999)
+	_, err := service.CreateUser(testUserCtx(), &UserParam{Username: "dept_missing", Tel: "18100000302", Password: "abc123", DeptId: &missing})
+	if !errors.Is(err, ErrUserDeptNotExist) {
+		t.Fatalf("CreateUser with missing dept expected ErrUserDeptNotExist, got %v", err)
+	}
+
+	userID, err := service.CreateUser(testUserCtx(), &UserParam{Username: "dept_user", Tel: "18100000303", Password: "abc123", DeptId: &child.ID})
+	if err != nil {
+		t.Fatalf("CreateUser expected success, got %v", err)
+	}
+	info, err := service.GetUserById(testUserCtx(), userID)
+	if err != nil {
+		t.Fatalf("GetUserById expected success, got %v", err)
+	}
+	if info.DeptId != child.ID || info.DeptName != "研发" {
+		t.Fatalf("expected user in dept %d 研发, got %d %s", child.ID, info.DeptId, info.DeptName)
+	}
+
+	// 按上级部门过滤包含下级部门的用户
+	list, err := service.GetUserList(testUserCtx(), &UserListCondition{DeptId: root.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetUserList expected success, got %v", err)
+	}
+	if list.Total != 1 || list.List[0].ID != userID {
+		t.Fatalf("expected dept filter to return user %d, got %+v", userID, list)
+	}
+
+	// 部门id为0清空所属部门
+	clear := int32(0)
+	if _, err := service.UpdateUser(testUserCtx(), &UserParam{ID: userID, Username: "dept_user", Tel: "18100000303", DeptId: &clear}); err != nil {
+		t.Fatalf("UpdateUser expected success, got %v", err)
+	}
+	if countRows(t, db, model.TableNameSysUser, "id = ? AND dept_id IS NULL", userID) != 1 {
+		t.Fatalf("expected user dept to be cleared")
+	}
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+// newTestDeptTree 总公司(1) -> 研发(2) -> 后端(4)；总公司(1) -> 市场(3)
+func newTestDeptTree() *fakeDeptRepo {
+	return &fakeDeptRepo{allDepts: []*model.SysDept{
+		{ID: 4, ParentID: 2, Name: "后端", SortOrder: 1},
+		{ID: 3, ParentID: 1, Name: "市场", SortOrder: 1},
+		{ID: 1, ParentID: 0, Name: "总公司", SortOrder: 1},
+		{ID: 2, ParentID: 1, Name: "研发", SortOrder: 2},
+	}}
+}
+
+func TestDeptServiceEarlyValidation(t *testing.T) {
+	service := &DeptService{}
+
+	if err := service.UpdateDept(testUserCtx(), &DeptParam{Name: "研发"}); !errors.Is(err, ErrDeptIDInvalid) {
+		t.Fatalf("UpdateDept expected ErrDeptIDInvalid, got %v", err)
+	}
+	if err := service.MoveDept(testUserCtx(), &DeptMoveParam{}); !errors.Is(err, ErrDeptIDInvalid) {
+		t.Fatalf("MoveDept expected ErrDeptIDInvalid, got %v", err)
+	}
+	if err := service.SortDept(testUserCtx(), &DeptSortParam{Items: []*DeptSortItem{{ID: 0}}}); !errors.Is(err, ErrDeptIDInvalid) {
+		t.Fatalf("SortDept expected ErrDeptIDInvalid, got %v", err)
+	}
+	if err := service.DeleteDeptById(testUserCtx(), 0); !errors.Is(err, ErrDeptIDInvalid) {
+		t.Fatalf("DeleteDeptById expected ErrDeptIDInvalid, got %v", err)
+	}
+}
+
+func TestDeptServiceGetDeptTree(t *testing.T) {
+	cache := newFakeCache()
+	repo := newTestDeptTree()
+	service := &DeptService{deptDao: repo, cache: cache}
+
+	got, err := service.GetDeptTree(testUserCtx())
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "总公司" {
+		t.Fatalf("unexpected roots: %+v", got)
+	}
+	children := got[0].Children
+	if len(children) != 2 || children[0].Name != "市场" || children[1].Name != "研发" {
+		t.Fatalf("expected children sorted by sort_order, got %+v", children)
+	}
+	if len(children[1].Children) != 1 || children[1].Children[0].ID != 4 {
+		t.Fatalf("expected nested child 4, got %+v", children[1].Children)
+	}
+	if _, ok := cache.values[constant.CacheDeptTree]; !ok {
+		t.Fatalf("expected tree cached")
+	}
+
+	// 第二次读取命中缓存
+	if _, err := service.GetDeptTree(testUserCtx()); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repo.getAllDeptsNum != 1 {
+		t.Fatalf("expected dao called once, got %d", repo.getAllDeptsNum)
+	}
+}
+
+func TestDeptServiceGetDeptAndChildIds(t *testing.T) {
+	service := &DeptService{deptDao: newTestDeptTree(), cache: newFakeCache()}
+
+	ids, err := service.GetDeptAndChildIds(testUserCtx(), 2)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []int32{2, 4}) {
+		t.Fatalf("expected dept 2 and its child 4, got %v", ids)
+	}
+	ids, err = service.GetDeptAndChildIds(testUserCtx(), 99)
+	if err != nil || ids != nil {
+		t.Fatalf("expected empty ids for missing dept, got %v %v", ids, err)
+	}
+}
+
+func TestDeptServiceCheckParent(t *testing.T) {
+	service := &DeptService{deptDao: newTestDeptTree()}
+
+	tests := []struct {
+		name     string
+		id       int32
+		parentId int32
+		want     error
+	}{
+		{"root", 2, 0, nil},
+		{"create under existing", 0, 4, nil},
+		{"move to sibling", 3, 2, nil},
+		{"self", 2, 2, ErrDeptParentCycle},
+		{"own descendant", 1, 4, ErrDeptParentCycle},
+		{"missing parent", 2, 99, ErrDeptParentInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.checkParent(testUserCtx(), nil, tt.id, tt.parentId); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -153,7 +153,14 @@ func migrateIntegrationTables(t *testing.T, db *gorm.DB) {
 		&model.SysMenu{},
 		&model.SysUserRole{},
 		&model.SysRoleMenu{},
+		&model.SysRoleDept{},
+		&model.SysRoleInherit{},
+		&model.SysDept{},
 		&model.SysOperationLog{},
+		&model.SysUserMfa{},
+		&model.SysAPIKey{},
+		&model.SysUserOidc{},
+		&model.SysUserPwdHistory{},
 	); err != nil {
 		t.Fatalf("migrate integration tables: %v", err)
 	}
@@ -197,6 +204,13 @@ func cleanupIntegrationTables(t *testing.T, db *gorm.DB) {
 		model.TableNameSysOperationLog,
 		model.TableNameSysUserRole,
 		model.TableNameSysRoleMenu,
+		model.TableNameSysRoleDept,
+		model.TableNameSysRoleInherit,
+		model.TableNameSysDept,
+		model.TableNameSysUserMfa,
+		model.TableNameSysAPIKey,
+		model.TableNameSysUserOidc,
+		model.TableNameSysUserPwdHistory,
 		model.TableNameSysUser,
 		model.TableNameSysRole,
 		model.TableNameSysMenu,
@@ -233,16 +247,42 @@ func newIntegrationOperationLogService(deps *integrationDeps) *systemService.Ope
 }
 
 func newIntegrationRoleService(deps *integrationDeps) *RoleService {
-	return NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, newIntegrationOperationLogService(deps))
+	return NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, newIntegrationOperationLogService(deps), nil)
+}
+
+func newIntegrationDeptService(deps *integrationDeps) *DeptService {
+	return NewDeptService(deps.repo, deps.cache, daoAccount.NewDeptDao(deps.repo), newIntegrationOperationLogService(deps))
 }
 
 func newIntegrationUserService(deps *integrationDeps) *UserService {
 	roleService := newIntegrationRoleService(deps)
-	return NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, newIntegrationOperationLogService(deps))
+	return NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, newIntegrationDeptService(deps),
+		newIntegrationRevocationService(deps), newIntegrationOperationLogService(deps), UserServiceOptions{})
+}
+
+func newIntegrationRevocationService(deps *integrationDeps) *RevocationService {
+	return NewRevocationService(deps.cache, 10*time.Minute, time.Hour)
+}
+
+func newIntegrationApiKeyService(deps *integrationDeps) *ApiKeyService {
+	return NewApiKeyService(deps.repo, daoAccount.NewApiKeyDao(deps.repo), daoAccount.NewUserDao(deps.repo),
+		deps.cache, newIntegrationOperationLogService(deps))
+}
+
+func newIntegrationMfaService(deps *integrationDeps) *MfaService {
+	return NewMfaService(deps.repo, daoAccount.NewMfaDao(deps.repo), deps.cache, newIntegrationOperationLogService(deps), MfaConfig{
+		Issuer:     "snowgo-test",
+		EncryptKey: "0123456789abcdef0123456789abcdef",
+	})
+}
+
+func newIntegrationOidcService(deps *integrationDeps, provider OidcProvider, conf OidcConfig) *OidcService {
+	return NewOidcService(deps.repo, daoAccount.NewOidcDao(deps.repo), daoAccount.NewUserDao(deps.repo),
+		deps.cache, newIntegrationOperationLogService(deps), provider, conf)
 }
 
 func newIntegrationMenuService(deps *integrationDeps) *MenuService {
-	return NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), newIntegrationOperationLogService(deps))
+	return NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), newIntegrationOperationLogService(deps), nil)
 }
 
 func insertIntegrationRole(t *testing.T, db *gorm.DB, code, name string) *model.SysRole {
@@ -270,6 +310,18 @@ func insertIntegrationMenu(t *testing.T, db *gorm.DB, parentID int32, menuType,
 	return menu
 }
 
+func insertIntegrationDept(t *testing.T, db *gorm.DB, parentID int32, name string) *model.SysDept {
+	t.Helper()
+	dept := &model.SysDept{
+		ParentID: parentID,
+		Name:     name,
+	}
+	if err := db.Create(dept).Error; err != nil {
+		t.Fatalf("insert integration dept: %v", err)
+	}
+	return dept
+}
+
 func insertIntegrationUser(t *testing.T, db *gorm.DB, username, tel string, roleIds ...int32) *model.SysUser {
 	t.Helper()
 	activeStatus := constant.UserStatusActive
@@ -279,7 +331,7 @@ func insertIntegrationUser(t *testing.T, db *gorm.DB, username, tel string, role
 	}
 	user := &model.SysUser{
 		Username: username,
-		Tel:      tel,
+		Tel:      &tel,
 		Password: password,
 		Status:   &activeStatus,
 	}
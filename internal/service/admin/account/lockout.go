@@ -0,0 +1,270 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+// lockoutOperatorName 登录失败锁定账号写操作日志时的操作人名称
+const lockoutOperatorName = "login-lockout"
+
+var (
+	ErrUserNotLocked        = e.NewBizError(e.UserNotLocked)
+	ErrLoginLocked          = e.NewBizError(e.LoginLocked)
+	ErrLoginLockedPermanent = e.NewBizError(e.LoginLockedPermanent)
+)
+
+// LockoutPolicy 登录失败渐进锁定策略：窗口内失败次数超限即锁定账号，连续锁定时长逐级递增，达到次数后永久锁定
+type LockoutPolicy struct {
+	Durations      []time.Duration // 连续第 N 次锁定的时长，超出按最后一项，为空表示不锁定账号
+	PermanentAfter int             // 连续第 N 次锁定改为永久锁定，0 表示不永久锁定
+}
+
+// Enabled 是否启用账号锁定
+func (p LockoutPolicy) Enabled() bool {
+	return len(p.Durations) > 0
+}
+
+// lockFor 连续第 count 次锁定的时长，permanent 为 true 表示永久锁定
+func (p LockoutPolicy) lockFor(count int32) (duration time.Duration, permanent bool) {
+	if p.PermanentAfter > 0 && int(count) >= p.PermanentAfter {
+		return 0, true
+	}
+	idx := min(int(count), len(p.Durations)) - 1
+	return p.Durations[max(idx, 0)], false
+}
+
+// LoginLock 用户登录锁定状态
+type LoginLock struct {
+	UserId    int32
+	Username  string
+	Count     int32     // 连续锁定次数
+	Permanent bool      // 永久锁定，需管理员解锁
+	Until     time.Time // 临时锁定截止时间
+}
+
+// Err 锁定对应的业务错误
+func (l *LoginLock) Err() *e.BizError {
+	if l.Permanent {
+		return ErrLoginLockedPermanent
+	}
+	return ErrLoginLocked
+}
+
+// Message 锁定描述，用于登录日志与操作日志
+func (l *LoginLock) Message() string {
+	if l.Permanent {
+		return fmt.Sprintf("连续%d次登录失败超限，账号已永久锁定，需管理员解锁", l.Count)
+	}
+	return fmt.Sprintf("连续%d次登录失败超限，账号锁定至%s", l.Count, l.Until.Format(constant.TimeFmtWithS))
+}
+
+// activeLoginLock 用户在 now 时刻的锁定状态，未锁定或临时锁定已到期返回 nil
+func activeLoginLock(user *model.SysUser, now time.Time) *LoginLock {
+	lock := &LoginLock{
+		UserId:   user.ID,
+		Username: user.Username,
+		Count:    common.DerefOrZero(user.LockCount),
+	}
+	switch common.DerefOrZero(user.LockStatus) {
+	case constant.UserLockPermanent:
+		lock.Permanent = true
+		return lock
+	case constant.UserLockTemporary:
+		if user.LockedUntil != nil && user.LockedUntil.After(now) {
+			lock.Until = *user.LockedUntil
+			return lock
+		}
+	}
+	return nil
+}
+
+// userLockState 用户当前锁定状态与临时锁定截止时间，临时锁定到期后视为未锁定
+func userLockState(user *model.SysUser, now time.Time) (int8, *time.Time) {
+	lock := activeLoginLock(user, now)
+	switch {
+	case lock == nil:
+		return constant.UserLockNone, nil
+	case lock.Permanent:
+		return constant.UserLockPermanent, nil
+	default:
+		return constant.UserLockTemporary, &lock.Until
+	}
+}
+
+// GetLoginLock 查询用户名当前的登录锁定状态，用户不存在或未锁定返回 nil
+func (u *UserService) GetLoginLock(ctx context.Context, username string) (*LoginLock, error) {
+	if len(username) <= 0 {
+		return nil, nil
+	}
+	// 读取主库，避免复制延迟导致刚锁定的账号继续尝试
+	user, err := u.userDao.GetUserByUsername(ctx, u.db.WriteQuery(), username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%s)信息异常: %v", username, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	return activeLoginLock(user, time.Now()), nil
+}
+
+// LockUser 登录失败超限时按锁定策略锁定账号并写系统操作日志，未启用锁定或用户不存在返回 nil；已处于锁定期时返回当前锁定
+func (u *UserService) LockUser(ctx context.Context, username string) (*LoginLock, error) {
+	if !u.lockout.Enabled() || len(username) <= 0 {
+		return nil, nil
+	}
+	traceCtx := xauth.GetContext(ctx)
+	var lock *LoginLock
+	err := u.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		user, err := u.userDao.LockUserByUsername(ctx, tx, username)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			xlogger.ErrorfCtx(ctx, "获取用户(%s)信息异常: %v", username, err)
+			return fmt.Errorf("用户信息查询失败: %w", err)
+		}
+		now := time.Now()
+		// 并发请求已完成锁定
+		if lock = activeLoginLock(user, now); lock != nil {
+			return nil
+		}
+
+		lock = &LoginLock{
+			UserId:   user.ID,
+			Username: user.Username,
+			Count:    common.DerefOrZero(user.LockCount) + 1,
+		}
+		status := constant.UserLockPermanent
+		var until *time.Time
+		duration, permanent := u.lockout.lockFor(lock.Count)
+		if permanent {
+			lock.Permanent = true
+		} else {
+			status = constant.UserLockTemporary
+			lock.Until = now.Add(duration)
+			until = &lock.Until
+		}
+		if err := u.userDao.SaveUserLock(ctx, tx, user.ID, status, lock.Count, until); err != nil {
+			xlogger.ErrorfCtx(ctx, "锁定用户(%d)失败: %v", user.ID, err)
+			return fmt.Errorf("锁定用户失败: %w", err)
+		}
+
+		err = u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorName: lockoutOperatorName,
+			OperatorType: constant.OperatorSystem,
+			Resource:     constant.ResourceUser,
+			ResourceID:   int64(user.ID),
+			TraceID:      traceCtx.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   nil,
+			AfterData:    lock,
+			Description:  fmt.Sprintf("系统锁定了用户(%d-%s)：%s", user.ID, user.Username, lock.Message()),
+			IP:           traceCtx.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v", err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if lock != nil {
+		xlogger.InfofCtx(ctx, "用户(%d-%s)登录失败超限被锁定: %s", lock.UserId, lock.Username, lock.Message())
+	}
+	return lock, nil
+}
+
+// ClearLoginLock 登录成功后清除用户锁定状态，连续锁定次数重新计算
+func (u *UserService) ClearLoginLock(ctx context.Context, userId int32) error {
+	if err := u.userDao.ClearUserLock(ctx, u.db.WriteQuery(), userId); err != nil {
+		xlogger.ErrorfCtx(ctx, "清除用户(%d)锁定状态失败: %v", userId, err)
+		return fmt.Errorf("清除用户锁定状态失败: %w", err)
+	}
+	return nil
+}
+
+// UnlockUser 管理员解锁用户，清除锁定状态、连续锁定次数与登录失败计数，返回解锁前的锁定状态
+func (u *UserService) UnlockUser(ctx context.Context, userId int32) (*LoginLock, error) {
+	if userId <= 0 {
+		return nil, ErrUserNotFound
+	}
+	// 获取登录ctx
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userDao.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userId, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	// 临时锁定到期但连续锁定次数未清零时仍允许解锁，使下次锁定重新从第一级开始
+	lock := &LoginLock{
+		UserId:   user.ID,
+		Username: user.Username,
+		Count:    common.DerefOrZero(user.LockCount),
+	}
+	if active := activeLoginLock(user, time.Now()); active != nil {
+		lock = active
+	} else if lock.Count == 0 {
+		return nil, ErrUserNotLocked
+	}
+
+	err = u.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := u.userDao.SaveUserLock(ctx, tx, userId, constant.UserLockNone, 0, nil); err != nil {
+			xlogger.ErrorfCtx(ctx, "解锁用户(%d)失败: %v", userId, err)
+			return fmt.Errorf("解锁用户失败: %w", err)
+		}
+
+		// 创建操作日志
+		err = u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceUser,
+			ResourceID:   int64(userId),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   lock,
+			AfterData:    nil,
+			Description: fmt.Sprintf("用户(%d-%s)解锁了用户(%d-%s)",
+				userContext.UserId, userContext.Username, userId, user.Username),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v", err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 清除登录失败计数，解锁后立即可以重新登录
+	if _, err := u.cache.Delete(ctx, constant.CacheLoginFailPrefix+user.Username); err != nil {
+		xlogger.ErrorfCtx(ctx, "清除用户(%d)登录失败计数失败: %v", userId, err)
+	}
+	xlogger.InfofCtx(ctx, "用户(%d-%s)解锁用户(%d)成功", userContext.UserId, userContext.Username, userId)
+	return lock, nil
+}
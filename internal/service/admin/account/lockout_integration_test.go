@@ -0,0 +1,111 @@
+//go:build integration
+
+package account
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	daoAccount "snowgo/internal/dao/admin/account"
+)
+
+func TestUserServiceLockoutIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+	user := insertIntegrationUser(t, db, "it_lock_user", "18100000301")
+	policy := LockoutPolicy{Durations: []time.Duration{time.Minute, time.Hour}, PermanentAfter: 3}
+	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, newIntegrationRoleService(deps),
+		newIntegrationDeptService(deps), newIntegrationRevocationService(deps), newIntegrationOperationLogService(deps), UserServiceOptions{Lockout: policy})
+	expireLock := func() {
+		t.Helper()
+		if err := db.Model(&model.SysUser{}).Where("id = ?", user.ID).
+			Update("locked_until", time.Now().Add(-time.Second)).Error; err != nil {
+			t.Fatalf("expire lock: %v", err)
+		}
+	}
+
+	// 不存在的用户名只受窗口限流
+	if lock, err := service.LockUser(testUserCtx(), "it_missing_user"); err != nil || lock != nil {
+		t.Fatalf("LockUser missing user expected nil, got %+v %v", lock, err)
+	}
+
+	lock, err := service.LockUser(testUserCtx(), user.Username)
+	if err != nil || lock == nil || lock.Count != 1 || lock.Permanent {
+		t.Fatalf("LockUser expected first temporary lock, got %+v %v", lock, err)
+	}
+	if until := time.Until(lock.Until); until <= 0 || until > time.Minute {
+		t.Fatalf("expected first lock duration, got %v", until)
+	}
+	if got, err := service.GetLoginLock(testUserCtx(), user.Username); err != nil || got == nil {
+		t.Fatalf("GetLoginLock expected active lock, got %+v %v", got, err)
+	}
+	// 锁定期内重复触发不升级
+	if again, err := service.LockUser(testUserCtx(), user.Username); err != nil || again.Count != 1 {
+		t.Fatalf("LockUser while locked expected current lock, got %+v %v", again, err)
+	}
+	if count := countRows(t, db, model.TableNameSysOperationLog, "resource = ? AND resource_id = ? AND operator_type = ?",
+		constant.ResourceUser, user.ID, constant.OperatorSystem); count != 1 {
+		t.Fatalf("expected one system operation log, got %d", count)
+	}
+
+	// 到期后再次超限按下一级锁定，达到次数后永久锁定
+	expireLock()
+	if got, err := service.GetLoginLock(testUserCtx(), user.Username); err != nil || got != nil {
+		t.Fatalf("GetLoginLock expected expired lock ignored, got %+v %v", got, err)
+	}
+	lock, err = service.LockUser(testUserCtx(), user.Username)
+	if err != nil || lock.Count != 2 || time.Until(lock.Until) <= time.Minute {
+		t.Fatalf("LockUser expected escalated lock, got %+v %v", lock, err)
+	}
+	expireLock()
+	lock, err = service.LockUser(testUserCtx(), user.Username)
+	if err != nil || lock.Count != 3 || !lock.Permanent {
+		t.Fatalf("LockUser expected permanent lock, got %+v %v", lock, err)
+	}
+	info, err := service.GetUserById(testUserCtx(), user.ID)
+	if err != nil || info.LockStatus != constant.UserLockPermanent || info.LockCount != 3 || info.LockedUntil != nil {
+		t.Fatalf("expected permanent lock in user info, got %+v %v", info, err)
+	}
+
+	// 管理员解锁清除锁定状态与登录失败计数
+	failKey := constant.CacheLoginFailPrefix + user.Username
+	if err := deps.cache.Set(testUserCtx(), failKey, "6", time.Minute); err != nil {
+		t.Fatalf("set login fail counter: %v", err)
+	}
+	unlocked, err := service.UnlockUser(testUserCtx(), user.ID)
+	if err != nil || !unlocked.Permanent || unlocked.Username != user.Username {
+		t.Fatalf("UnlockUser expected previous lock, got %+v %v", unlocked, err)
+	}
+	if count := countRows(t, db, model.TableNameSysUser, "id = ? AND lock_status = ? AND lock_count = 0 AND locked_until IS NULL",
+		user.ID, constant.UserLockNone); count != 1 {
+		t.Fatalf("expected lock cleared, got count %d", count)
+	}
+	if count := countRows(t, db, model.TableNameSysOperationLog, "resource = ? AND resource_id = ? AND operator_type = ? AND operator_id = ?",
+		constant.ResourceUser, user.ID, constant.OperatorUser, 1); count != 1 {
+		t.Fatalf("expected unlock operation log, got %d", count)
+	}
+	if _, ok, err := deps.cache.Get(testUserCtx(), failKey); err != nil || ok {
+		t.Fatalf("expected login fail counter cleared, got %v %v", ok, err)
+	}
+	if _, err := service.UnlockUser(testUserCtx(), user.ID); !errors.Is(err, ErrUserNotLocked) {
+		t.Fatalf("UnlockUser unlocked user expected ErrUserNotLocked, got %v", err)
+	}
+
+	// 登录成功清除连续锁定次数
+	if _, err := service.LockUser(testUserCtx(), user.Username); err != nil {
+		t.Fatalf("LockUser error: %v", err)
+	}
+	if err := service.ClearLoginLock(testUserCtx(), user.ID); err != nil {
+		t.Fatalf("ClearLoginLock error: %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysUser, "id = ? AND lock_count = 0", user.ID); count != 1 {
+		t.Fatalf("expected lock count cleared after login, got count %d", count)
+	}
+}
@@ -0,0 +1,82 @@
+package account
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+func TestLockoutPolicyLockFor(t *testing.T) {
+	policy := LockoutPolicy{Durations: []time.Duration{5 * time.Minute, 30 * time.Minute, 2 * time.Hour}, PermanentAfter: 5}
+	tests := []struct {
+		count     int32
+		duration  time.Duration
+		permanent bool
+	}{
+		{1, 5 * time.Minute, false},
+		{2, 30 * time.Minute, false},
+		{3, 2 * time.Hour, false},
+		{4, 2 * time.Hour, false},
+		{5, 0, true},
+		{6, 0, true},
+	}
+	for _, tt := range tests {
+		duration, permanent := policy.lockFor(tt.count)
+		if duration != tt.duration || permanent != tt.permanent {
+			t.Fatalf("lock %d expected %v/%v, got %v/%v", tt.count, tt.duration, tt.permanent, duration, permanent)
+		}
+	}
+
+	// 不永久锁定时一直按最后一级锁定
+	policy.PermanentAfter = 0
+	if duration, permanent := policy.lockFor(10); duration != 2*time.Hour || permanent {
+		t.Fatalf("expected last duration without permanent lock, got %v/%v", duration, permanent)
+	}
+}
+
+func TestActiveLoginLock(t *testing.T) {
+	now := time.Now()
+	past, later := now.Add(-time.Minute), now.Add(time.Hour)
+	lockUser := func(status int8, count int32, until *time.Time) *model.SysUser {
+		return &model.SysUser{ID: 2, Username: "alice", LockStatus: &status, LockCount: &count, LockedUntil: until}
+	}
+
+	if lock := activeLoginLock(&model.SysUser{ID: 2, Username: "alice"}, now); lock != nil {
+		t.Fatalf("expected unlocked user, got %+v", lock)
+	}
+	if lock := activeLoginLock(lockUser(constant.UserLockTemporary, 1, &past), now); lock != nil {
+		t.Fatalf("expected expired temporary lock ignored, got %+v", lock)
+	}
+
+	lock := activeLoginLock(lockUser(constant.UserLockTemporary, 2, &later), now)
+	if lock == nil || lock.Permanent || !lock.Until.Equal(later) || lock.Count != 2 {
+		t.Fatalf("expected active temporary lock, got %+v", lock)
+	}
+	if !errors.Is(lock.Err(), ErrLoginLocked) || !strings.Contains(lock.Message(), later.Format(constant.TimeFmtWithS)) {
+		t.Fatalf("expected temporary lock error and message, got %v %q", lock.Err(), lock.Message())
+	}
+	if status, until := userLockState(lockUser(constant.UserLockTemporary, 2, &later), now); status != constant.UserLockTemporary || until == nil {
+		t.Fatalf("expected temporary lock state, got %d %v", status, until)
+	}
+
+	lock = activeLoginLock(lockUser(constant.UserLockPermanent, 5, nil), now)
+	if lock == nil || !lock.Permanent || !errors.Is(lock.Err(), ErrLoginLockedPermanent) {
+		t.Fatalf("expected permanent lock, got %+v", lock)
+	}
+	if status, until := userLockState(lockUser(constant.UserLockTemporary, 1, &past), now); status != constant.UserLockNone || until != nil {
+		t.Fatalf("expected expired lock shown as unlocked, got %d %v", status, until)
+	}
+}
+
+func TestUserServiceLockUserDisabled(t *testing.T) {
+	service := &UserService{userDao: &fakeUserRepo{}}
+
+	lock, err := service.LockUser(testUserCtx(), "alice")
+	if err != nil || lock != nil {
+		t.Fatalf("expected no lock without lockout policy, got %+v %v", lock, err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"snowgo/internal/service/admin/contract"
 	common "snowgo/pkg"
 	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/perm"
 	"snowgo/pkg/xcache"
 	e "snowgo/pkg/xerror"
 	"snowgo/pkg/xlogger"
@@ -29,6 +30,7 @@ type MenuRepo interface {
 	IsPermsExists(ctx context.Context, q *query.Query, perms string, excludeId int32) (bool, error)
 	IsPathExists(ctx context.Context, q *query.Query, path string, excludeId int32) (bool, error)
 	GetRoleIdsByIds(ctx context.Context, menuId int32) ([]int32, error)
+	GetAllRoleInherits(ctx context.Context) ([]*model.SysRoleInherit, error)
 }
 
 type MenuService struct {
@@ -36,14 +38,17 @@ type MenuService struct {
 	menuDao    MenuRepo
 	cache      xcache.Cache
 	logService contract.OperationLogWriter
+	permCache  *PermCache
 }
 
-func NewMenuService(db *repo.Repository, cache xcache.Cache, menuDao MenuRepo, logService contract.OperationLogWriter) *MenuService {
+func NewMenuService(db *repo.Repository, cache xcache.Cache, menuDao MenuRepo, logService contract.OperationLogWriter,
+	permCache *PermCache) *MenuService {
 	return &MenuService{
 		db:         db,
 		cache:      cache,
 		menuDao:    menuDao,
 		logService: logService,
+		permCache:  permCache,
 	}
 }
 
@@ -96,6 +101,7 @@ var (
 	ErrMenuHasChildren   = e.NewBizError(e.MenuHasChildren)
 	ErrMenuUsedByRole    = e.NewBizError(e.MenuUsedByRole)
 	ErrMenuIDInvalid     = e.NewBizError(e.MenuIDInvalid)
+	ErrMenuPermsInvalid  = e.NewBizError(e.MenuPermsInvalid)
 )
 
 // CreateMenu 创建菜单权限
@@ -110,6 +116,10 @@ func (s *MenuService) CreateMenu(ctx context.Context, p *MenuParam) (int32, erro
 	if p.ID > 0 && p.ParentID == p.ID {
 		return 0, ErrMenuParentSelf
 	}
+	// 权限标识按 : 分段，通配符 * 必须独占一段
+	if permsVal := common.DerefOrZero(p.Perms); permsVal != "" && perm.Validate(permsVal) != nil {
+		return 0, ErrMenuPermsInvalid
+	}
 
 	menu := &model.SysMenu{
 		ParentID:  p.ParentID,
@@ -206,6 +216,9 @@ func (s *MenuService) UpdateMenu(ctx context.Context, p *MenuParam) error {
 	if p.ID <= 0 {
 		return ErrMenuIDInvalid
 	}
+	if permsVal := common.DerefOrZero(p.Perms); permsVal != "" && perm.Validate(permsVal) != nil {
+		return ErrMenuPermsInvalid
+	}
 
 	// 获取原始角色信息（可选）
 	oldMenu, err := s.menuDao.GetById(ctx, s.db.Query(), p.ID)
@@ -298,16 +311,21 @@ func (s *MenuService) UpdateMenu(ctx context.Context, p *MenuParam) error {
 		xlogger.ErrorfCtx(ctx, "清理菜单树缓存失败: %v", err)
 	}
 
-	// 清理绑定了该菜单的角色缓存（精准失效）
+	// 清理绑定了该菜单的角色及其下级角色的缓存（精准失效）
 	roleIds, err := s.menuDao.GetRoleIdsByIds(ctx, p.ID)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "获取角色ids异常: %v", err)
 	}
-	for _, roleId := range roleIds {
-		cacheKey := fmt.Sprintf("%s%d", constant.CacheRoleMenuPrefix, roleId)
-		if _, err := s.cache.Delete(ctx, cacheKey); err != nil {
-			xlogger.ErrorfCtx(ctx, "清除角色对应接口权限缓存失败: %v", err)
+	if len(roleIds) > 0 {
+		edges, inheritErr := s.menuDao.GetAllRoleInherits(ctx)
+		if inheritErr != nil {
+			xlogger.ErrorfCtx(ctx, "获取角色继承关系异常: %v", inheritErr)
 		}
+		clearRoleMenuCache(ctx, s.cache, edges, roleIds...)
+	}
+	// 进程内权限缓存按用户存储，查询角色失败时同样全部失效
+	if err != nil || len(roleIds) > 0 {
+		s.permCache.InvalidateAll(ctx)
 	}
 	return nil
 }
@@ -133,7 +133,7 @@ func TestMenuServiceCreateMenuRollbackIntegration(t *testing.T) {
 	cleanupIntegrationTables(t, db)
 
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
-	service := NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), operationLogService)
+	service := NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), operationLogService, nil)
 	path := "/rollback/menu"
 	perms := "rollback:menu"
 	_, err := service.CreateMenu(testUserCtx(), &MenuParam{
@@ -306,7 +306,7 @@ func TestMenuServiceUpdateMenuRollbackIntegration(t *testing.T) {
 		t.Fatalf("update existing menu path/perms: %v", err)
 	}
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
-	service := NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), operationLogService)
+	service := NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), operationLogService, nil)
 	newPath := "/rollback/menu-updated"
 	newPerms := "rollback:menu:update"
 	err := service.UpdateMenu(testUserCtx(), &MenuParam{
@@ -394,7 +394,7 @@ func TestMenuServiceDeleteMenuByIdRollbackIntegration(t *testing.T) {
 
 	menu := insertIntegrationMenu(t, db, 0, constant.MenuTypeMenu, "待回滚菜单")
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
-	service := NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), operationLogService)
+	service := NewMenuService(deps.repo, deps.cache, daoAccount.NewMenuDao(deps.repo), operationLogService, nil)
 
 	err := service.DeleteMenuById(testUserCtx(), menu.ID)
 	if !errors.Is(err, errIntegrationOperationLog) {
@@ -0,0 +1,534 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/totp"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xcryption"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+const (
+	defaultMfaIssuer       = "snowgo"
+	defaultMfaChallengeTTL = 5 * time.Minute
+	recoveryCodeCount      = 10                                 // 每次生成的恢复码数量
+	recoveryCodeAlphabet   = "abcdefghijklmnopqrstuvwxyz234567" // 32 个字符，字节取模无偏差
+)
+
+var (
+	ErrMfaChallengeInvalid = e.NewBizError(e.MfaChallengeInvalid)
+	ErrMfaCodeInvalid      = e.NewBizError(e.MfaCodeInvalid)
+	ErrMfaNotEnabled       = e.NewBizError(e.MfaNotEnabled)
+	ErrMfaAlreadyEnabled   = e.NewBizError(e.MfaAlreadyEnabled)
+	ErrMfaNotEnrolled      = e.NewBizError(e.MfaNotEnrolled)
+	ErrMfaRequiredByRole   = e.NewBizError(e.MfaRequiredByRole)
+	ErrMfaUnavailable      = e.NewBizError(e.MfaUnavailable)
+)
+
+// MfaRepo 定义两步验证相关db操作接口
+type MfaRepo interface {
+	GetMfaByUserId(ctx context.Context, q *query.Query, userId int32) (*model.SysUserMfa, error)
+	LockMfaByUserId(ctx context.Context, q *query.Query, userId int32) (*model.SysUserMfa, error)
+	SavePendingMfa(ctx context.Context, q *query.Query, userId int32, secret string) error
+	EnableMfa(ctx context.Context, q *query.Query, userId int32, recoveryCodes string) error
+	ConsumeStep(ctx context.Context, q *query.Query, userId int32, step int64) (bool, error)
+	UpdateRecoveryCodes(ctx context.Context, q *query.Query, userId int32, recoveryCodes string) error
+	DeleteMfa(ctx context.Context, q *query.Query, userId int32) error
+	IsMfaRequiredByRole(ctx context.Context, q *query.Query, userId int32) (bool, error)
+}
+
+// MfaConfig 两步验证配置
+type MfaConfig struct {
+	Issuer       string        // 验证器 App 中展示的发行方
+	EncryptKey   string        // TOTP 密钥加密 key(AES 16/24/32 字节)，为空时两步验证不可用
+	ChallengeTTL time.Duration // 登录挑战有效期
+}
+
+// MfaService TOTP 两步验证
+type MfaService struct {
+	db         *repo.Repository
+	mfaDao     MfaRepo
+	cache      xcache.Cache
+	logService contract.OperationLogWriter
+	conf       MfaConfig
+}
+
+func NewMfaService(db *repo.Repository, mfaDao MfaRepo, cache xcache.Cache,
+	logService contract.OperationLogWriter, conf MfaConfig) *MfaService {
+	if conf.Issuer == "" {
+		conf.Issuer = defaultMfaIssuer
+	}
+	if conf.ChallengeTTL <= 0 {
+		conf.ChallengeTTL = defaultMfaChallengeTTL
+	}
+	return &MfaService{
+		db:         db,
+		mfaDao:     mfaDao,
+		cache:      cache,
+		logService: logService,
+		conf:       conf,
+	}
+}
+
+// MfaStatus 用户两步验证状态
+type MfaStatus struct {
+	Enabled           bool `json:"enabled"`             // 是否已启用
+	Required          bool `json:"required"`            // 登录是否需要两步验证（已启用或角色强制）
+	RequiredByRole    bool `json:"required_by_role"`    // 是否被角色强制
+	RecoveryCodesLeft int  `json:"recovery_codes_left"` // 剩余恢复码数量
+}
+
+// MfaEnrollment 绑定验证器信息
+type MfaEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MfaChallenge 登录挑战，密码校验通过、两步验证前的临时状态
+type MfaChallenge struct {
+	UserId        int32     `json:"user_id"`
+	Username      string    `json:"username"`
+	Device        string    `json:"device"`
+	SetupRequired bool      `json:"setup_required"` // 角色强制但尚未绑定，需要先绑定验证器
+	ExpireAt      time.Time `json:"expire_at"`
+}
+
+// MfaVerifyInput 两步验证输入，验证码与恢复码二选一
+type MfaVerifyInput struct {
+	Code         string
+	RecoveryCode string
+}
+
+// Available 是否已配置加密 key
+func (s *MfaService) Available() bool {
+	return s.conf.EncryptKey != ""
+}
+
+// GetStatus 查询用户两步验证状态
+func (s *MfaService) GetStatus(ctx context.Context, userId int32) (*MfaStatus, error) {
+	if userId <= 0 {
+		return nil, ErrUserNotFound
+	}
+	status := &MfaStatus{}
+	// 登录鉴权读取主库，避免刚启用或刚重置后读到旧状态
+	mfa, err := s.mfaDao.GetMfaByUserId(ctx, s.db.WriteQuery(), userId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		xlogger.ErrorfCtx(ctx, "查询用户(%d)两步验证信息异常: %v", userId, err)
+		return nil, fmt.Errorf("查询两步验证信息失败: %w", err)
+	}
+	if err == nil && common.DerefOrZero(mfa.Status) == constant.MfaStatusEnabled {
+		status.Enabled = true
+		status.RecoveryCodesLeft = len(decodeRecoveryCodes(mfa.RecoveryCodes))
+	}
+	status.RequiredByRole, err = s.mfaDao.IsMfaRequiredByRole(ctx, s.db.WriteQuery(), userId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询用户(%d)角色两步验证要求异常: %v", userId, err)
+		return nil, fmt.Errorf("查询角色两步验证要求失败: %w", err)
+	}
+	status.Required = status.Enabled || status.RequiredByRole
+	// 未配置加密 key 时无法校验验证码：需要两步验证的用户拒绝登录，不能当作无需两步验证放行
+	if !s.Available() {
+		if status.Required {
+			xlogger.ErrorfCtx(ctx, "用户(%d)需要两步验证但未配置 auth.mfa.encrypt_key", userId)
+			return nil, ErrMfaUnavailable
+		}
+		return &MfaStatus{}, nil
+	}
+	return status, nil
+}
+
+// CreateChallenge 创建登录挑战，返回挑战令牌
+func (s *MfaService) CreateChallenge(ctx context.Context, challenge *MfaChallenge) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成挑战令牌失败: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	challenge.ExpireAt = time.Now().Add(s.conf.ChallengeTTL)
+	raw, err := json.Marshal(challenge)
+	if err != nil {
+		return "", fmt.Errorf("挑战序列化失败: %w", err)
+	}
+	if err := s.cache.Set(ctx, constant.CacheMfaChallengePrefix+token, string(raw), s.conf.ChallengeTTL); err != nil {
+		xlogger.ErrorfCtx(ctx, "保存登录挑战失败 uid=%d: %v", challenge.UserId, err)
+		return "", fmt.Errorf("保存登录挑战失败: %w", err)
+	}
+	return token, nil
+}
+
+// GetChallenge 查询登录挑战
+func (s *MfaService) GetChallenge(ctx context.Context, token string) (*MfaChallenge, error) {
+	if token == "" {
+		return nil, ErrMfaChallengeInvalid
+	}
+	raw, ok, err := s.cache.Get(ctx, constant.CacheMfaChallengePrefix+token)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询登录挑战失败: %v", err)
+		return nil, fmt.Errorf("查询登录挑战失败: %w", err)
+	}
+	if !ok {
+		return nil, ErrMfaChallengeInvalid
+	}
+	var challenge MfaChallenge
+	if err := json.Unmarshal([]byte(raw), &challenge); err != nil {
+		return nil, ErrMfaChallengeInvalid
+	}
+	return &challenge, nil
+}
+
+// SetupChallenge 角色强制但未绑定的用户，在登录挑战内绑定验证器
+func (s *MfaService) SetupChallenge(ctx context.Context, token string) (*MfaEnrollment, error) {
+	challenge, err := s.GetChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.SetupRequired {
+		return nil, ErrMfaAlreadyEnabled
+	}
+	return s.enroll(ctx, challenge.UserId, challenge.Username)
+}
+
+// VerifyChallenge 校验登录挑战的验证码，成功后挑战作废；首次绑定时同时启用两步验证并返回恢复码
+func (s *MfaService) VerifyChallenge(ctx context.Context, token string, input *MfaVerifyInput) (*MfaChallenge, []string, error) {
+	challenge, err := s.GetChallenge(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.SetupRequired {
+		operator := &xauth.UserContext{Context: *xauth.GetContext(ctx), UserId: challenge.UserId, Username: challenge.Username}
+		recoveryCodes, err = s.activate(ctx, operator, input.Code)
+	} else {
+		err = s.verify(ctx, challenge.UserId, input)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 挑战只能使用一次，并发请求以删除成功者为准
+	if deleted, err := s.cache.Delete(ctx, constant.CacheMfaChallengePrefix+token); err != nil || deleted == 0 {
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "删除登录挑战失败 uid=%d: %v", challenge.UserId, err)
+		}
+		return nil, nil, ErrMfaChallengeInvalid
+	}
+	return challenge, recoveryCodes, nil
+}
+
+// Enroll 当前登录用户生成新的 TOTP 密钥，验证首个验证码后才生效
+func (s *MfaService) Enroll(ctx context.Context) (*MfaEnrollment, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.enroll(ctx, userContext.UserId, userContext.Username)
+}
+
+// Activate 当前登录用户验证首个验证码，启用两步验证并返回恢复码
+func (s *MfaService) Activate(ctx context.Context, code string) ([]string, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.activate(ctx, userContext, code)
+}
+
+// Disable 当前登录用户关闭两步验证，角色强制时不允许关闭
+func (s *MfaService) Disable(ctx context.Context, input *MfaVerifyInput) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !s.Available() {
+		return ErrMfaUnavailable
+	}
+	required, err := s.mfaDao.IsMfaRequiredByRole(ctx, s.db.WriteQuery(), userContext.UserId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询用户(%d)角色两步验证要求异常: %v", userContext.UserId, err)
+		return fmt.Errorf("查询角色两步验证要求失败: %w", err)
+	}
+	if required {
+		return ErrMfaRequiredByRole
+	}
+	if err := s.verify(ctx, userContext.UserId, input); err != nil {
+		return err
+	}
+	return s.remove(ctx, userContext, userContext.UserId,
+		fmt.Sprintf("用户(%d-%s)关闭了两步验证", userContext.UserId, userContext.Username))
+}
+
+// RegenerateRecoveryCodes 当前登录用户重新生成恢复码，旧恢复码全部作废
+func (s *MfaService) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Available() {
+		return nil, ErrMfaUnavailable
+	}
+	if err := s.verify(ctx, userContext.UserId, &MfaVerifyInput{Code: code}); err != nil {
+		return nil, err
+	}
+	codes, hashed, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := s.mfaDao.UpdateRecoveryCodes(ctx, tx, userContext.UserId, hashed); err != nil {
+			xlogger.ErrorfCtx(ctx, "更新用户(%d)恢复码异常: %v", userContext.UserId, err)
+			return fmt.Errorf("更新恢复码失败: %w", err)
+		}
+		return s.writeLog(ctx, tx, userContext, userContext.UserId, constant.ActionUpdate,
+			fmt.Sprintf("用户(%d-%s)重新生成了两步验证恢复码", userContext.UserId, userContext.Username))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// ResetUserMfa 管理员重置用户两步验证（用户丢失验证器和恢复码时使用），下次登录需重新绑定
+func (s *MfaService) ResetUserMfa(ctx context.Context, userId int32) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	if userId <= 0 {
+		return ErrUserNotFound
+	}
+	if _, err := s.mfaDao.GetMfaByUserId(ctx, s.db.WriteQuery(), userId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMfaNotEnabled
+		}
+		xlogger.ErrorfCtx(ctx, "查询用户(%d)两步验证信息异常: %v", userId, err)
+		return fmt.Errorf("查询两步验证信息失败: %w", err)
+	}
+	return s.remove(ctx, userContext, userId,
+		fmt.Sprintf("用户(%d-%s)重置了用户(%d)的两步验证", userContext.UserId, userContext.Username, userId))
+}
+
+// enroll 生成待验证的密钥，已启用时需先关闭
+func (s *MfaService) enroll(ctx context.Context, userId int32, username string) (*MfaEnrollment, error) {
+	if !s.Available() {
+		return nil, ErrMfaUnavailable
+	}
+	mfa, err := s.mfaDao.GetMfaByUserId(ctx, s.db.WriteQuery(), userId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		xlogger.ErrorfCtx(ctx, "查询用户(%d)两步验证信息异常: %v", userId, err)
+		return nil, fmt.Errorf("查询两步验证信息失败: %w", err)
+	}
+	if err == nil && common.DerefOrZero(mfa.Status) == constant.MfaStatusEnabled {
+		return nil, ErrMfaAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := xcryption.AesGCMEncrypt(secret, s.conf.EncryptKey)
+	if err != nil {
+		return nil, fmt.Errorf("加密totp密钥失败: %w", err)
+	}
+	if err := s.mfaDao.SavePendingMfa(ctx, s.db.WriteQuery(), userId, encrypted); err != nil {
+		xlogger.ErrorfCtx(ctx, "保存用户(%d)totp密钥异常: %v", userId, err)
+		return nil, fmt.Errorf("保存totp密钥失败: %w", err)
+	}
+	return &MfaEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.conf.Issuer, username, secret),
+	}, nil
+}
+
+// activate 校验首个验证码并启用两步验证
+func (s *MfaService) activate(ctx context.Context, operator *xauth.UserContext, code string) ([]string, error) {
+	if !s.Available() {
+		return nil, ErrMfaUnavailable
+	}
+	userId := operator.UserId
+	codes, hashed, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		mfa, err := s.mfaDao.LockMfaByUserId(ctx, tx, userId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMfaNotEnrolled
+			}
+			xlogger.ErrorfCtx(ctx, "查询用户(%d)两步验证信息异常: %v", userId, err)
+			return fmt.Errorf("查询两步验证信息失败: %w", err)
+		}
+		if common.DerefOrZero(mfa.Status) == constant.MfaStatusEnabled {
+			return ErrMfaAlreadyEnabled
+		}
+		if err := s.checkCode(ctx, tx, mfa, code); err != nil {
+			return err
+		}
+		if err := s.mfaDao.EnableMfa(ctx, tx, userId, hashed); err != nil {
+			xlogger.ErrorfCtx(ctx, "启用用户(%d)两步验证异常: %v", userId, err)
+			return fmt.Errorf("启用两步验证失败: %w", err)
+		}
+		return s.writeLog(ctx, tx, operator, userId, constant.ActionCreate,
+			fmt.Sprintf("用户(%d-%s)开启了两步验证", operator.UserId, operator.Username))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verify 校验已启用用户的验证码或恢复码，恢复码使用后作废
+func (s *MfaService) verify(ctx context.Context, userId int32, input *MfaVerifyInput) error {
+	if !s.Available() {
+		return ErrMfaUnavailable
+	}
+	return s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		mfa, err := s.mfaDao.LockMfaByUserId(ctx, tx, userId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMfaNotEnabled
+			}
+			xlogger.ErrorfCtx(ctx, "查询用户(%d)两步验证信息异常: %v", userId, err)
+			return fmt.Errorf("查询两步验证信息失败: %w", err)
+		}
+		if common.DerefOrZero(mfa.Status) != constant.MfaStatusEnabled {
+			return ErrMfaNotEnabled
+		}
+
+		if input.RecoveryCode == "" {
+			return s.checkCode(ctx, tx, mfa, input.Code)
+		}
+		hashes := decodeRecoveryCodes(mfa.RecoveryCodes)
+		target := hashRecoveryCode(input.RecoveryCode)
+		for i, hashed := range hashes {
+			if hashed != target {
+				continue
+			}
+			remaining := append(hashes[:i:i], hashes[i+1:]...)
+			raw, _ := json.Marshal(remaining)
+			if err := s.mfaDao.UpdateRecoveryCodes(ctx, tx, userId, string(raw)); err != nil {
+				xlogger.ErrorfCtx(ctx, "更新用户(%d)恢复码异常: %v", userId, err)
+				return fmt.Errorf("更新恢复码失败: %w", err)
+			}
+			xlogger.InfofCtx(ctx, "用户(%d)使用恢复码完成两步验证，剩余%d个", userId, len(remaining))
+			return nil
+		}
+		return ErrMfaCodeInvalid
+	})
+}
+
+// checkCode 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (s *MfaService) checkCode(ctx context.Context, tx *query.Query, mfa *model.SysUserMfa, code string) error {
+	secret, err := xcryption.AesGCMDecrypt(mfa.Secret, s.conf.EncryptKey)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "解密用户(%d)totp密钥失败: %v", mfa.UserID, err)
+		return fmt.Errorf("解密totp密钥失败: %w", err)
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return ErrMfaCodeInvalid
+	}
+	consumed, err := s.mfaDao.ConsumeStep(ctx, tx, mfa.UserID, step)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "记录用户(%d)totp时间步异常: %v", mfa.UserID, err)
+		return fmt.Errorf("记录totp时间步失败: %w", err)
+	}
+	if !consumed {
+		return ErrMfaCodeInvalid
+	}
+	return nil
+}
+
+// remove 删除两步验证信息并记录操作日志
+func (s *MfaService) remove(ctx context.Context, operator *xauth.UserContext, userId int32, description string) error {
+	return s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := s.mfaDao.DeleteMfa(ctx, tx, userId); err != nil {
+			xlogger.ErrorfCtx(ctx, "删除用户(%d)两步验证异常: %v", userId, err)
+			return fmt.Errorf("删除两步验证失败: %w", err)
+		}
+		return s.writeLog(ctx, tx, operator, userId, constant.ActionDelete, description)
+	})
+}
+
+func (s *MfaService) writeLog(ctx context.Context, tx *query.Query, operator *xauth.UserContext,
+	userId int32, action string, description string) error {
+	err := s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+		OperatorID:   operator.UserId,
+		OperatorName: operator.Username,
+		OperatorType: constant.OperatorUser,
+		Resource:     constant.ResourceMfa,
+		ResourceID:   int64(userId),
+		TraceID:      operator.TraceId,
+		Action:       action,
+		Description:  description,
+		IP:           operator.IP,
+	})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "操作日志创建失败 resource=mfa resource_id=%d err: %v", userId, err)
+		return fmt.Errorf("操作日志创建失败: %w", err)
+	}
+	return nil
+}
+
+// generateRecoveryCodes 生成恢复码，返回明文（仅展示一次）和哈希后的 JSON
+func generateRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	buf := make([]byte, 10)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, "", fmt.Errorf("生成恢复码失败: %w", err)
+		}
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+		code := sb.String()
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	raw, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", fmt.Errorf("恢复码序列化失败: %w", err)
+	}
+	return codes, string(raw), nil
+}
+
+// hashRecoveryCode 恢复码为高熵随机串，使用 SHA-256 存储即可，忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return xcryption.Sha256(normalized)
+}
+
+func decodeRecoveryCodes(raw *string) []string {
+	var hashes []string
+	if raw == nil || *raw == "" {
+		return hashes
+	}
+	if err := json.Unmarshal([]byte(*raw), &hashes); err != nil {
+		return nil
+	}
+	return hashes
+}
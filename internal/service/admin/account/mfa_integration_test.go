@@ -0,0 +1,145 @@
+//go:build integration
+
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/totp"
+)
+
+func mfaUserCtx(user *model.SysUser) context.Context {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, xauth.XUserId, user.ID)
+	ctx = context.WithValue(ctx, xauth.XUserName, user.Username)
+	ctx = context.WithValue(ctx, xauth.XTraceId, "trace-mfa")
+	ctx = context.WithValue(ctx, xauth.XIp, "127.0.0.1")
+	return ctx
+}
+
+func TestMfaServiceLifecycleIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	user := insertIntegrationUser(t, db, "mfa_operator", "18100000101")
+	ctx := mfaUserCtx(user)
+	service := newIntegrationMfaService(deps)
+
+	enrollment, err := service.Enroll(ctx)
+	if err != nil {
+		t.Fatalf("Enroll error: %v", err)
+	}
+	if _, err := service.Activate(ctx, "000000"); err != ErrMfaCodeInvalid {
+		t.Fatalf("expected ErrMfaCodeInvalid for wrong code, got %v", err)
+	}
+
+	// 使用上一时间步的验证码激活，保证后续校验能拿到更新的时间步
+	now := time.Now()
+	code, _ := totp.GenerateCode(enrollment.Secret, now.Add(-totp.Period*time.Second))
+	recoveryCodes, err := service.Activate(ctx, code)
+	if err != nil {
+		t.Fatalf("Activate error: %v", err)
+	}
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(recoveryCodes))
+	}
+	queryOperationLog(t, db, constant.ResourceMfa, int64(user.ID), constant.ActionCreate)
+
+	status, err := service.GetStatus(ctx, user.ID)
+	if err != nil || !status.Enabled || !status.Required || status.RecoveryCodesLeft != recoveryCodeCount {
+		t.Fatalf("expected enabled status, got %+v %v", status, err)
+	}
+
+	// 同一验证码不能重复使用
+	if err := service.verify(ctx, user.ID, &MfaVerifyInput{Code: code}); err != ErrMfaCodeInvalid {
+		t.Fatalf("expected replayed code rejected, got %v", err)
+	}
+
+	token, err := service.CreateChallenge(ctx, &MfaChallenge{UserId: user.ID, Username: user.Username})
+	if err != nil {
+		t.Fatalf("CreateChallenge error: %v", err)
+	}
+	current, _ := totp.GenerateCode(enrollment.Secret, now)
+	challenge, codes, err := service.VerifyChallenge(ctx, token, &MfaVerifyInput{Code: current})
+	if err != nil {
+		t.Fatalf("VerifyChallenge error: %v", err)
+	}
+	if challenge.UserId != user.ID || len(codes) != 0 {
+		t.Fatalf("unexpected verified challenge %+v codes %v", challenge, codes)
+	}
+	if _, err := service.GetChallenge(ctx, token); err != ErrMfaChallengeInvalid {
+		t.Fatalf("expected challenge consumed, got %v", err)
+	}
+
+	// 恢复码只能使用一次
+	if err := service.verify(ctx, user.ID, &MfaVerifyInput{RecoveryCode: recoveryCodes[0]}); err != nil {
+		t.Fatalf("expected recovery code accepted, got %v", err)
+	}
+	if err := service.verify(ctx, user.ID, &MfaVerifyInput{RecoveryCode: recoveryCodes[0]}); err != ErrMfaCodeInvalid {
+		t.Fatalf("expected used recovery code rejected, got %v", err)
+	}
+	status, _ = service.GetStatus(ctx, user.ID)
+	if status.RecoveryCodesLeft != recoveryCodeCount-1 {
+		t.Fatalf("expected %d recovery codes left, got %d", recoveryCodeCount-1, status.RecoveryCodesLeft)
+	}
+
+	if err := service.Disable(ctx, &MfaVerifyInput{RecoveryCode: recoveryCodes[1]}); err != nil {
+		t.Fatalf("Disable error: %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysUserMfa, "user_id = ?", user.ID); count != 0 {
+		t.Fatalf("expected mfa removed, got count %d", count)
+	}
+}
+
+func TestMfaServiceRequiredByRoleIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	role := insertIntegrationRole(t, db, "it_mfa_role", "强制两步验证角色")
+	if err := db.Model(role).Update("require_mfa", constant.RoleRequireMfa).Error; err != nil {
+		t.Fatalf("update role require_mfa: %v", err)
+	}
+	user := insertIntegrationUser(t, db, "mfa_forced", "18100000102", role.ID)
+	ctx := mfaUserCtx(user)
+	service := newIntegrationMfaService(deps)
+
+	status, err := service.GetStatus(ctx, user.ID)
+	if err != nil || status.Enabled || !status.Required || !status.RequiredByRole {
+		t.Fatalf("expected required by role, got %+v %v", status, err)
+	}
+
+	// 未绑定用户在登录挑战内完成绑定与激活
+	token, _ := service.CreateChallenge(context.Background(), &MfaChallenge{UserId: user.ID, Username: user.Username, SetupRequired: true})
+	enrollment, err := service.SetupChallenge(context.Background(), token)
+	if err != nil {
+		t.Fatalf("SetupChallenge error: %v", err)
+	}
+	code, _ := totp.GenerateCode(enrollment.Secret, time.Now())
+	_, recoveryCodes, err := service.VerifyChallenge(context.Background(), token, &MfaVerifyInput{Code: code})
+	if err != nil {
+		t.Fatalf("VerifyChallenge error: %v", err)
+	}
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("expected recovery codes after setup, got %v", recoveryCodes)
+	}
+
+	if err := service.Disable(ctx, &MfaVerifyInput{RecoveryCode: recoveryCodes[0]}); err != ErrMfaRequiredByRole {
+		t.Fatalf("expected ErrMfaRequiredByRole, got %v", err)
+	}
+
+	if err := service.ResetUserMfa(testUserCtx(), user.ID); err != nil {
+		t.Fatalf("ResetUserMfa error: %v", err)
+	}
+	queryOperationLog(t, db, constant.ResourceMfa, int64(user.ID), constant.ActionDelete)
+	status, _ = service.GetStatus(ctx, user.ID)
+	if status.Enabled || !status.Required {
+		t.Fatalf("expected reset user required to enroll again, got %+v", status)
+	}
+}
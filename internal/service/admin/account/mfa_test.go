@@ -0,0 +1,230 @@
+package account
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/pkg/xcryption"
+)
+
+const testMfaEncryptKey = "0123456789abcdef0123456789abcdef"
+
+type fakeMfaRepo struct {
+	mfa            *model.SysUserMfa
+	requiredByRole bool
+	savedSecret    string
+}
+
+func (f *fakeMfaRepo) GetMfaByUserId(context.Context, *query.Query, int32) (*model.SysUserMfa, error) {
+	if f.mfa == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.mfa, nil
+}
+
+func (f *fakeMfaRepo) LockMfaByUserId(context.Context, *query.Query, int32) (*model.SysUserMfa, error) {
+	panic("not implemented")
+}
+
+func (f *fakeMfaRepo) SavePendingMfa(_ context.Context, _ *query.Query, _ int32, secret string) error {
+	f.savedSecret = secret
+	return nil
+}
+
+func (f *fakeMfaRepo) EnableMfa(context.Context, *query.Query, int32, string) error {
+	panic("not implemented")
+}
+
+func (f *fakeMfaRepo) ConsumeStep(context.Context, *query.Query, int32, int64) (bool, error) {
+	panic("not implemented")
+}
+
+func (f *fakeMfaRepo) UpdateRecoveryCodes(context.Context, *query.Query, int32, string) error {
+	panic("not implemented")
+}
+
+func (f *fakeMfaRepo) DeleteMfa(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeMfaRepo) IsMfaRequiredByRole(context.Context, *query.Query, int32) (bool, error) {
+	return f.requiredByRole, nil
+}
+
+func newTestMfaService(mfaRepo *fakeMfaRepo, cache *fakeCache, encryptKey string) *MfaService {
+	return NewMfaService(&repo.Repository{}, mfaRepo, cache, &fakeOperationLogWriter{}, MfaConfig{
+		Issuer:     "snowgo-test",
+		EncryptKey: encryptKey,
+	})
+}
+
+func TestMfaServiceGetStatus(t *testing.T) {
+	ctx := testUserCtx()
+	enabled := constant.MfaStatusEnabled
+	pending := constant.MfaStatusPending
+	recoveryCodes := `["a","b","c"]`
+
+	tests := []struct {
+		name       string
+		repo       *fakeMfaRepo
+		encryptKey string
+		want       MfaStatus
+	}{
+		{"unavailable", &fakeMfaRepo{}, "", MfaStatus{}},
+		{"not enrolled", &fakeMfaRepo{}, testMfaEncryptKey, MfaStatus{}},
+		{"pending", &fakeMfaRepo{mfa: &model.SysUserMfa{Status: &pending}}, testMfaEncryptKey, MfaStatus{}},
+		{"enabled", &fakeMfaRepo{mfa: &model.SysUserMfa{Status: &enabled, RecoveryCodes: &recoveryCodes}}, testMfaEncryptKey,
+			MfaStatus{Enabled: true, Required: true, RecoveryCodesLeft: 3}},
+		{"required by role", &fakeMfaRepo{requiredByRole: true}, testMfaEncryptKey,
+			MfaStatus{Required: true, RequiredByRole: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestMfaService(tt.repo, newFakeCache(), tt.encryptKey)
+			status, err := service.GetStatus(ctx, 2)
+			if err != nil {
+				t.Fatalf("GetStatus error: %v", err)
+			}
+			if *status != tt.want {
+				t.Fatalf("GetStatus = %+v, want %+v", *status, tt.want)
+			}
+		})
+	}
+}
+
+// TestMfaServiceGetStatusUnavailableRequired 未配置加密 key 时，需要两步验证的用户不能被当作无需两步验证
+func TestMfaServiceGetStatusUnavailableRequired(t *testing.T) {
+	ctx := testUserCtx()
+	enabled := constant.MfaStatusEnabled
+	for name, mfaRepo := range map[string]*fakeMfaRepo{
+		"required by role": {requiredByRole: true},
+		"enabled":          {mfa: &model.SysUserMfa{Status: &enabled}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			service := newTestMfaService(mfaRepo, newFakeCache(), "")
+			if _, err := service.GetStatus(ctx, 2); err != ErrMfaUnavailable {
+				t.Fatalf("expected ErrMfaUnavailable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMfaServiceChallenge(t *testing.T) {
+	ctx := testUserCtx()
+	cache := newFakeCache()
+	service := newTestMfaService(&fakeMfaRepo{}, cache, testMfaEncryptKey)
+
+	challenge := &MfaChallenge{UserId: 2, Username: "operator", Device: "Mac", SetupRequired: true}
+	token, err := service.CreateChallenge(ctx, challenge)
+	if err != nil {
+		t.Fatalf("CreateChallenge error: %v", err)
+	}
+	if len(token) != 64 {
+		t.Fatalf("expected 64 char hex token, got %q", token)
+	}
+	if cache.expirations[constant.CacheMfaChallengePrefix+token] != defaultMfaChallengeTTL {
+		t.Fatalf("expected challenge ttl %v, got %v", defaultMfaChallengeTTL, cache.expirations[constant.CacheMfaChallengePrefix+token])
+	}
+
+	got, err := service.GetChallenge(ctx, token)
+	if err != nil {
+		t.Fatalf("GetChallenge error: %v", err)
+	}
+	if got.UserId != 2 || got.Username != "operator" || got.Device != "Mac" || !got.SetupRequired {
+		t.Fatalf("unexpected challenge: %+v", got)
+	}
+
+	if _, err := service.GetChallenge(ctx, "missing"); err != ErrMfaChallengeInvalid {
+		t.Fatalf("expected ErrMfaChallengeInvalid, got %v", err)
+	}
+	if _, err := service.GetChallenge(ctx, ""); err != ErrMfaChallengeInvalid {
+		t.Fatalf("expected ErrMfaChallengeInvalid for empty token, got %v", err)
+	}
+
+	// 已绑定用户的挑战不允许重新绑定
+	token, _ = service.CreateChallenge(ctx, &MfaChallenge{UserId: 2, Username: "operator"})
+	if _, err := service.SetupChallenge(ctx, token); err != ErrMfaAlreadyEnabled {
+		t.Fatalf("expected ErrMfaAlreadyEnabled, got %v", err)
+	}
+}
+
+func TestMfaServiceEnroll(t *testing.T) {
+	ctx := testUserCtx()
+
+	t.Run("unavailable", func(t *testing.T) {
+		service := newTestMfaService(&fakeMfaRepo{}, newFakeCache(), "")
+		if _, err := service.Enroll(ctx); err != ErrMfaUnavailable {
+			t.Fatalf("expected ErrMfaUnavailable, got %v", err)
+		}
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		enabled := constant.MfaStatusEnabled
+		service := newTestMfaService(&fakeMfaRepo{mfa: &model.SysUserMfa{Status: &enabled}}, newFakeCache(), testMfaEncryptKey)
+		if _, err := service.Enroll(ctx); err != ErrMfaAlreadyEnabled {
+			t.Fatalf("expected ErrMfaAlreadyEnabled, got %v", err)
+		}
+	})
+
+	t.Run("save encrypted secret", func(t *testing.T) {
+		mfaRepo := &fakeMfaRepo{}
+		service := newTestMfaService(mfaRepo, newFakeCache(), testMfaEncryptKey)
+		enrollment, err := service.Enroll(ctx)
+		if err != nil {
+			t.Fatalf("Enroll error: %v", err)
+		}
+		if mfaRepo.savedSecret == enrollment.Secret {
+			t.Fatal("expected secret stored encrypted")
+		}
+		secret, err := xcryption.AesGCMDecrypt(mfaRepo.savedSecret, testMfaEncryptKey)
+		if err != nil || secret != enrollment.Secret {
+			t.Fatalf("expected stored secret decrypt to %q, got %q %v", enrollment.Secret, secret, err)
+		}
+		if !strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/snowgo-test:admin?") {
+			t.Fatalf("unexpected provisioning uri: %s", enrollment.ProvisioningURI)
+		}
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashed, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes error: %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("expected %d codes, got %d", recoveryCodeCount, len(codes))
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("unexpected recovery code format: %q", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate recovery code: %q", code)
+		}
+		seen[code] = true
+	}
+
+	hashes := decodeRecoveryCodes(&hashed)
+	if len(hashes) != recoveryCodeCount || hashes[0] != hashRecoveryCode(codes[0]) {
+		t.Fatalf("expected stored hashes match codes, got %v", hashes)
+	}
+	if strings.Contains(hashed, codes[0]) {
+		t.Fatal("expected recovery codes stored hashed")
+	}
+	// 忽略大小写、空白和分隔符
+	normalized := " " + strings.ToUpper(strings.ReplaceAll(codes[0], "-", "")) + " "
+	if hashRecoveryCode(normalized) != hashes[0] {
+		t.Fatal("expected recovery code hash ignore case and separator")
+	}
+	if len(decodeRecoveryCodes(nil)) != 0 {
+		t.Fatal("expected empty hashes for nil recovery codes")
+	}
+}
@@ -0,0 +1,446 @@
+package account
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/oidc"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xcryption"
+	"snowgo/pkg/xdatabase/mysql"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+const (
+	defaultOidcStateTTL      = 10 * time.Minute
+	defaultOidcUsernameClaim = "preferred_username"
+	oidcOperatorName         = "oidc"
+)
+
+var (
+	ErrOidcUnavailable      = e.NewBizError(e.OidcUnavailable)
+	ErrOidcStateInvalid     = e.NewBizError(e.OidcStateInvalid)
+	ErrOidcLogin            = e.NewBizError(e.OidcLoginError)
+	ErrOidcUserNotLinked    = e.NewBizError(e.OidcUserNotLinked)
+	ErrOidcUsernameConflict = e.NewBizError(e.OidcUsernameConflict)
+	ErrOidcUserDisabled     = e.NewBizError(e.OidcUserDisabled)
+)
+
+// OidcRepo 定义OIDC身份关联相关db操作接口
+type OidcRepo interface {
+	GetLinkBySubject(ctx context.Context, issuer, subject string) (*model.SysUserOidc, error)
+	CreateLink(ctx context.Context, q *query.Query, link *model.SysUserOidc) (*model.SysUserOidc, error)
+	UpdateLastLoginAt(ctx context.Context, linkId int32, loginAt time.Time) error
+	GetUsersByEmail(ctx context.Context, email string) ([]*model.SysUser, error)
+}
+
+// OidcProvider OIDC 授权码 + PKCE 客户端，由 pkg/xauth/oidc 实现
+type OidcProvider interface {
+	Issuer() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier string) (*oidc.Token, error)
+	VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*oidc.IDTokenClaims, error)
+}
+
+// OidcConfig OIDC 单点登录配置
+type OidcConfig struct {
+	AutoProvision bool          // 未关联的 IdP 用户首次登录时自动创建系统用户
+	DefaultRoleId int32         // 自动创建用户时绑定的角色，0 表示不绑定
+	LinkByEmail   bool          // 未关联时按已验证邮箱关联唯一的已有用户
+	UsernameClaim string        // 自动创建用户时作为用户名的声明：preferred_username / email / sub
+	StateTTL      time.Duration // 授权请求(state)有效期
+	CookieSecure  bool          // 浏览器绑定 Cookie 仅通过 HTTPS 发送
+}
+
+// OidcService OIDC 授权码 + PKCE 单点登录，外部身份通过关联表映射到 sys_user
+type OidcService struct {
+	db         *repo.Repository
+	oidcDao    OidcRepo
+	userDao    UserRepo
+	cache      xcache.Cache
+	logService contract.OperationLogWriter
+	provider   OidcProvider
+	conf       OidcConfig
+}
+
+func NewOidcService(db *repo.Repository, oidcDao OidcRepo, userDao UserRepo, cache xcache.Cache,
+	logService contract.OperationLogWriter, provider OidcProvider, conf OidcConfig) *OidcService {
+	if conf.StateTTL <= 0 {
+		conf.StateTTL = defaultOidcStateTTL
+	}
+	if conf.UsernameClaim == "" {
+		conf.UsernameClaim = defaultOidcUsernameClaim
+	}
+	return &OidcService{
+		db:         db,
+		oidcDao:    oidcDao,
+		userDao:    userDao,
+		cache:      cache,
+		logService: logService,
+		provider:   provider,
+		conf:       conf,
+	}
+}
+
+// OidcAuthorization 发起登录返回的授权地址
+type OidcAuthorization struct {
+	AuthorizationURL string
+	State            string
+	Binding          string // 浏览器绑定值，写入发起登录浏览器的 HttpOnly Cookie，回调时比对
+	ExpireAt         time.Time
+}
+
+// oidcState 授权请求状态，回调时校验并一次性消费
+type oidcState struct {
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	BindingHash  string    `json:"binding_hash"` // 浏览器绑定值摘要，防止攻击者的授权码在受害者浏览器中完成登录（登录 CSRF）
+	Device       string    `json:"device"`
+	ExpireAt     time.Time `json:"expire_at"`
+}
+
+// OidcLoginResult OIDC 登录成功后关联的系统用户
+type OidcLoginResult struct {
+	UserId      int32
+	Username    string
+	Device      string
+	Provisioned bool // 本次登录是否自动创建了用户
+}
+
+// Available 是否已配置 IdP
+func (s *OidcService) Available() bool {
+	return s.provider != nil
+}
+
+// CookieSecure 浏览器绑定 Cookie 是否仅通过 HTTPS 发送
+func (s *OidcService) CookieSecure() bool {
+	return s.conf.CookieSecure
+}
+
+// Begin 生成 state / nonce / PKCE code_verifier 并返回 IdP 授权地址
+func (s *OidcService) Begin(ctx context.Context, device string) (*OidcAuthorization, error) {
+	if !s.Available() {
+		return nil, ErrOidcUnavailable
+	}
+	state, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	binding, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "生成OIDC授权地址失败: %v", err)
+		return nil, fmt.Errorf("生成OIDC授权地址失败: %w", err)
+	}
+
+	st := &oidcState{
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		BindingHash:  xcryption.Sha256(binding),
+		Device:       device,
+		ExpireAt:     time.Now().Add(s.conf.StateTTL),
+	}
+	raw, err := json.Marshal(st)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC登录状态序列化失败: %w", err)
+	}
+	if err := s.cache.Set(ctx, constant.CacheOidcStatePrefix+state, string(raw), s.conf.StateTTL); err != nil {
+		xlogger.ErrorfCtx(ctx, "保存OIDC登录状态失败: %v", err)
+		return nil, fmt.Errorf("保存OIDC登录状态失败: %w", err)
+	}
+	return &OidcAuthorization{AuthorizationURL: authURL, State: state, Binding: binding, ExpireAt: st.ExpireAt}, nil
+}
+
+// Callback 校验 state 及其浏览器绑定，使用授权码换取并校验 ID Token，返回关联（或自动创建）的系统用户
+// binding 为回调请求携带的浏览器绑定 Cookie，与发起登录时不一致说明回调不是由发起登录的浏览器完成
+func (s *OidcService) Callback(ctx context.Context, code, state, binding string) (*OidcLoginResult, error) {
+	if !s.Available() {
+		return nil, ErrOidcUnavailable
+	}
+	st, err := s.consumeState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if binding == "" || subtle.ConstantTimeCompare([]byte(xcryption.Sha256(binding)), []byte(st.BindingHash)) != 1 {
+		xlogger.ErrorfCtx(ctx, "OIDC回调浏览器绑定校验失败")
+		return nil, ErrOidcStateInvalid
+	}
+	if code == "" {
+		return nil, ErrOidcLogin
+	}
+
+	token, err := s.provider.Exchange(ctx, code, st.CodeVerifier)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "OIDC授权码换取token失败: %v", err)
+		return nil, e.WrapBizError(e.OidcLoginError, err)
+	}
+	claims, err := s.provider.VerifyIDToken(ctx, token.IDToken, st.Nonce)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "OIDC id token校验失败: %v", err)
+		return nil, e.WrapBizError(e.OidcLoginError, err)
+	}
+
+	user, linkId, provisioned, err := s.resolveUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if common.DerefOrZero(user.Status) == constant.UserStatusDisabled || isServiceAccount(user) {
+		return nil, ErrOidcUserDisabled
+	}
+	if err := s.oidcDao.UpdateLastLoginAt(ctx, linkId, time.Now()); err != nil {
+		xlogger.ErrorfCtx(ctx, "更新OIDC关联(%d)最后登录时间失败: %v", linkId, err)
+	}
+	return &OidcLoginResult{UserId: user.ID, Username: user.Username, Device: st.Device, Provisioned: provisioned}, nil
+}
+
+// consumeState 读取并删除授权请求状态，同一 state 只能使用一次
+func (s *OidcService) consumeState(ctx context.Context, state string) (*oidcState, error) {
+	if state == "" {
+		return nil, ErrOidcStateInvalid
+	}
+	key := constant.CacheOidcStatePrefix + state
+	raw, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询OIDC登录状态失败: %v", err)
+		return nil, fmt.Errorf("查询OIDC登录状态失败: %w", err)
+	}
+	if !ok {
+		return nil, ErrOidcStateInvalid
+	}
+	// 并发回调以删除成功者为准
+	if deleted, err := s.cache.Delete(ctx, key); err != nil || deleted == 0 {
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "删除OIDC登录状态失败: %v", err)
+		}
+		return nil, ErrOidcStateInvalid
+	}
+	var st oidcState
+	if err := json.Unmarshal([]byte(raw), &st); err != nil || time.Now().After(st.ExpireAt) {
+		return nil, ErrOidcStateInvalid
+	}
+	return &st, nil
+}
+
+// resolveUser 按关联表查找用户；未关联时依次尝试按已验证邮箱关联、自动创建
+func (s *OidcService) resolveUser(ctx context.Context, claims *oidc.IDTokenClaims) (*model.SysUser, int32, bool, error) {
+	issuer := s.provider.Issuer()
+	link, err := s.oidcDao.GetLinkBySubject(ctx, issuer, claims.Subject)
+	if err == nil {
+		user, err := s.userDao.GetUserById(ctx, link.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, false, ErrOidcUserNotLinked
+			}
+			xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", link.UserID, err)
+			return nil, 0, false, fmt.Errorf("用户信息查询失败: %w", err)
+		}
+		return user, link.ID, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		xlogger.ErrorfCtx(ctx, "查询OIDC关联异常 sub=%s: %v", claims.Subject, err)
+		return nil, 0, false, fmt.Errorf("OIDC关联查询失败: %w", err)
+	}
+
+	if s.conf.LinkByEmail && claims.EmailVerified && claims.Email != "" {
+		users, err := s.oidcDao.GetUsersByEmail(ctx, claims.Email)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "按邮箱查询用户异常: %v", err)
+			return nil, 0, false, fmt.Errorf("按邮箱查询用户失败: %w", err)
+		}
+		// 邮箱不唯一时无法确定关联对象，交给管理员处理
+		if len(users) == 1 {
+			linkId, err := s.linkUser(ctx, users[0], claims)
+			if err != nil {
+				return nil, 0, false, err
+			}
+			return users[0], linkId, false, nil
+		}
+	}
+
+	if !s.conf.AutoProvision {
+		return nil, 0, false, ErrOidcUserNotLinked
+	}
+	user, linkId, err := s.provisionUser(ctx, claims)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return user, linkId, true, nil
+}
+
+// linkUser 将 IdP 身份关联到已有用户
+func (s *OidcService) linkUser(ctx context.Context, user *model.SysUser, claims *oidc.IDTokenClaims) (int32, error) {
+	var link *model.SysUserOidc
+	err := s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		var err error
+		link, err = s.createLink(ctx, tx, user.ID, claims)
+		if err != nil {
+			return err
+		}
+		return s.writeLog(ctx, tx, user, constant.ActionUpdate, nil, link,
+			fmt.Sprintf("OIDC身份(%s)按邮箱%s关联到用户(%d-%s)", claims.Subject, claims.Email, user.ID, user.Username))
+	})
+	if err != nil {
+		return 0, err
+	}
+	xlogger.InfofCtx(ctx, "OIDC身份关联成功 user_id=%d sub=%s", user.ID, claims.Subject)
+	return link.ID, nil
+}
+
+// provisionUser 自动创建用户、绑定默认角色并关联 IdP 身份
+func (s *OidcService) provisionUser(ctx context.Context, claims *oidc.IDTokenClaims) (*model.SysUser, int32, error) {
+	username := s.usernameFromClaims(claims)
+	if username == "" || len(username) > 64 {
+		xlogger.ErrorfCtx(ctx, "OIDC声明%s无法作为用户名 sub=%s", s.conf.UsernameClaim, claims.Subject)
+		return nil, 0, ErrOidcLogin
+	}
+	if _, err := s.userDao.GetUserByUsername(ctx, s.db.WriteQuery(), username); err == nil {
+		// 不按用户名自动关联已有用户，避免 IdP 侧可修改的用户名被用于接管账号
+		return nil, 0, ErrOidcUsernameConflict
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		xlogger.ErrorfCtx(ctx, "获取用户(%s)信息异常: %v", username, err)
+		return nil, 0, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+
+	// 单点登录用户不使用本地密码，写入随机密码占位
+	password, err := randomPassword()
+	if err != nil {
+		return nil, 0, err
+	}
+	pwd, err := xcryption.HashPassword(password)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "密码加密异常: %v", err)
+		return nil, 0, fmt.Errorf("密码加密异常: %w", err)
+	}
+	activeStatus := constant.UserStatusActive
+	userType := constant.UserTypeNormal
+	var userObj *model.SysUser
+	var link *model.SysUserOidc
+	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		userObj, err = s.userDao.CreateUser(ctx, tx, &model.SysUser{
+			Username: username,
+			Password: pwd,
+			Nickname: common.PtrIfNonZero(claims.Name),
+			Email:    common.PtrIfNonZero(claims.Email),
+			Remark:   common.PtrIfNonZero("OIDC自动创建"),
+			Status:   &activeStatus,
+			UserType: &userType,
+		})
+		if err != nil {
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrOidcUsernameConflict
+			}
+			xlogger.ErrorfCtx(ctx, "OIDC用户创建失败 username=%s err: %v", username, err)
+			return fmt.Errorf("用户创建失败: %w", err)
+		}
+
+		if s.conf.DefaultRoleId > 0 {
+			roleLen, err := s.userDao.CountRoleByIds(ctx, tx, []int32{s.conf.DefaultRoleId})
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "查询角色数量存在异常: %v", err)
+				return fmt.Errorf("查询角色数量存在异常: %w", err)
+			}
+			// 默认角色被删除时仍创建用户，只记录日志，由管理员后续授权
+			if roleLen == 1 {
+				err = s.userDao.CreateUserRoleInBatches(ctx, tx, []*model.SysUserRole{{UserID: userObj.ID, RoleID: s.conf.DefaultRoleId}})
+				if err != nil {
+					xlogger.ErrorfCtx(ctx, "用户与角色关联关系创建失败 user_id=%d err: %v", userObj.ID, err)
+					return fmt.Errorf("用户与角色关联关系创建失败: %w", err)
+				}
+			} else {
+				xlogger.ErrorfCtx(ctx, "OIDC默认角色(%d)不存在，用户(%d)未绑定角色", s.conf.DefaultRoleId, userObj.ID)
+			}
+		}
+
+		link, err = s.createLink(ctx, tx, userObj.ID, claims)
+		if err != nil {
+			return err
+		}
+		return s.writeLog(ctx, tx, userObj, constant.ActionCreate, nil, userObj,
+			fmt.Sprintf("OIDC身份(%s)首次登录，自动创建了用户(%d-%s)", claims.Subject, userObj.ID, userObj.Username))
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	xlogger.InfofCtx(ctx, "OIDC用户自动创建成功 user_id=%d username=%s", userObj.ID, userObj.Username)
+	return userObj, link.ID, nil
+}
+
+func (s *OidcService) createLink(ctx context.Context, tx *query.Query, userId int32, claims *oidc.IDTokenClaims) (*model.SysUserOidc, error) {
+	link, err := s.oidcDao.CreateLink(ctx, tx, &model.SysUserOidc{
+		UserID:  userId,
+		Issuer:  s.provider.Issuer(),
+		Subject: claims.Subject,
+		Email:   common.PtrIfNonZero(claims.Email),
+	})
+	if err != nil {
+		// 并发首次登录时唯一索引兜底，让客户端重新发起登录
+		if mysql.IsDuplicateKeyErr(err) {
+			return nil, ErrOidcStateInvalid
+		}
+		xlogger.ErrorfCtx(ctx, "OIDC身份关联创建失败 user_id=%d err: %v", userId, err)
+		return nil, fmt.Errorf("OIDC身份关联创建失败: %w", err)
+	}
+	return link, nil
+}
+
+// usernameFromClaims 按配置的声明取用户名
+func (s *OidcService) usernameFromClaims(claims *oidc.IDTokenClaims) string {
+	switch s.conf.UsernameClaim {
+	case "email":
+		return strings.TrimSpace(claims.Email)
+	case "sub":
+		return claims.Subject
+	default:
+		return strings.TrimSpace(claims.PreferredUsername)
+	}
+}
+
+// writeLog 系统行为记录操作日志，操作人记为登录的用户本身
+func (s *OidcService) writeLog(ctx context.Context, tx *query.Query, user *model.SysUser,
+	action string, before, after any, description string) error {
+	traceCtx := xauth.GetContext(ctx)
+	err := s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+		OperatorID:   user.ID,
+		OperatorName: oidcOperatorName,
+		OperatorType: constant.OperatorSystem,
+		Resource:     constant.ResourceUser,
+		ResourceID:   int64(user.ID),
+		TraceID:      traceCtx.TraceId,
+		Action:       action,
+		BeforeData:   before,
+		AfterData:    after,
+		Description:  description,
+		IP:           traceCtx.IP,
+	})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "操作日志创建失败 resource=user resource_id=%d err: %v", user.ID, err)
+		return fmt.Errorf("操作日志创建失败: %w", err)
+	}
+	return nil
+}
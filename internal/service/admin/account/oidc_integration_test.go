@@ -0,0 +1,131 @@
+//go:build integration
+
+package account
+
+import (
+	"context"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth/oidc"
+	"snowgo/pkg/xauth/oidc/oidctest"
+)
+
+func newIntegrationOidcProvider(t *testing.T) (*oidc.Provider, *oidctest.IdP) {
+	t.Helper()
+	idp, err := oidctest.NewIdP("snowgo-admin", "client-secret")
+	if err != nil {
+		t.Fatalf("start idp: %v", err)
+	}
+	t.Cleanup(idp.Close)
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "snowgo-admin",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://admin.example.com/oidc/callback",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+	return provider, idp
+}
+
+func integrationOidcLogin(t *testing.T, service *OidcService, idp *oidctest.IdP, identity oidctest.Identity) (*OidcLoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	authorization, err := service.Begin(ctx, "Chrome")
+	if err != nil {
+		t.Fatalf("Begin error: %v", err)
+	}
+	code, state, err := idp.Authorize(authorization.AuthorizationURL, identity)
+	if err != nil {
+		t.Fatalf("Authorize error: %v", err)
+	}
+	return service.Callback(ctx, code, state, authorization.Binding)
+}
+
+func TestOidcAutoProvisionIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	role := insertIntegrationRole(t, db, "oidc_default", "OIDC默认角色")
+	provider, idp := newIntegrationOidcProvider(t)
+	service := newIntegrationOidcService(deps, provider, OidcConfig{AutoProvision: true, DefaultRoleId: role.ID})
+	identity := oidctest.Identity{Subject: "sub-alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice", PreferredUsername: "alice"}
+
+	result, err := integrationOidcLogin(t, service, idp, identity)
+	if err != nil {
+		t.Fatalf("Callback error: %v", err)
+	}
+	if !result.Provisioned || result.Username != "alice" || result.Device != "Chrome" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if countRows(t, db, model.TableNameSysUserRole, "user_id = ? AND role_id = ?", result.UserId, role.ID) != 1 {
+		t.Fatal("expected default role to be bound")
+	}
+	if countRows(t, db, model.TableNameSysUserOidc, "user_id = ? AND issuer = ? AND subject = ?", result.UserId, idp.Issuer(), "sub-alice") != 1 {
+		t.Fatal("expected oidc link to be created")
+	}
+	log := queryOperationLog(t, db, constant.ResourceUser, int64(result.UserId), constant.ActionCreate)
+	if log.OperatorType == nil || *log.OperatorType != constant.OperatorSystem {
+		t.Fatalf("operator type = %v, want %s", log.OperatorType, constant.OperatorSystem)
+	}
+
+	// 再次登录复用关联，不重复创建
+	again, err := integrationOidcLogin(t, service, idp, identity)
+	if err != nil {
+		t.Fatalf("second Callback error: %v", err)
+	}
+	if again.Provisioned || again.UserId != result.UserId {
+		t.Fatalf("expected existing link to be reused, got %+v", again)
+	}
+	if countRows(t, db, model.TableNameSysUser, "username = ?", "alice") != 1 {
+		t.Fatal("expected exactly one provisioned user")
+	}
+
+	// 其他 IdP 身份使用相同用户名不得接管已有账号
+	if _, err := integrationOidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-mallory", PreferredUsername: "alice"}); err != ErrOidcUsernameConflict {
+		t.Fatalf("expected ErrOidcUsernameConflict, got %v", err)
+	}
+
+	// 删除用户时一并删除关联
+	operator := insertIntegrationUser(t, db, "oidc_operator", "18100000301")
+	if err := newIntegrationUserService(deps).DeleteById(mfaUserCtx(operator), result.UserId); err != nil {
+		t.Fatalf("DeleteById error: %v", err)
+	}
+	if countRows(t, db, model.TableNameSysUserOidc, "user_id = ?", result.UserId) != 0 {
+		t.Fatal("expected oidc links to be deleted with the user")
+	}
+}
+
+func TestOidcLinkByEmailIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	user := insertIntegrationUser(t, db, "bob", "18100000302")
+	if err := db.Model(user).Update("email", "bob@example.com").Error; err != nil {
+		t.Fatalf("update email: %v", err)
+	}
+	provider, idp := newIntegrationOidcProvider(t)
+	service := newIntegrationOidcService(deps, provider, OidcConfig{LinkByEmail: true})
+
+	// 未验证的邮箱不能用于关联
+	if _, err := integrationOidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-bob", Email: "bob@example.com"}); err != ErrOidcUserNotLinked {
+		t.Fatalf("expected ErrOidcUserNotLinked for unverified email, got %v", err)
+	}
+
+	result, err := integrationOidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-bob", Email: "bob@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("Callback error: %v", err)
+	}
+	if result.UserId != user.ID || result.Provisioned {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if countRows(t, db, model.TableNameSysUserOidc, "user_id = ? AND subject = ?", user.ID, "sub-bob") != 1 {
+		t.Fatal("expected oidc link to be created")
+	}
+	queryOperationLog(t, db, constant.ResourceUser, int64(user.ID), constant.ActionUpdate)
+}
@@ -0,0 +1,191 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/pkg/xauth/oidc"
+	"snowgo/pkg/xauth/oidc/oidctest"
+	e "snowgo/pkg/xerror"
+)
+
+type fakeOidcRepo struct {
+	links     map[string]*model.SysUserOidc
+	lastLogin map[int32]time.Time
+}
+
+func (f *fakeOidcRepo) GetLinkBySubject(_ context.Context, issuer, subject string) (*model.SysUserOidc, error) {
+	link, ok := f.links[issuer+"|"+subject]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return link, nil
+}
+
+func (f *fakeOidcRepo) CreateLink(context.Context, *query.Query, *model.SysUserOidc) (*model.SysUserOidc, error) {
+	panic("not implemented")
+}
+
+func (f *fakeOidcRepo) UpdateLastLoginAt(_ context.Context, linkId int32, loginAt time.Time) error {
+	f.lastLogin[linkId] = loginAt
+	return nil
+}
+
+func (f *fakeOidcRepo) GetUsersByEmail(context.Context, string) ([]*model.SysUser, error) {
+	panic("not implemented")
+}
+
+func newTestOidcService(t *testing.T, userRepo *fakeUserRepo, conf OidcConfig) (*OidcService, *oidctest.IdP, *fakeOidcRepo, *fakeCache) {
+	t.Helper()
+	idp, err := oidctest.NewIdP("snowgo-admin", "client-secret")
+	if err != nil {
+		t.Fatalf("start idp: %v", err)
+	}
+	t.Cleanup(idp.Close)
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "snowgo-admin",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://admin.example.com/oidc/callback",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+	oidcRepo := &fakeOidcRepo{links: map[string]*model.SysUserOidc{}, lastLogin: map[int32]time.Time{}}
+	cache := newFakeCache()
+	service := NewOidcService(&repo.Repository{}, oidcRepo, userRepo, cache, &fakeOperationLogWriter{}, provider, conf)
+	return service, idp, oidcRepo, cache
+}
+
+// oidcLogin 发起登录并在 IdP 完成授权，返回回调参数与浏览器绑定值
+func oidcLogin(t *testing.T, service *OidcService, idp *oidctest.IdP, identity oidctest.Identity) (string, string, string) {
+	t.Helper()
+	authorization, err := service.Begin(context.Background(), "Chrome")
+	if err != nil {
+		t.Fatalf("Begin error: %v", err)
+	}
+	code, state, err := idp.Authorize(authorization.AuthorizationURL, identity)
+	if err != nil {
+		t.Fatalf("Authorize error: %v", err)
+	}
+	return code, state, authorization.Binding
+}
+
+func TestOidcCallback_LinkedUser(t *testing.T) {
+	status := constant.UserStatusActive
+	user := &model.SysUser{ID: 7, Username: "alice", Status: &status}
+	service, idp, oidcRepo, _ := newTestOidcService(t, &fakeUserRepo{userById: user}, OidcConfig{})
+	oidcRepo.links[idp.Issuer()+"|sub-alice"] = &model.SysUserOidc{ID: 3, UserID: 7, Issuer: idp.Issuer(), Subject: "sub-alice"}
+
+	code, state, binding := oidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-alice"})
+	result, err := service.Callback(context.Background(), code, state, binding)
+	if err != nil {
+		t.Fatalf("Callback error: %v", err)
+	}
+	if result.UserId != 7 || result.Username != "alice" || result.Device != "Chrome" || result.Provisioned {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, ok := oidcRepo.lastLogin[3]; !ok {
+		t.Fatal("expected last_login_at to be updated")
+	}
+
+	// state 只能使用一次
+	if _, err := service.Callback(context.Background(), code, state, binding); !errors.Is(err, ErrOidcStateInvalid) {
+		t.Fatalf("expected ErrOidcStateInvalid on replay, got %v", err)
+	}
+}
+
+func TestOidcCallback_Rejects(t *testing.T) {
+	disabled := constant.UserStatusDisabled
+	active := constant.UserStatusActive
+	serviceType := constant.UserTypeService
+
+	tests := []struct {
+		name     string
+		user     *model.SysUser
+		linked   bool
+		conf     OidcConfig
+		userRepo *fakeUserRepo
+		wantErr  error
+	}{
+		{"not linked", nil, false, OidcConfig{}, &fakeUserRepo{}, ErrOidcUserNotLinked},
+		{"disabled user", &model.SysUser{ID: 7, Status: &disabled}, true, OidcConfig{}, nil, ErrOidcUserDisabled},
+		{"service account", &model.SysUser{ID: 7, Status: &active, UserType: &serviceType}, true, OidcConfig{}, nil, ErrOidcUserDisabled},
+		{"linked user deleted", nil, true, OidcConfig{}, &fakeUserRepo{}, ErrOidcUserNotLinked},
+		{"provision username conflict", nil, false, OidcConfig{AutoProvision: true},
+			&fakeUserRepo{userByUsername: &model.SysUser{ID: 8, Username: "alice"}}, ErrOidcUsernameConflict},
+		{"provision without username claim", nil, false, OidcConfig{AutoProvision: true, UsernameClaim: "email"}, &fakeUserRepo{}, ErrOidcLogin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := tt.userRepo
+			if userRepo == nil {
+				userRepo = &fakeUserRepo{userById: tt.user}
+			}
+			service, idp, oidcRepo, _ := newTestOidcService(t, userRepo, tt.conf)
+			if tt.linked {
+				oidcRepo.links[idp.Issuer()+"|sub-alice"] = &model.SysUserOidc{ID: 3, UserID: 7}
+			}
+			code, state, binding := oidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-alice", PreferredUsername: "alice"})
+			if _, err := service.Callback(context.Background(), code, state, binding); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOidcCallback_InvalidState(t *testing.T) {
+	service, idp, _, cache := newTestOidcService(t, &fakeUserRepo{}, OidcConfig{})
+	code, state, binding := oidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-alice"})
+
+	if _, err := service.Callback(context.Background(), code, "forged-state", binding); !errors.Is(err, ErrOidcStateInvalid) {
+		t.Fatalf("expected ErrOidcStateInvalid for unknown state, got %v", err)
+	}
+	if got := cache.expirations[constant.CacheOidcStatePrefix+state]; got != defaultOidcStateTTL {
+		t.Fatalf("state ttl = %s, want %s", got, defaultOidcStateTTL)
+	}
+}
+
+// TestOidcCallback_BrowserBinding 攻击者发起登录拿到的授权码不能在没有其绑定 Cookie 的浏览器中完成登录
+func TestOidcCallback_BrowserBinding(t *testing.T) {
+	status := constant.UserStatusActive
+	user := &model.SysUser{ID: 7, Username: "alice", Status: &status}
+	for _, binding := range []string{"", "other-browser"} {
+		service, idp, oidcRepo, _ := newTestOidcService(t, &fakeUserRepo{userById: user}, OidcConfig{})
+		oidcRepo.links[idp.Issuer()+"|sub-alice"] = &model.SysUserOidc{ID: 3, UserID: 7}
+		code, state, _ := oidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-alice"})
+
+		if _, err := service.Callback(context.Background(), code, state, binding); !errors.Is(err, ErrOidcStateInvalid) {
+			t.Fatalf("binding %q: expected ErrOidcStateInvalid, got %v", binding, err)
+		}
+	}
+}
+
+func TestOidcCallback_CodeExchangeFailure(t *testing.T) {
+	service, idp, _, _ := newTestOidcService(t, &fakeUserRepo{}, OidcConfig{})
+	_, state, binding := oidcLogin(t, service, idp, oidctest.Identity{Subject: "sub-alice"})
+
+	_, err := service.Callback(context.Background(), "forged-code", state, binding)
+	var bizErr *e.BizError
+	if !errors.As(err, &bizErr) || bizErr.Code != e.OidcLoginError {
+		t.Fatalf("expected OidcLoginError, got %v", err)
+	}
+}
+
+func TestOidcService_Unavailable(t *testing.T) {
+	service := NewOidcService(&repo.Repository{}, &fakeOidcRepo{}, &fakeUserRepo{}, newFakeCache(), &fakeOperationLogWriter{}, nil, OidcConfig{})
+	if _, err := service.Begin(context.Background(), ""); !errors.Is(err, ErrOidcUnavailable) {
+		t.Fatalf("expected ErrOidcUnavailable, got %v", err)
+	}
+	if _, err := service.Callback(context.Background(), "code", "state", "binding"); !errors.Is(err, ErrOidcUnavailable) {
+		t.Fatalf("expected ErrOidcUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package account
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcryption"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+const (
+	defaultPwdChangeTTL = 10 * time.Minute
+
+	PwdChangeReasonRequired = "required" // 首次登录或被管理员重置后必须修改
+	PwdChangeReasonExpired  = "expired"  // 超过密码最长使用期限
+)
+
+var (
+	ErrPwdReused        = e.NewBizError(e.PwdReusedError)
+	ErrPwdBanned        = e.NewBizError(e.PwdBannedError)
+	ErrPwdChangeInvalid = e.NewBizError(e.PwdChangeInvalid)
+)
+
+// PasswordPolicy 密码生命周期策略，零值表示只校验长度与字符类型
+type PasswordPolicy struct {
+	HistoryCount            int                 // 不允许重复使用最近 N 次的密码，0 表示不校验历史密码
+	MaxAge                  time.Duration       // 密码最长使用期限，超过后登录需修改密码，0 表示不过期
+	ForceChangeOnFirstLogin bool                // 管理员创建的用户首次登录必须修改密码
+	ForceChangeAfterReset   bool                // 管理员重置密码后下次登录必须修改密码
+	ChangeTTL               time.Duration       // 强制修改密码凭证有效期
+	BannedPasswords         map[string]struct{} // 禁用的常见密码（小写）
+}
+
+// LoadBannedPasswords 读取禁用密码列表文件，每行一个，忽略空行与 # 开头的注释，比较时不区分大小写
+func LoadBannedPasswords(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取禁用密码列表失败: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	banned := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		banned[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取禁用密码列表失败: %w", err)
+	}
+	return banned, nil
+}
+
+// Validate 校验密码强度，并拒绝禁用列表中的常见密码
+func (p PasswordPolicy) Validate(pw string) *e.BizError {
+	if bizErr := validatePassword(pw); bizErr != nil {
+		return bizErr
+	}
+	if _, ok := p.BannedPasswords[strings.ToLower(pw)]; ok {
+		return ErrPwdBanned
+	}
+	return nil
+}
+
+// ChangeReason 判断用户登录时是否需要修改密码，返回空字符串表示无需修改
+func (p PasswordPolicy) ChangeReason(user *model.SysUser, now time.Time) string {
+	if common.DerefOrZero(user.PwdMustChange) == constant.PwdMustChangeYes {
+		return PwdChangeReasonRequired
+	}
+	if p.MaxAge <= 0 {
+		return ""
+	}
+	// 未记录修改时间的存量用户按创建时间计算
+	changedAt := user.PwdChangedAt
+	if changedAt == nil {
+		changedAt = user.CreatedAt
+	}
+	if changedAt != nil && now.Sub(*changedAt) > p.MaxAge {
+		return PwdChangeReasonExpired
+	}
+	return ""
+}
+
+// PwdChangeTicket 强制修改密码凭证，密码校验通过但需要修改密码时签发，只能用于修改密码
+type PwdChangeTicket struct {
+	UserId   int32     `json:"user_id"`
+	Username string    `json:"username"`
+	Reason   string    `json:"reason"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+// GetPwdChangeReason 查询用户登录时是否需要修改密码，返回空字符串表示无需修改
+func (u *UserService) GetPwdChangeReason(ctx context.Context, userId int32) (string, error) {
+	user, err := u.userDao.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userId, err)
+		return "", fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	return u.pwdPolicy.ChangeReason(user, time.Now()), nil
+}
+
+// CreatePwdChangeTicket 签发强制修改密码凭证，返回凭证令牌
+func (u *UserService) CreatePwdChangeTicket(ctx context.Context, ticket *PwdChangeTicket) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成修改密码凭证失败: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	ttl := u.pwdPolicy.ChangeTTL
+	if ttl <= 0 {
+		ttl = defaultPwdChangeTTL
+	}
+	ticket.ExpireAt = time.Now().Add(ttl)
+	raw, err := json.Marshal(ticket)
+	if err != nil {
+		return "", fmt.Errorf("修改密码凭证序列化失败: %w", err)
+	}
+	if err := u.cache.Set(ctx, constant.CachePwdChangePrefix+token, string(raw), ttl); err != nil {
+		xlogger.ErrorfCtx(ctx, "保存修改密码凭证失败: %v", err)
+		return "", fmt.Errorf("保存修改密码凭证失败: %w", err)
+	}
+	return token, nil
+}
+
+// ChangePwdByTicket 使用强制修改密码凭证设置新密码，成功后凭证作废，用户需使用新密码重新登录
+func (u *UserService) ChangePwdByTicket(ctx context.Context, token, password string) (*PwdChangeTicket, error) {
+	if token == "" {
+		return nil, ErrPwdChangeInvalid
+	}
+	key := constant.CachePwdChangePrefix + token
+	raw, ok, err := u.cache.Get(ctx, key)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询修改密码凭证失败: %v", err)
+		return nil, fmt.Errorf("查询修改密码凭证失败: %w", err)
+	}
+	if !ok {
+		return nil, ErrPwdChangeInvalid
+	}
+	var ticket PwdChangeTicket
+	if err := json.Unmarshal([]byte(raw), &ticket); err != nil || time.Now().After(ticket.ExpireAt) {
+		return nil, ErrPwdChangeInvalid
+	}
+	// 新密码不符合策略时保留凭证，允许重新输入
+	if bizErr := u.pwdPolicy.Validate(password); bizErr != nil {
+		return nil, bizErr
+	}
+
+	user, err := u.userDao.GetUserByUsername(ctx, u.db.WriteQuery(), ticket.Username)
+	if err != nil || user.ID != ticket.UserId {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPwdChangeInvalid
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%s)信息异常: %v", ticket.Username, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	if common.DerefOrZero(user.Status) == constant.UserStatusDisabled || isServiceAccount(user) {
+		return nil, ErrPwdChangeInvalid
+	}
+	// 强制修改时新密码不能与当前密码相同
+	if xcryption.CheckPassword(user.Password, password) {
+		return nil, ErrPwdReused
+	}
+	if err := u.checkPwdHistory(ctx, u.db.WriteQuery(), user.ID, password); err != nil {
+		return nil, err
+	}
+	pwd, err := xcryption.HashPassword(password)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "密码加密异常: %v", err)
+		return nil, fmt.Errorf("密码加密异常: %w", err)
+	}
+
+	// 并发提交以删除成功者为准
+	if deleted, err := u.cache.Delete(ctx, key); err != nil || deleted == 0 {
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "删除修改密码凭证失败: %v", err)
+		}
+		return nil, ErrPwdChangeInvalid
+	}
+
+	traceCtx := xauth.GetContext(ctx)
+	desc := fmt.Sprintf("用户(%d-%s)登录时修改了密码(%s)", user.ID, user.Username, ticket.Reason)
+	if err := u.changeOwnPwd(ctx, user, pwd, traceCtx, desc); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// changeOwnPwd 用户修改自己的密码：更新密码并清除强制修改标记、记录历史密码与操作日志，成功后吊销该用户已签发的token
+func (u *UserService) changeOwnPwd(ctx context.Context, user *model.SysUser, pwd string, traceCtx *xauth.Context, description string) error {
+	err := u.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := u.userDao.ResetPwdById(ctx, tx, user.ID, pwd, constant.PwdMustChangeNo); err != nil {
+			return fmt.Errorf("修改密码失败: %w", err)
+		}
+		if err := u.savePwdHistory(ctx, tx, user.ID, pwd); err != nil {
+			return err
+		}
+		err := u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   user.ID,
+			OperatorName: user.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceUser,
+			ResourceID:   int64(user.ID),
+			TraceID:      traceCtx.TraceId,
+			Action:       constant.ActionUpdate,
+			Description:  description,
+			IP:           traceCtx.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v", err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	xlogger.InfofCtx(ctx, "用户(%d-%s)修改密码成功", user.ID, user.Username)
+	u.revokeUserTokens(ctx, user.ID)
+	return nil
+}
+
+// checkPwdHistory 新密码不能与最近使用过的密码相同
+func (u *UserService) checkPwdHistory(ctx context.Context, q *query.Query, userId int32, password string) error {
+	keep := u.pwdPolicy.HistoryCount
+	if keep <= 0 {
+		return nil
+	}
+	histories, err := u.userDao.GetPwdHistory(ctx, q, userId, keep)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询用户(%d)历史密码异常: %v", userId, err)
+		return fmt.Errorf("查询历史密码失败: %w", err)
+	}
+	for _, h := range histories {
+		if xcryption.CheckPassword(h.Password, password) {
+			return ErrPwdReused
+		}
+	}
+	return nil
+}
+
+// savePwdHistory 记录新密码到历史密码，未开启历史校验时不记录
+func (u *UserService) savePwdHistory(ctx context.Context, tx *query.Query, userId int32, pwd string) error {
+	keep := u.pwdPolicy.HistoryCount
+	if keep <= 0 {
+		return nil
+	}
+	if err := u.userDao.CreatePwdHistory(ctx, tx, &model.SysUserPwdHistory{UserID: userId, Password: pwd}, keep); err != nil {
+		xlogger.ErrorfCtx(ctx, "记录用户(%d)历史密码失败: %v", userId, err)
+		return fmt.Errorf("记录历史密码失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	daoAccount "snowgo/internal/dao/admin/account"
+	common "snowgo/pkg"
+	"snowgo/pkg/xcryption"
+)
+
+func TestUserServicePasswordLifecycleIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	user := insertIntegrationUser(t, db, "pwd_operator", "18100000201")
+	policy := PasswordPolicy{HistoryCount: 2, ForceChangeAfterReset: true}
+	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, newIntegrationRoleService(deps),
+		newIntegrationDeptService(deps), newIntegrationRevocationService(deps), newIntegrationOperationLogService(deps), UserServiceOptions{PwdPolicy: policy})
+
+	// 管理员重置后下次登录必须修改密码
+	if err := service.ResetPwdById(testUserCtx(), user.ID, "reset123"); err != nil {
+		t.Fatalf("ResetPwdById error: %v", err)
+	}
+	reason, err := service.GetPwdChangeReason(context.Background(), user.ID)
+	if err != nil || reason != PwdChangeReasonRequired {
+		t.Fatalf("expected change required after reset, got %q %v", reason, err)
+	}
+	if err := service.ResetPwdById(testUserCtx(), user.ID, "reset123"); !errors.Is(err, ErrPwdReused) {
+		t.Fatalf("expected ErrPwdReused for recent password, got %v", err)
+	}
+
+	token, err := service.CreatePwdChangeTicket(context.Background(), &PwdChangeTicket{
+		UserId: user.ID, Username: user.Username, Reason: reason,
+	})
+	if err != nil {
+		t.Fatalf("CreatePwdChangeTicket error: %v", err)
+	}
+	if _, err := service.ChangePwdByTicket(context.Background(), token, "reset123"); !errors.Is(err, ErrPwdReused) {
+		t.Fatalf("expected ErrPwdReused for current password, got %v", err)
+	}
+	if _, err := service.ChangePwdByTicket(context.Background(), token, "change123"); err != nil {
+		t.Fatalf("ChangePwdByTicket error: %v", err)
+	}
+	if _, err := service.ChangePwdByTicket(context.Background(), token, "other123"); !errors.Is(err, ErrPwdChangeInvalid) {
+		t.Fatalf("expected ticket to be single-use, got %v", err)
+	}
+
+	var updated model.SysUser
+	if err := db.Where("id = ?", user.ID).First(&updated).Error; err != nil {
+		t.Fatalf("query updated user: %v", err)
+	}
+	if !xcryption.CheckPassword(updated.Password, "change123") || common.DerefOrZero(updated.PwdMustChange) != constant.PwdMustChangeNo {
+		t.Fatalf("expected password changed and must-change cleared, got %+v", updated.PwdMustChange)
+	}
+	// 只保留最近 history_count 条历史密码
+	if count := countRows(t, db, model.TableNameSysUserPwdHistory, "user_id = ?", user.ID); count != 2 {
+		t.Fatalf("expected 2 password history rows, got %d", count)
+	}
+}
@@ -0,0 +1,140 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	e "snowgo/pkg/xerror"
+)
+
+func TestLoadBannedPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banned.txt")
+	content := "# 常见密码\nPassword1\n\n  qwe123  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write banned list: %v", err)
+	}
+
+	banned, err := LoadBannedPasswords(path)
+	if err != nil {
+		t.Fatalf("LoadBannedPasswords error: %v", err)
+	}
+	if len(banned) != 2 {
+		t.Fatalf("expected 2 banned passwords, got %v", banned)
+	}
+	for _, pw := range []string{"password1", "qwe123"} {
+		if _, ok := banned[pw]; !ok {
+			t.Fatalf("expected %q to be banned, got %v", pw, banned)
+		}
+	}
+
+	if _, err := LoadBannedPasswords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Fatalf("expected error for missing banned list")
+	}
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := PasswordPolicy{BannedPasswords: map[string]struct{}{"qwe123": {}}}
+
+	if err := policy.Validate("QWE123"); !errors.Is(err, ErrPwdBanned) {
+		t.Fatalf("expected banned password rejected case-insensitively, got %v", err)
+	}
+	var bizErr *e.BizError
+	if err := policy.Validate("abcdef"); !errors.As(err, &bizErr) || bizErr.Code.GetErrCode() != e.PwdComplexityError.GetErrCode() {
+		t.Fatalf("expected complexity check before banned list, got %v", err)
+	}
+	if err := policy.Validate("abc123"); err != nil {
+		t.Fatalf("expected password valid, got %v", err)
+	}
+	if err := (PasswordPolicy{}).Validate("qwe123"); err != nil {
+		t.Fatalf("expected zero policy to skip banned list, got %v", err)
+	}
+}
+
+func TestPasswordPolicyChangeReason(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-100 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	mustChange := constant.PwdMustChangeYes
+	noChange := constant.PwdMustChangeNo
+	maxAge := PasswordPolicy{MaxAge: 90 * 24 * time.Hour}
+
+	tests := []struct {
+		name   string
+		policy PasswordPolicy
+		user   *model.SysUser
+		want   string
+	}{
+		{"must change flag", PasswordPolicy{}, &model.SysUser{PwdMustChange: &mustChange, PwdChangedAt: &recent}, PwdChangeReasonRequired},
+		{"no max age", PasswordPolicy{}, &model.SysUser{PwdMustChange: &noChange, PwdChangedAt: &old}, ""},
+		{"expired", maxAge, &model.SysUser{PwdChangedAt: &old}, PwdChangeReasonExpired},
+		{"not expired", maxAge, &model.SysUser{PwdChangedAt: &recent}, ""},
+		{"fallback to created at", maxAge, &model.SysUser{CreatedAt: &old}, PwdChangeReasonExpired},
+		{"no timestamps", maxAge, &model.SysUser{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ChangeReason(tt.user, now); got != tt.want {
+				t.Fatalf("expected reason %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCreatePwdChangeTicket(t *testing.T) {
+	cache := newFakeCache()
+	service := &UserService{cache: cache, pwdPolicy: PasswordPolicy{ChangeTTL: 3 * time.Minute}}
+
+	ticket := &PwdChangeTicket{UserId: 2, Username: "operator", Reason: PwdChangeReasonRequired}
+	token, err := service.CreatePwdChangeTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("CreatePwdChangeTicket error: %v", err)
+	}
+	key := constant.CachePwdChangePrefix + token
+	if cache.expirations[key] != 3*time.Minute {
+		t.Fatalf("expected ticket ttl 3m, got %v", cache.expirations[key])
+	}
+	var saved PwdChangeTicket
+	if err := json.Unmarshal([]byte(cache.values[key]), &saved); err != nil {
+		t.Fatalf("unmarshal saved ticket: %v", err)
+	}
+	if saved.UserId != 2 || saved.Reason != PwdChangeReasonRequired || saved.ExpireAt.IsZero() {
+		t.Fatalf("unexpected saved ticket %+v", saved)
+	}
+}
+
+func TestChangePwdByTicketRejectsInvalidTicket(t *testing.T) {
+	cache := newFakeCache()
+	service := &UserService{cache: cache}
+	ctx := context.Background()
+
+	if _, err := service.ChangePwdByTicket(ctx, "", "abc123!"); !errors.Is(err, ErrPwdChangeInvalid) {
+		t.Fatalf("expected ErrPwdChangeInvalid for empty token, got %v", err)
+	}
+	if _, err := service.ChangePwdByTicket(ctx, "missing", "abc123!"); !errors.Is(err, ErrPwdChangeInvalid) {
+		t.Fatalf("expected ErrPwdChangeInvalid for unknown token, got %v", err)
+	}
+
+	expired, _ := json.Marshal(&PwdChangeTicket{UserId: 2, Username: "operator", ExpireAt: time.Now().Add(-time.Second)})
+	cache.values[constant.CachePwdChangePrefix+"expired"] = string(expired)
+	if _, err := service.ChangePwdByTicket(ctx, "expired", "abc123!"); !errors.Is(err, ErrPwdChangeInvalid) {
+		t.Fatalf("expected ErrPwdChangeInvalid for expired ticket, got %v", err)
+	}
+
+	// 新密码不符合策略时保留凭证，允许重新提交
+	valid, _ := json.Marshal(&PwdChangeTicket{UserId: 2, Username: "operator", ExpireAt: time.Now().Add(time.Minute)})
+	cache.values[constant.CachePwdChangePrefix+"valid"] = string(valid)
+	var bizErr *e.BizError
+	if _, err := service.ChangePwdByTicket(ctx, "valid", "abcdef"); !errors.As(err, &bizErr) || bizErr.Code.GetErrCode() != e.PwdComplexityError.GetErrCode() {
+		t.Fatalf("expected PwdComplexityError, got %v", err)
+	}
+	if _, ok := cache.values[constant.CachePwdChangePrefix+"valid"]; !ok {
+		t.Fatalf("expected ticket kept after weak password")
+	}
+}
@@ -0,0 +1,141 @@
+package account
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/pkg/xauth/perm"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xlogger"
+)
+
+// permInvalidateAll 失效全部用户的广播消息，其余消息为逗号分隔的用户id
+const permInvalidateAll = "*"
+
+// PermCacheConfig 进程内权限缓存配置
+type PermCacheConfig struct {
+	Size int           // 最多缓存的用户数，0 表示关闭进程内缓存
+	TTL  time.Duration // 缓存有效期，兜底跨实例通知丢失
+}
+
+// PermCache 进程内缓存用户已解析的权限匹配树，避免每次鉴权都读取 Redis
+// 角色或菜单分配变更时本地立即失效，并通过 Redis 发布订阅通知其他实例；nil 表示关闭，所有方法均可安全调用
+type PermCache struct {
+	local    *xcache.LocalCache[int32, *perm.Matcher]
+	notifier xcache.Notifier
+
+	mu  sync.Mutex
+	gen uint64 // 失效代数，失效前开始的加载结果不再回写
+}
+
+func NewPermCache(conf PermCacheConfig, notifier xcache.Notifier) *PermCache {
+	if conf.Size <= 0 || conf.TTL <= 0 {
+		return nil
+	}
+	return &PermCache{
+		local:    xcache.NewLocalCache[int32, *perm.Matcher](conf.Size, conf.TTL),
+		notifier: notifier,
+	}
+}
+
+// get 读取本地缓存，同时返回当前失效代数供回写时校验
+func (c *PermCache) get(userId int32) (*perm.Matcher, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+	m, ok := c.local.Get(userId)
+	return m, gen, ok
+}
+
+// set 回写本地缓存，有效期取 ttl 与配置 TTL 的较小值，ttl<=0 或加载期间发生过失效则放弃
+func (c *PermCache) set(userId int32, m *perm.Matcher, gen uint64, ttl time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return
+	}
+	c.local.SetWithTTL(userId, m, ttl)
+}
+
+// InvalidateUsers 用户角色变更后失效指定用户并广播
+func (c *PermCache) InvalidateUsers(ctx context.Context, userIds ...int32) {
+	if c == nil || len(userIds) == 0 {
+		return
+	}
+	c.deleteLocal(userIds...)
+	ids := make([]string, 0, len(userIds))
+	for _, id := range userIds {
+		ids = append(ids, strconv.FormatInt(int64(id), 10))
+	}
+	c.publish(ctx, strings.Join(ids, ","))
+}
+
+// InvalidateAll 角色菜单变更影响的用户不确定，失效全部用户并广播
+func (c *PermCache) InvalidateAll(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.purgeLocal()
+	c.publish(ctx, permInvalidateAll)
+}
+
+// Listen 订阅其他实例的失效通知，阻塞直到 ctx 取消；(重新)订阅成功时清空本地缓存，避免断线期间漏掉通知
+func (c *PermCache) Listen(ctx context.Context) error {
+	if c == nil || c.notifier == nil {
+		return nil
+	}
+	return c.notifier.Subscribe(ctx, constant.ChannelPermInvalidate, c.purgeLocal, c.handle)
+}
+
+func (c *PermCache) handle(message string) {
+	if message == permInvalidateAll {
+		c.purgeLocal()
+		return
+	}
+	userIds := make([]int32, 0, 1)
+	for _, raw := range strings.Split(message, ",") {
+		id, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			// 无法识别的消息按全部失效处理
+			xlogger.Errorf("权限缓存失效消息无法解析: %q", message)
+			c.purgeLocal()
+			return
+		}
+		userIds = append(userIds, int32(id))
+	}
+	c.deleteLocal(userIds...)
+}
+
+func (c *PermCache) publish(ctx context.Context, message string) {
+	if c.notifier == nil {
+		return
+	}
+	if err := c.notifier.Publish(ctx, constant.ChannelPermInvalidate, message); err != nil {
+		// 其他实例依赖缓存有效期兜底
+		xlogger.ErrorfCtx(ctx, "广播权限缓存失效失败 message=%s: %v", message, err)
+	}
+}
+
+func (c *PermCache) deleteLocal(userIds ...int32) {
+	c.mu.Lock()
+	c.gen++
+	c.local.Delete(userIds...)
+	c.mu.Unlock()
+}
+
+func (c *PermCache) purgeLocal() {
+	c.mu.Lock()
+	c.gen++
+	c.local.Purge()
+	c.mu.Unlock()
+}
@@ -0,0 +1,149 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+)
+
+type fakeNotifier struct {
+	published []string
+	err       error
+}
+
+func (f *fakeNotifier) Publish(_ context.Context, channel string, message string) error {
+	if channel != constant.ChannelPermInvalidate {
+		return errors.New("unexpected channel " + channel)
+	}
+	f.published = append(f.published, message)
+	return f.err
+}
+
+func (f *fakeNotifier) Subscribe(context.Context, string, func(), func(string)) error {
+	return nil
+}
+
+func TestUserServiceGetPermMatcherByIdUsesPermCache(t *testing.T) {
+	cache := newFakeCache()
+	cache.values[constant.CacheUserRolePrefix+"2"] = "[1]"
+	notifier := &fakeNotifier{}
+	permCache := NewPermCache(PermCacheConfig{Size: 10, TTL: time.Minute}, notifier)
+	service := &UserService{
+		userDao:     &fakeUserRepo{},
+		cache:       cache,
+		roleService: &RoleService{roleDao: &fakeRoleRepo{}, cache: newFakeCache()},
+		permCache:   permCache,
+	}
+
+	first, err := service.GetPermMatcherById(testUserCtx(), 2)
+	if err != nil || !first.Match("account:user:list") {
+		t.Fatalf("expected super admin matcher, got %v", err)
+	}
+
+	// 角色被移除但未失效时，命中进程内缓存不再读取 Redis
+	cache.values[constant.CacheUserRolePrefix+"2"] = "[]"
+	cached, _ := service.GetPermMatcherById(testUserCtx(), 2)
+	if cached != first {
+		t.Fatalf("expected cached matcher to be reused")
+	}
+
+	permCache.InvalidateUsers(testUserCtx(), 2)
+	if !slices.Equal(notifier.published, []string{"2"}) {
+		t.Fatalf("expected invalidation broadcast, got %v", notifier.published)
+	}
+	fresh, err := service.GetPermMatcherById(testUserCtx(), 2)
+	if err != nil || fresh.Match("account:user:list") {
+		t.Fatalf("expected reloaded matcher without perms, got %v", err)
+	}
+}
+
+func TestUserServiceGetPermMatcherByIdCapsPermCacheAtGrantBoundary(t *testing.T) {
+	cacheKey := constant.CacheUserRolePrefix + "2"
+	cache := newFakeCache()
+	// 角色缓存按最近一次限时授权失效时间设置了过期时间
+	cache.values[cacheKey] = "[1]"
+	cache.expirations[cacheKey] = 20 * time.Millisecond
+	service := &UserService{
+		userDao:     &fakeUserRepo{},
+		cache:       cache,
+		roleService: &RoleService{roleDao: &fakeRoleRepo{}, cache: newFakeCache()},
+		permCache:   NewPermCache(PermCacheConfig{Size: 10, TTL: time.Minute}, &fakeNotifier{}),
+	}
+
+	first, err := service.GetPermMatcherById(testUserCtx(), 2)
+	if err != nil || !first.Match("account:user:list") {
+		t.Fatalf("expected super admin matcher, got %v", err)
+	}
+
+	// 授权到期后进程内缓存随之失效，不再沿用配置的 TTL
+	time.Sleep(40 * time.Millisecond)
+	cache.values[cacheKey] = "[]"
+	delete(cache.expirations, cacheKey)
+	fresh, err := service.GetPermMatcherById(testUserCtx(), 2)
+	if err != nil || fresh.Match("account:user:list") {
+		t.Fatalf("expected matcher reloaded after grant boundary, got %v", err)
+	}
+}
+
+func TestPermCacheHandleMessage(t *testing.T) {
+	c := NewPermCache(PermCacheConfig{Size: 10, TTL: time.Minute}, nil)
+	fill := func() {
+		for _, id := range []int32{1, 2, 3} {
+			_, gen, _ := c.get(id)
+			c.set(id, (&permMatcherCache{}).get(nil), gen, time.Minute)
+		}
+	}
+	cached := func(id int32) bool {
+		_, _, ok := c.get(id)
+		return ok
+	}
+
+	fill()
+	c.handle("1,3")
+	if cached(1) || !cached(2) || cached(3) {
+		t.Fatalf("expected only users 1 and 3 invalidated")
+	}
+
+	fill()
+	c.handle(permInvalidateAll)
+	if cached(1) || cached(2) || cached(3) {
+		t.Fatalf("expected all users invalidated")
+	}
+
+	fill()
+	c.handle("bad")
+	if cached(2) {
+		t.Fatalf("expected unparsable message to invalidate all")
+	}
+}
+
+func TestPermCacheDropsStaleLoad(t *testing.T) {
+	c := NewPermCache(PermCacheConfig{Size: 10, TTL: time.Minute}, &fakeNotifier{})
+	_, gen, _ := c.get(1)
+	// 加载期间发生失效，旧结果不能回写
+	c.InvalidateAll(testUserCtx())
+	c.set(1, (&permMatcherCache{}).get(nil), gen, time.Minute)
+	if _, _, ok := c.get(1); ok {
+		t.Fatalf("expected stale load to be dropped")
+	}
+}
+
+func TestPermCacheDisabled(t *testing.T) {
+	c := NewPermCache(PermCacheConfig{}, &fakeNotifier{})
+	if c != nil {
+		t.Fatalf("expected nil cache when size is 0")
+	}
+	c.InvalidateAll(testUserCtx())
+	c.InvalidateUsers(testUserCtx(), 1)
+	c.set(1, nil, 0, time.Minute)
+	if _, _, ok := c.get(1); ok {
+		t.Fatalf("expected disabled cache to miss")
+	}
+	if err := c.Listen(testUserCtx()); err != nil {
+		t.Fatalf("expected disabled listen to return nil, got %v", err)
+	}
+}
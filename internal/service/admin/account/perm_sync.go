@@ -0,0 +1,115 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/service/admin/contract"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/perm"
+	"snowgo/pkg/xlogger"
+)
+
+// permSyncOperatorName 权限同步命令写操作日志时的操作人名称
+const permSyncOperatorName = "perm-sync"
+
+// DiffPerms 对比权限注册表与菜单 Btn 节点，报告缺失、孤立、未使用的权限
+func (s *MenuService) DiffPerms(ctx context.Context, reg *perm.Registry) (*perm.Drift, error) {
+	menus, err := s.menuDao.GetAllMenus(ctx)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取全部菜单失败: %v", err)
+		return nil, fmt.Errorf("获取全部菜单失败: %w", err)
+	}
+	menuPerms := make([]string, 0, len(menus))
+	for _, m := range menus {
+		if m.MenuType == constant.MenuTypeBtn && common.DerefOrZero(m.Perms) != "" {
+			menuPerms = append(menuPerms, *m.Perms)
+		}
+	}
+	return reg.Diff(menuPerms), nil
+}
+
+// SyncPerms 将注册表中缺少菜单节点的权限点补建为 parentId 下的 Btn 节点，返回新建的节点
+// 新节点未分配给任何角色，不影响已有用户权限；操作日志记为系统操作
+func (s *MenuService) SyncPerms(ctx context.Context, reg *perm.Registry, parentId int32) ([]*model.SysMenu, error) {
+	if parentId < 1 {
+		return nil, ErrMenuParentInvalid
+	}
+	traceCtx := xauth.GetContext(ctx)
+
+	var created []*model.SysMenu
+	err := s.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		parent, err := s.menuDao.GetById(ctx, tx, parentId)
+		if err != nil || parent.MenuType == constant.MenuTypeBtn {
+			return ErrMenuParentInvalid
+		}
+		siblings, err := s.menuDao.GetByParentId(ctx, tx, parentId)
+		if err != nil {
+			return fmt.Errorf("获取子菜单失败: %w", err)
+		}
+		var sortOrder int32
+		for _, m := range siblings {
+			sortOrder = max(sortOrder, m.SortOrder)
+		}
+
+		for _, def := range reg.Defs() {
+			// 事务内逐个校验，menu 表 perms 无唯一索引
+			exists, err := s.menuDao.IsPermsExists(ctx, tx, def.Code, 0)
+			if err != nil {
+				return fmt.Errorf("校验权限标识失败: %w", err)
+			}
+			if exists {
+				continue
+			}
+			sortOrder++
+			menuObj, err := s.menuDao.CreateMenu(ctx, tx, &model.SysMenu{
+				ParentID:  parentId,
+				MenuType:  constant.MenuTypeBtn,
+				Name:      def.Name,
+				Perms:     &def.Code,
+				SortOrder: sortOrder,
+			})
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "同步权限创建菜单失败 perms=%s: %v", def.Code, err)
+				return fmt.Errorf("创建菜单失败: %w", err)
+			}
+
+			err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+				OperatorName: permSyncOperatorName,
+				OperatorType: constant.OperatorSystem,
+				Resource:     constant.ResourceMenu,
+				ResourceID:   int64(menuObj.ID),
+				TraceID:      traceCtx.TraceId,
+				Action:       constant.ActionCreate,
+				AfterData:    menuObj,
+				Description: fmt.Sprintf("权限同步在菜单(%d-%s)下创建了按钮(%d-%s)，权限标识 %s",
+					parent.ID, parent.Name, menuObj.ID, menuObj.Name, def.Code),
+				IP: traceCtx.IP,
+			})
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "操作日志创建失败: %v err: %v", menuObj, err)
+				return fmt.Errorf("操作日志创建失败: %w", err)
+			}
+			created = append(created, menuObj)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		return nil, nil
+	}
+
+	xlogger.InfofCtx(ctx, "权限同步创建按钮 %d 个，父菜单: %d", len(created), parentId)
+
+	// 清理菜单树缓存
+	if _, err := s.cache.Delete(ctx, constant.CacheMenuTree); err != nil {
+		xlogger.ErrorfCtx(ctx, "清理菜单树缓存失败: %v", err)
+	}
+	return created, nil
+}
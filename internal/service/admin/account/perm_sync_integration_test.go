@@ -0,0 +1,85 @@
+//go:build integration
+
+package account
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth/perm"
+)
+
+func TestMenuServiceSyncPermsIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	parent := insertIntegrationMenu(t, db, 0, constant.MenuTypeMenu, "用户管理")
+	existing := insertIntegrationMenu(t, db, parent.ID, constant.MenuTypeBtn, "用户列表")
+	listPerms := "account:user:list"
+	if err := db.Model(existing).Updates(map[string]any{"perms": listPerms, "sort_order": 3}).Error; err != nil {
+		t.Fatalf("update existing btn: %v", err)
+	}
+	if err := deps.cache.Set(testUserCtx(), constant.CacheMenuTree, `[{"id":1}]`, time.Hour); err != nil {
+		t.Fatalf("prime menu tree cache: %v", err)
+	}
+
+	reg := perm.NewRegistry()
+	reg.Use(reg.Define(listPerms, "查看用户列表"), reg.Define("account:user:create", "创建用户"))
+	service := newIntegrationMenuService(deps)
+
+	created, err := service.SyncPerms(testUserCtx(), reg, parent.ID)
+	if err != nil {
+		t.Fatalf("SyncPerms expected success, got %v", err)
+	}
+	if len(created) != 1 || *created[0].Perms != "account:user:create" {
+		t.Fatalf("expected only missing perm created, got %+v", created)
+	}
+	count := countRows(t, db, model.TableNameSysMenu, "parent_id = ? AND menu_type = ? AND name = ? AND perms = ? AND sort_order = ?",
+		parent.ID, constant.MenuTypeBtn, "创建用户", "account:user:create", 4)
+	if count != 1 {
+		t.Fatalf("expected btn appended after siblings, got count %d", count)
+	}
+	operationLog := queryOperationLog(t, db, constant.ResourceMenu, int64(created[0].ID), constant.ActionCreate)
+	if operationLog.OperatorType == nil || *operationLog.OperatorType != constant.OperatorSystem {
+		t.Fatalf("expected system operation log, got %+v", operationLog.OperatorType)
+	}
+	if _, ok, err := deps.cache.Get(testUserCtx(), constant.CacheMenuTree); err != nil {
+		t.Fatalf("get menu tree cache: %v", err)
+	} else if ok {
+		t.Fatalf("expected menu tree cache to be invalidated")
+	}
+
+	drift, err := service.DiffPerms(testUserCtx(), reg)
+	if err != nil || !drift.Empty() {
+		t.Fatalf("expected no drift after sync, got %+v %v", drift, err)
+	}
+	// 再次同步无需创建
+	created, err = service.SyncPerms(testUserCtx(), reg, parent.ID)
+	if err != nil || len(created) != 0 {
+		t.Fatalf("expected idempotent sync, got %+v %v", created, err)
+	}
+}
+
+func TestMenuServiceSyncPermsParentGuardIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	btn := insertIntegrationMenu(t, db, 0, constant.MenuTypeBtn, "按钮")
+	reg := perm.NewRegistry()
+	reg.Define("account:user:create", "创建用户")
+	service := newIntegrationMenuService(deps)
+
+	for _, parentId := range []int32{btn.ID, btn.ID + 100} {
+		if _, err := service.SyncPerms(testUserCtx(), reg, parentId); !errors.Is(err, ErrMenuParentInvalid) {
+			t.Fatalf("parent %d expected ErrMenuParentInvalid, got %v", parentId, err)
+		}
+	}
+	if count := countRows(t, db, model.TableNameSysMenu, "perms = ?", "account:user:create"); count != 0 {
+		t.Fatalf("expected no btn created, got %d", count)
+	}
+}
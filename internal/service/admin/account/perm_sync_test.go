@@ -0,0 +1,51 @@
+package account
+
+import (
+	"errors"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth/perm"
+)
+
+func TestMenuServiceDiffPerms(t *testing.T) {
+	reg := perm.NewRegistry()
+	reg.Use(reg.Define("account:user:list", "查看用户列表"), reg.Define("account:user:create", "创建用户"))
+
+	list, orphan, dirPerms := "account:user:list", "account:user:export", "account:user:create"
+	repo := &fakeMenuRepo{allMenus: []*model.SysMenu{
+		{ID: 1, MenuType: constant.MenuTypeMenu, Name: "用户管理", Perms: &dirPerms},
+		{ID: 2, ParentID: 1, MenuType: constant.MenuTypeBtn, Name: "用户列表", Perms: &list},
+		{ID: 3, ParentID: 1, MenuType: constant.MenuTypeBtn, Name: "导出用户", Perms: &orphan},
+		{ID: 4, ParentID: 1, MenuType: constant.MenuTypeBtn, Name: "空按钮"},
+	}}
+	service := &MenuService{menuDao: repo}
+
+	drift, err := service.DiffPerms(testUserCtx(), reg)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	// 非 Btn 节点上的 perms 不参与对比
+	if len(drift.Missing) != 1 || drift.Missing[0].Code != "account:user:create" {
+		t.Fatalf("unexpected missing: %+v", drift.Missing)
+	}
+	if len(drift.Orphaned) != 1 || drift.Orphaned[0] != orphan {
+		t.Fatalf("unexpected orphaned: %v", drift.Orphaned)
+	}
+	if len(drift.Unused) != 0 || len(drift.Undefined) != 0 {
+		t.Fatalf("unexpected drift: %+v", drift)
+	}
+
+	repo.allMenusErr = errors.New("db down")
+	if _, err := service.DiffPerms(testUserCtx(), reg); err == nil {
+		t.Fatalf("expected dao error")
+	}
+}
+
+func TestMenuServiceSyncPermsInvalidParent(t *testing.T) {
+	service := &MenuService{}
+	if _, err := service.SyncPerms(testUserCtx(), perm.NewRegistry(), 0); !errors.Is(err, ErrMenuParentInvalid) {
+		t.Fatalf("expected ErrMenuParentInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package account
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"snowgo/pkg/xauth/perm"
+)
+
+// permMatcherCacheSize 预编译权限树缓存上限，超出后整体清空重建
+const permMatcherCacheSize = 1024
+
+// permMatcherCache 按权限集合缓存预编译的权限树，拥有相同权限集合的用户共用同一棵树
+type permMatcherCache struct {
+	mu    sync.RWMutex
+	items map[string]*perm.Matcher
+}
+
+func (c *permMatcherCache) get(perms []string) *perm.Matcher {
+	sorted := append([]string(nil), perms...)
+	sort.Strings(sorted)
+	key := strings.Join(sorted, "\n")
+
+	c.mu.RLock()
+	m, ok := c.items[key]
+	c.mu.RUnlock()
+	if ok {
+		return m
+	}
+
+	m = perm.Compile(sorted)
+	c.mu.Lock()
+	if c.items == nil || len(c.items) >= permMatcherCacheSize {
+		c.items = make(map[string]*perm.Matcher)
+	}
+	c.items[key] = m
+	c.mu.Unlock()
+	return m
+}
+
+// GetPermMatcherById 获取用户权限的预编译匹配树，支持通配授权（account:user:*、account:*、*）
+// 优先读取进程内权限缓存，未命中再从 Redis 解析角色权限；缓存不超过下一次限时授权生效或失效的时间
+func (u *UserService) GetPermMatcherById(ctx context.Context, userId int32) (*perm.Matcher, error) {
+	cached, gen, ok := u.permCache.get(userId)
+	if ok {
+		return cached, nil
+	}
+	perms, ttl, err := u.getPermsWithTTL(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	m := u.permMatchers.get(perms)
+	u.permCache.set(userId, m, gen, ttl)
+	return m, nil
+}
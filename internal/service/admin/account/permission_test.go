@@ -0,0 +1,41 @@
+package account
+
+import (
+	"testing"
+
+	"snowgo/internal/constant"
+)
+
+func TestUserServiceGetPermMatcherById(t *testing.T) {
+	cache := newFakeCache()
+	cache.values[constant.CacheUserRolePrefix+"2"] = "[1]"
+	roleService := &RoleService{roleDao: &fakeRoleRepo{}, cache: newFakeCache()}
+	service := &UserService{userDao: &fakeUserRepo{}, cache: cache, roleService: roleService}
+
+	matcher, err := service.GetPermMatcherById(testUserCtx(), 2)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !matcher.MatchAll("account:user:list", "system:dict:delete") {
+		t.Fatalf("expected super admin matcher to allow every perm")
+	}
+	again, _ := service.GetPermMatcherById(testUserCtx(), 2)
+	if again != matcher {
+		t.Fatalf("expected compiled matcher to be reused for the same perm set")
+	}
+}
+
+func TestPermMatcherCacheSharesSamePermSet(t *testing.T) {
+	var c permMatcherCache
+	a := c.get([]string{"account:user:list", "account:role:*"})
+	b := c.get([]string{"account:role:*", "account:user:list"})
+	if a != b {
+		t.Fatalf("expected same matcher for perm sets in different order")
+	}
+	if !a.Match("account:role:delete") || a.Match("account:menu:list") {
+		t.Fatalf("unexpected matcher result")
+	}
+	if c.get([]string{"account:user:list"}) == a {
+		t.Fatalf("expected different matcher for different perm set")
+	}
+}
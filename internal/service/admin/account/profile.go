@@ -0,0 +1,157 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/service/admin/contract"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcryption"
+	"snowgo/pkg/xdatabase/mysql"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+// ProfileParam 当前登录用户可修改的个人资料，字段为空表示不修改
+type ProfileParam struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=60"`
+	Email    *string `json:"email" binding:"omitempty,max=100"`
+	Tel      *string `json:"tel" binding:"omitempty,max=20"`
+}
+
+var (
+	ErrPwdOldInvalid = e.NewBizError(e.PwdOldError)
+)
+
+// GetProfile 获取当前登录用户信息
+func (u *UserService) GetProfile(ctx context.Context) (*UserInfo, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetUserById(ctx, userContext.UserId)
+}
+
+// getSelf 获取当前登录用户，服务账号不允许修改个人资料与密码
+func (u *UserService) getSelf(ctx context.Context, userId int32) (*model.SysUser, error) {
+	user, err := u.userDao.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userId, err)
+		return nil, fmt.Errorf("用户信息查询失败: %w", err)
+	}
+	if isServiceAccount(user) {
+		return nil, ErrUserTypeNotAllow
+	}
+	return user, nil
+}
+
+// UpdateProfile 当前登录用户修改自己的昵称、邮箱、电话
+func (u *UserService) UpdateProfile(ctx context.Context, param *ProfileParam) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	oldUser, err := u.getSelf(ctx, userContext.UserId)
+	if err != nil {
+		return err
+	}
+
+	// 普通用户电话必填，只允许修改为其他未被使用的电话
+	if param.Tel != nil {
+		if *param.Tel == "" {
+			return ErrUserNameTelEmpty
+		}
+		isDuplicate, err := u.userDao.IsNameTelDuplicate(ctx, oldUser.Username, *param.Tel, oldUser.ID)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "查询用户名或电话是否存在异常: %v", err)
+			return fmt.Errorf("查询用户名或电话是否存在异常: %w", err)
+		}
+		if isDuplicate {
+			return ErrUserNameTelExist
+		}
+	}
+
+	newUser := &model.SysUser{
+		ID:        oldUser.ID,
+		Username:  oldUser.Username,
+		Tel:       param.Tel,
+		Nickname:  param.Nickname,
+		Email:     param.Email,
+		UpdatedBy: &oldUser.ID,
+	}
+	err = u.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		_, err := u.userDao.UpdateUser(ctx, tx, newUser)
+		if err != nil {
+			// 唯一索引冲突兜底
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrUserNameTelExist
+			}
+			xlogger.ErrorfCtx(ctx, "个人资料更新失败 user_id=%d err: %v", oldUser.ID, err)
+			return fmt.Errorf("个人资料更新失败: %w", err)
+		}
+
+		err = u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+			OperatorID:   oldUser.ID,
+			OperatorName: oldUser.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourceUser,
+			ResourceID:   int64(oldUser.ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   oldUser,
+			AfterData:    newUser,
+			Description:  fmt.Sprintf("用户(%d-%s)修改了个人资料", oldUser.ID, oldUser.Username),
+			IP:           userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败 resource=user resource_id=%d err: %v", oldUser.ID, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	xlogger.InfofCtx(ctx, "个人资料更新成功 user_id=%d", oldUser.ID)
+	return nil
+}
+
+// ChangePwd 当前登录用户校验原密码后修改密码，成功后已签发的token全部失效，需重新登录
+func (u *UserService) ChangePwd(ctx context.Context, oldPassword, password string) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := u.getSelf(ctx, userContext.UserId)
+	if err != nil {
+		return err
+	}
+	if !xcryption.CheckPassword(user.Password, oldPassword) {
+		return ErrPwdOldInvalid
+	}
+	if bizErr := u.pwdPolicy.Validate(password); bizErr != nil {
+		return bizErr
+	}
+	if oldPassword == password {
+		return ErrPwdReused
+	}
+	if err := u.checkPwdHistory(ctx, u.db.WriteQuery(), user.ID, password); err != nil {
+		return err
+	}
+	pwd, err := xcryption.HashPassword(password)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "密码加密异常: %v", err)
+		return fmt.Errorf("密码加密异常: %w", err)
+	}
+	desc := fmt.Sprintf("用户(%d-%s)修改了自己的密码", user.ID, user.Username)
+	return u.changeOwnPwd(ctx, user, pwd, &userContext.Context, desc)
+}
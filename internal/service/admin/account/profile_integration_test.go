@@ -0,0 +1,83 @@
+//go:build integration
+
+package account
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcryption"
+)
+
+func profileUserCtx(user *model.SysUser) context.Context {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, xauth.XUserId, user.ID)
+	ctx = context.WithValue(ctx, xauth.XUserName, user.Username)
+	ctx = context.WithValue(ctx, xauth.XTraceId, "trace-profile")
+	ctx = context.WithValue(ctx, xauth.XIp, "127.0.0.1")
+	return ctx
+}
+
+func TestUserServiceUpdateProfileIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationUser(t, db, "other", "18100000301")
+	user := insertIntegrationUser(t, db, "profile_operator", "18100000302")
+	ctx := profileUserCtx(user)
+	service := newIntegrationUserService(deps)
+
+	taken := "18100000301"
+	if err := service.UpdateProfile(ctx, &ProfileParam{Tel: &taken}); err != ErrUserNameTelExist {
+		t.Fatalf("expected ErrUserNameTelExist, got %v", err)
+	}
+
+	nickname, email, tel := "新昵称", "me@example.com", "18100000303"
+	if err := service.UpdateProfile(ctx, &ProfileParam{Nickname: &nickname, Email: &email, Tel: &tel}); err != nil {
+		t.Fatalf("UpdateProfile error: %v", err)
+	}
+	profile, err := service.GetProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetProfile error: %v", err)
+	}
+	if profile.Nickname != nickname || profile.Email != email || profile.Tel != tel || profile.UpdatedBy != user.ID {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+	log := queryOperationLog(t, db, constant.ResourceUser, int64(user.ID), constant.ActionUpdate)
+	if log.OperatorID != user.ID || log.Description == nil || !strings.Contains(*log.Description, "个人资料") {
+		t.Fatalf("expected self profile operation log, got %+v", log)
+	}
+}
+
+func TestUserServiceChangePwdIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	user := insertIntegrationUser(t, db, "pwd_self", "18100000304")
+	ctx := profileUserCtx(user)
+	service := newIntegrationUserService(deps)
+
+	if err := service.ChangePwd(ctx, "wrong123", "new123!"); err != ErrPwdOldInvalid {
+		t.Fatalf("expected ErrPwdOldInvalid, got %v", err)
+	}
+	if err := service.ChangePwd(ctx, "abc123", "new123!"); err != nil {
+		t.Fatalf("ChangePwd error: %v", err)
+	}
+	var updated model.SysUser
+	if err := db.Where("id = ?", user.ID).First(&updated).Error; err != nil {
+		t.Fatalf("query updated user: %v", err)
+	}
+	if !xcryption.CheckPassword(updated.Password, "new123!") || updated.PwdChangedAt == nil {
+		t.Fatalf("expected password changed with pwd_changed_at set")
+	}
+	log := queryOperationLog(t, db, constant.ResourceUser, int64(user.ID), constant.ActionUpdate)
+	if log.OperatorID != user.ID || log.Description == nil || !strings.Contains(*log.Description, "密码") {
+		t.Fatalf("expected self password operation log, got %+v", log)
+	}
+}
@@ -0,0 +1,59 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xcryption"
+)
+
+func TestProfileRequiresLogin(t *testing.T) {
+	service := &UserService{}
+	if _, err := service.GetProfile(context.Background()); err == nil {
+		t.Fatalf("GetProfile expected error without login context")
+	}
+	if err := service.UpdateProfile(context.Background(), &ProfileParam{}); err == nil {
+		t.Fatalf("UpdateProfile expected error without login context")
+	}
+	if err := service.ChangePwd(context.Background(), "abc123", "new123"); err == nil {
+		t.Fatalf("ChangePwd expected error without login context")
+	}
+}
+
+func TestProfileRejectsServiceAccount(t *testing.T) {
+	serviceType := constant.UserTypeService
+	service := &UserService{userDao: &fakeUserRepo{userById: &model.SysUser{ID: 1, Username: "admin", UserType: &serviceType}}}
+
+	if err := service.UpdateProfile(testUserCtx(), &ProfileParam{}); !errors.Is(err, ErrUserTypeNotAllow) {
+		t.Fatalf("UpdateProfile expected ErrUserTypeNotAllow, got %v", err)
+	}
+	if err := service.ChangePwd(testUserCtx(), "abc123", "new123"); !errors.Is(err, ErrUserTypeNotAllow) {
+		t.Fatalf("ChangePwd expected ErrUserTypeNotAllow, got %v", err)
+	}
+}
+
+func TestUpdateProfileRejectsEmptyTel(t *testing.T) {
+	service := &UserService{userDao: &fakeUserRepo{userById: &model.SysUser{ID: 1, Username: "admin"}}}
+	empty := ""
+	if err := service.UpdateProfile(testUserCtx(), &ProfileParam{Tel: &empty}); !errors.Is(err, ErrUserNameTelEmpty) {
+		t.Fatalf("UpdateProfile expected ErrUserNameTelEmpty, got %v", err)
+	}
+}
+
+func TestChangePwdValidation(t *testing.T) {
+	hashed, err := xcryption.HashPassword("abc123")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	service := &UserService{userDao: &fakeUserRepo{userById: &model.SysUser{ID: 1, Username: "admin", Password: hashed}}}
+
+	if err := service.ChangePwd(testUserCtx(), "wrong123", "new123"); !errors.Is(err, ErrPwdOldInvalid) {
+		t.Fatalf("ChangePwd expected ErrPwdOldInvalid, got %v", err)
+	}
+	if err := service.ChangePwd(testUserCtx(), "abc123", "abc123"); !errors.Is(err, ErrPwdReused) {
+		t.Fatalf("ChangePwd expected ErrPwdReused, got %v", err)
+	}
+}
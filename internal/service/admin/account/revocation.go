@@ -0,0 +1,128 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xlogger"
+)
+
+// RevocationService access token 吊销
+// 支持按 access jti、按会话(SessionId)、按令牌族(FamilyId) 吊销，以及按用户设置水位线使之前签发的 token 全部失效
+type RevocationService struct {
+	cache      xcache.Cache
+	accessTTL  time.Duration // access token 最长有效期，jti/会话吊销标记只需保留该时长
+	refreshTTL time.Duration // refresh token 最长有效期，用户水位线需保留该时长
+}
+
+func NewRevocationService(cache xcache.Cache, accessTTL, refreshTTL time.Duration) *RevocationService {
+	return &RevocationService{
+		cache:      cache,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// TokenIdentity 待校验 token 的身份信息
+type TokenIdentity struct {
+	UserId    int32
+	TokenId   string // jti
+	SessionId string
+	FamilyId  string
+	IssuedAt  time.Time
+}
+
+// RevokeToken 吊销单个 access token
+func (r *RevocationService) RevokeToken(ctx context.Context, jti string) error {
+	if jti == "" {
+		return nil
+	}
+	if err := r.cache.Set(ctx, constant.CacheRevokedJtiPrefix+jti, "1", r.accessTTL); err != nil {
+		xlogger.ErrorfCtx(ctx, "吊销token失败 jti=%s: %v", jti, err)
+		return fmt.Errorf("吊销token失败: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession 吊销会话下所有 access token
+func (r *RevocationService) RevokeSession(ctx context.Context, sessionId string) error {
+	if sessionId == "" {
+		return nil
+	}
+	if err := r.cache.Set(ctx, constant.CacheRevokedSessionPrefix+sessionId, "1", r.accessTTL); err != nil {
+		xlogger.ErrorfCtx(ctx, "吊销会话失败 session=%s: %v", sessionId, err)
+		return fmt.Errorf("吊销会话失败: %w", err)
+	}
+	return nil
+}
+
+// RevokeFamily 吊销令牌族，族内的 refresh token 与 access token 全部失效
+// 族内 refresh token 不断轮换，标记需保留到 refresh token 最长有效期
+func (r *RevocationService) RevokeFamily(ctx context.Context, familyId string) error {
+	if familyId == "" {
+		return nil
+	}
+	if err := r.cache.Set(ctx, constant.CacheRevokedFamilyPrefix+familyId, "1", r.refreshTTL); err != nil {
+		xlogger.ErrorfCtx(ctx, "吊销令牌族失败 family=%s: %v", familyId, err)
+		return fmt.Errorf("吊销令牌族失败: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserTokens 设置用户水位线（unix 毫秒），写入当前毫秒的下一毫秒，早于水位线签发的 token 全部失效
+// 与吊销同一毫秒内签发的 token 一律视为失效，宁可让极少数刚登录的用户重新登录，也不放过吊销前签发的 token
+func (r *RevocationService) RevokeUserTokens(ctx context.Context, userId int32) error {
+	if userId <= 0 {
+		return ErrUserNotFound
+	}
+	cacheKey := fmt.Sprintf("%s%d", constant.CacheRevokedUserPrefix, userId)
+	watermark := strconv.FormatInt(time.Now().UnixMilli()+1, 10)
+	if err := r.cache.Set(ctx, cacheKey, watermark, r.refreshTTL); err != nil {
+		xlogger.ErrorfCtx(ctx, "设置用户token吊销水位线失败 uid=%d: %v", userId, err)
+		return fmt.Errorf("设置用户token吊销水位线失败: %w", err)
+	}
+	xlogger.InfofCtx(ctx, "用户token已全部吊销 uid=%d watermark=%s", userId, watermark)
+	return nil
+}
+
+// IsRevoked 判断 token 是否已被吊销，依次检查 jti/会话/令牌族标记与用户水位线
+// 各 key 分属不同的 Cluster 槽位，逐个查询而不放进同一个脚本，命中任一标记即返回
+func (r *RevocationService) IsRevoked(ctx context.Context, token *TokenIdentity) (bool, error) {
+	keys := make([]string, 0, 3)
+	if token.TokenId != "" {
+		keys = append(keys, constant.CacheRevokedJtiPrefix+token.TokenId)
+	}
+	if token.SessionId != "" {
+		keys = append(keys, constant.CacheRevokedSessionPrefix+token.SessionId)
+	}
+	if token.FamilyId != "" {
+		keys = append(keys, constant.CacheRevokedFamilyPrefix+token.FamilyId)
+	}
+	for _, key := range keys {
+		exists, err := r.cache.Exists(ctx, key)
+		if err != nil {
+			return false, fmt.Errorf("查询token吊销状态失败: %w", err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+
+	data, ok, err := r.cache.Get(ctx, fmt.Sprintf("%s%d", constant.CacheRevokedUserPrefix, token.UserId))
+	if err != nil {
+		return false, fmt.Errorf("查询token吊销状态失败: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	watermark, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "用户token吊销水位线格式错误 uid=%d", token.UserId)
+		return false, nil
+	}
+	return token.IssuedAt.UnixMilli() < watermark, nil
+}
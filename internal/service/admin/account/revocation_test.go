@@ -0,0 +1,167 @@
+package account
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+)
+
+func TestRevocationService(t *testing.T) {
+	ctx := testUserCtx()
+	issuedAt := time.Now().Add(-time.Minute)
+
+	t.Run("not revoked", func(t *testing.T) {
+		service := NewRevocationService(newFakeCache(), 10*time.Minute, time.Hour)
+		revoked, err := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, TokenId: "jti", SessionId: "sid", IssuedAt: issuedAt})
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if revoked {
+			t.Fatal("expected token not revoked")
+		}
+	})
+
+	t.Run("revoke by jti", func(t *testing.T) {
+		cache := newFakeCache()
+		service := NewRevocationService(cache, 10*time.Minute, time.Hour)
+		if err := service.RevokeToken(ctx, "jti"); err != nil {
+			t.Fatalf("RevokeToken error: %v", err)
+		}
+		if cache.expirations[constant.CacheRevokedJtiPrefix+"jti"] != 10*time.Minute {
+			t.Fatalf("expected jti mark ttl 10m, got %v", cache.expirations[constant.CacheRevokedJtiPrefix+"jti"])
+		}
+		revoked, _ := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, TokenId: "jti", SessionId: "sid", IssuedAt: issuedAt})
+		if !revoked {
+			t.Fatal("expected token revoked by jti")
+		}
+		revoked, _ = service.IsRevoked(ctx, &TokenIdentity{UserId: 2, TokenId: "other", SessionId: "sid", IssuedAt: issuedAt})
+		if revoked {
+			t.Fatal("expected other token not revoked")
+		}
+	})
+
+	t.Run("revoke by session", func(t *testing.T) {
+		service := NewRevocationService(newFakeCache(), 10*time.Minute, time.Hour)
+		if err := service.RevokeSession(ctx, "sid"); err != nil {
+			t.Fatalf("RevokeSession error: %v", err)
+		}
+		revoked, _ := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, TokenId: "jti", SessionId: "sid", IssuedAt: issuedAt})
+		if !revoked {
+			t.Fatal("expected token revoked by session")
+		}
+	})
+
+	t.Run("revoke by family", func(t *testing.T) {
+		cache := newFakeCache()
+		service := NewRevocationService(cache, 10*time.Minute, time.Hour)
+		if err := service.RevokeFamily(ctx, "fid"); err != nil {
+			t.Fatalf("RevokeFamily error: %v", err)
+		}
+		if cache.expirations[constant.CacheRevokedFamilyPrefix+"fid"] != time.Hour {
+			t.Fatalf("expected family mark ttl 1h, got %v", cache.expirations[constant.CacheRevokedFamilyPrefix+"fid"])
+		}
+		revoked, _ := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, TokenId: "jti", SessionId: "sid", FamilyId: "fid", IssuedAt: issuedAt})
+		if !revoked {
+			t.Fatal("expected token revoked by family")
+		}
+		revoked, _ = service.IsRevoked(ctx, &TokenIdentity{UserId: 2, TokenId: "jti", SessionId: "sid", FamilyId: "other", IssuedAt: issuedAt})
+		if revoked {
+			t.Fatal("expected other family not revoked")
+		}
+	})
+
+	t.Run("user watermark", func(t *testing.T) {
+		cache := newFakeCache()
+		service := NewRevocationService(cache, 10*time.Minute, time.Hour)
+		if err := service.RevokeUserTokens(ctx, 2); err != nil {
+			t.Fatalf("RevokeUserTokens error: %v", err)
+		}
+		cacheKey := constant.CacheRevokedUserPrefix + "2"
+		if cache.expirations[cacheKey] != time.Hour {
+			t.Fatalf("expected watermark ttl 1h, got %v", cache.expirations[cacheKey])
+		}
+		revoked, _ := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, IssuedAt: issuedAt})
+		if !revoked {
+			t.Fatal("expected token issued before watermark revoked")
+		}
+		revoked, _ = service.IsRevoked(ctx, &TokenIdentity{UserId: 3, IssuedAt: issuedAt})
+		if revoked {
+			t.Fatal("expected other user not revoked")
+		}
+
+		cache.values[cacheKey] = strconv.FormatInt(issuedAt.UnixMilli(), 10)
+		revoked, _ = service.IsRevoked(ctx, &TokenIdentity{UserId: 2, IssuedAt: issuedAt})
+		if revoked {
+			t.Fatal("expected token issued at watermark valid")
+		}
+	})
+
+	t.Run("watermark millisecond precision", func(t *testing.T) {
+		cache := newFakeCache()
+		service := NewRevocationService(cache, 10*time.Minute, time.Hour)
+		before := time.Now()
+		if err := service.RevokeUserTokens(ctx, 2); err != nil {
+			t.Fatalf("RevokeUserTokens error: %v", err)
+		}
+		after := time.Now()
+		watermark, err := strconv.ParseInt(cache.values[constant.CacheRevokedUserPrefix+"2"], 10, 64)
+		if err != nil || watermark <= before.UnixMilli() || watermark > after.UnixMilli()+1 {
+			t.Fatalf("expected watermark one millisecond after revoke, got %d (before %d after %d)", watermark, before.UnixMilli(), after.UnixMilli())
+		}
+		// 吊销所在毫秒及之前签发的 token 失效，水位线所在毫秒起签发的 token 有效
+		revoked, _ := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, IssuedAt: before})
+		if !revoked {
+			t.Fatal("expected token issued just before revoke revoked")
+		}
+		revoked, _ = service.IsRevoked(ctx, &TokenIdentity{UserId: 2, IssuedAt: time.UnixMilli(watermark - 1)})
+		if !revoked {
+			t.Fatal("expected token issued in the revoke millisecond revoked")
+		}
+		revoked, _ = service.IsRevoked(ctx, &TokenIdentity{UserId: 2, IssuedAt: time.UnixMilli(watermark)})
+		if revoked {
+			t.Fatal("expected token issued from the watermark on valid")
+		}
+	})
+
+	t.Run("invalid watermark", func(t *testing.T) {
+		cache := newFakeCache()
+		service := NewRevocationService(cache, 10*time.Minute, time.Hour)
+		cache.values[constant.CacheRevokedUserPrefix+"2"] = "bad"
+		revoked, err := service.IsRevoked(ctx, &TokenIdentity{UserId: 2, IssuedAt: issuedAt})
+		if err != nil || revoked {
+			t.Fatalf("expected malformed watermark ignored, got revoked=%v err=%v", revoked, err)
+		}
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		service := NewRevocationService(newFakeCache(), 10*time.Minute, time.Hour)
+		if err := service.RevokeUserTokens(ctx, 0); err != ErrUserNotFound {
+			t.Fatalf("expected ErrUserNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSameInt32Set(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int32
+		want bool
+	}{
+		{"both empty", nil, []int32{}, true},
+		{"same order", []int32{1, 2}, []int32{1, 2}, true},
+		{"different order", []int32{2, 1}, []int32{1, 2}, true},
+		{"duplicate ids", []int32{1, 1, 2}, []int32{2, 1}, true},
+		{"added role", []int32{1}, []int32{1, 2}, false},
+		{"removed role", []int32{1, 2}, []int32{1}, false},
+		{"replaced role", []int32{1}, []int32{2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameInt32Set(tt.a, tt.b); got != tt.want {
+				t.Fatalf("sameInt32Set(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
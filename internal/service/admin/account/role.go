@@ -31,14 +31,24 @@ type RoleRepo interface {
 	GetRoleList(ctx context.Context, cond *account.RoleListCondition) ([]*model.SysRole, int64, error)
 	CreateRoleMenu(ctx context.Context, q *query.Query, roleMenuList []*model.SysRoleMenu) error
 	DeleteRoleMenu(ctx context.Context, q *query.Query, roleId int32) error
+	CreateRoleDept(ctx context.Context, q *query.Query, roleDeptList []*model.SysRoleDept) error
+	DeleteRoleDept(ctx context.Context, q *query.Query, roleId int32) error
+	GetDeptIdsByRoleIds(ctx context.Context, roleIds []int32) ([]int32, error)
 	IsUsedUserByIds(ctx context.Context, q *query.Query, userId int32) (bool, error)
 	CountMenuByIds(ctx context.Context, q *query.Query, ids []int32) (int64, error)
 	GetMenuIdsByRoleId(ctx context.Context, roleId int32) ([]int32, error)
 	GetMenuPermsByRoleId(ctx context.Context, roleId int32) ([]string, error)
 	GetMenuPermsByRoleIds(ctx context.Context, roleIds []int32) ([]string, error)
-	GetMenuListByRoleId(ctx context.Context, roleId int32) ([]*model.SysMenu, error)
+	GetMenuListByRoleIds(ctx context.Context, roleIds []int32) ([]*model.SysMenu, error)
 	ListRoleMenuPerms(ctx context.Context) ([]*account.RoleMenuPerm, error)
-	GetUserMenuIds(ctx context.Context, q *query.Query, userId int32) ([]int32, error)
+	GetRoleIdsByUserId(ctx context.Context, q *query.Query, userId int32) ([]int32, error)
+	GetMenuIdsByRoleIds(ctx context.Context, q *query.Query, roleIds []int32) ([]int32, error)
+	CountRoleByIds(ctx context.Context, q *query.Query, ids []int32) (int64, error)
+	CreateRoleInherit(ctx context.Context, q *query.Query, inheritList []*model.SysRoleInherit) error
+	DeleteRoleInherit(ctx context.Context, q *query.Query, roleId int32) error
+	IsInherited(ctx context.Context, q *query.Query, roleId int32) (bool, error)
+	GetAllRoleInherits(ctx context.Context) ([]*model.SysRoleInherit, error)
+	LockRoleInherits(ctx context.Context, q *query.Query) ([]*model.SysRoleInherit, error)
 	IsSuperAdmin(ctx context.Context, q *query.Query, userId int32) (bool, error)
 }
 
@@ -47,11 +57,13 @@ type RoleService struct {
 	roleDao    RoleRepo
 	cache      xcache.Cache
 	logService contract.OperationLogWriter
+	permCache  *PermCache
 }
 
 // NewRoleService 构造函数
-func NewRoleService(db *repo.Repository, roleDao RoleRepo, cache xcache.Cache, logService contract.OperationLogWriter) *RoleService {
-	return &RoleService{db: db, roleDao: roleDao, cache: cache, logService: logService}
+func NewRoleService(db *repo.Repository, roleDao RoleRepo, cache xcache.Cache, logService contract.OperationLogWriter,
+	permCache *PermCache) *RoleService {
+	return &RoleService{db: db, roleDao: roleDao, cache: cache, logService: logService, permCache: permCache}
 }
 
 // RoleParam 创建或更新角色输入
@@ -60,7 +72,11 @@ type RoleParam struct {
 	Name        string  `json:"name" binding:"required,max=128"`
 	Code        string  `json:"code" binding:"required,max=64"`
 	Description string  `json:"description"`
+	RequireMfa  bool    `json:"require_mfa"` // 是否强制该角色用户开启两步验证
 	MenuIds     []int32 `json:"menu_ids" binding:"required"`
+	DataScope   int8    `json:"data_scope" binding:"omitempty,oneof=1 2 3 4 5"` // 数据范围，创建时不传默认仅本人，更新时不传保持不变
+	DeptIds     []int32 `json:"dept_ids"`                                       // 自定义数据范围的部门，仅 data_scope=5 生效
+	ParentIds   []int32 `json:"parent_ids"`                                     // 继承的父角色，拥有父角色及其上级的全部菜单
 }
 
 // RoleInfo 返回给前端的角色信息
@@ -69,7 +85,11 @@ type RoleInfo struct {
 	Name        string    `json:"name"`
 	Code        string    `json:"code"`
 	Description string    `json:"description"`
+	RequireMfa  bool      `json:"require_mfa"`
+	DataScope   int8      `json:"data_scope"`
 	MenuIds     []int32   `json:"menu_ids"`
+	DeptIds     []int32   `json:"dept_ids"`
+	ParentIds   []int32   `json:"parent_ids"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -95,6 +115,10 @@ var (
 	ErrRoleIDInvalid         = e.NewBizError(e.RoleIDInvalid)
 	ErrRoleMenuNotExist      = e.NewBizError(e.RoleMenuNotExist)
 	ErrRoleMenuNotAuthorized = e.NewBizError(e.RoleMenuNotAuthorized)
+	ErrRoleDataScopeInvalid  = e.NewBizError(e.RoleDataScopeInvalid)
+	ErrRoleParentInvalid     = e.NewBizError(e.RoleParentInvalid)
+	ErrRoleParentCycle       = e.NewBizError(e.RoleParentCycle)
+	ErrRoleInherited         = e.NewBizError(e.RoleInherited)
 )
 
 // CreateRole 创建角色
@@ -114,10 +138,18 @@ func (s *RoleService) CreateRole(ctx context.Context, param *RoleParam) (int32,
 		return 0, ErrRoleCodeUsed
 	}
 
+	dataScope, deptIds, err := roleDataScopeValue(param, constant.DataScopeSelf)
+	if err != nil {
+		return 0, err
+	}
+	parentIds := roleParentIdsValue(param.ParentIds)
+	requireMfa := roleRequireMfaValue(param.RequireMfa)
 	role := &model.SysRole{
 		Name:        &param.Name,
 		Code:        param.Code,
 		Description: &param.Description,
+		RequireMfa:  &requireMfa,
+		DataScope:   &dataScope,
 	}
 
 	var roleObj *model.SysRole
@@ -133,18 +165,22 @@ func (s *RoleService) CreateRole(ctx context.Context, param *RoleParam) (int32,
 			if menuLen != int64(len(param.MenuIds)) {
 				return ErrRoleMenuNotExist
 			}
+		}
 
-			// 校验操作者是否有权限分配这些菜单
-			operatorMenuIds, err := s.roleDao.GetUserMenuIds(ctx, tx, userContext.UserId)
-			if err != nil {
-				xlogger.ErrorfCtx(ctx, "获取操作者菜单权限异常: %v", err)
-				return fmt.Errorf("校验操作者菜单权限失败: %w", err)
-			}
-			for _, id := range param.MenuIds {
-				if !slices.Contains(operatorMenuIds, id) {
-					return ErrRoleMenuNotAuthorized
-				}
-			}
+		// 校验父角色，加锁读取继承关系
+		edges, err := s.roleDao.LockRoleInherits(ctx, tx)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "获取角色继承关系异常: %v", err)
+			return fmt.Errorf("获取角色继承关系失败: %w", err)
+		}
+		graph := newRoleInheritGraph(edges)
+		if err := s.checkRoleParents(ctx, tx, graph, 0, parentIds); err != nil {
+			return err
+		}
+
+		// 校验操作者是否有权限分配这些菜单（含继承的父角色菜单）
+		if err := s.checkOperatorMenus(ctx, tx, graph, userContext.UserId, param.MenuIds, parentIds); err != nil {
+			return err
 		}
 
 		// 创建角色
@@ -174,6 +210,16 @@ func (s *RoleService) CreateRole(ctx context.Context, param *RoleParam) (int32,
 			}
 		}
 
+		// 创建角色自定义数据范围部门
+		if err = s.createRoleDept(ctx, tx, roleObj.ID, deptIds); err != nil {
+			return err
+		}
+
+		// 创建角色继承关系
+		if err = s.createRoleInherit(ctx, tx, roleObj.ID, parentIds); err != nil {
+			return err
+		}
+
 		// 创建操作日志
 		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
 			OperatorID:   userContext.UserId,
@@ -214,6 +260,13 @@ func (s *RoleService) UpdateRole(ctx context.Context, param *RoleParam) error {
 	if param.ID <= 0 {
 		return ErrRoleIDInvalid
 	}
+	// 未传数据范围时保持原值，不重建自定义部门
+	keepDataScope := param.DataScope == 0
+	dataScope, deptIds, err := roleDataScopeValue(param, 0)
+	if err != nil {
+		return err
+	}
+	parentIds := roleParentIdsValue(param.ParentIds)
 	// 校验 code 是否重复（事务外快速失败，排除当前 role.ID）
 	isDuplicate, err := s.roleDao.IsCodeExists(ctx, param.Code, param.ID)
 	if err != nil {
@@ -227,9 +280,13 @@ func (s *RoleService) UpdateRole(ctx context.Context, param *RoleParam) error {
 	if err != nil {
 		return ErrRoleNotFound
 	}
+	if keepDataScope {
+		dataScope = common.DerefOrZero(oldRole.DataScope)
+	}
 
 	// 事务内更新角色，以及关联菜单权限
 	var ruleObj *model.SysRole
+	var edges []*model.SysRoleInherit
 	err = s.db.WriteQuery().Transaction(func(tx *query.Query) error {
 		// 校验菜单id是否都存在
 		if len(param.MenuIds) > 0 {
@@ -241,33 +298,42 @@ func (s *RoleService) UpdateRole(ctx context.Context, param *RoleParam) error {
 			if menuLen != int64(len(param.MenuIds)) {
 				return ErrRoleMenuNotExist
 			}
+		}
+
+		// 校验父角色，加锁读取继承关系，串行化并发修改，避免各自校验通过后形成环
+		edges, err = s.roleDao.LockRoleInherits(ctx, tx)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "获取角色继承关系异常: %v", err)
+			return fmt.Errorf("获取角色继承关系失败: %w", err)
+		}
+		graph := newRoleInheritGraph(edges)
+		if err := s.checkRoleParents(ctx, tx, graph, param.ID, parentIds); err != nil {
+			return err
+		}
 
-			// 校验操作者是否有权限分配这些菜单（超级管理员跳过）
+		// 校验操作者是否有权限分配这些菜单（含继承的父角色菜单，超级管理员跳过）
+		if len(param.MenuIds) > 0 || len(parentIds) > 0 {
 			isSuperAdmin, err := s.roleDao.IsSuperAdmin(ctx, tx, userContext.UserId)
 			if err != nil {
 				xlogger.ErrorfCtx(ctx, "判断操作者是否为超级管理员异常: %v", err)
 				return fmt.Errorf("判断操作者身份失败: %w", err)
 			}
 			if !isSuperAdmin {
-				operatorMenuIds, err := s.roleDao.GetUserMenuIds(ctx, tx, userContext.UserId)
-				if err != nil {
-					xlogger.ErrorfCtx(ctx, "获取操作者菜单权限异常: %v", err)
-					return fmt.Errorf("校验操作者菜单权限失败: %w", err)
-				}
-				for _, id := range param.MenuIds {
-					if !slices.Contains(operatorMenuIds, id) {
-						return ErrRoleMenuNotAuthorized
-					}
+				if err := s.checkOperatorMenus(ctx, tx, graph, userContext.UserId, param.MenuIds, parentIds); err != nil {
+					return err
 				}
 			}
 		}
 
 		// 更新字段
+		requireMfa := roleRequireMfaValue(param.RequireMfa)
 		ruleObj, err = s.roleDao.UpdateRole(ctx, tx, &model.SysRole{
 			ID:          param.ID,
 			Name:        &param.Name,
 			Code:        param.Code,
 			Description: &param.Description,
+			RequireMfa:  &requireMfa,
+			DataScope:   &dataScope,
 		})
 		if err != nil {
 			// 唯一索引冲突兜底
@@ -300,6 +366,28 @@ func (s *RoleService) UpdateRole(ctx context.Context, param *RoleParam) error {
 			}
 		}
 
+		// 重建角色自定义数据范围部门
+		if !keepDataScope {
+			err = s.roleDao.DeleteRoleDept(ctx, tx, param.ID)
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "角色与部门关联关系删除失败: %v", err)
+				return fmt.Errorf("角色与部门关联关系删除失败: %w", err)
+			}
+			if err = s.createRoleDept(ctx, tx, param.ID, deptIds); err != nil {
+				return err
+			}
+		}
+
+		// 重建角色继承关系
+		err = s.roleDao.DeleteRoleInherit(ctx, tx, param.ID)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "角色继承关系删除失败: %v", err)
+			return fmt.Errorf("角色继承关系删除失败: %w", err)
+		}
+		if err = s.createRoleInherit(ctx, tx, param.ID, parentIds); err != nil {
+			return err
+		}
+
 		// 创建操作日志
 		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
 			OperatorID:   userContext.UserId,
@@ -328,11 +416,9 @@ func (s *RoleService) UpdateRole(ctx context.Context, param *RoleParam) error {
 
 	xlogger.InfofCtx(ctx, "角色更新成功: old=%+v new=%+v", oldRole, ruleObj)
 
-	// 清除角色对应接口权限缓存
-	cacheKey := fmt.Sprintf("%s%d", constant.CacheRoleMenuPrefix, param.ID)
-	if _, err := s.cache.Delete(ctx, cacheKey); err != nil {
-		xlogger.ErrorfCtx(ctx, "清除角色对应接口权限缓存失败: %v", err)
-	}
+	// 清除角色及继承它的下级角色的接口权限缓存（本次只修改了当前角色的父角色，事务内读取的下级关系仍然有效）
+	clearRoleMenuCache(ctx, s.cache, edges, param.ID)
+	s.permCache.InvalidateAll(ctx)
 
 	return nil
 }
@@ -370,6 +456,15 @@ func (s *RoleService) DeleteRole(ctx context.Context, id int32) error {
 			return ErrRoleUsed
 		}
 
+		// 检查角色是否被其他角色继承
+		isInherited, err := s.roleDao.IsInherited(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("检查角色继承情况失败: %w", err)
+		}
+		if isInherited {
+			return ErrRoleInherited
+		}
+
 		// 删除角色
 		err = s.roleDao.DeleteById(ctx, tx, id)
 		if err != nil {
@@ -384,6 +479,20 @@ func (s *RoleService) DeleteRole(ctx context.Context, id int32) error {
 			return fmt.Errorf("角色与菜单关联关系删除失败: %w", err)
 		}
 
+		// 删除角色自定义数据范围部门
+		err = s.roleDao.DeleteRoleDept(ctx, tx, id)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "角色与部门关联关系删除失败: %v", err)
+			return fmt.Errorf("角色与部门关联关系删除失败: %w", err)
+		}
+
+		// 删除角色继承关系
+		err = s.roleDao.DeleteRoleInherit(ctx, tx, id)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "角色继承关系删除失败: %v", err)
+			return fmt.Errorf("角色继承关系删除失败: %w", err)
+		}
+
 		// 创建操作日志
 		err = s.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
 			OperatorID:   userContext.UserId,
@@ -411,11 +520,9 @@ func (s *RoleService) DeleteRole(ctx context.Context, id int32) error {
 	}
 	xlogger.InfofCtx(ctx, "角色删除成功: %d", id)
 
-	// 清除角色对应接口权限缓存
-	cacheKey := fmt.Sprintf("%s%d", constant.CacheRoleMenuPrefix, id)
-	if _, err := s.cache.Delete(ctx, cacheKey); err != nil {
-		xlogger.ErrorfCtx(ctx, "清除角色对应接口权限缓存失败: %v", err)
-	}
+	// 清除角色对应接口权限缓存，被继承的角色不允许删除，无需级联
+	clearRoleMenuCache(ctx, s.cache, nil, id)
+	s.permCache.InvalidateAll(ctx)
 
 	return nil
 }
@@ -441,12 +548,30 @@ func (s *RoleService) GetRoleById(ctx context.Context, id int32) (*RoleInfo, err
 		xlogger.ErrorfCtx(ctx, "获取关联的菜单id列表失败: %v", err)
 		return nil, fmt.Errorf("获取关联的菜单id列表失败: %w", err)
 	}
+
+	// 获取role自定义数据范围的部门ids
+	deptIds, err := s.roleDao.GetDeptIdsByRoleIds(ctx, []int32{id})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取关联的部门id列表失败: %v", err)
+		return nil, fmt.Errorf("获取关联的部门id列表失败: %w", err)
+	}
+
+	// 获取role直接继承的父角色
+	edges, err := s.roleDao.GetAllRoleInherits(ctx)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取角色继承关系失败: %v", err)
+		return nil, fmt.Errorf("获取角色继承关系失败: %w", err)
+	}
 	return &RoleInfo{
 		ID:          r.ID,
 		Name:        common.DerefOrZero(r.Name),
 		Code:        r.Code,
 		Description: common.DerefOrZero(r.Description),
+		RequireMfa:  common.DerefOrZero(r.RequireMfa) == constant.RoleRequireMfa,
+		DataScope:   common.DerefOrZero(r.DataScope),
 		MenuIds:     menuIds,
+		DeptIds:     deptIds,
+		ParentIds:   newRoleInheritGraph(edges).parentIds(id),
 		CreatedAt:   common.DerefOrZero(r.CreatedAt),
 		UpdatedAt:   common.DerefOrZero(r.UpdatedAt),
 	}, nil
@@ -473,6 +598,8 @@ func (s *RoleService) ListRoles(ctx context.Context, cond *RoleListCondition) (*
 			Name:        common.DerefOrZero(r.Name),
 			Code:        r.Code,
 			Description: common.DerefOrZero(r.Description),
+			RequireMfa:  common.DerefOrZero(r.RequireMfa) == constant.RoleRequireMfa,
+			DataScope:   common.DerefOrZero(r.DataScope),
 			CreatedAt:   common.DerefOrZero(r.CreatedAt),
 			UpdatedAt:   common.DerefOrZero(r.UpdatedAt),
 		})
@@ -507,6 +634,10 @@ func (s *RoleService) GetRolePermsListByRuleID(ctx context.Context, roleId int32
 	if roleId <= 0 {
 		return nil, ErrRoleIDInvalid
 	}
+	// 超级管理员通过单个通配授权拥有全部接口权限
+	if roleId == constant.SuperAdminRoleId {
+		return []string{constant.PermAll}, nil
+	}
 	menuList, err := s.GetRoleMenuListByRuleID(ctx, roleId)
 	if err != nil {
 		return nil, err
@@ -520,7 +651,7 @@ func (s *RoleService) GetRolePermsListByRuleID(ctx context.Context, roleId int32
 	return perms, nil
 }
 
-// GetRoleMenuListByRuleID 获取角色对应菜单列表
+// GetRoleMenuListByRuleID 获取角色对应菜单列表，包含继承自上级角色的菜单
 func (s *RoleService) GetRoleMenuListByRuleID(ctx context.Context, roleId int32) ([]*MenuData, error) {
 	// 尝试从缓存读取
 	cacheKey := fmt.Sprintf("%s%d", constant.CacheRoleMenuPrefix, roleId)
@@ -531,15 +662,28 @@ func (s *RoleService) GetRoleMenuListByRuleID(ctx context.Context, roleId int32)
 		}
 	}
 
-	// 获取roleId: perms数组
-	menuList, err := s.roleDao.GetMenuListByRoleId(ctx, roleId)
+	// 获取角色及其全部上级角色
+	edges, err := s.roleDao.GetAllRoleInherits(ctx)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "list role inherit is err: %v", err)
+		return nil, err
+	}
+	roleIds := newRoleInheritGraph(edges).withAncestors(roleId)
+
+	// 获取roleIds: 菜单数组，多个角色关联同一菜单时去重
+	menuList, err := s.roleDao.GetMenuListByRoleIds(ctx, roleIds)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "list role menu is err: %v", err)
 		return nil, err
 	}
 
 	menus := make([]*MenuData, 0, len(menuList))
+	menuSet := make(map[int32]struct{}, len(menuList))
 	for _, m := range menuList {
+		if _, ok := menuSet[m.ID]; ok {
+			continue
+		}
+		menuSet[m.ID] = struct{}{}
 		menus = append(menus, &MenuData{
 			ID:        m.ID,
 			ParentID:  m.ParentID,
@@ -562,10 +706,81 @@ func (s *RoleService) GetRoleMenuListByRuleID(ctx context.Context, roleId int32)
 	return menus, nil
 }
 
-// GetRolePermsListByRuleIds 批量获取多个角色的接口权限列表
+// GetRolePermsListByRuleIds 批量获取多个角色的接口权限列表，包含继承自上级角色的权限
 func (s *RoleService) GetRolePermsListByRuleIds(ctx context.Context, roleIds []int32) ([]string, error) {
 	if len(roleIds) == 0 {
 		return nil, nil
 	}
-	return s.roleDao.GetMenuPermsByRoleIds(ctx, roleIds)
+	// 与单角色一致，含超级管理员时只返回通配授权
+	if slices.Contains(roleIds, constant.SuperAdminRoleId) {
+		return []string{constant.PermAll}, nil
+	}
+	edges, err := s.roleDao.GetAllRoleInherits(ctx)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "list role inherit is err: %v", err)
+		return nil, err
+	}
+	return s.roleDao.GetMenuPermsByRoleIds(ctx, newRoleInheritGraph(edges).withAncestors(roleIds...))
+}
+
+// GetRoleDeptIdsByRuleIds 批量获取角色自定义数据范围的部门id
+func (s *RoleService) GetRoleDeptIdsByRuleIds(ctx context.Context, roleIds []int32) ([]int32, error) {
+	if len(roleIds) == 0 {
+		return nil, nil
+	}
+	return s.roleDao.GetDeptIdsByRoleIds(ctx, roleIds)
+}
+
+// createRoleDept 创建角色与自定义数据范围部门关联关系
+func (s *RoleService) createRoleDept(ctx context.Context, tx *query.Query, roleId int32, deptIds []int32) error {
+	if len(deptIds) == 0 {
+		return nil
+	}
+	roleDeptList := make([]*model.SysRoleDept, 0, len(deptIds))
+	for _, deptId := range deptIds {
+		roleDeptList = append(roleDeptList, &model.SysRoleDept{
+			RoleID: roleId,
+			DeptID: deptId,
+		})
+	}
+	if err := s.roleDao.CreateRoleDept(ctx, tx, roleDeptList); err != nil {
+		xlogger.ErrorfCtx(ctx, "角色与部门关联关系创建失败: %v", err)
+		return fmt.Errorf("角色与部门关联关系创建失败: %w", err)
+	}
+	return nil
+}
+
+// roleDataScopeValue 数据范围转换为db存储值，未指定时使用 defaultScope，仅自定义范围保留部门
+func roleDataScopeValue(param *RoleParam, defaultScope int8) (int8, []int32, error) {
+	switch param.DataScope {
+	case 0:
+		return defaultScope, nil, nil
+	case constant.DataScopeCustom:
+		if len(param.DeptIds) == 0 {
+			return 0, nil, ErrRoleDataScopeInvalid
+		}
+		deptIds := slices.Clone(param.DeptIds)
+		slices.Sort(deptIds)
+		return param.DataScope, slices.Compact(deptIds), nil
+	default:
+		return param.DataScope, nil, nil
+	}
+}
+
+// roleParentIdsValue 父角色去重
+func roleParentIdsValue(parentIds []int32) []int32 {
+	if len(parentIds) == 0 {
+		return nil
+	}
+	ids := slices.Clone(parentIds)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// roleRequireMfaValue 强制两步验证标识转换为db存储值
+func roleRequireMfaValue(requireMfa bool) int8 {
+	if requireMfa {
+		return constant.RoleRequireMfa
+	}
+	return 0
 }
@@ -0,0 +1,227 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/service/admin/contract"
+	"snowgo/pkg/xauth"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlock"
+	"snowgo/pkg/xlogger"
+	"snowgo/pkg/xtrace"
+)
+
+const (
+	// roleGrantSweepOperatorName 清理过期角色授权写操作日志时的操作人名称
+	roleGrantSweepOperatorName = "role-grant-sweep"
+	// roleGrantSweepBatch 每批清理的过期授权数
+	roleGrantSweepBatch = 500
+)
+
+var ErrRoleGrantInvalid = e.NewBizError(e.UserRoleGrantInvalid)
+
+// RoleGrantParam 限时角色授权，例如临时值班管理员
+type RoleGrantParam struct {
+	RoleId     int32  `json:"role_id" binding:"required"`
+	ValidFrom  string `json:"valid_from"`  // 生效时间 yyyy-MM-dd HH:mm:ss，为空表示立即生效
+	ValidUntil string `json:"valid_until"` // 失效时间 yyyy-MM-dd HH:mm:ss，为空表示永久有效
+}
+
+// buildUserRoles 合并永久角色与限时授权为用户角色关联（不含 UserID），同一角色只能授权一次
+// 失效时间必须晚于当前时间和生效时间
+func buildUserRoles(roleIds []int32, grants []*RoleGrantParam, now time.Time) ([]*model.SysUserRole, error) {
+	userRoles := make([]*model.SysUserRole, 0, len(roleIds)+len(grants))
+	seen := make(map[int32]struct{}, cap(userRoles))
+	for _, roleId := range roleIds {
+		seen[roleId] = struct{}{}
+		userRoles = append(userRoles, &model.SysUserRole{RoleID: roleId})
+	}
+	for _, grant := range grants {
+		if grant == nil {
+			return nil, ErrRoleGrantInvalid
+		}
+		if _, ok := seen[grant.RoleId]; ok {
+			return nil, ErrRoleGrantInvalid
+		}
+		seen[grant.RoleId] = struct{}{}
+		validFrom, err := parseGrantTime(grant.ValidFrom)
+		if err != nil {
+			return nil, err
+		}
+		validUntil, err := parseGrantTime(grant.ValidUntil)
+		if err != nil {
+			return nil, err
+		}
+		if validUntil != nil && (!validUntil.After(now) || (validFrom != nil && !validUntil.After(*validFrom))) {
+			return nil, ErrRoleGrantInvalid
+		}
+		userRoles = append(userRoles, &model.SysUserRole{
+			RoleID:     grant.RoleId,
+			ValidFrom:  validFrom,
+			ValidUntil: validUntil,
+		})
+	}
+	return userRoles, nil
+}
+
+// parseGrantTime 解析授权有效期，为空返回 nil
+func parseGrantTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(constant.TimeFmtWithS, value, time.Local)
+	if err != nil {
+		return nil, ErrRoleGrantInvalid
+	}
+	return &t, nil
+}
+
+// userRoleIds 用户角色关联中的角色id
+func userRoleIds(userRoles []*model.SysUserRole) []int32 {
+	roleIds := make([]int32, 0, len(userRoles))
+	for _, ur := range userRoles {
+		roleIds = append(roleIds, ur.RoleID)
+	}
+	return roleIds
+}
+
+// activeRoleIds 返回 now 时刻生效的角色id，以及之后最近一次授权生效或失效的时间（零值表示不会再变化）
+func activeRoleIds(grants []*model.SysUserRole, now time.Time) ([]int32, time.Time) {
+	roleIds := make([]int32, 0, len(grants))
+	var next time.Time
+	earlier := func(t *time.Time) {
+		if t != nil && t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = *t
+		}
+	}
+	for _, grant := range grants {
+		earlier(grant.ValidFrom)
+		earlier(grant.ValidUntil)
+		if grant.ValidFrom != nil && grant.ValidFrom.After(now) {
+			continue
+		}
+		if grant.ValidUntil != nil && !grant.ValidUntil.After(now) {
+			continue
+		}
+		roleIds = append(roleIds, grant.RoleID)
+	}
+	return roleIds, next
+}
+
+// userRoleCacheTTL 用户角色缓存有效期，不超过下一次授权生效或失效的时间
+func userRoleCacheTTL(now, next time.Time) time.Duration {
+	ttl := constant.CacheUserRoleExpirationDay * 24 * time.Hour
+	if !next.IsZero() && next.Sub(now) < ttl {
+		ttl = next.Sub(now)
+	}
+	return ttl
+}
+
+// SweepExpiredRoleGrants 删除已过期的限时角色授权，每个用户写一条系统操作日志并清理其角色与权限缓存，返回删除的授权数
+func (u *UserService) SweepExpiredRoleGrants(ctx context.Context) (int, error) {
+	traceCtx := xauth.GetContext(ctx)
+	total := 0
+	for {
+		now := time.Now()
+		var userIds []int32
+		var swept int
+		err := u.db.WriteQuery().Transaction(func(tx *query.Query) error {
+			expired, err := u.userDao.GetExpiredUserRoles(ctx, tx, now, roleGrantSweepBatch)
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "获取过期角色授权失败: %v", err)
+				return fmt.Errorf("获取过期角色授权失败: %w", err)
+			}
+			swept = len(expired)
+			if swept == 0 {
+				return nil
+			}
+			ids := make([]int64, 0, len(expired))
+			byUser := make(map[int32][]*model.SysUserRole)
+			for _, grant := range expired {
+				ids = append(ids, grant.ID)
+				if _, ok := byUser[grant.UserID]; !ok {
+					userIds = append(userIds, grant.UserID)
+				}
+				byUser[grant.UserID] = append(byUser[grant.UserID], grant)
+			}
+			if err := u.userDao.DeleteUserRoleByIds(ctx, tx, ids); err != nil {
+				xlogger.ErrorfCtx(ctx, "删除过期角色授权失败: %v", err)
+				return fmt.Errorf("删除过期角色授权失败: %w", err)
+			}
+
+			for _, userId := range userIds {
+				grants := byUser[userId]
+				err = u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
+					OperatorName: roleGrantSweepOperatorName,
+					OperatorType: constant.OperatorSystem,
+					Resource:     constant.ResourceUser,
+					ResourceID:   int64(userId),
+					TraceID:      traceCtx.TraceId,
+					Action:       constant.ActionUpdate,
+					BeforeData:   grants,
+					AfterData:    nil,
+					Description:  fmt.Sprintf("系统清理了用户(%d)已过期的角色授权，角色%v", userId, userRoleIds(grants)),
+					IP:           traceCtx.IP,
+				})
+				if err != nil {
+					xlogger.ErrorfCtx(ctx, "操作日志创建失败 resource=user resource_id=%d err: %v", userId, err)
+					return fmt.Errorf("操作日志创建失败: %w", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += swept
+
+		// 清除用户对应角色缓存
+		if len(userIds) > 0 {
+			keys := make([]string, 0, len(userIds))
+			for _, userId := range userIds {
+				keys = append(keys, fmt.Sprintf("%s%d", constant.CacheUserRolePrefix, userId))
+			}
+			if _, err := u.cache.Delete(ctx, keys...); err != nil {
+				xlogger.ErrorfCtx(ctx, "清除用户对应角色缓存失败: %v", err)
+			}
+			u.permCache.InvalidateUsers(ctx, userIds...)
+			xlogger.InfofCtx(ctx, "清理过期角色授权 %d 条 user_ids=%v", swept, userIds)
+		}
+		if swept < roleGrantSweepBatch {
+			return total, nil
+		}
+	}
+}
+
+// RunRoleGrantSweep 按 interval 定期清理过期角色授权直到 ctx 取消，多实例部署时通过分布式锁只由一个实例执行
+func (u *UserService) RunRoleGrantSweep(ctx context.Context, lock xlock.Lock, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	expireSecond := max(int64(interval/time.Second), 1)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sweepCtx := xtrace.NewContextWithTrace(ctx, strings.ReplaceAll(uuid.New().String(), "-", ""))
+		err := lock.TryLock(sweepCtx, constant.LockRoleGrantSweep, expireSecond, func(isLock bool, _ xlock.LockContext) error {
+			if !isLock {
+				return nil
+			}
+			_, err := u.SweepExpiredRoleGrants(sweepCtx)
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			xlogger.ErrorfCtx(sweepCtx, "清理过期角色授权失败: %v", err)
+		}
+	}
+}
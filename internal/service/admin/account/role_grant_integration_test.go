@@ -0,0 +1,99 @@
+//go:build integration
+
+package account
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+func TestUserServiceRoleGrantIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	oncall := insertIntegrationRole(t, db, "it_oncall", "值班管理员")
+	readonly := insertIntegrationRole(t, db, "it_readonly", "只读")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+
+	service := newIntegrationUserService(deps)
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	userID, err := service.CreateUser(testUserCtx(), &UserParam{
+		Username: "it_grant_user",
+		Password: "abc123!",
+		Tel:      "18000000001",
+		RoleIds:  []int32{readonly.ID},
+		RoleGrants: []*RoleGrantParam{
+			{RoleId: oncall.ID, ValidUntil: until.Format(constant.TimeFmtWithS)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateUser expected success, got %v", err)
+	}
+	count := countRows(t, db, model.TableNameSysUserRole, "user_id = ? AND role_id = ? AND valid_until IS NOT NULL", userID, oncall.ID)
+	if count != 1 {
+		t.Fatalf("expected time-bound grant stored, got count %d", count)
+	}
+
+	roleIds, err := service.GetRoleIdsByUserId(testUserCtx(), userID)
+	if err != nil || !equalInt32s(roleIds, []int32{readonly.ID, oncall.ID}) {
+		t.Fatalf("expected active grants, got %v %v", roleIds, err)
+	}
+	cacheKey := fmt.Sprintf("%s%d", constant.CacheUserRolePrefix, userID)
+	ttl, err := deps.rdb.TTL(testUserCtx(), cacheKey).Result()
+	if err != nil || ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("expected user role cache ttl capped by grant expiry, got %v %v", ttl, err)
+	}
+	info, err := service.GetUserById(testUserCtx(), userID)
+	if err != nil || len(info.RoleList) != 2 || info.RoleList[1].ValidUntil == nil {
+		t.Fatalf("expected grant window in user info, got %+v %v", info, err)
+	}
+
+	// 模拟授权到期：过期授权立即不再生效，清理后删除并记录系统操作日志
+	if err := db.Model(&model.SysUserRole{}).Where("user_id = ? AND role_id = ?", userID, oncall.ID).
+		Update("valid_until", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("expire grant: %v", err)
+	}
+	if _, err := deps.cache.Delete(testUserCtx(), cacheKey); err != nil {
+		t.Fatalf("clear user role cache: %v", err)
+	}
+	roleIds, err = service.GetRoleIdsByUserId(testUserCtx(), userID)
+	if err != nil || !equalInt32s(roleIds, []int32{readonly.ID}) {
+		t.Fatalf("expected expired grant excluded, got %v %v", roleIds, err)
+	}
+
+	swept, err := service.SweepExpiredRoleGrants(testUserCtx())
+	if err != nil || swept != 1 {
+		t.Fatalf("SweepExpiredRoleGrants expected 1 swept, got %d %v", swept, err)
+	}
+	if count := countRows(t, db, model.TableNameSysUserRole, "user_id = ?", userID); count != 1 {
+		t.Fatalf("expected only permanent grant left, got count %d", count)
+	}
+	operationLog := queryOperationLog(t, db, constant.ResourceUser, int64(userID), constant.ActionUpdate)
+	if operationLog.OperatorType == nil || *operationLog.OperatorType != constant.OperatorSystem {
+		t.Fatalf("expected system operation log, got %+v", operationLog.OperatorType)
+	}
+	if _, ok, err := deps.cache.Get(testUserCtx(), cacheKey); err != nil {
+		t.Fatalf("get user role cache: %v", err)
+	} else if ok {
+		t.Fatalf("expected user role cache %q to be invalidated", cacheKey)
+	}
+	if swept, err := service.SweepExpiredRoleGrants(testUserCtx()); err != nil || swept != 0 {
+		t.Fatalf("expected nothing left to sweep, got %d %v", swept, err)
+	}
+
+	// 已过期的授权不允许设置
+	_, err = service.UpdateUser(testUserCtx(), &UserParam{
+		ID: userID, Username: "it_grant_user", Tel: "18000000001",
+		RoleGrants: []*RoleGrantParam{{RoleId: oncall.ID, ValidUntil: time.Now().Add(-time.Hour).Format(constant.TimeFmtWithS)}},
+	})
+	if !errors.Is(err, ErrRoleGrantInvalid) {
+		t.Fatalf("UpdateUser expired grant expected ErrRoleGrantInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package account
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	daoAccount "snowgo/internal/dao/admin/account"
+)
+
+func TestBuildUserRoles(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+
+	t.Run("merge permanent and time-bound", func(t *testing.T) {
+		got, err := buildUserRoles([]int32{2}, []*RoleGrantParam{
+			{RoleId: 3, ValidFrom: "2026-10-02 00:00:00", ValidUntil: "2026-10-09 00:00:00"},
+			{RoleId: 4, ValidUntil: "2026-10-01 13:00:00"},
+		}, now)
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if !slices.Equal(userRoleIds(got), []int32{2, 3, 4}) {
+			t.Fatalf("expected merged role ids, got %v", userRoleIds(got))
+		}
+		if got[0].ValidFrom != nil || got[0].ValidUntil != nil {
+			t.Fatalf("expected permanent grant, got %+v", got[0])
+		}
+		if got[1].ValidFrom == nil || got[1].ValidFrom.Day() != 2 || got[1].ValidUntil.Day() != 9 {
+			t.Fatalf("expected parsed window, got %+v", got[1])
+		}
+		if got[2].ValidFrom != nil || got[2].ValidUntil == nil {
+			t.Fatalf("expected open start, got %+v", got[2])
+		}
+	})
+
+	tests := []struct {
+		name   string
+		grants []*RoleGrantParam
+	}{
+		{"duplicate with permanent", []*RoleGrantParam{{RoleId: 2, ValidUntil: "2026-10-09 00:00:00"}}},
+		{"duplicate grant", []*RoleGrantParam{{RoleId: 3}, {RoleId: 3}}},
+		{"already expired", []*RoleGrantParam{{RoleId: 3, ValidUntil: "2026-10-01 11:00:00"}}},
+		{"until before from", []*RoleGrantParam{{RoleId: 3, ValidFrom: "2026-10-05 00:00:00", ValidUntil: "2026-10-04 00:00:00"}}},
+		{"bad format", []*RoleGrantParam{{RoleId: 3, ValidUntil: "2026/10/09"}}},
+		{"nil grant", []*RoleGrantParam{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildUserRoles([]int32{2}, tt.grants, now); !errors.Is(err, ErrRoleGrantInvalid) {
+				t.Fatalf("expected ErrRoleGrantInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestActiveRoleIds(t *testing.T) {
+	now := time.Now()
+	past, soon, later := now.Add(-time.Hour), now.Add(time.Hour), now.Add(48*time.Hour)
+	grants := []*model.SysUserRole{
+		{RoleID: 2},
+		{RoleID: 3, ValidUntil: &later},
+		{RoleID: 4, ValidFrom: &soon},
+		{RoleID: 5, ValidUntil: &past},
+		{RoleID: 6, ValidFrom: &past, ValidUntil: &later},
+	}
+
+	roleIds, next := activeRoleIds(grants, now)
+	if !slices.Equal(roleIds, []int32{2, 3, 6}) {
+		t.Fatalf("expected active roles, got %v", roleIds)
+	}
+	if !next.Equal(soon) {
+		t.Fatalf("expected next change at pending grant start, got %v", next)
+	}
+	if ttl := userRoleCacheTTL(now, next); ttl != time.Hour {
+		t.Fatalf("expected ttl capped to next change, got %v", ttl)
+	}
+
+	_, next = activeRoleIds([]*model.SysUserRole{{RoleID: 2}}, now)
+	if ttl := userRoleCacheTTL(now, next); ttl != constant.CacheUserRoleExpirationDay*24*time.Hour {
+		t.Fatalf("expected default ttl without time-bound grants, got %v", ttl)
+	}
+}
+
+func TestUserServiceGetRoleIdsByUserIdRespectsGrantWindow(t *testing.T) {
+	cacheKey := constant.CacheUserRolePrefix + "2"
+	now := time.Now()
+	past, until := now.Add(-time.Minute), now.Add(30*time.Minute)
+	cache := newFakeCache()
+	repo := &fakeUserRepo{roleGrants: []*model.SysUserRole{
+		{RoleID: 2},
+		{RoleID: 3, ValidUntil: &until},
+		{RoleID: 4, ValidUntil: &past},
+	}}
+	service := &UserService{userDao: repo, cache: cache}
+
+	got, err := service.GetRoleIdsByUserId(testUserCtx(), 2)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !equalInt32s(got, []int32{2, 3}) {
+		t.Fatalf("expected expired grant excluded, got %v", got)
+	}
+	if ttl := cache.expirations[cacheKey]; ttl <= 0 || ttl > 30*time.Minute {
+		t.Fatalf("expected cache ttl not to outlive earliest expiry, got %v", ttl)
+	}
+}
+
+func TestUserServiceGetUserPermissionByIdIgnoresInactiveGrants(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	perms := "account:user:list"
+	userRepo := &fakeUserRepo{
+		userById: &model.SysUser{ID: 2, Username: "alice"},
+		roleGrants: []*model.SysUserRole{
+			{RoleID: 3},
+			{RoleID: constant.SuperAdminRoleId, ValidUntil: &past},
+		},
+		roleGrantList: []*daoAccount.UserRoleGrant{
+			{SysRole: model.SysRole{ID: 3, Code: "viewer"}},
+			{SysRole: model.SysRole{ID: constant.SuperAdminRoleId, Code: "super_admin"}, ValidUntil: &past},
+		},
+	}
+	roleRepo := &fakeRoleRepo{menuList: []*model.SysMenu{{ID: 10, MenuType: constant.MenuTypeBtn, Name: "List", Perms: &perms}}}
+	service := &UserService{
+		userDao:     userRepo,
+		cache:       newFakeCache(),
+		roleService: &RoleService{roleDao: roleRepo, cache: newFakeCache()},
+	}
+
+	info, err := service.GetUserPermissionById(testUserCtx(), 2)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	// 已过期的授权仍然展示，但不参与权限计算
+	if len(info.RoleList) != 2 {
+		t.Fatalf("expected all grants displayed, got %d", len(info.RoleList))
+	}
+	if roleRepo.getMenuListCall != 1 || !slices.Equal(roleRepo.menuListRoleIds, []int32{3}) {
+		t.Fatalf("expected menus loaded for active role only, got %d calls %v", roleRepo.getMenuListCall, roleRepo.menuListRoleIds)
+	}
+	if len(info.Permissions) != 1 || info.Permissions[0].Perms != perms {
+		t.Fatalf("expected only active role perms without wildcard, got %+v", info.Permissions)
+	}
+}
@@ -0,0 +1,178 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xlogger"
+)
+
+// roleInheritGraph 角色继承关系图，角色可继承多个父角色，有效权限为自身及全部上级角色授权的并集
+type roleInheritGraph struct {
+	parents  map[int32][]int32
+	children map[int32][]int32
+}
+
+func newRoleInheritGraph(edges []*model.SysRoleInherit) *roleInheritGraph {
+	g := &roleInheritGraph{
+		parents:  make(map[int32][]int32, len(edges)),
+		children: make(map[int32][]int32, len(edges)),
+	}
+	for _, edge := range edges {
+		g.parents[edge.RoleID] = append(g.parents[edge.RoleID], edge.ParentID)
+		g.children[edge.ParentID] = append(g.children[edge.ParentID], edge.RoleID)
+	}
+	return g
+}
+
+// parentIds 角色直接继承的父角色
+func (g *roleInheritGraph) parentIds(roleId int32) []int32 {
+	ids := slices.Clone(g.parents[roleId])
+	slices.Sort(ids)
+	return ids
+}
+
+// withAncestors 返回 roleIds 及其全部上级角色
+func (g *roleInheritGraph) withAncestors(roleIds ...int32) []int32 {
+	return walkRoles(g.parents, roleIds)
+}
+
+// withDescendants 返回 roleIds 及继承它们的全部下级角色
+func (g *roleInheritGraph) withDescendants(roleIds ...int32) []int32 {
+	return walkRoles(g.children, roleIds)
+}
+
+// walkRoles 沿邻接表遍历，已访问的角色不重复展开，历史数据存在环时也能结束
+func walkRoles(adj map[int32][]int32, roleIds []int32) []int32 {
+	visited := make(map[int32]struct{}, len(roleIds))
+	queue := slices.Clone(roleIds)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+		queue = append(queue, adj[id]...)
+	}
+	ids := make([]int32, 0, len(visited))
+	for id := range visited {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// checkRoleParents 校验父角色：不能继承超级管理员和自身，父角色必须存在，且继承后不能形成环（roleId 为 0 表示新建）
+func (s *RoleService) checkRoleParents(ctx context.Context, tx *query.Query, graph *roleInheritGraph,
+	roleId int32, parentIds []int32) error {
+	if len(parentIds) == 0 {
+		return nil
+	}
+	for _, parentId := range parentIds {
+		if parentId <= 0 || parentId == constant.SuperAdminRoleId {
+			return ErrRoleParentInvalid
+		}
+		if parentId == roleId {
+			return ErrRoleParentCycle
+		}
+	}
+	count, err := s.roleDao.CountRoleByIds(ctx, tx, parentIds)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取父角色数量异常: %v", err)
+		return fmt.Errorf("校验父角色失败: %w", err)
+	}
+	if count != int64(len(parentIds)) {
+		return ErrRoleParentInvalid
+	}
+	// 当前角色出现在父角色的上级链路中即形成环
+	if roleId > 0 && slices.Contains(graph.withAncestors(parentIds...), roleId) {
+		return ErrRoleParentCycle
+	}
+	return nil
+}
+
+// createRoleInherit 创建角色与父角色的继承关系
+func (s *RoleService) createRoleInherit(ctx context.Context, tx *query.Query, roleId int32, parentIds []int32) error {
+	if len(parentIds) == 0 {
+		return nil
+	}
+	inheritList := make([]*model.SysRoleInherit, 0, len(parentIds))
+	for _, parentId := range parentIds {
+		inheritList = append(inheritList, &model.SysRoleInherit{
+			RoleID:   roleId,
+			ParentID: parentId,
+		})
+	}
+	if err := s.roleDao.CreateRoleInherit(ctx, tx, inheritList); err != nil {
+		xlogger.ErrorfCtx(ctx, "角色继承关系创建失败: %v", err)
+		return fmt.Errorf("角色继承关系创建失败: %w", err)
+	}
+	return nil
+}
+
+// userMenuIds 用户全部角色（含继承）关联的菜单id，用于校验操作者能否分配菜单
+// 超级管理员通过通配授权拥有全部菜单，不依赖角色菜单关联，此时 superAdmin 为 true
+func (s *RoleService) userMenuIds(ctx context.Context, tx *query.Query, graph *roleInheritGraph, userId int32) (menuIds []int32, superAdmin bool, err error) {
+	roleIds, err := s.roleDao.GetRoleIdsByUserId(ctx, tx, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	if slices.Contains(roleIds, constant.SuperAdminRoleId) {
+		return nil, true, nil
+	}
+	menuIds, err = s.roleDao.GetMenuIdsByRoleIds(ctx, tx, graph.withAncestors(roleIds...))
+	return menuIds, false, err
+}
+
+// checkOperatorMenus 操作者只能分配自己拥有的菜单，继承父角色等同于分配父角色（含其上级）的全部菜单
+func (s *RoleService) checkOperatorMenus(ctx context.Context, tx *query.Query, graph *roleInheritGraph,
+	userId int32, menuIds, parentIds []int32) error {
+	requested := slices.Clone(menuIds)
+	if len(parentIds) > 0 {
+		parentMenuIds, err := s.roleDao.GetMenuIdsByRoleIds(ctx, tx, graph.withAncestors(parentIds...))
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "获取父角色菜单权限异常: %v", err)
+			return fmt.Errorf("校验父角色菜单权限失败: %w", err)
+		}
+		requested = append(requested, parentMenuIds...)
+	}
+	if len(requested) == 0 {
+		return nil
+	}
+	operatorMenuIds, superAdmin, err := s.userMenuIds(ctx, tx, graph, userId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取操作者菜单权限异常: %v", err)
+		return fmt.Errorf("校验操作者菜单权限失败: %w", err)
+	}
+	if superAdmin {
+		return nil
+	}
+	for _, id := range requested {
+		if !slices.Contains(operatorMenuIds, id) {
+			return ErrRoleMenuNotAuthorized
+		}
+	}
+	return nil
+}
+
+// clearRoleMenuCache 角色菜单缓存保存的是含继承的有效菜单，清理时级联到全部下级角色
+// edges 获取失败时只能清理 roleIds 自身，下级角色依赖缓存过期
+func clearRoleMenuCache(ctx context.Context, cache xcache.Cache, edges []*model.SysRoleInherit, roleIds ...int32) {
+	if len(roleIds) == 0 {
+		return
+	}
+	ids := newRoleInheritGraph(edges).withDescendants(roleIds...)
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("%s%d", constant.CacheRoleMenuPrefix, id))
+	}
+	if _, err := cache.Delete(ctx, keys...); err != nil {
+		xlogger.ErrorfCtx(ctx, "清除角色对应接口权限缓存失败 roleIds=%v: %v", ids, err)
+	}
+}
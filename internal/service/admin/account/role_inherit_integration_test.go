@@ -0,0 +1,128 @@
+//go:build integration
+
+package account
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+func TestRoleServiceInheritIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+	listMenu := insertIntegrationMenu(t, db, 0, constant.MenuTypeBtn, "用户列表")
+	createMenu := insertIntegrationMenu(t, db, 0, constant.MenuTypeBtn, "创建用户")
+	if err := db.Model(listMenu).Update("perms", "account:user:list").Error; err != nil {
+		t.Fatalf("update menu perms: %v", err)
+	}
+	if err := db.Model(createMenu).Update("perms", "account:user:create").Error; err != nil {
+		t.Fatalf("update menu perms: %v", err)
+	}
+	insertIntegrationRoleMenu(t, db, constant.SuperAdminRoleId, listMenu.ID)
+	insertIntegrationRoleMenu(t, db, constant.SuperAdminRoleId, createMenu.ID)
+
+	service := newIntegrationRoleService(deps)
+	operatorID, err := service.CreateRole(testUserCtx(), &RoleParam{
+		Name: "运维", Code: "it_operator", MenuIds: []int32{listMenu.ID},
+	})
+	if err != nil {
+		t.Fatalf("CreateRole operator expected success, got %v", err)
+	}
+	seniorID, err := service.CreateRole(testUserCtx(), &RoleParam{
+		Name: "高级运维", Code: "it_senior", MenuIds: []int32{createMenu.ID}, ParentIds: []int32{operatorID},
+	})
+	if err != nil {
+		t.Fatalf("CreateRole senior expected success, got %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysRoleInherit, "role_id = ? AND parent_id = ?", seniorID, operatorID); count != 1 {
+		t.Fatalf("expected inherit relation, got count %d", count)
+	}
+
+	perms, err := service.GetRolePermsListByRuleID(testUserCtx(), seniorID)
+	if err != nil {
+		t.Fatalf("GetRolePermsListByRuleID expected success, got %v", err)
+	}
+	slices.Sort(perms)
+	if !slices.Equal(perms, []string{"account:user:create", "account:user:list"}) {
+		t.Fatalf("expected inherited perms, got %v", perms)
+	}
+	batch, err := service.GetRolePermsListByRuleIds(testUserCtx(), []int32{seniorID})
+	if err != nil || !slices.Contains(batch, "account:user:list") {
+		t.Fatalf("expected batch perms to include inherited, got %v %v", batch, err)
+	}
+	info, err := service.GetRoleById(testUserCtx(), seniorID)
+	if err != nil || !slices.Equal(info.ParentIds, []int32{operatorID}) {
+		t.Fatalf("expected parent ids in role info, got %+v %v", info, err)
+	}
+
+	// 父角色改为继承子角色会形成环
+	err = service.UpdateRole(testUserCtx(), &RoleParam{
+		ID: operatorID, Name: "运维", Code: "it_operator", MenuIds: []int32{listMenu.ID}, ParentIds: []int32{seniorID},
+	})
+	if !errors.Is(err, ErrRoleParentCycle) {
+		t.Fatalf("UpdateRole cycle expected ErrRoleParentCycle, got %v", err)
+	}
+
+	// 修改父角色菜单级联清理子角色缓存
+	seniorKey := fmt.Sprintf("%s%d", constant.CacheRoleMenuPrefix, seniorID)
+	if _, ok, _ := deps.cache.Get(testUserCtx(), seniorKey); !ok {
+		t.Fatalf("expected role menu cache %q populated by previous read", seniorKey)
+	}
+	err = service.UpdateRole(testUserCtx(), &RoleParam{
+		ID: operatorID, Name: "运维", Code: "it_operator", MenuIds: []int32{},
+	})
+	if err != nil {
+		t.Fatalf("UpdateRole parent expected success, got %v", err)
+	}
+	if _, ok, err := deps.cache.Get(testUserCtx(), seniorKey); err != nil {
+		t.Fatalf("get role cache: %v", err)
+	} else if ok {
+		t.Fatalf("expected descendant role cache %q to be invalidated", seniorKey)
+	}
+	perms, err = service.GetRolePermsListByRuleID(testUserCtx(), seniorID)
+	if err != nil || !slices.Equal(perms, []string{"account:user:create"}) {
+		t.Fatalf("expected inherited perm removed, got %v %v", perms, err)
+	}
+
+	// 被继承的角色不能删除
+	if err := service.DeleteRole(testUserCtx(), operatorID); !errors.Is(err, ErrRoleInherited) {
+		t.Fatalf("DeleteRole inherited expected ErrRoleInherited, got %v", err)
+	}
+	if err := service.DeleteRole(testUserCtx(), seniorID); err != nil {
+		t.Fatalf("DeleteRole child expected success, got %v", err)
+	}
+	if count := countRows(t, db, model.TableNameSysRoleInherit, "role_id = ?", seniorID); count != 0 {
+		t.Fatalf("expected inherit relation removed, got count %d", count)
+	}
+}
+
+func TestRoleServiceInheritGuardsIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	insertIntegrationRole(t, db, "super_admin", "超级管理员")
+	insertIntegrationUser(t, db, "admin", "18000000000", constant.SuperAdminRoleId)
+	service := newIntegrationRoleService(deps)
+
+	for _, parentIds := range [][]int32{{constant.SuperAdminRoleId}, {999}} {
+		_, err := service.CreateRole(testUserCtx(), &RoleParam{
+			Name: "非法继承", Code: "it_bad_parent", MenuIds: []int32{}, ParentIds: parentIds,
+		})
+		if !errors.Is(err, ErrRoleParentInvalid) {
+			t.Fatalf("CreateRole parents %v expected ErrRoleParentInvalid, got %v", parentIds, err)
+		}
+	}
+	if count := countRows(t, db, model.TableNameSysRole, "code = ?", "it_bad_parent"); count != 0 {
+		t.Fatalf("expected role not created, got count %d", count)
+	}
+}
@@ -0,0 +1,138 @@
+package account
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+)
+
+// newTestRoleInherits 高级运维(4) -> 运维(3) -> 只读(2)；审计(5) -> 只读(2)
+func newTestRoleInherits() []*model.SysRoleInherit {
+	return []*model.SysRoleInherit{
+		{RoleID: 4, ParentID: 3},
+		{RoleID: 3, ParentID: 2},
+		{RoleID: 5, ParentID: 2},
+	}
+}
+
+func TestRoleInheritGraph(t *testing.T) {
+	graph := newRoleInheritGraph(newTestRoleInherits())
+
+	if got := graph.withAncestors(4); !slices.Equal(got, []int32{2, 3, 4}) {
+		t.Fatalf("expected ancestors of 4, got %v", got)
+	}
+	if got := graph.withDescendants(2); !slices.Equal(got, []int32{2, 3, 4, 5}) {
+		t.Fatalf("expected descendants of 2, got %v", got)
+	}
+	if got := graph.withDescendants(4); !slices.Equal(got, []int32{4}) {
+		t.Fatalf("expected leaf only, got %v", got)
+	}
+	if got := graph.parentIds(4); !slices.Equal(got, []int32{3}) {
+		t.Fatalf("expected direct parent, got %v", got)
+	}
+
+	// 历史数据存在环时遍历也能结束
+	cyclic := newRoleInheritGraph([]*model.SysRoleInherit{{RoleID: 2, ParentID: 3}, {RoleID: 3, ParentID: 2}})
+	if got := cyclic.withAncestors(2); !slices.Equal(got, []int32{2, 3}) {
+		t.Fatalf("expected cyclic walk to terminate, got %v", got)
+	}
+}
+
+func TestRoleServiceCheckRoleParents(t *testing.T) {
+	service := &RoleService{roleDao: &fakeRoleRepo{}}
+	graph := newRoleInheritGraph(newTestRoleInherits())
+
+	tests := []struct {
+		name      string
+		roleId    int32
+		parentIds []int32
+		want      error
+	}{
+		{"no parents", 3, nil, nil},
+		{"create", 0, []int32{3, 5}, nil},
+		{"sibling", 5, []int32{3}, nil},
+		{"super admin", 0, []int32{constant.SuperAdminRoleId}, ErrRoleParentInvalid},
+		{"self", 3, []int32{3}, ErrRoleParentCycle},
+		{"descendant", 2, []int32{4}, ErrRoleParentCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.checkRoleParents(testUserCtx(), nil, graph, tt.roleId, tt.parentIds); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRoleServiceGetRoleMenuListIncludesInherited(t *testing.T) {
+	perms := "account:user:list"
+	repo := &fakeRoleRepo{
+		inherits: newTestRoleInherits(),
+		// 父子角色关联同一菜单时 dao 会重复返回
+		menuList: []*model.SysMenu{
+			{ID: 1, MenuType: constant.MenuTypeBtn, Name: "List", Perms: &perms},
+			{ID: 1, MenuType: constant.MenuTypeBtn, Name: "List", Perms: &perms},
+		},
+	}
+	service := &RoleService{roleDao: repo, cache: newFakeCache()}
+
+	got, err := service.GetRolePermsListByRuleID(testUserCtx(), 4)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !slices.Equal(repo.menuListRoleIds, []int32{2, 3, 4}) {
+		t.Fatalf("expected menus loaded for role and ancestors, got %v", repo.menuListRoleIds)
+	}
+	if !slices.Equal(got, []string{perms}) {
+		t.Fatalf("expected deduplicated perms, got %v", got)
+	}
+}
+
+func TestClearRoleMenuCacheCascades(t *testing.T) {
+	cache := newFakeCache()
+	clearRoleMenuCache(testUserCtx(), cache, newTestRoleInherits(), 3)
+
+	want := []string{constant.CacheRoleMenuPrefix + "3", constant.CacheRoleMenuPrefix + "4"}
+	if !slices.Equal(cache.deletes, want) {
+		t.Fatalf("expected role and descendant caches cleared, got %v", cache.deletes)
+	}
+}
+
+func TestRoleServiceCheckOperatorMenus(t *testing.T) {
+	graph := newRoleInheritGraph(newTestRoleInherits())
+	tests := []struct {
+		name        string
+		userRoleIds []int32
+		roleMenuIds []int32
+		want        error
+	}{
+		// 超级管理员未关联任何菜单，依靠通配授权仍可分配全部菜单
+		{"super admin without role menus", []int32{constant.SuperAdminRoleId}, nil, nil},
+		{"owns menus", []int32{3}, []int32{10, 11}, nil},
+		{"missing menu", []int32{3}, []int32{10}, ErrRoleMenuNotAuthorized},
+		{"no roles", nil, nil, ErrRoleMenuNotAuthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &RoleService{roleDao: &fakeRoleRepo{userRoleIds: tt.userRoleIds, roleMenuIds: tt.roleMenuIds}}
+			err := service.checkOperatorMenus(testUserCtx(), nil, graph, 1, []int32{10, 11}, nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRoleServiceGetRolePermsListByRuleIdsSuperAdmin(t *testing.T) {
+	service := &RoleService{roleDao: &fakeRoleRepo{}}
+	got, err := service.GetRolePermsListByRuleIds(testUserCtx(), []int32{3, constant.SuperAdminRoleId})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !slices.Equal(got, []string{constant.PermAll}) {
+		t.Fatalf("expected wildcard perms, got %v", got)
+	}
+}
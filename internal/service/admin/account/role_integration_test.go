@@ -144,7 +144,7 @@ func TestRoleServiceCreateRoleRollbackIntegration(t *testing.T) {
 	insertIntegrationRoleMenu(t, db, constant.SuperAdminRoleId, menu.ID)
 
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
-	service := NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, operationLogService)
+	service := NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, operationLogService, nil)
 	_, err := service.CreateRole(testUserCtx(), &RoleParam{
 		Name:        "待回滚角色",
 		Code:        "it_role_create_rollback",
@@ -231,7 +231,7 @@ func TestRoleServiceDeleteRoleRollbackIntegration(t *testing.T) {
 	insertIntegrationRoleMenu(t, db, role.ID, menu.ID)
 
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
-	service := NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, operationLogService)
+	service := NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, operationLogService, nil)
 	err := service.DeleteRole(testUserCtx(), role.ID)
 	if !errors.Is(err, errIntegrationOperationLog) {
 		t.Fatalf("DeleteRole expected operation log error, got %v", err)
@@ -259,7 +259,7 @@ func TestRoleServiceUpdateRoleRollbackIntegration(t *testing.T) {
 	insertIntegrationRoleMenu(t, db, role.ID, oldMenu.ID)
 
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
-	service := NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, operationLogService)
+	service := NewRoleService(deps.repo, daoAccount.NewRoleDao(deps.repo), deps.cache, operationLogService, nil)
 	err := service.UpdateRole(testUserCtx(), &RoleParam{
 		ID:      role.ID,
 		Name:    "回滚后的角色",
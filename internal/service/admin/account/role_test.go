@@ -105,3 +105,15 @@ func TestRoleServiceGetRolePermsListByRuleID(t *testing.T) {
 		t.Fatalf("expected only button perms, got %v", got)
 	}
 }
+
+func TestRoleServiceGetRolePermsListBySuperAdmin(t *testing.T) {
+	service := &RoleService{roleDao: &fakeRoleRepo{}, cache: newFakeCache()}
+
+	got, err := service.GetRolePermsListByRuleID(testUserCtx(), constant.SuperAdminRoleId)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(got) != 1 || got[0] != constant.PermAll {
+		t.Fatalf("expected super admin to have single * grant, got %v", got)
+	}
+}
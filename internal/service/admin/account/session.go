@@ -0,0 +1,508 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/repo"
+	"snowgo/internal/service/admin/contract"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+const (
+	SessionOverflowEvictOldest = "evict_oldest" // 超出上限时踢出最早登录的会话
+	SessionOverflowReject      = "reject"       // 超出上限时拒绝新登录
+
+	sessionTouchInterval = time.Minute // 最后活跃时间的最小刷新间隔，避免每个请求都写缓存
+)
+
+// sessionRegisterScript 原子地清理过期会话、按上限拒绝或踢出最早登录的会话并写入新会话
+// KEYS[1] 会话 hash；ARGV: 会话ID、会话JSON、当前毫秒、上限(0 不限制)、超限拒绝(1/0)、hash 过期毫秒
+// 返回 {1, 被踢出的会话ID...}，超限拒绝时返回 {0}
+const sessionRegisterScript = `
+local now = tonumber(ARGV[3])
+local limit = tonumber(ARGV[4])
+local all = redis.call("HGETALL", KEYS[1])
+local live = {}
+for i = 1, #all, 2 do
+    local ok, info = pcall(cjson.decode, all[i + 1])
+    if ok and type(info) == "table" and (tonumber(info.expire_ms) or 0) > now then
+        table.insert(live, {all[i], tonumber(info.login_ms) or 0})
+    else
+        redis.call("HDEL", KEYS[1], all[i])
+    end
+end
+local result = {1}
+if limit > 0 and #live >= limit then
+    if ARGV[5] == "1" then
+        return {0}
+    end
+    table.sort(live, function(a, b) return a[2] < b[2] end)
+    for i = 1, #live - limit + 1 do
+        redis.call("HDEL", KEYS[1], live[i][1])
+        table.insert(result, live[i][1])
+    end
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("PEXPIRE", KEYS[1], ARGV[6])
+return result
+`
+
+// sessionTouchScript 原子地刷新会话最后活跃时间，会话已被踢出（不存在）时不写入
+// KEYS[1] 会话 hash；ARGV: 会话ID、当前毫秒、当前时间(RFC3339)、IP、最小刷新间隔毫秒
+// 返回 1 表示已刷新，0 表示会话不存在或无需刷新
+const sessionTouchScript = `
+local raw = redis.call("HGET", KEYS[1], ARGV[1])
+if not raw then
+    return 0
+end
+local ok, info = pcall(cjson.decode, raw)
+if not ok or type(info) ~= "table" then
+    return 0
+end
+local now = tonumber(ARGV[2])
+if now - (tonumber(info.seen_ms) or 0) < tonumber(ARGV[5]) and info.ip == ARGV[4] then
+    return 0
+end
+info.last_seen_at = ARGV[3]
+info.seen_ms = now
+if ARGV[4] ~= "" then
+    info.ip = ARGV[4]
+end
+redis.call("HSET", KEYS[1], ARGV[1], cjson.encode(info))
+return 1
+`
+
+// sessionRotateScript 原子地把会话改名为新的会话ID，保留登录时间等信息；旧会话不存在时不写入
+// KEYS[1] 会话 hash；ARGV: 旧会话ID、新会话ID、令牌族ID、当前时间(RFC3339)、当前毫秒、过期时间(RFC3339)、过期毫秒、hash 过期毫秒
+// 返回 1 表示已轮换，0 表示旧会话不存在（已被踢出、登出或淘汰）
+const sessionRotateScript = `
+local raw = redis.call("HGET", KEYS[1], ARGV[1])
+if not raw then
+    return 0
+end
+redis.call("HDEL", KEYS[1], ARGV[1])
+local ok, info = pcall(cjson.decode, raw)
+if not ok or type(info) ~= "table" then
+    return 0
+end
+info.session_id = ARGV[2]
+info.family_id = ARGV[3]
+info.last_seen_at = ARGV[4]
+info.seen_ms = tonumber(ARGV[5])
+info.expire_at = ARGV[6]
+info.expire_ms = tonumber(ARGV[7])
+redis.call("HSET", KEYS[1], ARGV[2], cjson.encode(info))
+redis.call("PEXPIRE", KEYS[1], ARGV[8])
+return 1
+`
+
+// refreshJtiUseScript 标记 refresh token jti 已使用并保留剩余有效期
+// 返回 0 表示 jti 不存在（已过期、登出、被踢出或会话被淘汰），1 表示本次标记成功，2 表示已使用过
+const refreshJtiUseScript = `
+local value = redis.call("GET", KEYS[1])
+if not value then
+    return 0
+end
+if value == ARGV[1] then
+    return 2
+end
+redis.call("SET", KEYS[1], ARGV[1], "KEEPTTL")
+return 1
+`
+
+const (
+	refreshJtiUnused = "1"    // 已签发未使用
+	refreshJtiUsed   = "used" // 已换取过新令牌，保留到原有效期用于重复使用检测
+)
+
+// RefreshJtiState refresh token jti 的使用状态
+type RefreshJtiState int
+
+const (
+	RefreshJtiMissing  RefreshJtiState = iota // 不存在：已过期、登出、被踢出或会话被淘汰，按无效令牌处理
+	RefreshJtiConsumed                        // 本次成功标记为已使用，可以换取新令牌
+	RefreshJtiReused                          // 已使用过又再次出现，令牌可能被窃取
+)
+
+var (
+	ErrSessionLimit    = e.NewBizError(e.SessionLimitExceeded)
+	ErrSessionNotFound = e.NewBizError(e.SessionNotFound)
+)
+
+// SessionConfig 会话数量限制
+type SessionConfig struct {
+	MaxPerUser     int    // 每个用户最大并发会话数，<=0 表示不限制
+	OverflowPolicy string // 超出上限的处理策略 evict_oldest / reject
+}
+
+// SessionService 登录会话登记，会话ID即 refresh token 的 jti
+type SessionService struct {
+	db         *repo.Repository
+	cache      xcache.Cache
+	revocation *RevocationService
+	logService contract.OperationLogWriter
+	conf       SessionConfig
+	ttl        time.Duration // 会话记录最长保留时间，与 refresh token 有效期一致
+}
+
+func NewSessionService(db *repo.Repository, cache xcache.Cache, revocation *RevocationService,
+	logService contract.OperationLogWriter, conf SessionConfig, ttl time.Duration) *SessionService {
+	if conf.OverflowPolicy == "" {
+		conf.OverflowPolicy = SessionOverflowEvictOldest
+	}
+	return &SessionService{
+		db:         db,
+		cache:      cache,
+		revocation: revocation,
+		logService: logService,
+		conf:       conf,
+		ttl:        ttl,
+	}
+}
+
+// SessionInput 登录会话信息
+type SessionInput struct {
+	UserId    int32
+	SessionId string
+	FamilyId  string // refresh token 族ID
+	Device    string
+	IP        string
+	UserAgent string
+	ExpireAt  time.Time
+}
+
+type SessionInfo struct {
+	SessionId  string    `json:"session_id"`
+	FamilyId   string    `json:"family_id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	LoginAt    time.Time `json:"login_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpireAt   time.Time `json:"expire_at"`
+}
+
+// sessionRecord 缓存中保存的会话，附带毫秒时间戳供 Lua 脚本判断过期、排序与刷新间隔
+type sessionRecord struct {
+	*SessionInfo
+	LoginMs  int64 `json:"login_ms"`
+	SeenMs   int64 `json:"seen_ms"`
+	ExpireMs int64 `json:"expire_ms"`
+}
+
+func marshalSession(info *SessionInfo) (string, error) {
+	raw, err := json.Marshal(&sessionRecord{
+		SessionInfo: info,
+		LoginMs:     info.LoginAt.UnixMilli(),
+		SeenMs:      info.LastSeenAt.UnixMilli(),
+		ExpireMs:    info.ExpireAt.UnixMilli(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("会话序列化失败: %w", err)
+	}
+	return string(raw), nil
+}
+
+func sessionCacheKey(userId int32) string {
+	return fmt.Sprintf("%s%d", constant.CacheSessionPrefix, userId)
+}
+
+// loadSessions 读取用户全部会话，顺带清理已过期的会话
+func (s *SessionService) loadSessions(ctx context.Context, userId int32) ([]*SessionInfo, error) {
+	cacheKey := sessionCacheKey(userId)
+	data, err := s.cache.HGetAll(ctx, cacheKey)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询用户会话失败 uid=%d: %v", userId, err)
+		return nil, fmt.Errorf("查询用户会话失败: %w", err)
+	}
+	now := time.Now()
+	sessions := make([]*SessionInfo, 0, len(data))
+	expired := make([]string, 0)
+	for sid, raw := range data {
+		var info SessionInfo
+		if err := json.Unmarshal([]byte(raw), &info); err != nil || !info.ExpireAt.After(now) {
+			expired = append(expired, sid)
+			continue
+		}
+		sessions = append(sessions, &info)
+	}
+	if len(expired) > 0 {
+		if _, err := s.cache.HDel(ctx, cacheKey, expired...); err != nil {
+			xlogger.ErrorfCtx(ctx, "清理过期会话失败 uid=%d: %v", userId, err)
+		}
+	}
+	// 最近登录的排在前面
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LoginAt.After(sessions[j].LoginAt)
+	})
+	return sessions, nil
+}
+
+// terminate 结束会话：删除登记、删除 refresh jti、吊销会话下的 access token
+func (s *SessionService) terminate(ctx context.Context, userId int32, sessionId string) error {
+	if _, err := s.cache.HDel(ctx, sessionCacheKey(userId), sessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "删除用户会话失败 uid=%d sid=%s: %v", userId, sessionId, err)
+		return fmt.Errorf("删除用户会话失败: %w", err)
+	}
+	return s.revokeSessionTokens(ctx, sessionId)
+}
+
+// revokeSessionTokens 删除会话的 refresh jti 并吊销会话下的 access token，会话登记已由调用方删除
+func (s *SessionService) revokeSessionTokens(ctx context.Context, sessionId string) error {
+	if _, err := s.cache.Delete(ctx, constant.CacheRefreshJtiPrefix+sessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "删除refresh token jti失败 sid=%s: %v", sessionId, err)
+		return fmt.Errorf("删除refresh token jti失败: %w", err)
+	}
+	return s.revocation.RevokeSession(ctx, sessionId)
+}
+
+// Register 登记新会话，超出并发上限时按策略踢出最早的会话或拒绝登录
+// 计数、踢出与写入在同一脚本内完成，并发登录不会超出上限
+func (s *SessionService) Register(ctx context.Context, input *SessionInput) error {
+	if input.UserId <= 0 {
+		return ErrUserNotFound
+	}
+	now := time.Now()
+	raw, err := marshalSession(&SessionInfo{
+		SessionId:  input.SessionId,
+		FamilyId:   input.FamilyId,
+		Device:     input.Device,
+		IP:         input.IP,
+		UserAgent:  input.UserAgent,
+		LoginAt:    now,
+		LastSeenAt: now,
+		ExpireAt:   input.ExpireAt,
+	})
+	if err != nil {
+		return err
+	}
+	reject := "0"
+	if s.conf.OverflowPolicy == SessionOverflowReject {
+		reject = "1"
+	}
+	res, err := s.cache.Eval(ctx, sessionRegisterScript, []string{sessionCacheKey(input.UserId)},
+		input.SessionId, raw, now.UnixMilli(), max(s.conf.MaxPerUser, 0), reject, s.ttl.Milliseconds())
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "登记用户会话失败 uid=%d sid=%s: %v", input.UserId, input.SessionId, err)
+		return fmt.Errorf("登记用户会话失败: %w", err)
+	}
+	result, ok := res.([]any)
+	if !ok || len(result) == 0 {
+		return fmt.Errorf("登记用户会话返回值异常: %v", res)
+	}
+	if registered, _ := result[0].(int64); registered != 1 {
+		return ErrSessionLimit
+	}
+	for _, item := range result[1:] {
+		sessionId, _ := item.(string)
+		if sessionId == "" {
+			continue
+		}
+		if err := s.revokeSessionTokens(ctx, sessionId); err != nil {
+			return err
+		}
+		xlogger.InfofCtx(ctx, "会话数超出上限，踢出最早会话 uid=%d sid=%s", input.UserId, sessionId)
+	}
+	return nil
+}
+
+// SaveRefreshJti 登记新签发的 refresh token jti，有效期与 refresh token 一致
+func (s *SessionService) SaveRefreshJti(ctx context.Context, jti string, ttl time.Duration) error {
+	if err := s.cache.Set(ctx, constant.CacheRefreshJtiPrefix+jti, refreshJtiUnused, ttl); err != nil {
+		xlogger.ErrorfCtx(ctx, "保存refresh token jti失败 jti=%s: %v", jti, err)
+		return fmt.Errorf("保存refresh token jti失败: %w", err)
+	}
+	return nil
+}
+
+// UseRefreshJti 使用 refresh token 前原子地标记 jti 已使用
+// 只有 jti 存在且已标记使用才视为重复使用；jti 不存在说明令牌已随登出、踢出或淘汰失效，不属于重复使用
+func (s *SessionService) UseRefreshJti(ctx context.Context, jti string) (RefreshJtiState, error) {
+	res, err := s.cache.Eval(ctx, refreshJtiUseScript, []string{constant.CacheRefreshJtiPrefix + jti}, refreshJtiUsed)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "标记refresh token jti失败 jti=%s: %v", jti, err)
+		return RefreshJtiMissing, fmt.Errorf("标记refresh token jti失败: %w", err)
+	}
+	switch res {
+	case int64(1):
+		return RefreshJtiConsumed, nil
+	case int64(2):
+		return RefreshJtiReused, nil
+	}
+	return RefreshJtiMissing, nil
+}
+
+// Rotate refresh token 轮换后，会话ID随之变更，令牌族不变；登录时间等信息保留
+// 改名在同一脚本内完成，旧会话已被踢出或登出时返回 ErrSessionNotFound，不会以新ID重新出现
+func (s *SessionService) Rotate(ctx context.Context, userId int32, oldSessionId, newSessionId, familyId string, expireAt time.Time) error {
+	now := time.Now()
+	res, err := s.cache.Eval(ctx, sessionRotateScript, []string{sessionCacheKey(userId)},
+		oldSessionId, newSessionId, familyId, now.Format(time.RFC3339Nano), now.UnixMilli(),
+		expireAt.Format(time.RFC3339Nano), expireAt.UnixMilli(), s.ttl.Milliseconds())
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "轮换用户会话失败 uid=%d sid=%s: %v", userId, oldSessionId, err)
+		return fmt.Errorf("轮换用户会话失败: %w", err)
+	}
+	if res != int64(1) {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeFamily refresh token 被重复使用时吊销整个令牌族，结束族内仍在线的会话，返回结束的会话数
+func (s *SessionService) RevokeFamily(ctx context.Context, userId int32, familyId string) (int, error) {
+	if userId <= 0 {
+		return 0, ErrUserNotFound
+	}
+	if err := s.revocation.RevokeFamily(ctx, familyId); err != nil {
+		return 0, err
+	}
+	sessions, err := s.loadSessions(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, session := range sessions {
+		if session.FamilyId != familyId {
+			continue
+		}
+		if err := s.terminate(ctx, userId, session.SessionId); err != nil {
+			return count, err
+		}
+		count++
+	}
+	xlogger.InfofCtx(ctx, "令牌族已吊销 uid=%d family=%s sessions=%d", userId, familyId, count)
+	return count, nil
+}
+
+// Touch 刷新会话最后活跃时间，读取与写回在同一脚本内完成，不会让刚被踢出的会话重新出现
+func (s *SessionService) Touch(ctx context.Context, userId int32, sessionId string, ip string) error {
+	now := time.Now()
+	_, err := s.cache.Eval(ctx, sessionTouchScript, []string{sessionCacheKey(userId)},
+		sessionId, now.UnixMilli(), now.Format(time.RFC3339Nano), ip, sessionTouchInterval.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("刷新用户会话失败: %w", err)
+	}
+	return nil
+}
+
+// Remove 用户主动退出时删除会话登记
+func (s *SessionService) Remove(ctx context.Context, userId int32, sessionId string) error {
+	if _, err := s.cache.HDel(ctx, sessionCacheKey(userId), sessionId); err != nil {
+		xlogger.ErrorfCtx(ctx, "删除用户会话失败 uid=%d sid=%s: %v", userId, sessionId, err)
+		return fmt.Errorf("删除用户会话失败: %w", err)
+	}
+	return nil
+}
+
+// ListSessions 用户当前在线会话
+func (s *SessionService) ListSessions(ctx context.Context, userId int32) ([]*SessionInfo, error) {
+	if userId <= 0 {
+		return nil, ErrUserNotFound
+	}
+	return s.loadSessions(ctx, userId)
+}
+
+// KickSession 管理员踢出用户的某个会话
+func (s *SessionService) KickSession(ctx context.Context, userId int32, sessionId string) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	if userId <= 0 {
+		return ErrUserNotFound
+	}
+	_, ok, err := s.cache.HGet(ctx, sessionCacheKey(userId), sessionId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询用户会话失败 uid=%d sid=%s: %v", userId, sessionId, err)
+		return fmt.Errorf("查询用户会话失败: %w", err)
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if err := s.terminate(ctx, userId, sessionId); err != nil {
+		return err
+	}
+
+	s.writeKickLog(ctx, userContext, userId, fmt.Sprintf("用户(%d-%s)踢出了用户(%d)的会话(%s)",
+		userContext.UserId, userContext.Username, userId, sessionId))
+	return nil
+}
+
+// KickAllSessions 管理员踢出用户的全部会话，返回踢出的会话数
+func (s *SessionService) KickAllSessions(ctx context.Context, userId int32) (int, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if userId <= 0 {
+		return 0, ErrUserNotFound
+	}
+	sessions, err := s.loadSessions(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	for _, session := range sessions {
+		if err := s.terminate(ctx, userId, session.SessionId); err != nil {
+			return 0, err
+		}
+	}
+	// 水位线兜底：未登记的会话（如登记失败）同样失效
+	if err := s.revocation.RevokeUserTokens(ctx, userId); err != nil {
+		return 0, err
+	}
+
+	s.writeKickLog(ctx, userContext, userId, fmt.Sprintf("用户(%d-%s)踢出了用户(%d)的全部会话(%d个)",
+		userContext.UserId, userContext.Username, userId, len(sessions)))
+	return len(sessions), nil
+}
+
+// writeKickLog 记录踢出会话操作日志，会话仅存在于缓存，日志写入失败不回滚
+func (s *SessionService) writeKickLog(ctx context.Context, userContext *xauth.UserContext, userId int32, description string) {
+	err := s.logService.CreateOperationLog(ctx, s.db.WriteQuery(), &contract.OperationLogInput{
+		OperatorID:   userContext.UserId,
+		OperatorName: userContext.Username,
+		OperatorType: constant.OperatorUser,
+		Resource:     constant.ResourceSession,
+		ResourceID:   int64(userId),
+		TraceID:      userContext.TraceId,
+		Action:       constant.ActionDelete,
+		Description:  description,
+		IP:           userContext.IP,
+	})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "操作日志创建失败 resource=session resource_id=%d err: %v", userId, err)
+	}
+}
+
+// DeviceFromUserAgent 根据 User-Agent 粗略识别设备类型
+func DeviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return "Unknown"
+	case strings.Contains(ua, "ipad"):
+		return "iPad"
+	case strings.Contains(ua, "iphone"):
+		return "iPhone"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "macintosh"), strings.Contains(ua, "mac os"):
+		return "Mac"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	default:
+		return "Other"
+	}
+}
@@ -0,0 +1,126 @@
+//go:build integration
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/repo"
+	"snowgo/pkg/xcache"
+)
+
+func newRedisSessionService(t *testing.T, conf SessionConfig) (*SessionService, xcache.Cache) {
+	t.Helper()
+	rdb := setupTestRedis(t)
+	t.Cleanup(func() { _ = rdb.Close() })
+	cache, err := xcache.NewRedisCache(rdb)
+	if err != nil {
+		t.Fatalf("create redis cache: %v", err)
+	}
+	revocation := NewRevocationService(cache, 10*time.Minute, time.Hour)
+	return NewSessionService(&repo.Repository{}, cache, revocation, &fakeOperationLogWriter{}, conf, time.Hour), cache
+}
+
+func TestSessionServiceConcurrentRegisterIntegration(t *testing.T) {
+	ctx := context.Background()
+	service, cache := newRedisSessionService(t, SessionConfig{MaxPerUser: 3})
+	const userId = int32(990001)
+	_, _ = cache.Delete(ctx, sessionCacheKey(userId))
+	t.Cleanup(func() { _, _ = cache.Delete(context.Background(), sessionCacheKey(userId)) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := service.Register(ctx, &SessionInput{
+				UserId:    userId,
+				SessionId: fmt.Sprintf("it-sid-%d", i),
+				IP:        "127.0.0.1",
+				ExpireAt:  time.Now().Add(time.Hour),
+			})
+			if err != nil {
+				t.Errorf("Register error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sessions, err := service.ListSessions(ctx, userId)
+	if err != nil {
+		t.Fatalf("ListSessions error: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected concurrent logins capped at 3 sessions, got %d", len(sessions))
+	}
+}
+
+func TestSessionServiceTouchIntegration(t *testing.T) {
+	ctx := context.Background()
+	service, cache := newRedisSessionService(t, SessionConfig{})
+	const userId = int32(990002)
+	_, _ = cache.Delete(ctx, sessionCacheKey(userId))
+	t.Cleanup(func() { _, _ = cache.Delete(context.Background(), sessionCacheKey(userId)) })
+
+	err := service.Register(ctx, &SessionInput{
+		UserId:    userId,
+		SessionId: "it-sid",
+		Device:    "Mac",
+		UserAgent: "Mozilla/5.0 \"quoted\" / 浏览器",
+		IP:        "127.0.0.1",
+		ExpireAt:  time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	if err := service.Touch(ctx, userId, "it-sid", "10.0.0.2"); err != nil {
+		t.Fatalf("Touch error: %v", err)
+	}
+	sessions, err := service.ListSessions(ctx, userId)
+	if err != nil {
+		t.Fatalf("ListSessions error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].IP != "10.0.0.2" || sessions[0].UserAgent != "Mozilla/5.0 \"quoted\" / 浏览器" {
+		t.Fatalf("expected touched session with fields kept, got %+v", sessions)
+	}
+
+	if _, err := cache.HDel(ctx, sessionCacheKey(userId), "it-sid"); err != nil {
+		t.Fatalf("HDel error: %v", err)
+	}
+	if err := service.Touch(ctx, userId, "it-sid", "10.0.0.3"); err != nil {
+		t.Fatalf("Touch error: %v", err)
+	}
+	if _, ok, _ := cache.HGet(ctx, sessionCacheKey(userId), "it-sid"); ok {
+		t.Fatal("expected touch not to restore removed session")
+	}
+}
+
+func TestSessionServiceRefreshJtiIntegration(t *testing.T) {
+	ctx := context.Background()
+	service, cache := newRedisSessionService(t, SessionConfig{})
+	jti := "it-refresh-jti"
+	t.Cleanup(func() { _, _ = cache.Delete(context.Background(), constant.CacheRefreshJtiPrefix+jti) })
+
+	if err := service.SaveRefreshJti(ctx, jti, time.Hour); err != nil {
+		t.Fatalf("SaveRefreshJti error: %v", err)
+	}
+	if state, err := service.UseRefreshJti(ctx, jti); err != nil || state != RefreshJtiConsumed {
+		t.Fatalf("expected first use consumed, got %v err=%v", state, err)
+	}
+	if state, err := service.UseRefreshJti(ctx, jti); err != nil || state != RefreshJtiReused {
+		t.Fatalf("expected second use reused, got %v err=%v", state, err)
+	}
+	// 标记已使用后保留原有效期
+	if ttl, err := cache.TTL(ctx, constant.CacheRefreshJtiPrefix+jti); err != nil || ttl <= 0 {
+		t.Fatalf("expected used jti to keep ttl, got %v err=%v", ttl, err)
+	}
+	_, _ = cache.Delete(ctx, constant.CacheRefreshJtiPrefix+jti)
+	if state, err := service.UseRefreshJti(ctx, jti); err != nil || state != RefreshJtiMissing {
+		t.Fatalf("expected deleted jti missing, got %v err=%v", state, err)
+	}
+}
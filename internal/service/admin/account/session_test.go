@@ -0,0 +1,297 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	"snowgo/internal/service/admin/contract"
+)
+
+type fakeOperationLogWriter struct {
+	inputs []*contract.OperationLogInput
+}
+
+func (f *fakeOperationLogWriter) CreateOperationLog(_ context.Context, _ *query.Query, input *contract.OperationLogInput) error {
+	f.inputs = append(f.inputs, input)
+	return nil
+}
+
+func newTestSessionService(cache *fakeCache, conf SessionConfig) (*SessionService, *fakeOperationLogWriter) {
+	logWriter := &fakeOperationLogWriter{}
+	revocation := NewRevocationService(cache, 10*time.Minute, time.Hour)
+	return NewSessionService(&repo.Repository{}, cache, revocation, logWriter, conf, time.Hour), logWriter
+}
+
+func registerTestSession(t *testing.T, service *SessionService, userId int32, sessionId string) {
+	t.Helper()
+	err := service.Register(testUserCtx(), &SessionInput{
+		UserId:    userId,
+		SessionId: sessionId,
+		Device:    "Mac",
+		IP:        "127.0.0.1",
+		ExpireAt:  time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Register %s error: %v", sessionId, err)
+	}
+	// 保证登录时间有先后
+	time.Sleep(time.Millisecond)
+}
+
+func TestSessionService(t *testing.T) {
+	ctx := testUserCtx()
+
+	t.Run("register and list", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+		registerTestSession(t, service, 2, "sid-2")
+
+		sessions, err := service.ListSessions(ctx, 2)
+		if err != nil {
+			t.Fatalf("ListSessions error: %v", err)
+		}
+		if len(sessions) != 2 || sessions[0].SessionId != "sid-2" {
+			t.Fatalf("expected 2 sessions with newest first, got %+v", sessions)
+		}
+		if cache.expirations[constant.CacheSessionPrefix+"2"] != time.Hour {
+			t.Fatalf("expected session hash ttl 1h, got %v", cache.expirations[constant.CacheSessionPrefix+"2"])
+		}
+	})
+
+	t.Run("prune expired", func(t *testing.T) {
+		service, _ := newTestSessionService(newFakeCache(), SessionConfig{})
+		err := service.Register(ctx, &SessionInput{UserId: 2, SessionId: "expired", ExpireAt: time.Now().Add(-time.Second)})
+		if err != nil {
+			t.Fatalf("Register error: %v", err)
+		}
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 0 {
+			t.Fatalf("expected expired session pruned, got %+v", sessions)
+		}
+	})
+
+	t.Run("reject over limit", func(t *testing.T) {
+		service, _ := newTestSessionService(newFakeCache(), SessionConfig{MaxPerUser: 1, OverflowPolicy: SessionOverflowReject})
+		registerTestSession(t, service, 2, "sid-1")
+		err := service.Register(ctx, &SessionInput{UserId: 2, SessionId: "sid-2", ExpireAt: time.Now().Add(time.Hour)})
+		if err != ErrSessionLimit {
+			t.Fatalf("expected ErrSessionLimit, got %v", err)
+		}
+	})
+
+	t.Run("evict oldest over limit", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{MaxPerUser: 2})
+		registerTestSession(t, service, 2, "sid-1")
+		registerTestSession(t, service, 2, "sid-2")
+		registerTestSession(t, service, 2, "sid-3")
+
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 2 || sessions[0].SessionId != "sid-3" || sessions[1].SessionId != "sid-2" {
+			t.Fatalf("expected sid-3 and sid-2 kept, got %+v", sessions)
+		}
+		if _, ok := cache.values[constant.CacheRevokedSessionPrefix+"sid-1"]; !ok {
+			t.Fatal("expected evicted session revoked")
+		}
+	})
+
+	t.Run("rotate keeps login info", func(t *testing.T) {
+		service, _ := newTestSessionService(newFakeCache(), SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+		before, _ := service.ListSessions(ctx, 2)
+
+		if err := service.Rotate(ctx, 2, "sid-1", "sid-2", "fid-1", time.Now().Add(2*time.Hour)); err != nil {
+			t.Fatalf("Rotate error: %v", err)
+		}
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 1 || sessions[0].SessionId != "sid-2" {
+			t.Fatalf("expected rotated session sid-2, got %+v", sessions)
+		}
+		if sessions[0].Device != "Mac" || sessions[0].FamilyId != "fid-1" || !sessions[0].LoginAt.Equal(before[0].LoginAt) {
+			t.Fatalf("expected device and login time kept, got %+v", sessions[0])
+		}
+	})
+
+	t.Run("rotate kicked session", func(t *testing.T) {
+		service, _ := newTestSessionService(newFakeCache(), SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+		if err := service.Remove(ctx, 2, "sid-1"); err != nil {
+			t.Fatalf("Remove error: %v", err)
+		}
+
+		// 会话在刷新期间被踢出或登出，不能以新ID重新出现
+		if err := service.Rotate(ctx, 2, "sid-1", "sid-2", "fid-1", time.Now().Add(2*time.Hour)); err != ErrSessionNotFound {
+			t.Fatalf("expected ErrSessionNotFound, got %v", err)
+		}
+		if sessions, _ := service.ListSessions(ctx, 2); len(sessions) != 0 {
+			t.Fatalf("expected no session recreated, got %+v", sessions)
+		}
+	})
+
+	t.Run("kick session", func(t *testing.T) {
+		cache := newFakeCache()
+		service, logWriter := newTestSessionService(cache, SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+		cache.values[constant.CacheRefreshJtiPrefix+"sid-1"] = "1"
+
+		if err := service.KickSession(ctx, 2, "missing"); err != ErrSessionNotFound {
+			t.Fatalf("expected ErrSessionNotFound, got %v", err)
+		}
+		if err := service.KickSession(ctx, 2, "sid-1"); err != nil {
+			t.Fatalf("KickSession error: %v", err)
+		}
+		if _, ok := cache.values[constant.CacheRefreshJtiPrefix+"sid-1"]; ok {
+			t.Fatal("expected refresh jti deleted")
+		}
+		if _, ok := cache.values[constant.CacheRevokedSessionPrefix+"sid-1"]; !ok {
+			t.Fatal("expected kicked session revoked")
+		}
+		if len(logWriter.inputs) != 1 || logWriter.inputs[0].Resource != constant.ResourceSession {
+			t.Fatalf("expected one session operation log, got %+v", logWriter.inputs)
+		}
+	})
+
+	t.Run("revoke family", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{})
+		for _, input := range []*SessionInput{
+			{UserId: 2, SessionId: "sid-2", FamilyId: "fid-1", ExpireAt: time.Now().Add(time.Hour)},
+			{UserId: 2, SessionId: "sid-3", FamilyId: "fid-2", ExpireAt: time.Now().Add(time.Hour)},
+		} {
+			if err := service.Register(ctx, input); err != nil {
+				t.Fatalf("Register error: %v", err)
+			}
+		}
+		cache.values[constant.CacheRefreshJtiPrefix+"sid-2"] = "1"
+
+		count, err := service.RevokeFamily(ctx, 2, "fid-1")
+		if err != nil {
+			t.Fatalf("RevokeFamily error: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("expected 1 session terminated, got %d", count)
+		}
+		if cache.expirations[constant.CacheRevokedFamilyPrefix+"fid-1"] != time.Hour {
+			t.Fatalf("expected family mark ttl 1h, got %v", cache.expirations[constant.CacheRevokedFamilyPrefix+"fid-1"])
+		}
+		// 已轮换出的新 refresh token 同样失效
+		if _, ok := cache.values[constant.CacheRefreshJtiPrefix+"sid-2"]; ok {
+			t.Fatal("expected successor refresh jti deleted")
+		}
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 1 || sessions[0].SessionId != "sid-3" {
+			t.Fatalf("expected other family session kept, got %+v", sessions)
+		}
+	})
+
+	t.Run("touch", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+
+		// 间隔内同 IP 不刷新，IP 变化立即刷新
+		if err := service.Touch(ctx, 2, "sid-1", "127.0.0.1"); err != nil {
+			t.Fatalf("Touch error: %v", err)
+		}
+		if err := service.Touch(ctx, 2, "sid-1", "10.0.0.2"); err != nil {
+			t.Fatalf("Touch error: %v", err)
+		}
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 1 || sessions[0].IP != "10.0.0.2" || sessions[0].Device != "Mac" {
+			t.Fatalf("expected session ip refreshed and other fields kept, got %+v", sessions)
+		}
+	})
+
+	t.Run("touch does not restore kicked session", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+		if err := service.KickSession(ctx, 2, "sid-1"); err != nil {
+			t.Fatalf("KickSession error: %v", err)
+		}
+		if err := service.Touch(ctx, 2, "sid-1", "10.0.0.2"); err != nil {
+			t.Fatalf("Touch error: %v", err)
+		}
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 0 {
+			t.Fatalf("expected kicked session to stay removed, got %+v", sessions)
+		}
+	})
+
+	t.Run("refresh jti states", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{})
+		if err := service.SaveRefreshJti(ctx, "sid-1", time.Hour); err != nil {
+			t.Fatalf("SaveRefreshJti error: %v", err)
+		}
+		if cache.expirations[constant.CacheRefreshJtiPrefix+"sid-1"] != time.Hour {
+			t.Fatalf("expected jti ttl 1h, got %v", cache.expirations[constant.CacheRefreshJtiPrefix+"sid-1"])
+		}
+		for _, want := range []RefreshJtiState{RefreshJtiConsumed, RefreshJtiReused, RefreshJtiReused} {
+			state, err := service.UseRefreshJti(ctx, "sid-1")
+			if err != nil || state != want {
+				t.Fatalf("expected state %v, got %v err=%v", want, state, err)
+			}
+		}
+
+		// 登出、踢出或淘汰后 jti 被删除，再次使用按无效令牌处理，不是重复使用
+		registerTestSession(t, service, 2, "sid-2")
+		if err := service.SaveRefreshJti(ctx, "sid-2", time.Hour); err != nil {
+			t.Fatalf("SaveRefreshJti error: %v", err)
+		}
+		if err := service.KickSession(ctx, 2, "sid-2"); err != nil {
+			t.Fatalf("KickSession error: %v", err)
+		}
+		if state, err := service.UseRefreshJti(ctx, "sid-2"); err != nil || state != RefreshJtiMissing {
+			t.Fatalf("expected kicked jti missing, got %v err=%v", state, err)
+		}
+	})
+
+	t.Run("kick all sessions", func(t *testing.T) {
+		cache := newFakeCache()
+		service, _ := newTestSessionService(cache, SessionConfig{})
+		registerTestSession(t, service, 2, "sid-1")
+		registerTestSession(t, service, 2, "sid-2")
+
+		count, err := service.KickAllSessions(ctx, 2)
+		if err != nil {
+			t.Fatalf("KickAllSessions error: %v", err)
+		}
+		if count != 2 {
+			t.Fatalf("expected 2 sessions kicked, got %d", count)
+		}
+		if _, ok := cache.values[constant.CacheRevokedUserPrefix+"2"]; !ok {
+			t.Fatal("expected user watermark set")
+		}
+		sessions, _ := service.ListSessions(ctx, 2)
+		if len(sessions) != 0 {
+			t.Fatalf("expected no sessions left, got %+v", sessions)
+		}
+	})
+}
+
+func TestDeviceFromUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"", "Unknown"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)", "iPhone"},
+		{"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)", "iPad"},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8)", "Android"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "Windows"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0)", "Mac"},
+		{"Mozilla/5.0 (X11; Linux x86_64)", "Linux"},
+		{"curl/8.0", "Other"},
+	}
+	for _, tt := range tests {
+		if got := DeviceFromUserAgent(tt.userAgent); got != tt.want {
+			t.Fatalf("DeviceFromUserAgent(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
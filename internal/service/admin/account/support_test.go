@@ -2,9 +2,13 @@ package account
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"sort"
 	"time"
 
+	"gorm.io/gorm"
+
 	"snowgo/internal/dal/model"
 	"snowgo/internal/dal/query"
 	daoAccount "snowgo/internal/dao/admin/account"
@@ -21,11 +25,16 @@ func testUserCtx() context.Context {
 }
 
 type fakeUserRepo struct {
+	userById          *model.SysUser
+	userByIdErr       error
 	userByUsername    *model.SysUser
 	userByUsernameErr error
 	roleIds           []int32
 	roleIdsErr        error
 	getRoleIDsCalls   int
+	roleList          []*model.SysRole
+	roleGrants        []*model.SysUserRole // 为空时由 roleIds 生成永久授权
+	roleGrantList     []*daoAccount.UserRoleGrant
 }
 
 func (f *fakeUserRepo) CreateUser(context.Context, *query.Query, *model.SysUser) (*model.SysUser, error) {
@@ -48,12 +57,20 @@ func (f *fakeUserRepo) DeleteUserRole(context.Context, *query.Query, int32) erro
 	panic("not implemented")
 }
 
+func (f *fakeUserRepo) DeleteUserRoleByIds(context.Context, *query.Query, []int64) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) GetExpiredUserRoles(context.Context, *query.Query, time.Time, int) ([]*model.SysUserRole, error) {
+	panic("not implemented")
+}
+
 func (f *fakeUserRepo) DeleteById(context.Context, *query.Query, int32) error {
 	panic("not implemented")
 }
 
 func (f *fakeUserRepo) GetRoleListByUserId(context.Context, int32) ([]*model.SysRole, error) {
-	panic("not implemented")
+	return f.roleList, nil
 }
 
 func (f *fakeUserRepo) GetRoleIdsByUserId(context.Context, int32) ([]int32, error) {
@@ -61,6 +78,25 @@ func (f *fakeUserRepo) GetRoleIdsByUserId(context.Context, int32) ([]int32, erro
 	return f.roleIds, f.roleIdsErr
 }
 
+func (f *fakeUserRepo) GetUserRoleGrants(context.Context, int32) ([]*model.SysUserRole, error) {
+	f.getRoleIDsCalls++
+	if f.roleIdsErr != nil {
+		return nil, f.roleIdsErr
+	}
+	if f.roleGrants != nil {
+		return f.roleGrants, nil
+	}
+	grants := make([]*model.SysUserRole, 0, len(f.roleIds))
+	for _, roleId := range f.roleIds {
+		grants = append(grants, &model.SysUserRole{RoleID: roleId})
+	}
+	return grants, nil
+}
+
+func (f *fakeUserRepo) GetRoleGrantListByUserId(context.Context, int32) ([]*daoAccount.UserRoleGrant, error) {
+	return f.roleGrantList, nil
+}
+
 func (f *fakeUserRepo) IsNameTelDuplicate(context.Context, string, string, int32) (bool, error) {
 	panic("not implemented")
 }
@@ -73,25 +109,69 @@ func (f *fakeUserRepo) CountRoleByIds(context.Context, *query.Query, []int32) (i
 	panic("not implemented")
 }
 
-func (f *fakeUserRepo) GetUserById(context.Context, int32) (*model.SysUser, error) {
+func (f *fakeUserRepo) IsDeptExists(context.Context, *query.Query, int32) (bool, error) {
 	panic("not implemented")
 }
 
+func (f *fakeUserRepo) GetUserById(context.Context, int32) (*model.SysUser, error) {
+	if f.userById == nil && f.userByIdErr == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.userById, f.userByIdErr
+}
+
 func (f *fakeUserRepo) GetUserByUsername(context.Context, *query.Query, string) (*model.SysUser, error) {
 	return f.userByUsername, f.userByUsernameErr
 }
 
+func (f *fakeUserRepo) LockUserByUsername(context.Context, *query.Query, string) (*model.SysUser, error) {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) SaveUserLock(context.Context, *query.Query, int32, int8, int32, *time.Time) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) ClearUserLock(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
 func (f *fakeUserRepo) GetUserList(context.Context, *daoAccount.UserListCondition) ([]*model.SysUser, int64, error) {
 	panic("not implemented")
 }
 
-func (f *fakeUserRepo) ResetPwdById(context.Context, *query.Query, int32, string) error {
+func (f *fakeUserRepo) DeleteUserMfa(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) DeleteUserOidc(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) DeleteUserApiKeys(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) DeleteUserPwdHistory(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) ResetPwdById(context.Context, *query.Query, int32, string, int8) error {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) GetPwdHistory(context.Context, *query.Query, int32, int) ([]*model.SysUserPwdHistory, error) {
+	panic("not implemented")
+}
+
+func (f *fakeUserRepo) CreatePwdHistory(context.Context, *query.Query, *model.SysUserPwdHistory, int) error {
 	panic("not implemented")
 }
 
 type fakeCache struct {
 	values      map[string]string
 	sets        map[string]string
+	hashes      map[string]map[string]string
 	deletes     []string
 	expirations map[string]time.Duration
 }
@@ -100,14 +180,114 @@ func newFakeCache() *fakeCache {
 	return &fakeCache{
 		values:      make(map[string]string),
 		sets:        make(map[string]string),
+		hashes:      make(map[string]map[string]string),
 		expirations: make(map[string]time.Duration),
 	}
 }
 
-func (f *fakeCache) Eval(context.Context, string, []string, ...any) (any, error) {
+func (f *fakeCache) Eval(_ context.Context, script string, keys []string, args ...any) (any, error) {
+	switch script {
+	case sessionRegisterScript:
+		return f.evalSessionRegister(keys[0], args...), nil
+	case sessionTouchScript:
+		return f.evalSessionTouch(keys[0], args...), nil
+	case sessionRotateScript:
+		return f.evalSessionRotate(keys[0], args...), nil
+	case refreshJtiUseScript:
+		value, ok := f.values[keys[0]]
+		if !ok {
+			return int64(0), nil
+		}
+		if value == args[0] {
+			return int64(2), nil
+		}
+		f.values[keys[0]] = args[0].(string)
+		return int64(1), nil
+	}
 	panic("not implemented")
 }
 
+// evalSessionRegister 模拟会话登记脚本
+func (f *fakeCache) evalSessionRegister(key string, args ...any) any {
+	sessionId, raw, now := args[0].(string), args[1].(string), args[2].(int64)
+	limit, reject := args[3].(int), args[4].(string)
+	type liveSession struct {
+		id      string
+		loginMs int64
+	}
+	live := make([]liveSession, 0)
+	for sid, value := range f.hashes[key] {
+		var rec sessionRecord
+		if err := json.Unmarshal([]byte(value), &rec); err != nil || rec.ExpireMs <= now {
+			delete(f.hashes[key], sid)
+			continue
+		}
+		live = append(live, liveSession{id: sid, loginMs: rec.LoginMs})
+	}
+	result := []any{int64(1)}
+	if limit > 0 && len(live) >= limit {
+		if reject == "1" {
+			return []any{int64(0)}
+		}
+		sort.Slice(live, func(i, j int) bool { return live[i].loginMs < live[j].loginMs })
+		for _, old := range live[:len(live)-limit+1] {
+			delete(f.hashes[key], old.id)
+			result = append(result, old.id)
+		}
+	}
+	_ = f.HSet(context.Background(), key, sessionId, raw)
+	f.expirations[key] = time.Duration(args[5].(int64)) * time.Millisecond
+	return result
+}
+
+// evalSessionRotate 模拟会话轮换脚本
+func (f *fakeCache) evalSessionRotate(key string, args ...any) any {
+	oldId, newId, familyId := args[0].(string), args[1].(string), args[2].(string)
+	raw, ok := f.hashes[key][oldId]
+	if !ok {
+		return int64(0)
+	}
+	delete(f.hashes[key], oldId)
+	var rec sessionRecord
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return int64(0)
+	}
+	rec.SessionId, rec.FamilyId = newId, familyId
+	rec.LastSeenAt, _ = time.Parse(time.RFC3339Nano, args[3].(string))
+	rec.SeenMs = args[4].(int64)
+	rec.ExpireAt, _ = time.Parse(time.RFC3339Nano, args[5].(string))
+	rec.ExpireMs = args[6].(int64)
+	value, _ := json.Marshal(&rec)
+	f.hashes[key][newId] = string(value)
+	f.expirations[key] = time.Duration(args[7].(int64)) * time.Millisecond
+	return int64(1)
+}
+
+// evalSessionTouch 模拟会话刷新脚本
+func (f *fakeCache) evalSessionTouch(key string, args ...any) any {
+	sessionId, now, seenAt, ip := args[0].(string), args[1].(int64), args[2].(string), args[3].(string)
+	raw, ok := f.hashes[key][sessionId]
+	if !ok {
+		return int64(0)
+	}
+	info := make(map[string]any)
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return int64(0)
+	}
+	seenMs, _ := info["seen_ms"].(float64)
+	if now-int64(seenMs) < args[4].(int64) && info["ip"] == ip {
+		return int64(0)
+	}
+	info["last_seen_at"] = seenAt
+	info["seen_ms"] = now
+	if ip != "" {
+		info["ip"] = ip
+	}
+	data, _ := json.Marshal(info)
+	f.hashes[key][sessionId] = string(data)
+	return int64(1)
+}
+
 func (f *fakeCache) Get(_ context.Context, key string) (string, bool, error) {
 	value, ok := f.values[key]
 	return value, ok, nil
@@ -136,20 +316,36 @@ func (f *fakeCache) DecrBy(context.Context, string, int64) (int64, error) {
 	panic("not implemented")
 }
 
-func (f *fakeCache) HSet(context.Context, string, string, string) error {
-	panic("not implemented")
+func (f *fakeCache) HSet(_ context.Context, key string, field string, value string) error {
+	if f.hashes[key] == nil {
+		f.hashes[key] = make(map[string]string)
+	}
+	f.hashes[key][field] = value
+	return nil
 }
 
-func (f *fakeCache) HGet(context.Context, string, string) (string, bool, error) {
-	panic("not implemented")
+func (f *fakeCache) HGet(_ context.Context, key string, field string) (string, bool, error) {
+	value, ok := f.hashes[key][field]
+	return value, ok, nil
 }
 
-func (f *fakeCache) HGetAll(context.Context, string) (map[string]string, error) {
-	panic("not implemented")
+func (f *fakeCache) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	result := make(map[string]string, len(f.hashes[key]))
+	for field, value := range f.hashes[key] {
+		result[field] = value
+	}
+	return result, nil
 }
 
-func (f *fakeCache) HDel(context.Context, string, ...string) (int64, error) {
-	panic("not implemented")
+func (f *fakeCache) HDel(_ context.Context, key string, fields ...string) (int64, error) {
+	var deleted int64
+	for _, field := range fields {
+		if _, ok := f.hashes[key][field]; ok {
+			delete(f.hashes[key], field)
+			deleted++
+		}
+	}
+	return deleted, nil
 }
 
 func (f *fakeCache) HIncrBy(context.Context, string, string, int64) (int64, error) {
@@ -176,16 +372,24 @@ func (f *fakeCache) ZCard(context.Context, string) (int64, error) {
 	panic("not implemented")
 }
 
-func (f *fakeCache) Exists(context.Context, string) (bool, error) {
-	panic("not implemented")
+func (f *fakeCache) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := f.values[key]
+	return ok, nil
 }
 
-func (f *fakeCache) Expire(context.Context, string, time.Duration) error {
-	panic("not implemented")
+func (f *fakeCache) Expire(_ context.Context, key string, expiration time.Duration) error {
+	f.expirations[key] = expiration
+	return nil
 }
 
-func (f *fakeCache) TTL(context.Context, string) (time.Duration, error) {
-	panic("not implemented")
+func (f *fakeCache) TTL(_ context.Context, key string) (time.Duration, error) {
+	if _, ok := f.values[key]; !ok {
+		return -2, nil
+	}
+	if expiration, ok := f.expirations[key]; ok {
+		return expiration, nil
+	}
+	return -1, nil
 }
 
 var errTestDAO = errors.New("dao error")
@@ -194,6 +398,11 @@ type fakeRoleRepo struct {
 	menuList        []*model.SysMenu
 	menuListErr     error
 	getMenuListCall int
+	menuListRoleIds []int32
+	deptIds         []int32
+	inherits        []*model.SysRoleInherit
+	userRoleIds     []int32
+	roleMenuIds     []int32
 }
 
 func (f *fakeRoleRepo) IsCodeExists(context.Context, string, int32) (bool, error) {
@@ -248,8 +457,9 @@ func (f *fakeRoleRepo) GetMenuPermsByRoleIds(context.Context, []int32) ([]string
 	panic("not implemented")
 }
 
-func (f *fakeRoleRepo) GetMenuListByRoleId(context.Context, int32) ([]*model.SysMenu, error) {
+func (f *fakeRoleRepo) GetMenuListByRoleIds(_ context.Context, roleIds []int32) ([]*model.SysMenu, error) {
 	f.getMenuListCall++
+	f.menuListRoleIds = roleIds
 	return f.menuList, f.menuListErr
 }
 
@@ -257,14 +467,117 @@ func (f *fakeRoleRepo) ListRoleMenuPerms(context.Context) ([]*daoAccount.RoleMen
 	panic("not implemented")
 }
 
-func (f *fakeRoleRepo) GetUserMenuIds(context.Context, *query.Query, int32) ([]int32, error) {
+func (f *fakeRoleRepo) GetRoleIdsByUserId(context.Context, *query.Query, int32) ([]int32, error) {
+	return f.userRoleIds, nil
+}
+
+func (f *fakeRoleRepo) GetMenuIdsByRoleIds(context.Context, *query.Query, []int32) ([]int32, error) {
+	return f.roleMenuIds, nil
+}
+
+func (f *fakeRoleRepo) CountRoleByIds(_ context.Context, _ *query.Query, ids []int32) (int64, error) {
+	return int64(len(ids)), nil
+}
+
+func (f *fakeRoleRepo) CreateRoleInherit(context.Context, *query.Query, []*model.SysRoleInherit) error {
+	panic("not implemented")
+}
+
+func (f *fakeRoleRepo) DeleteRoleInherit(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeRoleRepo) IsInherited(context.Context, *query.Query, int32) (bool, error) {
 	panic("not implemented")
 }
 
+func (f *fakeRoleRepo) GetAllRoleInherits(context.Context) ([]*model.SysRoleInherit, error) {
+	return f.inherits, nil
+}
+
+func (f *fakeRoleRepo) LockRoleInherits(context.Context, *query.Query) ([]*model.SysRoleInherit, error) {
+	return f.inherits, nil
+}
+
 func (f *fakeRoleRepo) IsSuperAdmin(context.Context, *query.Query, int32) (bool, error) {
 	panic("not implemented")
 }
 
+func (f *fakeRoleRepo) CreateRoleDept(context.Context, *query.Query, []*model.SysRoleDept) error {
+	panic("not implemented")
+}
+
+func (f *fakeRoleRepo) DeleteRoleDept(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeRoleRepo) GetDeptIdsByRoleIds(context.Context, []int32) ([]int32, error) {
+	return f.deptIds, nil
+}
+
+type fakeDeptRepo struct {
+	allDepts       []*model.SysDept
+	getAllDeptsNum int
+}
+
+func (f *fakeDeptRepo) CreateDept(context.Context, *query.Query, *model.SysDept) (*model.SysDept, error) {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) UpdateDept(context.Context, *query.Query, *model.SysDept) (*model.SysDept, error) {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) UpdateParent(context.Context, *query.Query, int32, int32, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) UpdateSortOrder(context.Context, *query.Query, int32, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) DeleteById(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) GetById(_ context.Context, _ *query.Query, id int32) (*model.SysDept, error) {
+	for _, d := range f.allDepts {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeDeptRepo) LockDepts(context.Context, *query.Query) ([]*model.SysDept, error) {
+	return f.allDepts, nil
+}
+
+func (f *fakeDeptRepo) CountByIds(context.Context, *query.Query, []int32) (int64, error) {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) GetByParentId(context.Context, *query.Query, int32) ([]*model.SysDept, error) {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) GetAllDepts(context.Context) ([]*model.SysDept, error) {
+	f.getAllDeptsNum++
+	return f.allDepts, nil
+}
+
+func (f *fakeDeptRepo) IsNameExists(context.Context, *query.Query, int32, string, int32) (bool, error) {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) IsUsedByUser(context.Context, *query.Query, int32) (bool, error) {
+	panic("not implemented")
+}
+
+func (f *fakeDeptRepo) DeleteRoleDept(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
 type fakeMenuRepo struct {
 	allMenus       []*model.SysMenu
 	allMenusErr    error
@@ -311,3 +624,7 @@ func (f *fakeMenuRepo) IsPathExists(context.Context, *query.Query, string, int32
 func (f *fakeMenuRepo) GetRoleIdsByIds(context.Context, int32) ([]int32, error) {
 	panic("not implemented")
 }
+
+func (f *fakeMenuRepo) GetAllRoleInherits(context.Context) ([]*model.SysRoleInherit, error) {
+	panic("not implemented")
+}
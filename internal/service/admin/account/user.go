@@ -2,6 +2,8 @@ package account
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,16 +37,30 @@ type UserRepo interface {
 	CreateUserRole(ctx context.Context, q *query.Query, userRole *model.SysUserRole) error
 	CreateUserRoleInBatches(ctx context.Context, q *query.Query, userRoleList []*model.SysUserRole) error
 	DeleteUserRole(ctx context.Context, q *query.Query, userId int32) error
+	DeleteUserRoleByIds(ctx context.Context, q *query.Query, ids []int64) error
+	GetExpiredUserRoles(ctx context.Context, q *query.Query, now time.Time, limit int) ([]*model.SysUserRole, error)
+	DeleteUserMfa(ctx context.Context, q *query.Query, userId int32) error
+	DeleteUserOidc(ctx context.Context, q *query.Query, userId int32) error
+	DeleteUserApiKeys(ctx context.Context, q *query.Query, userId int32) error
+	DeleteUserPwdHistory(ctx context.Context, q *query.Query, userId int32) error
 	DeleteById(ctx context.Context, q *query.Query, userId int32) error
 	GetRoleListByUserId(ctx context.Context, userId int32) ([]*model.SysRole, error)
+	GetRoleGrantListByUserId(ctx context.Context, userId int32) ([]*account.UserRoleGrant, error)
 	GetRoleIdsByUserId(ctx context.Context, userId int32) ([]int32, error)
+	GetUserRoleGrants(ctx context.Context, userId int32) ([]*model.SysUserRole, error)
 	IsNameTelDuplicate(ctx context.Context, username, tel string, userId int32) (bool, error)
 	IsExistByRoleId(ctx context.Context, roleId int32) (bool, error)
 	CountRoleByIds(ctx context.Context, q *query.Query, roleId []int32) (int64, error)
+	IsDeptExists(ctx context.Context, q *query.Query, deptId int32) (bool, error)
 	GetUserById(ctx context.Context, userId int32) (*model.SysUser, error)
 	GetUserByUsername(ctx context.Context, q *query.Query, username string) (*model.SysUser, error)
+	LockUserByUsername(ctx context.Context, q *query.Query, username string) (*model.SysUser, error)
+	SaveUserLock(ctx context.Context, q *query.Query, userId int32, status int8, count int32, until *time.Time) error
+	ClearUserLock(ctx context.Context, q *query.Query, userId int32) error
 	GetUserList(ctx context.Context, condition *account.UserListCondition) ([]*model.SysUser, int64, error)
-	ResetPwdById(ctx context.Context, q *query.Query, userId int32, password string) error
+	ResetPwdById(ctx context.Context, q *query.Query, userId int32, password string, mustChange int8) error
+	GetPwdHistory(ctx context.Context, q *query.Query, userId int32, limit int) ([]*model.SysUserPwdHistory, error)
+	CreatePwdHistory(ctx context.Context, q *query.Query, history *model.SysUserPwdHistory, keep int) error
 }
 
 type UserService struct {
@@ -52,17 +68,37 @@ type UserService struct {
 	userDao     UserRepo
 	cache       xcache.Cache
 	roleService *RoleService
+	deptService *DeptService
+	revocation  *RevocationService
 	logService  contract.OperationLogWriter
+	pwdPolicy   PasswordPolicy
+	lockout     LockoutPolicy
+	permCache   *PermCache
+
+	permMatchers permMatcherCache
+}
+
+// UserServiceOptions 用户服务的可选策略，零值表示不启用对应策略
+type UserServiceOptions struct {
+	PwdPolicy PasswordPolicy // 密码生命周期策略
+	Lockout   LockoutPolicy  // 登录失败渐进锁定策略
+	PermCache *PermCache     // 进程内权限缓存，为空时不缓存
 }
 
 func NewUserService(db *repo.Repository, userDao UserRepo, cache xcache.Cache, roleService *RoleService,
-	logService contract.OperationLogWriter) *UserService {
+	deptService *DeptService, revocation *RevocationService, logService contract.OperationLogWriter,
+	opts UserServiceOptions) *UserService {
 	return &UserService{
 		db:          db,
 		cache:       cache,
 		userDao:     userDao,
 		roleService: roleService,
+		deptService: deptService,
+		revocation:  revocation,
 		logService:  logService,
+		pwdPolicy:   opts.PwdPolicy,
+		lockout:     opts.Lockout,
+		permCache:   opts.PermCache,
 	}
 }
 
@@ -70,27 +106,37 @@ type UserParam struct {
 	ID       int32   `json:"id"`
 	Username string  `json:"username" binding:"required,max=64"`
 	Password string  `json:"password"`
-	Tel      string  `json:"tel" binding:"required"`
+	Tel      string  `json:"tel"` // 普通用户必填，服务账号为空
 	Nickname *string `json:"nickname"`
 	Email    *string `json:"email"`
 	Remark   *string `json:"remark"`
 	Status   *int8   `json:"status"`
-	RoleIds  []int32 `json:"role_ids"`
+	UserType int8    `json:"user_type"` // 仅创建时生效：1 普通用户（默认）, 2 服务账号
+	DeptId   *int32  `json:"dept_id"`   // 所属部门，更新时 nil 不修改、0 清空
+	RoleIds  []int32 `json:"role_ids"`  // 永久授权的角色
+	// RoleGrants 限时授权的角色，不能与 RoleIds 重复
+	RoleGrants []*RoleGrantParam `json:"role_grants"`
 }
 
 type UserInfo struct {
-	ID        int32
-	Username  string
-	Tel       string
-	Nickname  string
-	Email     string
-	Remark    string
-	Status    int8
-	CreatedBy int32
-	UpdatedBy int32
-	RoleList  []*UserRole
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int32
+	Username    string
+	Tel         string
+	Nickname    string
+	Email       string
+	Remark      string
+	Status      int8
+	UserType    int8
+	DeptId      int32
+	DeptName    string
+	LockStatus  int8       // 登录锁定状态，临时锁定到期后为未锁定
+	LockCount   int32      // 连续锁定次数
+	LockedUntil *time.Time // 临时锁定截止时间
+	CreatedBy   int32
+	UpdatedBy   int32
+	RoleList    []*UserRole
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type UserPermissionInfo struct {
@@ -107,9 +153,11 @@ type UserPermissionInfo struct {
 }
 
 type UserRole struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Code string `json:"code"`
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Code       string     `json:"code"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`  // 限时授权生效时间
+	ValidUntil *time.Time `json:"valid_until,omitempty"` // 限时授权失效时间
 }
 
 type UserList struct {
@@ -129,6 +177,8 @@ type UserListCondition struct {
 	Tel      string  `json:"tel" form:"tel"`
 	Nickname string  `json:"nickname" form:"nickname"`
 	Status   *int8   `json:"status" form:"status"`
+	UserType *int8   `json:"user_type" form:"user_type"`
+	DeptId   int32   `json:"dept_id" form:"dept_id"` // 按部门过滤，包含下级部门
 	Offset   int32   `json:"offset" form:"offset"`
 	Limit    int32   `json:"limit" form:"limit"`
 }
@@ -139,6 +189,10 @@ var (
 	ErrAuth             = e.NewBizError(e.AuthError)
 	ErrRoleNotExist     = e.NewBizError(e.UserRoleNotExist)
 	ErrDeleteSelf       = e.NewBizError(e.UserDeleteSelfError)
+	ErrUserNameTelEmpty = e.NewBizError(e.UserNameTelEmptyError)
+	ErrUserTypeInvalid  = e.NewBizError(e.UserTypeInvalid)
+	ErrUserTypeNotAllow = e.NewBizError(e.UserTypeUnsupported)
+	ErrUserDeptNotExist = e.NewBizError(e.UserDeptNotExist)
 )
 
 var (
@@ -188,6 +242,30 @@ func (u *UserService) CreateUser(ctx context.Context, userParam *UserParam) (int
 		return 0, err
 	}
 
+	userType := userParam.UserType
+	if userType == 0 {
+		userType = constant.UserTypeNormal
+	}
+	password := userParam.Password
+	switch userType {
+	case constant.UserTypeNormal:
+		if userParam.Tel == "" {
+			return 0, ErrUserNameTelEmpty
+		}
+		// 校验密码强度
+		if bizErr := u.pwdPolicy.Validate(password); bizErr != nil {
+			return 0, bizErr
+		}
+	case constant.UserTypeService:
+		// 服务账号不允许密码登录，写入随机密码占位
+		password, err = randomPassword()
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, ErrUserTypeInvalid
+	}
+
 	// 检查用户名或电话是否重复（事务外快速失败）
 	isDuplicate, err := u.userDao.IsNameTelDuplicate(ctx, userParam.Username, userParam.Tel, 0)
 	if err != nil {
@@ -197,42 +275,55 @@ func (u *UserService) CreateUser(ctx context.Context, userParam *UserParam) (int
 		return 0, ErrUserNameTelExist
 	}
 
-	// 校验密码强度
-	if bizErr := validatePassword(userParam.Password); bizErr != nil {
-		return 0, bizErr
-	}
-
 	// 加密密码
-	pwd, err := xcryption.HashPassword(userParam.Password)
+	pwd, err := xcryption.HashPassword(password)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "密码加密异常: %v", err)
 		return 0, fmt.Errorf("密码加密异常: %w", err)
 	}
+	now := time.Now()
+	userRoles, err := buildUserRoles(userParam.RoleIds, userParam.RoleGrants, now)
+	if err != nil {
+		return 0, err
+	}
 	activeStatus := constant.UserStatusActive
+	mustChange := constant.PwdMustChangeNo
+	if u.pwdPolicy.ForceChangeOnFirstLogin && userType == constant.UserTypeNormal {
+		mustChange = constant.PwdMustChangeYes
+	}
 	var userObj *model.SysUser
 	err = u.db.WriteQuery().Transaction(func(tx *query.Query) error {
 		// 检查设置的角色id是否存在（事务内防止并发删除）
-		if len(userParam.RoleIds) > 0 {
-			roleLen, err := u.userDao.CountRoleByIds(ctx, tx, userParam.RoleIds)
+		if len(userRoles) > 0 {
+			roleIds := userRoleIds(userRoles)
+			roleLen, err := u.userDao.CountRoleByIds(ctx, tx, roleIds)
 			if err != nil {
 				xlogger.ErrorfCtx(ctx, "查询角色数量存在异常: %v", err)
 				return fmt.Errorf("查询角色数量存在异常: %w", err)
 			}
-			if roleLen != int64(len(userParam.RoleIds)) {
+			if roleLen != int64(len(roleIds)) {
 				return ErrRoleNotExist
 			}
 		}
+		// 检查设置的部门是否存在（事务内防止并发删除）
+		if err := u.checkDept(ctx, tx, userParam.DeptId); err != nil {
+			return err
+		}
 
 		// 创建用户
 		userObj, err = u.userDao.CreateUser(ctx, tx, &model.SysUser{
-			Username:  userParam.Username,
-			Password:  pwd,
-			Tel:       userParam.Tel,
-			Nickname:  userParam.Nickname,
-			Email:     userParam.Email,
-			Remark:    userParam.Remark,
-			Status:    &activeStatus,
-			CreatedBy: &userContext.UserId,
+			Username:      userParam.Username,
+			Password:      pwd,
+			Tel:           common.PtrIfNonZero(userParam.Tel),
+			Nickname:      userParam.Nickname,
+			Email:         userParam.Email,
+			Remark:        userParam.Remark,
+			Status:        &activeStatus,
+			UserType:      &userType,
+			PwdChangedAt:  &now,
+			PwdMustChange: &mustChange,
+			DeptID:        common.PtrIfNonZero(common.DerefOrZero(userParam.DeptId)),
+			CreatedBy:     &userContext.UserId,
 		})
 		if err != nil {
 			// 唯一索引冲突兜底
@@ -242,19 +333,20 @@ func (u *UserService) CreateUser(ctx context.Context, userParam *UserParam) (int
 			xlogger.ErrorfCtx(ctx, "用户创建失败 username=%s err: %v", userParam.Username, err)
 			return fmt.Errorf("用户创建失败: %w", err)
 		}
+		if userType == constant.UserTypeNormal {
+			if err := u.savePwdHistory(ctx, tx, userObj.ID, pwd); err != nil {
+				return err
+			}
+		}
 
 		// 创建用户-role关联, 设置roleId才去创建
-		if len(userParam.RoleIds) > 0 {
-			userRoles := make([]*model.SysUserRole, 0, len(userParam.RoleIds))
-			for _, roleId := range userParam.RoleIds {
-				userRoles = append(userRoles, &model.SysUserRole{
-					UserID: userObj.ID,
-					RoleID: roleId,
-				})
+		if len(userRoles) > 0 {
+			for _, userRole := range userRoles {
+				userRole.UserID = userObj.ID
 			}
 			err = u.userDao.CreateUserRoleInBatches(ctx, tx, userRoles)
 			if err != nil {
-				xlogger.ErrorfCtx(ctx, "用户与角色关联关系创建失败 user_id=%d role_ids=%v err: %v", userObj.ID, userParam.RoleIds, err)
+				xlogger.ErrorfCtx(ctx, "用户与角色关联关系创建失败 user_id=%d role_ids=%v err: %v", userObj.ID, userRoleIds(userRoles), err)
 				return fmt.Errorf("用户与角色关联关系创建失败: %w", err)
 			}
 		}
@@ -306,6 +398,12 @@ func (u *UserService) UpdateUser(ctx context.Context, userParam *UserParam) (int
 		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userParam.ID, err)
 		return 0, fmt.Errorf("用户信息查询失败: %w", err)
 	}
+	// 用户类型创建后不可变更，普通用户电话必填，服务账号不设置电话
+	if isServiceAccount(oldUser) {
+		userParam.Tel = ""
+	} else if userParam.Tel == "" {
+		return 0, ErrUserNameTelEmpty
+	}
 
 	// 检查用户名，或者电话是否存在
 	isDuplicate, err := u.userDao.IsNameTelDuplicate(ctx, userParam.Username, userParam.Tel, userParam.ID)
@@ -317,28 +415,45 @@ func (u *UserService) UpdateUser(ctx context.Context, userParam *UserParam) (int
 		return 0, ErrUserNameTelExist
 	}
 
+	userRoles, err := buildUserRoles(userParam.RoleIds, userParam.RoleGrants, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	// 原角色，用于判断角色是否变更
+	oldRoleIds, err := u.userDao.GetRoleIdsByUserId(ctx, userParam.ID)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询用户角色id失败 uid=%d: %v", userParam.ID, err)
+		return 0, fmt.Errorf("查询用户角色id失败: %w", err)
+	}
+
 	err = u.db.WriteQuery().Transaction(func(tx *query.Query) error {
 		// 检查设置的角色id是否存在（事务内防止并发删除）
-		if len(userParam.RoleIds) > 0 {
-			roleLen, err := u.userDao.CountRoleByIds(ctx, tx, userParam.RoleIds)
+		if len(userRoles) > 0 {
+			roleIds := userRoleIds(userRoles)
+			roleLen, err := u.userDao.CountRoleByIds(ctx, tx, roleIds)
 			if err != nil {
 				xlogger.ErrorfCtx(ctx, "查询角色数量存在异常: %v", err)
 				return fmt.Errorf("查询角色数量存在异常: %w", err)
 			}
-			if roleLen != int64(len(userParam.RoleIds)) {
+			if roleLen != int64(len(roleIds)) {
 				return ErrRoleNotExist
 			}
 		}
+		// 检查设置的部门是否存在（事务内防止并发删除）
+		if err := u.checkDept(ctx, tx, userParam.DeptId); err != nil {
+			return err
+		}
 
 		// 更新用户（指针字段仅在非空时传入）
 		_, err = u.userDao.UpdateUser(ctx, tx, &model.SysUser{
 			ID:        userParam.ID,
 			Username:  userParam.Username,
-			Tel:       userParam.Tel,
+			Tel:       common.PtrIfNonZero(userParam.Tel),
 			Nickname:  userParam.Nickname,
 			Email:     userParam.Email,
 			Remark:    userParam.Remark,
 			Status:    userParam.Status,
+			DeptID:    userParam.DeptId,
 			UpdatedBy: &userContext.UserId,
 		})
 		if err != nil {
@@ -358,17 +473,13 @@ func (u *UserService) UpdateUser(ctx context.Context, userParam *UserParam) (int
 		}
 
 		// 创建用户-role关联, 设置roleId才去创建
-		if len(userParam.RoleIds) > 0 {
-			userRoles := make([]*model.SysUserRole, 0, len(userParam.RoleIds))
-			for _, roleId := range userParam.RoleIds {
-				userRoles = append(userRoles, &model.SysUserRole{
-					UserID: userParam.ID,
-					RoleID: roleId,
-				})
+		if len(userRoles) > 0 {
+			for _, userRole := range userRoles {
+				userRole.UserID = userParam.ID
 			}
 			err = u.userDao.CreateUserRoleInBatches(ctx, tx, userRoles)
 			if err != nil {
-				xlogger.ErrorfCtx(ctx, "用户与角色关联关系创建失败 user_id=%d role_ids=%v err: %v", userParam.ID, userParam.RoleIds, err)
+				xlogger.ErrorfCtx(ctx, "用户与角色关联关系创建失败 user_id=%d role_ids=%v err: %v", userParam.ID, userRoleIds(userRoles), err)
 				return fmt.Errorf("用户与角色关联关系创建失败: %w", err)
 			}
 		}
@@ -386,11 +497,12 @@ func (u *UserService) UpdateUser(ctx context.Context, userParam *UserParam) (int
 			AfterData: &model.SysUser{
 				ID:        userParam.ID,
 				Username:  userParam.Username,
-				Tel:       userParam.Tel,
+				Tel:       common.PtrIfNonZero(userParam.Tel),
 				Nickname:  userParam.Nickname,
 				Email:     userParam.Email,
 				Remark:    userParam.Remark,
 				Status:    userParam.Status,
+				DeptID:    userParam.DeptId,
 				UpdatedBy: &userContext.UserId,
 			},
 			Description: fmt.Sprintf("用户(%d-%s)修改了用户(%d-%s)信息",
@@ -415,9 +527,83 @@ func (u *UserService) UpdateUser(ctx context.Context, userParam *UserParam) (int
 	if _, err := u.cache.Delete(ctx, cacheKey); err != nil {
 		xlogger.ErrorfCtx(ctx, "清除用户对应角色缓存失败: %v", err)
 	}
+	u.permCache.InvalidateUsers(ctx, userParam.ID)
+
+	// 禁用用户或角色变更，吊销该用户已签发的token
+	disabled := userParam.Status != nil && *userParam.Status == constant.UserStatusDisabled &&
+		common.DerefOrZero(oldUser.Status) != constant.UserStatusDisabled
+	if disabled || !sameInt32Set(oldRoleIds, userRoleIds(userRoles)) {
+		u.revokeUserTokens(ctx, userParam.ID)
+	}
+	if disabled && isServiceAccount(oldUser) {
+		clearUserApiKeyCache(ctx, u.cache, userParam.ID)
+	}
 	return userParam.ID, nil
 }
 
+// checkDept 校验部门存在，nil 或 0 表示不设置部门
+func (u *UserService) checkDept(ctx context.Context, tx *query.Query, deptId *int32) error {
+	if common.DerefOrZero(deptId) == 0 {
+		return nil
+	}
+	exists, err := u.userDao.IsDeptExists(ctx, tx, *deptId)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询部门是否存在异常: %v", err)
+		return fmt.Errorf("查询部门是否存在异常: %w", err)
+	}
+	if !exists {
+		return ErrUserDeptNotExist
+	}
+	return nil
+}
+
+// deptNameMap 获取部门名称映射，失败只记录日志，不影响用户信息返回
+func (u *UserService) deptNameMap(ctx context.Context) map[int32]string {
+	names, err := u.deptService.GetDeptNameMap(ctx)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取部门名称异常: %v", err)
+		return nil
+	}
+	return names
+}
+
+// isServiceAccount 是否为服务账号
+func isServiceAccount(user *model.SysUser) bool {
+	return common.DerefOrZero(user.UserType) == constant.UserTypeService
+}
+
+// randomPassword 生成随机密码，用于不允许密码登录的服务账号
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成随机密码失败: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// revokeUserTokens 吊销用户已签发的token，失败只记录日志
+func (u *UserService) revokeUserTokens(ctx context.Context, userId int32) {
+	if err := u.revocation.RevokeUserTokens(ctx, userId); err != nil {
+		xlogger.ErrorfCtx(ctx, "吊销用户token失败 uid=%d: %v", userId, err)
+	}
+}
+
+// sameInt32Set 判断两个id集合是否相同（忽略顺序与重复）
+func sameInt32Set(a, b []int32) bool {
+	setA := make(map[int32]struct{}, len(a))
+	for _, v := range a {
+		setA[v] = struct{}{}
+	}
+	setB := make(map[int32]struct{}, len(b))
+	for _, v := range b {
+		if _, ok := setA[v]; !ok {
+			return false
+		}
+		setB[v] = struct{}{}
+	}
+	return len(setA) == len(setB)
+}
+
 // GetUserById 根据id获取用户信息
 func (u *UserService) GetUserById(ctx context.Context, userId int32) (*UserInfo, error) {
 	if userId <= 0 {
@@ -432,64 +618,108 @@ func (u *UserService) GetUserById(ctx context.Context, userId int32) (*UserInfo,
 		return nil, fmt.Errorf("用户信息查询失败: %w", err)
 	}
 
-	// 查询角色信息
-	roleList, err := u.userDao.GetRoleListByUserId(ctx, userId)
+	// 查询角色信息，包含未生效、已过期的限时授权
+	grantList, err := u.userDao.GetRoleGrantListByUserId(ctx, userId)
 	if err != nil {
 		return nil, fmt.Errorf("用户角色信息查询失败: %w", err)
 	}
-	roles := make([]*UserRole, 0, len(roleList))
-	for _, role := range roleList {
+	roles := make([]*UserRole, 0, len(grantList))
+	for _, grant := range grantList {
 		roles = append(roles, &UserRole{
-			ID:   role.ID,
-			Code: role.Code,
-			Name: common.DerefOrZero(role.Name),
+			ID:         grant.ID,
+			Code:       grant.Code,
+			Name:       common.DerefOrZero(grant.Name),
+			ValidFrom:  grant.ValidFrom,
+			ValidUntil: grant.ValidUntil,
 		})
 	}
+	deptId := common.DerefOrZero(user.DeptID)
+	var deptName string
+	if deptId > 0 {
+		deptName = u.deptNameMap(ctx)[deptId]
+	}
+	lockStatus, lockedUntil := userLockState(user, time.Now())
 	return &UserInfo{
-		ID:        user.ID,
-		Username:  user.Username,
-		Tel:       user.Tel,
-		Nickname:  common.DerefOrZero(user.Nickname),
-		Email:     common.DerefOrZero(user.Email),
-		Remark:    common.DerefOrZero(user.Remark),
-		Status:    common.DerefOrZero(user.Status),
-		CreatedBy: common.DerefOrZero(user.CreatedBy),
-		UpdatedBy: common.DerefOrZero(user.UpdatedBy),
-		RoleList:  roles,
-		CreatedAt: common.DerefOrZero(user.CreatedAt),
-		UpdatedAt: common.DerefOrZero(user.UpdatedAt),
+		ID:          user.ID,
+		Username:    user.Username,
+		Tel:         common.DerefOrZero(user.Tel),
+		Nickname:    common.DerefOrZero(user.Nickname),
+		Email:       common.DerefOrZero(user.Email),
+		Remark:      common.DerefOrZero(user.Remark),
+		Status:      common.DerefOrZero(user.Status),
+		UserType:    common.DerefOrZero(user.UserType),
+		DeptId:      deptId,
+		DeptName:    deptName,
+		LockStatus:  lockStatus,
+		LockCount:   common.DerefOrZero(user.LockCount),
+		LockedUntil: lockedUntil,
+		CreatedBy:   common.DerefOrZero(user.CreatedBy),
+		UpdatedBy:   common.DerefOrZero(user.UpdatedBy),
+		RoleList:    roles,
+		CreatedAt:   common.DerefOrZero(user.CreatedAt),
+		UpdatedAt:   common.DerefOrZero(user.UpdatedAt),
 	}, nil
 }
 
 // GetUserList 获取用户列表信息
 func (u *UserService) GetUserList(ctx context.Context, condition *UserListCondition) (*UserList, error) {
+	// 按当前登录用户的数据范围过滤
+	dataScope, err := u.GetDataScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 按部门过滤时包含其下级部门
+	var deptIds []int32
+	if condition.DeptId > 0 {
+		deptIds, err = u.deptService.GetDeptAndChildIds(ctx, condition.DeptId)
+		if err != nil {
+			return nil, err
+		}
+		if len(deptIds) == 0 {
+			// 部门不存在，按原id过滤得到空结果
+			deptIds = []int32{condition.DeptId}
+		}
+	}
 	userList, total, err := u.userDao.GetUserList(ctx, &account.UserListCondition{
-		Ids:      condition.Ids,
-		Username: condition.Username,
-		Tel:      condition.Tel,
-		Nickname: condition.Nickname,
-		Status:   condition.Status,
-		Offset:   condition.Offset,
-		Limit:    condition.Limit,
+		Ids:       condition.Ids,
+		Username:  condition.Username,
+		Tel:       condition.Tel,
+		Nickname:  condition.Nickname,
+		Status:    condition.Status,
+		UserType:  condition.UserType,
+		DeptIds:   deptIds,
+		Offset:    condition.Offset,
+		Limit:     condition.Limit,
+		DataScope: dataScope,
 	})
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "获取用户信息列表异常: %v", err)
 		return nil, fmt.Errorf("用户信息列表查询失败: %w", err)
 	}
+	deptNames := u.deptNameMap(ctx)
+	now := time.Now()
 	userInfoList := make([]*UserInfo, 0, len(userList))
 	for _, user := range userList {
+		deptId := common.DerefOrZero(user.DeptID)
+		lockStatus, lockedUntil := userLockState(user, now)
 		userInfoList = append(userInfoList, &UserInfo{
-			ID:        user.ID,
-			Username:  user.Username,
-			Tel:       user.Tel,
-			Nickname:  common.DerefOrZero(user.Nickname),
-			Email:     common.DerefOrZero(user.Email),
-			Remark:    common.DerefOrZero(user.Remark),
-			Status:    common.DerefOrZero(user.Status),
-			CreatedBy: common.DerefOrZero(user.CreatedBy),
-			UpdatedBy: common.DerefOrZero(user.UpdatedBy),
-			CreatedAt: common.DerefOrZero(user.CreatedAt),
-			UpdatedAt: common.DerefOrZero(user.UpdatedAt),
+			ID:          user.ID,
+			Username:    user.Username,
+			Tel:         common.DerefOrZero(user.Tel),
+			Nickname:    common.DerefOrZero(user.Nickname),
+			Email:       common.DerefOrZero(user.Email),
+			Remark:      common.DerefOrZero(user.Remark),
+			Status:      common.DerefOrZero(user.Status),
+			UserType:    common.DerefOrZero(user.UserType),
+			DeptId:      deptId,
+			DeptName:    deptNames[deptId],
+			LockStatus:  lockStatus,
+			LockCount:   common.DerefOrZero(user.LockCount),
+			LockedUntil: lockedUntil,
+			CreatedBy:   common.DerefOrZero(user.CreatedBy),
+			UpdatedBy:   common.DerefOrZero(user.UpdatedBy),
+			CreatedAt:   common.DerefOrZero(user.CreatedAt),
+			UpdatedAt:   common.DerefOrZero(user.UpdatedAt),
 		})
 	}
 	return &UserList{List: userInfoList, Total: total}, nil
@@ -534,6 +764,36 @@ func (u *UserService) DeleteById(ctx context.Context, userId int32) error {
 			return fmt.Errorf("用户与角色关联关系删除失败: %w", err)
 		}
 
+		// 删除用户两步验证信息
+		err = u.userDao.DeleteUserMfa(ctx, tx, userId)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "用户两步验证信息删除失败: %v", err)
+			return fmt.Errorf("用户两步验证信息删除失败: %w", err)
+		}
+
+		// 删除用户 OIDC 身份关联
+		err = u.userDao.DeleteUserOidc(ctx, tx, userId)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "用户OIDC身份关联删除失败: %v", err)
+			return fmt.Errorf("用户OIDC身份关联删除失败: %w", err)
+		}
+
+		// 删除用户历史密码
+		err = u.userDao.DeleteUserPwdHistory(ctx, tx, userId)
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "用户历史密码删除失败: %v", err)
+			return fmt.Errorf("用户历史密码删除失败: %w", err)
+		}
+
+		// 删除服务账号 API Key
+		if isServiceAccount(oldUser) {
+			err = u.userDao.DeleteUserApiKeys(ctx, tx, userId)
+			if err != nil {
+				xlogger.ErrorfCtx(ctx, "服务账号API Key删除失败: %v", err)
+				return fmt.Errorf("服务账号API Key删除失败: %w", err)
+			}
+		}
+
 		// 创建操作日志
 		err = u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
 			OperatorID:   userContext.UserId,
@@ -566,6 +826,9 @@ func (u *UserService) DeleteById(ctx context.Context, userId int32) error {
 	if _, cacheErr := u.cache.Delete(ctx, cacheKey); cacheErr != nil {
 		xlogger.ErrorfCtx(ctx, "清除用户对应角色缓存失败: %v", cacheErr)
 	}
+	u.permCache.InvalidateUsers(ctx, userId)
+	u.revokeUserTokens(ctx, userId)
+	clearUserApiKeyCache(ctx, u.cache, userId)
 	return nil
 }
 
@@ -575,7 +838,7 @@ func (u *UserService) ResetPwdById(ctx context.Context, userId int32, password s
 		return ErrUserNotFound
 	}
 	// 校验密码强度
-	if bizErr := validatePassword(password); bizErr != nil {
+	if bizErr := u.pwdPolicy.Validate(password); bizErr != nil {
 		return bizErr
 	}
 	// 获取登录ctx
@@ -592,6 +855,14 @@ func (u *UserService) ResetPwdById(ctx context.Context, userId int32, password s
 		xlogger.ErrorfCtx(ctx, "获取用户(%d)信息异常: %v", userId, err)
 		return fmt.Errorf("用户信息查询失败: %w", err)
 	}
+	// 服务账号不允许密码登录，无需重置密码
+	if isServiceAccount(user) {
+		return ErrUserTypeNotAllow
+	}
+	// 不允许重置为最近使用过的密码
+	if err := u.checkPwdHistory(ctx, u.db.WriteQuery(), userId, password); err != nil {
+		return err
+	}
 
 	// 密码加密
 	pwd, err := xcryption.HashPassword(password)
@@ -599,13 +870,20 @@ func (u *UserService) ResetPwdById(ctx context.Context, userId int32, password s
 		xlogger.ErrorfCtx(ctx, "密码加密异常: %v", err)
 		return fmt.Errorf("密码加密异常: %w", err)
 	}
+	mustChange := constant.PwdMustChangeNo
+	if u.pwdPolicy.ForceChangeAfterReset {
+		mustChange = constant.PwdMustChangeYes
+	}
 
 	err = u.db.WriteQuery().Transaction(func(tx *query.Query) error {
 		// 重置密码
-		err = u.userDao.ResetPwdById(ctx, tx, userId, pwd)
+		err = u.userDao.ResetPwdById(ctx, tx, userId, pwd, mustChange)
 		if err != nil {
 			return fmt.Errorf("重置密码失败: %w", err)
 		}
+		if err := u.savePwdHistory(ctx, tx, userId, pwd); err != nil {
+			return err
+		}
 
 		// 创建操作日志
 		err = u.logService.CreateOperationLog(ctx, tx, &contract.OperationLogInput{
@@ -632,6 +910,7 @@ func (u *UserService) ResetPwdById(ctx context.Context, userId int32, password s
 		return err
 	}
 	xlogger.InfofCtx(ctx, "用户(%d-%s)重置用户(%d)密码成功", userContext.UserId, userContext.Username, userId)
+	u.revokeUserTokens(ctx, userId)
 	return nil
 }
 
@@ -661,10 +940,14 @@ func (u *UserService) Authenticate(ctx context.Context, username, password strin
 	if user.Status != nil && *user.Status == constant.UserStatusDisabled {
 		return nil, ErrAuth
 	}
+	// 服务账号只能通过 API Key 调用
+	if isServiceAccount(user) {
+		return nil, ErrAuth
+	}
 	return &UserInfo{
 		ID:        user.ID,
 		Username:  user.Username,
-		Tel:       user.Tel,
+		Tel:       common.DerefOrZero(user.Tel),
 		Nickname:  common.DerefOrZero(user.Nickname),
 		Email:     common.DerefOrZero(user.Email),
 		Remark:    common.DerefOrZero(user.Remark),
@@ -674,55 +957,82 @@ func (u *UserService) Authenticate(ctx context.Context, username, password strin
 	}, nil
 }
 
-// GetRoleIdsByUserId 根据userId拿该用户角色
+// GetRoleIdsByUserId 根据userId拿该用户当前生效的角色，缓存不超过下一次限时授权生效或失效的时间
 func (u *UserService) GetRoleIdsByUserId(ctx context.Context, userId int32) ([]int32, error) {
+	roleIds, _, err := u.getRoleIdsWithTTL(ctx, userId)
+	return roleIds, err
+}
+
+// getRoleIdsWithTTL 同 GetRoleIdsByUserId，额外返回角色列表的剩余有效期（到下一次授权生效或失效为止）
+// 有效期未知时返回 0，调用方不应再缓存
+func (u *UserService) getRoleIdsWithTTL(ctx context.Context, userId int32) ([]int32, time.Duration, error) {
 	var roleIds []int32
 	if userId <= 0 {
-		return roleIds, ErrUserNotFound
+		return roleIds, 0, ErrUserNotFound
 	}
 
-	// 读缓存 user->roleId
+	// 读缓存 user->roleId，缓存写入时已按授权边界设置过期时间，剩余 TTL 即有效期
 	cacheKey := fmt.Sprintf("%s%d", constant.CacheUserRolePrefix, userId)
 	if data, ok, _ := u.cache.Get(ctx, cacheKey); ok {
 		if strErr := json.Unmarshal([]byte(data), &roleIds); strErr == nil {
-			return roleIds, nil
+			ttl, err := u.cache.TTL(ctx, cacheKey)
+			switch {
+			case err != nil:
+				ttl = 0
+			case ttl == -1:
+				// 未设置过期时间，视为没有限时授权
+				ttl = userRoleCacheTTL(time.Now(), time.Time{})
+			case ttl < 0:
+				// 读取期间 key 已过期
+				ttl = 0
+			}
+			return roleIds, ttl, nil
 		}
 	}
 
-	//  缓存未命中或解析失败：查库拿 RoleId
-	roleIds, err := u.userDao.GetRoleIdsByUserId(ctx, userId)
+	//  缓存未命中或解析失败：查库拿授权，按有效期过滤
+	grants, err := u.userDao.GetUserRoleGrants(ctx, userId)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "查询用户角色id失败 uid=%d: %v", userId, err)
-		return roleIds, fmt.Errorf("查询用户角色id失败: %w", err)
+		return roleIds, 0, fmt.Errorf("查询用户角色id失败: %w", err)
 	}
+	now := time.Now()
+	roleIds, next := activeRoleIds(grants, now)
+	ttl := userRoleCacheTTL(now, next)
 
 	// 写缓存
 	roleIdsBytes, err := json.Marshal(&roleIds)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "缓存用户角色id失败 uid=%d, roleIds: %v, %v", userId, roleIds, err)
-		return roleIds, fmt.Errorf("缓存用户角色id失败: %w", err)
+		return roleIds, 0, fmt.Errorf("缓存用户角色id失败: %w", err)
 	}
-	_ = u.cache.Set(ctx, cacheKey, string(roleIdsBytes), constant.CacheUserRoleExpirationDay*24*time.Hour)
+	_ = u.cache.Set(ctx, cacheKey, string(roleIdsBytes), ttl)
 
-	return roleIds, nil
+	return roleIds, ttl, nil
 }
 
 // GetPermsListById 根据userId拿该用户所有接口权限标识
 func (u *UserService) GetPermsListById(ctx context.Context, userId int32) ([]string, error) {
+	perms, _, err := u.getPermsWithTTL(ctx, userId)
+	return perms, err
+}
+
+// getPermsWithTTL 同 GetPermsListById，额外返回权限列表的剩余有效期，取自角色列表的有效期
+func (u *UserService) getPermsWithTTL(ctx context.Context, userId int32) ([]string, time.Duration, error) {
 	if userId <= 0 {
-		return nil, ErrUserNotFound
+		return nil, 0, ErrUserNotFound
 	}
 
 	// 根据userId拿到roleId
-	roleIds, err := u.GetRoleIdsByUserId(ctx, userId)
+	roleIds, ttl, err := u.getRoleIdsWithTTL(ctx, userId)
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "GetPermsListById 查询用户角色失败 uid=%d: %v", userId, err)
-		return nil, err
+		return nil, 0, err
 	}
 
 	// 如果没分配角色，直接返回空 perms
 	if len(roleIds) <= 0 {
-		return []string{}, nil
+		return []string{}, ttl, nil
 	}
 
 	// 逐个角色读取缓存的 perms，去重合并
@@ -731,7 +1041,7 @@ func (u *UserService) GetPermsListById(ctx context.Context, userId int32) ([]str
 		perms, err := u.roleService.GetRolePermsListByRuleID(ctx, roleId)
 		if err != nil {
 			xlogger.ErrorfCtx(ctx, "GetPermsListById 获取角色权限失败 roleId=%d: %v", roleId, err)
-			return nil, err
+			return nil, 0, err
 		}
 		for _, p := range perms {
 			permSet[p] = struct{}{}
@@ -742,7 +1052,7 @@ func (u *UserService) GetPermsListById(ctx context.Context, userId int32) ([]str
 	for p := range permSet {
 		permsList = append(permsList, p)
 	}
-	return permsList, nil
+	return permsList, ttl, nil
 }
 
 // GetUserPermissionById 根据id获取用户信息
@@ -750,11 +1060,16 @@ func (u *UserService) GetUserPermissionById(ctx context.Context, userId int32) (
 	if userId <= 0 {
 		return nil, ErrUserNotFound
 	}
-	// 获取用户信息
+	// 获取用户信息，其中的角色列表包含未生效、已过期的授权，仅用于展示
 	user, err := u.GetUserById(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
+	// 菜单与按钮权限只按当前生效的角色计算
+	roleIds, err := u.GetRoleIdsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
 
 	// 获取用户权限信息
 	menuList := make([]*MenuData, 0, 20)             // 菜单信息()
@@ -763,9 +1078,19 @@ func (u *UserService) GetUserPermissionById(ctx context.Context, userId int32) (
 	menuMap := make(map[int32]struct{}, 20)
 	permMap := make(map[int32]struct{}, 10)
 
-	if len(user.RoleList) > 0 {
-		for _, role := range user.RoleList {
-			menus, err := u.roleService.GetRoleMenuListByRuleID(ctx, role.ID)
+	if len(roleIds) > 0 {
+		for _, roleId := range roleIds {
+			// 超级管理员返回 * 授权，前端按通配规则渲染按钮
+			if roleId == constant.SuperAdminRoleId {
+				if _, exists := permMap[0]; !exists {
+					permissionList = append(permissionList, &UserPermission{
+						Name:  "全部权限",
+						Perms: constant.PermAll,
+					})
+					permMap[0] = struct{}{}
+				}
+			}
+			menus, err := u.roleService.GetRoleMenuListByRuleID(ctx, roleId)
 			if err != nil {
 				return nil, err
 			}
@@ -13,6 +13,7 @@ import (
 	"snowgo/internal/dal/model"
 	daoAccount "snowgo/internal/dao/admin/account"
 	systemService "snowgo/internal/service/admin/system"
+	common "snowgo/pkg"
 	"snowgo/pkg/xcryption"
 )
 
@@ -41,7 +42,7 @@ func TestUserServiceCreateUserIntegration(t *testing.T) {
 	if err := db.Where("id = ?", userID).First(&user).Error; err != nil {
 		t.Fatalf("query created user: %v", err)
 	}
-	if user.Username != "operator" || user.Tel != "18100000000" {
+	if user.Username != "operator" || common.DerefOrZero(user.Tel) != "18100000000" {
 		t.Fatalf("unexpected created user: %+v", user)
 	}
 	if user.Password == "abc123" || !xcryption.CheckPassword(user.Password, "abc123") {
@@ -66,7 +67,7 @@ func TestUserServiceCreateUserRollbackIntegration(t *testing.T) {
 	role := insertIntegrationRole(t, db, "it_user_create_rollback_role", "用户创建回滚角色")
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
 	roleService := newIntegrationRoleService(deps)
-	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, operationLogService)
+	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, newIntegrationDeptService(deps), newIntegrationRevocationService(deps), operationLogService, UserServiceOptions{})
 
 	_, err := service.CreateUser(testUserCtx(), &UserParam{
 		Username: "rollback_operator",
@@ -149,7 +150,7 @@ func TestUserServiceUpdateUserRollbackIntegration(t *testing.T) {
 	user := insertIntegrationUser(t, db, "operator", "18100000000", oldRole.ID)
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
 	roleService := newIntegrationRoleService(deps)
-	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, operationLogService)
+	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, newIntegrationDeptService(deps), newIntegrationRevocationService(deps), operationLogService, UserServiceOptions{})
 
 	_, err := service.UpdateUser(testUserCtx(), &UserParam{
 		ID:       user.ID,
@@ -238,7 +239,7 @@ func TestUserServiceDeleteByIdRollbackIntegration(t *testing.T) {
 	user := insertIntegrationUser(t, db, "rollback_operator", "18100000009", role.ID)
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
 	roleService := newIntegrationRoleService(deps)
-	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, operationLogService)
+	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, newIntegrationDeptService(deps), newIntegrationRevocationService(deps), operationLogService, UserServiceOptions{})
 
 	err := service.DeleteById(testUserCtx(), user.ID)
 	if !errors.Is(err, errIntegrationOperationLog) {
@@ -289,7 +290,7 @@ func TestUserServiceResetPwdByIdRollbackIntegration(t *testing.T) {
 	user := insertIntegrationUser(t, db, "operator", "18100000000")
 	operationLogService := systemService.NewOperationLogService(deps.repo, failingOperationLogRepo{})
 	roleService := newIntegrationRoleService(deps)
-	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, operationLogService)
+	service := NewUserService(deps.repo, daoAccount.NewUserDao(deps.repo), deps.cache, roleService, newIntegrationDeptService(deps), newIntegrationRevocationService(deps), operationLogService, UserServiceOptions{})
 
 	err := service.ResetPwdById(testUserCtx(), user.ID, "new123")
 	if !errors.Is(err, errIntegrationOperationLog) {
@@ -61,6 +61,18 @@ func TestUserServiceEarlyValidation(t *testing.T) {
 	if err := service.ResetPwdById(testUserCtx(), 0, "abc123"); !errors.Is(err, ErrUserNotFound) {
 		t.Fatalf("ResetPwdById expected ErrUserNotFound, got %v", err)
 	}
+	for _, tt := range []struct {
+		name    string
+		param   *UserParam
+		wantErr error
+	}{
+		{"invalid user type", &UserParam{Username: "svc", UserType: 9}, ErrUserTypeInvalid},
+		{"normal user requires tel", &UserParam{Username: "operator", Password: "abc123"}, ErrUserNameTelEmpty},
+	} {
+		if _, err := service.CreateUser(testUserCtx(), tt.param); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("CreateUser %s expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
 	err := service.ResetPwdById(testUserCtx(), 2, "abcdef")
 	var bizErr *e.BizError
 	if !errors.As(err, &bizErr) || bizErr.Code.GetErrCode() != e.PwdComplexityError.GetErrCode() {
@@ -71,6 +83,8 @@ func TestUserServiceEarlyValidation(t *testing.T) {
 func TestUserServiceAuthenticate(t *testing.T) {
 	activeStatus := constant.UserStatusActive
 	disabledStatus := constant.UserStatusDisabled
+	serviceType := constant.UserTypeService
+	tel := "18712345678"
 	passwordHash, err := xcryption.HashPassword("abc123")
 	if err != nil {
 		t.Fatalf("hash password: %v", err)
@@ -124,12 +138,24 @@ func TestUserServiceAuthenticate(t *testing.T) {
 				ID:       2,
 				Username: "operator",
 				Password: passwordHash,
-				Tel:      "18712345678",
+				Tel:      &tel,
 				Status:   &activeStatus,
 			}},
 			password:   "abc123",
 			wantStatus: activeStatus,
 		},
+		{
+			name: "service account cannot login",
+			repo: &fakeUserRepo{userByUsername: &model.SysUser{
+				ID:       3,
+				Username: "svc-report",
+				Password: passwordHash,
+				Status:   &activeStatus,
+				UserType: &serviceType,
+			}},
+			password: "abc123",
+			wantErr:  ErrAuth,
+		},
 	}
 
 	for _, tt := range tests {
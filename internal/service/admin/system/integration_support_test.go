@@ -147,6 +147,7 @@ func migrateIntegrationTables(t *testing.T, db *gorm.DB) {
 		&model.SysDict{},
 		&model.SysDictItem{},
 		&model.SysOperationLog{},
+		&model.SysPartnerCredential{},
 	); err != nil {
 		t.Fatalf("migrate integration tables: %v", err)
 	}
@@ -190,6 +191,7 @@ func cleanupIntegrationTables(t *testing.T, db *gorm.DB) {
 		model.TableNameSysOperationLog,
 		model.TableNameSysDictItem,
 		model.TableNameSysDict,
+		model.TableNameSysPartnerCredential,
 	}
 
 	truncateIntegrationTables(t, db, tables...)
@@ -224,6 +226,15 @@ func newIntegrationDictService(deps *integrationDeps) *DictService {
 	return NewDictService(deps.repo, deps.cache, daoSystem.NewDictDao(deps.repo), operationLogService)
 }
 
+func newIntegrationPartnerService(deps *integrationDeps) *PartnerService {
+	operationLogDao := daoSystem.NewOperationLogDao(deps.repo)
+	operationLogService := NewOperationLogService(deps.repo, operationLogDao)
+	return NewPartnerService(deps.repo, deps.cache, daoSystem.NewPartnerDao(deps.repo), operationLogService, PartnerConfig{
+		EncryptKey: "0123456789abcdef0123456789abcdef",
+		ClockSkew:  5 * time.Minute,
+	})
+}
+
 func insertIntegrationDict(t *testing.T, db *gorm.DB, code, name string) *model.SysDict {
 	t.Helper()
 	dict := &model.SysDict{
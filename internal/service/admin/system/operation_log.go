@@ -9,8 +9,10 @@ import (
 	"snowgo/internal/dal/model"
 	"snowgo/internal/dal/query"
 	"snowgo/internal/dal/repo"
+	"snowgo/internal/dao"
 	daoSystem "snowgo/internal/dao/admin/system"
 	"snowgo/internal/service/admin/contract"
+	"snowgo/pkg/xauth"
 	"snowgo/pkg/xlogger"
 	"strings"
 	"time"
@@ -34,6 +36,8 @@ var (
 		"refresh_token": {},
 		"secret":        {},
 		"jwt_secret":    {},
+		"api_key":       {},
+		"key_hash":      {},
 	}
 )
 
@@ -51,6 +55,8 @@ type OperationLogCondition struct {
 	EndTime      string `json:"end_time" form:"end_time"`
 	Offset       int32  `json:"offset" form:"offset"`
 	Limit        int32  `json:"limit" form:"limit"`
+
+	DataScope *dao.DataScope `json:"-" form:"-"` // 数据范围，由调用方按登录用户解析，nil 不限制
 }
 
 type OperationLog struct {
@@ -91,10 +97,18 @@ func (o *OperationLogService) CreateOperationLog(ctx context.Context, tx *query.
 	beforeJSON := marshalAuditData(input.BeforeData)
 	afterJSON := marshalAuditData(input.AfterData)
 
+	// 服务账号通过 API Key 调用时，操作来源记为 Api
+	operatorType := input.OperatorType
+	if operatorType == constant.OperatorUser {
+		if userContext, err := xauth.GetUserContext(ctx); err == nil && userContext.ApiKeyId > 0 {
+			operatorType = constant.OperatorApi
+		}
+	}
+
 	operationLog := &model.SysOperationLog{
 		OperatorID:   input.OperatorID,
 		OperatorName: input.OperatorName,
-		OperatorType: &input.OperatorType,
+		OperatorType: &operatorType,
 		Resource:     input.Resource,
 		ResourceID:   input.ResourceID,
 		Action:       &input.Action,
@@ -137,6 +151,7 @@ func (o *OperationLogService) GetOperationLogList(ctx context.Context, condition
 		EndTime:      endTimePtr,
 		Offset:       condition.Offset,
 		Limit:        condition.Limit,
+		DataScope:    condition.DataScope,
 	})
 	if err != nil {
 		xlogger.ErrorfCtx(ctx, "获取操作日志信息列表异常: %v", err)
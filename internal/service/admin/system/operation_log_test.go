@@ -1,16 +1,55 @@
 package system
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"snowgo/internal/constant"
+	"snowgo/pkg/xauth"
 )
 
+func TestCreateOperationLog_OperatorType(t *testing.T) {
+	userCtx := context.WithValue(context.Background(), xauth.XUserId, int32(3))
+	apiKeyCtx := context.WithValue(userCtx, xauth.XApiKeyId, int32(7))
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		operatorType string
+		want         string
+	}{
+		{"jwt user", userCtx, constant.OperatorUser, constant.OperatorUser},
+		{"api key", apiKeyCtx, constant.OperatorUser, constant.OperatorApi},
+		{"explicit job", apiKeyCtx, constant.OperatorJob, constant.OperatorJob},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOperationLogRepo{}
+			service := NewOperationLogService(nil, repo)
+			err := service.CreateOperationLog(tt.ctx, nil, &OperationLogInput{
+				OperatorID:   3,
+				OperatorType: tt.operatorType,
+				Resource:     constant.ResourceUser,
+				Action:       constant.ActionUpdate,
+			})
+			if err != nil {
+				t.Fatalf("CreateOperationLog error: %v", err)
+			}
+			if got := *repo.createdLog.OperatorType; got != tt.want {
+				t.Fatalf("operator type = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMarshalAuditData_DropsCredentialFieldsOnly(t *testing.T) {
 	input := map[string]any{
 		"id":       int64(9007199254740993),
 		"password": "$2a$10$hash",
 		"tel":      "18712345678",
 		"email":    "admin@example.com",
+		"key_hash": "9f86d081884c7d659a2feaa0c55ad015",
 		"profile": map[string]any{
 			"refresh_token": "secret-token",
 			"JWTSecret":     "jwt-secret-camel",
@@ -20,7 +59,7 @@ func TestMarshalAuditData_DropsCredentialFieldsOnly(t *testing.T) {
 	}
 
 	got := marshalAuditData(input)
-	for _, leaked := range []string{"password", "$2a$10$hash", "refresh_token", "secret-token", "JWTSecret", "jwt-secret-camel", "JWT_SECRET", "jwt-secret-env"} {
+	for _, leaked := range []string{"password", "$2a$10$hash", "refresh_token", "secret-token", "JWTSecret", "jwt-secret-camel", "JWT_SECRET", "jwt-secret-env", "9f86d081884c7d659a2feaa0c55ad015"} {
 		if strings.Contains(got, leaked) {
 			t.Fatalf("credential value %q leaked in audit json: %s", leaked, got)
 		}
@@ -0,0 +1,594 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/internal/dal/query"
+	"snowgo/internal/dal/repo"
+	daoSystem "snowgo/internal/dao/admin/system"
+	common "snowgo/pkg"
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xauth/signature"
+	"snowgo/pkg/xcache"
+	"snowgo/pkg/xcryption"
+	"snowgo/pkg/xdatabase/mysql"
+	e "snowgo/pkg/xerror"
+	"snowgo/pkg/xlogger"
+)
+
+const (
+	partnerMissingMarker   = "-"   // 访问密钥不存在或合作方已禁用时的缓存值
+	partnerAccessKeyScheme = "ak_" // 访问密钥ID固定前缀
+	partnerAccessKeyBytes  = 10    // 访问密钥ID随机部分字节数（20位十六进制）
+	partnerSecretBytes     = 32    // 签名密钥字节数（64位十六进制）
+)
+
+// partnerNonceScript nonce 不存在时写入并返回 1，已存在返回 0
+const partnerNonceScript = `
+if redis.call("SET", KEYS[1], "1", "NX", "PX", ARGV[1]) then
+    return 1
+end
+return 0
+`
+
+var (
+	ErrPartnerNotFound      = e.NewBizError(e.PartnerNotFound)
+	ErrPartnerNameExist     = e.NewBizError(e.PartnerNameExist)
+	ErrPartnerStatus        = e.NewBizError(e.PartnerStatusError)
+	ErrSignatureInvalid     = e.NewBizError(e.SignatureInvalid)
+	ErrSignatureExpired     = e.NewBizError(e.SignatureExpired)
+	ErrSignatureReplay      = e.NewBizError(e.SignatureReplay)
+	ErrSignatureUnavailable = e.NewBizError(e.SignatureUnavailable)
+)
+
+// PartnerRepo 定义开放接口合作方相关db操作接口
+type PartnerRepo interface {
+	GetPartnerById(ctx context.Context, q *query.Query, partnerId int32) (*model.SysPartnerCredential, error)
+	GetPartnerByAccessKey(ctx context.Context, accessKey string) (*model.SysPartnerCredential, error)
+	GetPartnerList(ctx context.Context, condition *daoSystem.PartnerListCondition) ([]*model.SysPartnerCredential, int64, error)
+	IsNameDuplicate(ctx context.Context, name string, partnerId int32) (bool, error)
+	CreatePartner(ctx context.Context, q *query.Query, partner *model.SysPartnerCredential) (*model.SysPartnerCredential, error)
+	UpdatePartner(ctx context.Context, q *query.Query, partner *model.SysPartnerCredential) error
+	UpdatePartnerSecret(ctx context.Context, q *query.Query, partnerId int32, secret string, updatedBy int32) error
+	DeletePartner(ctx context.Context, q *query.Query, partnerId int32) error
+	UpdateLastUsedAt(ctx context.Context, partnerId int32, usedAt time.Time) error
+}
+
+// PartnerConfig 开放接口签名认证配置
+type PartnerConfig struct {
+	EncryptKey string        // 签名密钥加密 key，为空时不启用签名认证
+	ClockSkew  time.Duration // 签名时间戳允许的时钟偏差
+}
+
+// Enabled 是否启用签名认证
+func (c PartnerConfig) Enabled() bool {
+	return c.EncryptKey != "" && c.ClockSkew > 0
+}
+
+// PartnerService 开放接口合作方凭证管理与请求验签
+type PartnerService struct {
+	db          *repo.Repository
+	cache       xcache.Cache
+	partnerRepo PartnerRepo
+	logService  *OperationLogService
+	conf        PartnerConfig
+}
+
+func NewPartnerService(db *repo.Repository, cache xcache.Cache, partnerRepo PartnerRepo, logService *OperationLogService,
+	conf PartnerConfig) *PartnerService {
+	return &PartnerService{
+		db:          db,
+		cache:       cache,
+		partnerRepo: partnerRepo,
+		logService:  logService,
+		conf:        conf,
+	}
+}
+
+type PartnerListCondition struct {
+	Name   string `json:"name" form:"name"`
+	Status int8   `json:"status" form:"status"`
+	Offset int32  `json:"offset" form:"offset"`
+	Limit  int32  `json:"limit" form:"limit"`
+}
+
+type PartnerParam struct {
+	ID     int32   `json:"id"`
+	Name   string  `json:"name" binding:"required,max=64"`
+	Status int8    `json:"status"` // 状态：1 启用, 2 禁用，创建时为空表示启用
+	Remark *string `json:"remark" binding:"omitempty,max=500"`
+}
+
+type PartnerInfo struct {
+	ID         int32
+	Name       string
+	AccessKey  string
+	Status     int8
+	Remark     *string
+	LastUsedAt *time.Time
+	CreatedBy  int32
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+}
+
+type PartnerList struct {
+	List  []*PartnerInfo
+	Total int64
+}
+
+// PartnerCreated 新建或更换密钥后的合作方凭证，签名密钥明文仅在此时返回一次
+type PartnerCreated struct {
+	PartnerInfo
+	Secret string
+}
+
+// PartnerPrincipal 签名认证通过的合作方
+type PartnerPrincipal struct {
+	PartnerId int32
+	Name      string
+	AccessKey string
+}
+
+// partnerCacheEntry 验签使用的合作方凭证缓存，签名密钥保持加密存储
+type partnerCacheEntry struct {
+	ID     int32  `json:"id"`
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// GetPartnerList 获取合作方列表
+func (p *PartnerService) GetPartnerList(ctx context.Context, condition *PartnerListCondition) (*PartnerList, error) {
+	partnerList, total, err := p.partnerRepo.GetPartnerList(ctx, &daoSystem.PartnerListCondition{
+		Name:   condition.Name,
+		Status: condition.Status,
+		Offset: condition.Offset,
+		Limit:  condition.Limit,
+	})
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "获取合作方列表异常: %v", err)
+		return nil, fmt.Errorf("合作方列表查询失败: %w", err)
+	}
+	infoList := make([]*PartnerInfo, 0, len(partnerList))
+	for _, partner := range partnerList {
+		infoList = append(infoList, toPartnerInfo(partner))
+	}
+	return &PartnerList{List: infoList, Total: total}, nil
+}
+
+// CreatePartner 创建合作方并生成访问密钥ID与签名密钥，签名密钥加密落库，明文仅返回一次
+func (p *PartnerService) CreatePartner(ctx context.Context, param *PartnerParam) (*PartnerCreated, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.conf.Enabled() {
+		return nil, ErrSignatureUnavailable
+	}
+	status, err := partnerStatus(param.Status)
+	if err != nil {
+		return nil, err
+	}
+	isDuplicate, err := p.partnerRepo.IsNameDuplicate(ctx, param.Name, 0)
+	if err != nil {
+		return nil, fmt.Errorf("查询合作方名称是否存在异常: %w", err)
+	}
+	if isDuplicate {
+		return nil, ErrPartnerNameExist
+	}
+
+	accessKey, secret, err := generatePartnerCredential()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := xcryption.AesGCMEncrypt(secret, p.conf.EncryptKey)
+	if err != nil {
+		return nil, fmt.Errorf("签名密钥加密失败: %w", err)
+	}
+
+	var partner *model.SysPartnerCredential
+	err = p.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		partner, err = p.partnerRepo.CreatePartner(ctx, tx, &model.SysPartnerCredential{
+			Name:      param.Name,
+			AccessKey: accessKey,
+			Secret:    encrypted,
+			Status:    &status,
+			Remark:    param.Remark,
+			CreatedBy: &userContext.UserId,
+			UpdatedBy: &userContext.UserId,
+		})
+		if err != nil {
+			// 唯一索引冲突兜底
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrPartnerNameExist
+			}
+			xlogger.ErrorfCtx(ctx, "合作方创建失败: %+v err: %v", param, err)
+			return fmt.Errorf("合作方创建失败: %w", err)
+		}
+
+		// 创建操作日志，签名密钥字段由操作日志统一脱敏
+		err = p.logService.CreateOperationLog(ctx, tx, &OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourcePartner,
+			ResourceID:   int64(partner.ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionCreate,
+			BeforeData:   nil,
+			AfterData:    partner,
+			Description: fmt.Sprintf("用户(%d-%s)创建了合作方(%d-%s)",
+				userContext.UserId, userContext.Username, partner.ID, partner.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %+v err: %v", param, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	xlogger.InfofCtx(ctx, "用户(%d)创建合作方成功 partner_id=%d access_key=%s", userContext.UserId, partner.ID, accessKey)
+	return &PartnerCreated{PartnerInfo: *toPartnerInfo(partner), Secret: secret}, nil
+}
+
+// UpdatePartner 更新合作方名称、状态、备注，禁用后签名请求立即被拒绝
+func (p *PartnerService) UpdatePartner(ctx context.Context, param *PartnerParam) (int32, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if param.ID <= 0 {
+		return 0, ErrPartnerNotFound
+	}
+	status, err := partnerStatus(param.Status)
+	if err != nil {
+		return 0, err
+	}
+	oldPartner, err := p.getPartner(ctx, param.ID)
+	if err != nil {
+		return 0, err
+	}
+	isDuplicate, err := p.partnerRepo.IsNameDuplicate(ctx, param.Name, param.ID)
+	if err != nil {
+		return 0, fmt.Errorf("查询合作方名称是否存在异常: %w", err)
+	}
+	if isDuplicate {
+		return 0, ErrPartnerNameExist
+	}
+
+	partner := &model.SysPartnerCredential{
+		ID:        param.ID,
+		Name:      param.Name,
+		Status:    &status,
+		Remark:    param.Remark,
+		UpdatedBy: &userContext.UserId,
+	}
+	err = p.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := p.partnerRepo.UpdatePartner(ctx, tx, partner); err != nil {
+			if mysql.IsDuplicateKeyErr(err) {
+				return ErrPartnerNameExist
+			}
+			xlogger.ErrorfCtx(ctx, "合作方更新失败: %+v err: %v", param, err)
+			return fmt.Errorf("合作方更新失败: %w", err)
+		}
+
+		err = p.logService.CreateOperationLog(ctx, tx, &OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourcePartner,
+			ResourceID:   int64(param.ID),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   oldPartner,
+			AfterData:    partner,
+			Description: fmt.Sprintf("用户(%d-%s)修改了合作方(%d-%s)信息",
+				userContext.UserId, userContext.Username, param.ID, param.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %+v err: %v", param, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	p.clearPartnerCache(ctx, oldPartner.AccessKey)
+	xlogger.InfofCtx(ctx, "用户(%d)更新合作方成功: old=%+v new=%+v", userContext.UserId, oldPartner, param)
+	return param.ID, nil
+}
+
+// RotatePartnerSecret 更换合作方签名密钥，旧密钥立即失效，新密钥明文仅返回一次
+func (p *PartnerService) RotatePartnerSecret(ctx context.Context, partnerId int32) (*PartnerCreated, error) {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if partnerId <= 0 {
+		return nil, ErrPartnerNotFound
+	}
+	if !p.conf.Enabled() {
+		return nil, ErrSignatureUnavailable
+	}
+	partner, err := p.getPartner(ctx, partnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, secret, err := generatePartnerCredential()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := xcryption.AesGCMEncrypt(secret, p.conf.EncryptKey)
+	if err != nil {
+		return nil, fmt.Errorf("签名密钥加密失败: %w", err)
+	}
+	err = p.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := p.partnerRepo.UpdatePartnerSecret(ctx, tx, partnerId, encrypted, userContext.UserId); err != nil {
+			xlogger.ErrorfCtx(ctx, "合作方(%d)签名密钥更换失败: %v", partnerId, err)
+			return fmt.Errorf("签名密钥更换失败: %w", err)
+		}
+
+		err = p.logService.CreateOperationLog(ctx, tx, &OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourcePartner,
+			ResourceID:   int64(partnerId),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionUpdate,
+			BeforeData:   nil,
+			AfterData:    nil,
+			Description: fmt.Sprintf("用户(%d-%s)更换了合作方(%d-%s)的签名密钥",
+				userContext.UserId, userContext.Username, partnerId, partner.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %+v err: %v", partnerId, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.clearPartnerCache(ctx, partner.AccessKey)
+	xlogger.InfofCtx(ctx, "用户(%d)更换合作方(%d)签名密钥成功", userContext.UserId, partnerId)
+	return &PartnerCreated{PartnerInfo: *toPartnerInfo(partner), Secret: secret}, nil
+}
+
+// DeletePartner 删除合作方，签名请求立即被拒绝
+func (p *PartnerService) DeletePartner(ctx context.Context, partnerId int32) error {
+	userContext, err := xauth.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	if partnerId <= 0 {
+		return ErrPartnerNotFound
+	}
+	partner, err := p.getPartner(ctx, partnerId)
+	if err != nil {
+		return err
+	}
+
+	err = p.db.WriteQuery().Transaction(func(tx *query.Query) error {
+		if err := p.partnerRepo.DeletePartner(ctx, tx, partnerId); err != nil {
+			xlogger.ErrorfCtx(ctx, "合作方(%d)删除失败: %v", partnerId, err)
+			return fmt.Errorf("合作方删除失败: %w", err)
+		}
+
+		err = p.logService.CreateOperationLog(ctx, tx, &OperationLogInput{
+			OperatorID:   userContext.UserId,
+			OperatorName: userContext.Username,
+			OperatorType: constant.OperatorUser,
+			Resource:     constant.ResourcePartner,
+			ResourceID:   int64(partnerId),
+			TraceID:      userContext.TraceId,
+			Action:       constant.ActionDelete,
+			BeforeData:   partner,
+			AfterData:    nil,
+			Description: fmt.Sprintf("用户(%d-%s)删除了合作方(%d-%s)",
+				userContext.UserId, userContext.Username, partnerId, partner.Name),
+			IP: userContext.IP,
+		})
+		if err != nil {
+			xlogger.ErrorfCtx(ctx, "操作日志创建失败: %+v err: %v", partnerId, err)
+			return fmt.Errorf("操作日志创建失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	p.clearPartnerCache(ctx, partner.AccessKey)
+	xlogger.InfofCtx(ctx, "用户(%d)删除合作方(%d)成功", userContext.UserId, partnerId)
+	return nil
+}
+
+// SignedRequest 已通过签名头、时间戳与合作方校验的请求，读取请求体后调用 Verify 完成验签
+type SignedRequest struct {
+	signed   *signature.Signed
+	signedAt time.Time
+	entry    *partnerCacheEntry
+}
+
+// Precheck 读取请求体前校验签名头格式、时间戳与合作方状态，未通过的请求不必读取请求体
+func (p *PartnerService) Precheck(ctx context.Context, r *http.Request) (*SignedRequest, error) {
+	if !p.conf.Enabled() {
+		return nil, ErrSignatureUnavailable
+	}
+	signed, err := signature.Parse(r.Header)
+	if err != nil {
+		return nil, ErrSignatureInvalid
+	}
+	now := time.Now()
+	signedAt := signed.Time()
+	if signedAt.Before(now.Add(-p.conf.ClockSkew)) || signedAt.After(now.Add(p.conf.ClockSkew)) {
+		return nil, ErrSignatureExpired
+	}
+
+	entry, err := p.getPartnerForAuth(ctx, signed.AccessKey, now)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedRequest{signed: signed, signedAt: signedAt, entry: entry}, nil
+}
+
+// Verify 校验签名，通过后登记 nonce 防止重放
+func (p *PartnerService) Verify(ctx context.Context, req *SignedRequest, r *http.Request, body []byte) (*PartnerPrincipal, error) {
+	signed, entry := req.signed, req.entry
+	secret, err := xcryption.AesGCMDecrypt(entry.Secret, p.conf.EncryptKey)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "合作方(%d)签名密钥解密失败: %v", entry.ID, err)
+		return nil, fmt.Errorf("签名密钥解密失败: %w", err)
+	}
+	if !signed.Verify(secret, r, body) {
+		return nil, ErrSignatureInvalid
+	}
+
+	// 签名通过后再登记 nonce，避免伪造请求占用合作方的 nonce；时间戳过了允许窗口后请求本身已被拒绝，nonce 无需继续保存
+	ttl := max(req.signedAt.Add(p.conf.ClockSkew).Sub(time.Now()), time.Second)
+	nonceKey := constant.CacheSignatureNoncePrefix + signed.AccessKey + ":" + signed.Nonce
+	res, err := p.cache.Eval(ctx, partnerNonceScript, []string{nonceKey}, ttl.Milliseconds())
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "登记签名nonce失败 access_key=%s: %v", signed.AccessKey, err)
+		return nil, fmt.Errorf("登记签名nonce失败: %w", err)
+	}
+	if stored, _ := res.(int64); stored != 1 {
+		return nil, ErrSignatureReplay
+	}
+	return &PartnerPrincipal{
+		PartnerId: entry.ID,
+		Name:      entry.Name,
+		AccessKey: signed.AccessKey,
+	}, nil
+}
+
+// Authenticate 校验签名请求：时间戳在允许偏差内、合作方启用、签名正确，最后登记 nonce 防止重放
+func (p *PartnerService) Authenticate(ctx context.Context, r *http.Request, body []byte) (*PartnerPrincipal, error) {
+	req, err := p.Precheck(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return p.Verify(ctx, req, r, body)
+}
+
+// getPartnerForAuth 读取启用状态的合作方凭证，缓存未命中时查库并更新最后调用时间
+func (p *PartnerService) getPartnerForAuth(ctx context.Context, accessKey string, now time.Time) (*partnerCacheEntry, error) {
+	cacheKey := constant.CachePartnerPrefix + accessKey
+	raw, ok, err := p.cache.Get(ctx, cacheKey)
+	if err != nil {
+		xlogger.ErrorfCtx(ctx, "查询合作方凭证缓存失败: %v", err)
+	}
+	if ok {
+		if raw == partnerMissingMarker {
+			return nil, ErrSignatureInvalid
+		}
+		var entry partnerCacheEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err == nil {
+			return &entry, nil
+		}
+	}
+
+	partner, err := p.partnerRepo.GetPartnerByAccessKey(ctx, accessKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.cachePartnerMissing(ctx, cacheKey)
+			return nil, ErrSignatureInvalid
+		}
+		xlogger.ErrorfCtx(ctx, "查询合作方凭证异常 access_key=%s: %v", accessKey, err)
+		return nil, fmt.Errorf("合作方凭证查询失败: %w", err)
+	}
+	if common.DerefOrZero(partner.Status) != constant.PartnerStatusEnabled {
+		p.cachePartnerMissing(ctx, cacheKey)
+		return nil, ErrSignatureInvalid
+	}
+	// 最后调用时间随缓存未命中更新，精度为缓存时长
+	if err := p.partnerRepo.UpdateLastUsedAt(ctx, partner.ID, now); err != nil {
+		xlogger.ErrorfCtx(ctx, "更新合作方(%d)最后调用时间失败: %v", partner.ID, err)
+	}
+	entry := &partnerCacheEntry{ID: partner.ID, Name: partner.Name, Secret: partner.Secret}
+	if data, err := json.Marshal(entry); err == nil {
+		if err := p.cache.Set(ctx, cacheKey, string(data), constant.CachePartnerExpirationSecond*time.Second); err != nil {
+			xlogger.ErrorfCtx(ctx, "写入合作方凭证缓存失败 partner_id=%d: %v", partner.ID, err)
+		}
+	}
+	return entry, nil
+}
+
+// cachePartnerMissing 缓存不存在或已禁用的访问密钥，避免伪造的访问密钥每次请求都查库；启用、删除合作方时随凭证缓存一起清除
+func (p *PartnerService) cachePartnerMissing(ctx context.Context, cacheKey string) {
+	if err := p.cache.Set(ctx, cacheKey, partnerMissingMarker, constant.CachePartnerExpirationSecond*time.Second); err != nil {
+		xlogger.ErrorfCtx(ctx, "写入合作方凭证缓存失败 key=%s: %v", cacheKey, err)
+	}
+}
+
+func (p *PartnerService) getPartner(ctx context.Context, partnerId int32) (*model.SysPartnerCredential, error) {
+	partner, err := p.partnerRepo.GetPartnerById(ctx, p.db.Query(), partnerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPartnerNotFound
+		}
+		xlogger.ErrorfCtx(ctx, "获取合作方(%d)信息异常: %v", partnerId, err)
+		return nil, fmt.Errorf("合作方信息查询失败: %w", err)
+	}
+	return partner, nil
+}
+
+// clearPartnerCache 清除验签使用的合作方凭证缓存
+func (p *PartnerService) clearPartnerCache(ctx context.Context, accessKey string) {
+	if _, err := p.cache.Delete(ctx, constant.CachePartnerPrefix+accessKey); err != nil {
+		xlogger.ErrorfCtx(ctx, "清除合作方凭证缓存失败 access_key=%s: %v", accessKey, err)
+	}
+}
+
+// partnerStatus 校验合作方状态，为空表示启用
+func partnerStatus(status int8) (int8, error) {
+	switch status {
+	case 0:
+		return constant.PartnerStatusEnabled, nil
+	case constant.PartnerStatusEnabled, constant.PartnerStatusDisabled:
+		return status, nil
+	default:
+		return 0, ErrPartnerStatus
+	}
+}
+
+// generatePartnerCredential 生成访问密钥ID(ak_<20位十六进制>)与签名密钥(64位十六进制)
+func generatePartnerCredential() (string, string, error) {
+	buf := make([]byte, partnerAccessKeyBytes+partnerSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("生成合作方凭证失败: %w", err)
+	}
+	return partnerAccessKeyScheme + hex.EncodeToString(buf[:partnerAccessKeyBytes]),
+		hex.EncodeToString(buf[partnerAccessKeyBytes:]), nil
+}
+
+func toPartnerInfo(partner *model.SysPartnerCredential) *PartnerInfo {
+	return &PartnerInfo{
+		ID:         partner.ID,
+		Name:       partner.Name,
+		AccessKey:  partner.AccessKey,
+		Status:     common.DerefOrZero(partner.Status),
+		Remark:     partner.Remark,
+		LastUsedAt: partner.LastUsedAt,
+		CreatedBy:  common.DerefOrZero(partner.CreatedBy),
+		CreatedAt:  partner.CreatedAt,
+		UpdatedAt:  partner.UpdatedAt,
+	}
+}
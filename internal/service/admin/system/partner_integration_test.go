@@ -0,0 +1,95 @@
+//go:build integration
+
+package system
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth/signature"
+)
+
+func TestPartnerServiceLifecycleIntegration(t *testing.T) {
+	deps := setupIntegrationDeps(t)
+	db := deps.repo.DB()
+	cleanupIntegrationTables(t, db)
+
+	service := newIntegrationPartnerService(deps)
+	created, err := service.CreatePartner(testUserCtx(), &PartnerParam{Name: "it_partner"})
+	if err != nil {
+		t.Fatalf("CreatePartner expected success, got %v", err)
+	}
+	if !strings.HasPrefix(created.AccessKey, "ak_") || len(created.Secret) != 64 {
+		t.Fatalf("unexpected created credential: %+v", created)
+	}
+	t.Cleanup(func() {
+		_ = deps.rdb.Del(context.Background(), constant.CachePartnerPrefix+created.AccessKey).Err()
+	})
+	if countRows(t, db, model.TableNameSysPartnerCredential, "id = ? AND secret <> ?", created.ID, created.Secret) != 1 {
+		t.Fatalf("expected secret stored encrypted")
+	}
+	operationLog := queryOperationLog(t, db, constant.ResourcePartner, int64(created.ID), constant.ActionCreate)
+	if operationLog.AfterData == nil || strings.Contains(*operationLog.AfterData, created.Secret) {
+		t.Fatalf("expected operation log without secret, got %+v", operationLog.AfterData)
+	}
+	if _, err := service.CreatePartner(testUserCtx(), &PartnerParam{Name: "it_partner"}); !errors.Is(err, ErrPartnerNameExist) {
+		t.Fatalf("CreatePartner duplicate expected ErrPartnerNameExist, got %v", err)
+	}
+
+	body := []byte(`{"ping":true}`)
+	signedRequest := func(secret string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/open/ping", nil)
+		if err := signature.Sign(req, body, created.AccessKey, secret, time.Now()); err != nil {
+			t.Fatalf("sign request: %v", err)
+		}
+		return req
+	}
+	principal, err := service.Authenticate(context.Background(), signedRequest(created.Secret), body)
+	if err != nil {
+		t.Fatalf("Authenticate expected success, got %v", err)
+	}
+	if principal.PartnerId != created.ID {
+		t.Fatalf("unexpected principal: %+v", principal)
+	}
+	if countRows(t, db, model.TableNameSysPartnerCredential, "id = ? AND last_used_at IS NOT NULL", created.ID) != 1 {
+		t.Fatalf("expected last_used_at updated")
+	}
+
+	// 更换密钥后旧密钥立即失效
+	rotated, err := service.RotatePartnerSecret(testUserCtx(), created.ID)
+	if err != nil {
+		t.Fatalf("RotatePartnerSecret expected success, got %v", err)
+	}
+	if _, err := service.Authenticate(context.Background(), signedRequest(created.Secret), body); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected old secret rejected, got %v", err)
+	}
+	if _, err := service.Authenticate(context.Background(), signedRequest(rotated.Secret), body); err != nil {
+		t.Fatalf("expected rotated secret verified, got %v", err)
+	}
+
+	// 禁用后立即拒绝
+	if _, err := service.UpdatePartner(testUserCtx(), &PartnerParam{
+		ID:     created.ID,
+		Name:   "it_partner",
+		Status: constant.PartnerStatusDisabled,
+	}); err != nil {
+		t.Fatalf("UpdatePartner expected success, got %v", err)
+	}
+	if _, err := service.Authenticate(context.Background(), signedRequest(rotated.Secret), body); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected disabled partner rejected, got %v", err)
+	}
+
+	if err := service.DeletePartner(testUserCtx(), created.ID); err != nil {
+		t.Fatalf("DeletePartner expected success, got %v", err)
+	}
+	if countRows(t, db, model.TableNameSysPartnerCredential, "id = ?", created.ID) != 0 {
+		t.Fatalf("expected partner deleted")
+	}
+}
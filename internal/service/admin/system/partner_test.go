@@ -0,0 +1,191 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"snowgo/internal/constant"
+	"snowgo/internal/dal/model"
+	"snowgo/pkg/xauth/signature"
+	"snowgo/pkg/xcryption"
+)
+
+const (
+	testPartnerEncryptKey = "0123456789abcdef0123456789abcdef"
+	testPartnerAccessKey  = "ak_test"
+	testPartnerSecret     = "partner-secret"
+)
+
+func newTestPartnerService(t *testing.T, status int8) (*PartnerService, *fakeCache, *fakePartnerRepo) {
+	t.Helper()
+	encrypted, err := xcryption.AesGCMEncrypt(testPartnerSecret, testPartnerEncryptKey)
+	if err != nil {
+		t.Fatalf("encrypt secret: %v", err)
+	}
+	cache := newFakeCache()
+	repo := &fakePartnerRepo{partner: &model.SysPartnerCredential{
+		ID:        7,
+		Name:      "erp",
+		AccessKey: testPartnerAccessKey,
+		Secret:    encrypted,
+		Status:    &status,
+	}}
+	service := &PartnerService{
+		cache:       cache,
+		partnerRepo: repo,
+		conf:        PartnerConfig{EncryptKey: testPartnerEncryptKey, ClockSkew: 5 * time.Minute},
+	}
+	return service, cache, repo
+}
+
+func newSignedRequest(t *testing.T, body string, secret string, signedAt time.Time) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/open/ping?b=2&a=1", strings.NewReader(body))
+	if err := signature.Sign(req, []byte(body), testPartnerAccessKey, secret, signedAt); err != nil {
+		t.Fatalf("sign request: %v", err)
+	}
+	return req
+}
+
+func TestPartnerServiceAuthenticate(t *testing.T) {
+	body := `{"order_no":"A1001"}`
+
+	t.Run("success caches partner and claims nonce", func(t *testing.T) {
+		service, cache, repo := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		req := newSignedRequest(t, body, testPartnerSecret, time.Now())
+
+		principal, err := service.Authenticate(context.Background(), req, []byte(body))
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if principal.PartnerId != 7 || principal.Name != "erp" || principal.AccessKey != testPartnerAccessKey {
+			t.Fatalf("unexpected principal: %+v", principal)
+		}
+		if repo.lastUsedAt.IsZero() {
+			t.Fatalf("expected last used time updated")
+		}
+		cached := cache.sets[constant.CachePartnerPrefix+testPartnerAccessKey]
+		if cached == "" || strings.Contains(cached, testPartnerSecret) {
+			t.Fatalf("expected encrypted partner cached, got %q", cached)
+		}
+		nonceKey := constant.CacheSignatureNoncePrefix + testPartnerAccessKey + ":" + req.Header.Get(signature.HeaderNonce)
+		if ttl := cache.expirations[nonceKey]; ttl <= 0 || ttl > 5*time.Minute {
+			t.Fatalf("unexpected nonce ttl: %v", ttl)
+		}
+
+		// 第二个请求命中缓存
+		next := newSignedRequest(t, body, testPartnerSecret, time.Now())
+		if _, err := service.Authenticate(context.Background(), next, []byte(body)); err != nil {
+			t.Fatalf("expected cached partner verified, got %v", err)
+		}
+		if repo.getByAccessCalls != 1 {
+			t.Fatalf("expected dao called once, got %d", repo.getByAccessCalls)
+		}
+	})
+
+	t.Run("replayed nonce rejected", func(t *testing.T) {
+		service, _, _ := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		req := newSignedRequest(t, body, testPartnerSecret, time.Now())
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); err != nil {
+			t.Fatalf("expected first request accepted, got %v", err)
+		}
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureReplay) {
+			t.Fatalf("expected ErrSignatureReplay, got %v", err)
+		}
+	})
+
+	t.Run("expired timestamp rejected", func(t *testing.T) {
+		service, _, repo := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		for _, signedAt := range []time.Time{time.Now().Add(-6 * time.Minute), time.Now().Add(6 * time.Minute)} {
+			req := newSignedRequest(t, body, testPartnerSecret, signedAt)
+			if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureExpired) {
+				t.Fatalf("expected ErrSignatureExpired, got %v", err)
+			}
+		}
+		if repo.getByAccessCalls != 0 {
+			t.Fatalf("expected dao not called, got %d calls", repo.getByAccessCalls)
+		}
+	})
+
+	t.Run("invalid signature rejected without claiming nonce", func(t *testing.T) {
+		service, cache, _ := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		req := newSignedRequest(t, body, "other-secret", time.Now())
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatalf("expected ErrSignatureInvalid, got %v", err)
+		}
+		nonceKey := constant.CacheSignatureNoncePrefix + testPartnerAccessKey + ":" + req.Header.Get(signature.HeaderNonce)
+		if _, ok := cache.values[nonceKey]; ok {
+			t.Fatalf("expected nonce not claimed by invalid signature")
+		}
+		if _, err := service.Authenticate(context.Background(), req, []byte(`{"order_no":"A1002"}`)); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatalf("expected tampered body rejected, got %v", err)
+		}
+	})
+
+	t.Run("missing header rejected", func(t *testing.T) {
+		service, _, _ := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		req := newSignedRequest(t, body, testPartnerSecret, time.Now())
+		req.Header.Del(signature.HeaderSignature)
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatalf("expected ErrSignatureInvalid, got %v", err)
+		}
+	})
+
+	t.Run("disabled or unknown partner rejected", func(t *testing.T) {
+		service, _, _ := newTestPartnerService(t, constant.PartnerStatusDisabled)
+		req := newSignedRequest(t, body, testPartnerSecret, time.Now())
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatalf("expected disabled partner rejected, got %v", err)
+		}
+
+		service, _, repo := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		repo.partner.AccessKey = "ak_other"
+		req = newSignedRequest(t, body, testPartnerSecret, time.Now())
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatalf("expected unknown partner rejected, got %v", err)
+		}
+
+		// 未知访问密钥短时缓存，重复请求不再查库
+		req = newSignedRequest(t, body, testPartnerSecret, time.Now())
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureInvalid) {
+			t.Fatalf("expected cached unknown partner rejected, got %v", err)
+		}
+		if repo.getByAccessCalls != 1 {
+			t.Fatalf("expected dao called once for unknown access key, got %d", repo.getByAccessCalls)
+		}
+	})
+
+	t.Run("unavailable without encrypt key", func(t *testing.T) {
+		service, _, _ := newTestPartnerService(t, constant.PartnerStatusEnabled)
+		service.conf.EncryptKey = ""
+		req := newSignedRequest(t, body, testPartnerSecret, time.Now())
+		if _, err := service.Authenticate(context.Background(), req, []byte(body)); !errors.Is(err, ErrSignatureUnavailable) {
+			t.Fatalf("expected ErrSignatureUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestPartnerServiceEarlyValidation(t *testing.T) {
+	service := &PartnerService{}
+
+	if _, err := service.CreatePartner(testUserCtx(), &PartnerParam{Name: "erp"}); !errors.Is(err, ErrSignatureUnavailable) {
+		t.Fatalf("CreatePartner expected ErrSignatureUnavailable, got %v", err)
+	}
+	if _, err := service.UpdatePartner(testUserCtx(), &PartnerParam{}); !errors.Is(err, ErrPartnerNotFound) {
+		t.Fatalf("UpdatePartner expected ErrPartnerNotFound, got %v", err)
+	}
+	if _, err := service.UpdatePartner(testUserCtx(), &PartnerParam{ID: 1, Status: 3}); !errors.Is(err, ErrPartnerStatus) {
+		t.Fatalf("UpdatePartner expected ErrPartnerStatus, got %v", err)
+	}
+	if _, err := service.RotatePartnerSecret(testUserCtx(), 0); !errors.Is(err, ErrPartnerNotFound) {
+		t.Fatalf("RotatePartnerSecret expected ErrPartnerNotFound, got %v", err)
+	}
+	if err := service.DeletePartner(testUserCtx(), 0); !errors.Is(err, ErrPartnerNotFound) {
+		t.Fatalf("DeletePartner expected ErrPartnerNotFound, got %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"gorm.io/gorm"
+
 	"snowgo/internal/dal/model"
 	"snowgo/internal/dal/query"
 	daoSystem "snowgo/internal/dao/admin/system"
@@ -34,8 +36,14 @@ func newFakeCache() *fakeCache {
 	}
 }
 
-func (f *fakeCache) Eval(context.Context, string, []string, ...any) (any, error) {
-	panic("not implemented")
+// Eval 模拟签名 nonce 登记脚本：key 不存在时写入并返回 1，已存在返回 0
+func (f *fakeCache) Eval(_ context.Context, _ string, keys []string, args ...any) (any, error) {
+	if _, ok := f.values[keys[0]]; ok {
+		return int64(0), nil
+	}
+	f.values[keys[0]] = "1"
+	f.expirations[keys[0]] = time.Duration(args[0].(int64)) * time.Millisecond
+	return int64(1), nil
 }
 
 func (f *fakeCache) Get(_ context.Context, key string) (string, bool, error) {
@@ -50,8 +58,15 @@ func (f *fakeCache) Set(_ context.Context, key string, value string, expiration
 	return nil
 }
 
-func (f *fakeCache) Delete(context.Context, ...string) (int64, error) {
-	panic("not implemented")
+func (f *fakeCache) Delete(_ context.Context, keys ...string) (int64, error) {
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := f.values[key]; ok {
+			delete(f.values, key)
+			deleted++
+		}
+	}
+	return deleted, nil
 }
 
 func (f *fakeCache) IncrBy(context.Context, string, int64) (int64, error) {
@@ -197,3 +212,67 @@ func (f *fakeLoginLogRepo) GetLoginLogList(_ context.Context, condition *daoSyst
 	f.condition = condition
 	return f.list, f.total, f.listErr
 }
+
+type fakeOperationLogRepo struct {
+	createdLog *model.SysOperationLog
+}
+
+func (f *fakeOperationLogRepo) Create(_ context.Context, _ *query.Query, log *model.SysOperationLog) (*model.SysOperationLog, error) {
+	f.createdLog = log
+	return log, nil
+}
+
+func (f *fakeOperationLogRepo) GetOperationLogList(context.Context, *daoSystem.OperationLogCondition) ([]*model.SysOperationLog, int64, error) {
+	panic("not implemented")
+}
+
+type fakePartnerRepo struct {
+	partner          *model.SysPartnerCredential
+	getErr           error
+	getByAccessCalls int
+	lastUsedAt       time.Time
+}
+
+func (f *fakePartnerRepo) GetPartnerById(context.Context, *query.Query, int32) (*model.SysPartnerCredential, error) {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) GetPartnerByAccessKey(_ context.Context, accessKey string) (*model.SysPartnerCredential, error) {
+	f.getByAccessCalls++
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	if f.partner == nil || f.partner.AccessKey != accessKey {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.partner, nil
+}
+
+func (f *fakePartnerRepo) GetPartnerList(context.Context, *daoSystem.PartnerListCondition) ([]*model.SysPartnerCredential, int64, error) {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) IsNameDuplicate(context.Context, string, int32) (bool, error) {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) CreatePartner(context.Context, *query.Query, *model.SysPartnerCredential) (*model.SysPartnerCredential, error) {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) UpdatePartner(context.Context, *query.Query, *model.SysPartnerCredential) error {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) UpdatePartnerSecret(context.Context, *query.Query, int32, string, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) DeletePartner(context.Context, *query.Query, int32) error {
+	panic("not implemented")
+}
+
+func (f *fakePartnerRepo) UpdateLastUsedAt(_ context.Context, _ int32, usedAt time.Time) error {
+	f.lastUsedAt = usedAt
+	return nil
+}
@@ -15,6 +15,11 @@ const (
 	XUserId        contextKey = "X-User-Id"
 	XUserName      contextKey = "X-User-Name"
 	XSessionId     contextKey = "X-Session-Id"
+	XTokenId       contextKey = "X-Token-Id"
+	XApiKeyId      contextKey = "X-Api-Key-Id"
+	XApiKeyHeader  string     = "X-Api-Key"
+	XPartnerId     contextKey = "X-Partner-Id"
+	XPartnerName   contextKey = "X-Partner-Name"
 )
 
 type Context struct {
@@ -28,6 +33,15 @@ type UserContext struct {
 	UserId    int32
 	Username  string
 	SessionId string
+	TokenId   string // access token jti
+	ApiKeyId  int32  // 通过 API Key 认证时的 Key ID，JWT 认证为 0
+}
+
+// PartnerContext 开放接口签名认证通过的合作方
+type PartnerContext struct {
+	Context
+	PartnerId   int32
+	PartnerName string
 }
 
 func GetContext(ctx context.Context) *Context {
@@ -52,6 +66,8 @@ func GetUserContext(ctx context.Context) (*UserContext, error) {
 	userAgent, _ := ctx.Value(XUserAgent).(string)
 	username, _ := ctx.Value(XUserName).(string)
 	sessionId, _ := ctx.Value(XSessionId).(string)
+	tokenId, _ := ctx.Value(XTokenId).(string)
+	apiKeyId, _ := ctx.Value(XApiKeyId).(int32)
 	return &UserContext{
 		Context: Context{
 			TraceId:   traceId,
@@ -61,5 +77,21 @@ func GetUserContext(ctx context.Context) (*UserContext, error) {
 		UserId:    userId,
 		Username:  username,
 		SessionId: sessionId,
+		TokenId:   tokenId,
+		ApiKeyId:  apiKeyId,
+	}, nil
+}
+
+// GetPartnerContext 获取签名认证的合作方ctx
+func GetPartnerContext(ctx context.Context) (*PartnerContext, error) {
+	partnerId, ok := ctx.Value(XPartnerId).(int32)
+	if !ok || partnerId <= 0 {
+		return nil, e.HttpUnauthorized
+	}
+	partnerName, _ := ctx.Value(XPartnerName).(string)
+	return &PartnerContext{
+		Context:     *GetContext(ctx),
+		PartnerId:   partnerId,
+		PartnerName: partnerName,
 	}, nil
 }
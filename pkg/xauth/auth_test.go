@@ -104,3 +104,20 @@ func TestGetUserContext(t *testing.T) {
 		}
 	})
 }
+
+func TestGetPartnerContext(t *testing.T) {
+	if _, err := xauth.GetPartnerContext(context.Background()); err == nil {
+		t.Fatal("expected error for missing partner ID")
+	}
+
+	ctx := context.WithValue(context.Background(), xauth.XPartnerId, int32(3))
+	ctx = context.WithValue(ctx, xauth.XPartnerName, "acme")
+	ctx = context.WithValue(ctx, xauth.XTraceId, "trace-123")
+	c, err := xauth.GetPartnerContext(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.PartnerId != 3 || c.PartnerName != "acme" || c.TraceId != "trace-123" {
+		t.Errorf("unexpected partner context: %+v", c)
+	}
+}
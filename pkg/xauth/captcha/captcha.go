@@ -0,0 +1,110 @@
+// Package captcha 图形验证码，题目由 Generator 生成，答案保存在 Redis 中并一次性校验
+package captcha
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"snowgo/pkg/xcache"
+)
+
+const (
+	TypeImage      = "image"      // 随机数字图片
+	TypeArithmetic = "arithmetic" // 算术题图片
+)
+
+// Challenge 返回给客户端的验证码
+type Challenge struct {
+	Id       string
+	Type     string
+	Data     string // 展示内容，内置生成器为 PNG 的 data URI
+	ExpireAt time.Time
+}
+
+// Generator 验证码题目生成器，返回展示内容与答案
+type Generator interface {
+	Type() string
+	Generate() (data string, answer string, err error)
+}
+
+type Config struct {
+	KeyPrefix  string        // Redis key 前缀
+	Expiration time.Duration // 验证码有效期
+}
+
+// Store 基于 Redis 的验证码存储，答案只能校验一次，校验后无论对错均失效
+type Store struct {
+	cache     xcache.Cache
+	generator Generator
+	conf      Config
+}
+
+func NewStore(cache xcache.Cache, generator Generator, conf Config) (*Store, error) {
+	if cache == nil {
+		return nil, errors.New("captcha cache is nil")
+	}
+	if generator == nil {
+		return nil, errors.New("captcha generator is nil")
+	}
+	if conf.KeyPrefix == "" {
+		return nil, errors.New("captcha key prefix required")
+	}
+	if conf.Expiration <= 0 {
+		return nil, errors.New("captcha expiration must be > 0")
+	}
+	return &Store{cache: cache, generator: generator, conf: conf}, nil
+}
+
+// Create 生成验证码并保存答案
+func (s *Store) Create(ctx context.Context) (*Challenge, error) {
+	data, answer, err := s.generator.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("generate captcha error: %w", err)
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("generate captcha id error: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+	if err := s.cache.Set(ctx, s.conf.KeyPrefix+id, strings.ToLower(answer), s.conf.Expiration); err != nil {
+		return nil, fmt.Errorf("save captcha error: %w", err)
+	}
+	return &Challenge{
+		Id:       id,
+		Type:     s.generator.Type(),
+		Data:     data,
+		ExpireAt: time.Now().Add(s.conf.Expiration),
+	}, nil
+}
+
+// getDelScript 原子读取并删除答案，保证同一验证码只能校验一次
+const getDelScript = `
+local v = redis.call("GET", KEYS[1])
+if v then
+    redis.call("DEL", KEYS[1])
+    return v
+end
+return ""
+`
+
+// Verify 校验验证码，答案不区分大小写；验证码不存在或已使用时返回 false
+func (s *Store) Verify(ctx context.Context, id, code string) (bool, error) {
+	if id == "" || code == "" {
+		return false, nil
+	}
+	res, err := s.cache.Eval(ctx, getDelScript, []string{s.conf.KeyPrefix + id})
+	if err != nil {
+		return false, fmt.Errorf("verify captcha error: %w", err)
+	}
+	answer, _ := res.(string)
+	if answer == "" {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(answer), []byte(strings.ToLower(strings.TrimSpace(code)))) == 1, nil
+}
@@ -0,0 +1,129 @@
+package captcha
+
+import (
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"snowgo/pkg/xcache"
+)
+
+// memCache 只实现验证码用到的方法，Eval 按 getDelScript 语义处理
+type memCache struct {
+	xcache.Cache
+	values map[string]string
+}
+
+func (c *memCache) Set(_ context.Context, key string, value string, _ time.Duration) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *memCache) Eval(_ context.Context, _ string, keys []string, _ ...any) (any, error) {
+	v := c.values[keys[0]]
+	delete(c.values, keys[0])
+	return v, nil
+}
+
+// fixedGenerator 固定答案，便于校验存储逻辑
+type fixedGenerator struct{}
+
+func (fixedGenerator) Type() string { return "fixed" }
+
+func (fixedGenerator) Generate() (string, string, error) { return "data", "AbC1", nil }
+
+func TestStoreVerifyOnce(t *testing.T) {
+	ctx := context.Background()
+	cache := &memCache{values: make(map[string]string)}
+	store, err := NewStore(cache, fixedGenerator{}, Config{KeyPrefix: "captcha:", Expiration: time.Minute})
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	challenge, err := store.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if challenge.Id == "" || challenge.Type != "fixed" || challenge.Data != "data" {
+		t.Fatalf("unexpected challenge: %+v", challenge)
+	}
+
+	if ok, err := store.Verify(ctx, challenge.Id, " abc1 "); err != nil || !ok {
+		t.Fatalf("expected case-insensitive answer accepted, got %v %v", ok, err)
+	}
+	if ok, _ := store.Verify(ctx, challenge.Id, "abc1"); ok {
+		t.Fatalf("expected captcha single-use")
+	}
+
+	// 答错同样消耗验证码
+	challenge, _ = store.Create(ctx)
+	if ok, _ := store.Verify(ctx, challenge.Id, "wrong"); ok {
+		t.Fatalf("expected wrong answer rejected")
+	}
+	if ok, _ := store.Verify(ctx, challenge.Id, "abc1"); ok {
+		t.Fatalf("expected captcha consumed after wrong answer")
+	}
+	if ok, _ := store.Verify(ctx, "", ""); ok {
+		t.Fatalf("expected empty id rejected")
+	}
+}
+
+func TestNewStoreValidation(t *testing.T) {
+	cache := &memCache{values: make(map[string]string)}
+	if _, err := NewStore(cache, nil, Config{KeyPrefix: "p:", Expiration: time.Minute}); err == nil {
+		t.Fatalf("expected nil generator rejected")
+	}
+	if _, err := NewStore(cache, fixedGenerator{}, Config{Expiration: time.Minute}); err == nil {
+		t.Fatalf("expected empty prefix rejected")
+	}
+	if _, err := NewStore(cache, fixedGenerator{}, Config{KeyPrefix: "p:"}); err == nil {
+		t.Fatalf("expected zero expiration rejected")
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	data, answer, err := DigitGenerator{Length: 5}.Generate()
+	if err != nil {
+		t.Fatalf("DigitGenerator error: %v", err)
+	}
+	if len(answer) != 5 {
+		t.Fatalf("expected 5 digits, got %q", answer)
+	}
+	if _, err := strconv.Atoi(answer); err != nil {
+		t.Fatalf("expected numeric answer, got %q", answer)
+	}
+	decodePNG(t, data)
+
+	for i := 0; i < 50; i++ {
+		data, answer, err = ArithmeticGenerator{}.Generate()
+		if err != nil {
+			t.Fatalf("ArithmeticGenerator error: %v", err)
+		}
+		if n, err := strconv.Atoi(answer); err != nil || n < 0 {
+			t.Fatalf("expected non-negative numeric answer, got %q", answer)
+		}
+	}
+	decodePNG(t, data)
+}
+
+func decodePNG(t *testing.T, data string) {
+	t.Helper()
+	raw, ok := strings.CutPrefix(data, "data:image/png;base64,")
+	if !ok {
+		t.Fatalf("expected png data uri, got %.30s", data)
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		t.Fatalf("decode base64 error: %v", err)
+	}
+	img, err := png.Decode(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatalf("decode png error: %v", err)
+	}
+	if img.Bounds().Dy() != imageHeight {
+		t.Fatalf("unexpected image height %d", img.Bounds().Dy())
+	}
+}
@@ -0,0 +1,214 @@
+package captcha
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/big"
+	mrand "math/rand/v2"
+	"strconv"
+)
+
+const (
+	defaultDigitLength = 4
+	glyphScale         = 4 // 点阵放大倍数
+	glyphGap           = 6 // 字符间距(像素)
+	imagePadding       = 10
+	imageHeight        = 48
+)
+
+// glyphs 5x7 点阵字体，仅包含验证码用到的字符
+var glyphs = map[rune][7]string{
+	'0': {" ### ", "#   #", "#  ##", "# # #", "##  #", "#   #", " ### "},
+	'1': {"  #  ", " ##  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'2': {" ### ", "#   #", "    #", "   # ", "  #  ", " #   ", "#####"},
+	'3': {"#####", "   # ", "  #  ", "   # ", "    #", "#   #", " ### "},
+	'4': {"   # ", "  ## ", " # # ", "#  # ", "#####", "   # ", "   # "},
+	'5': {"#####", "#    ", "#### ", "    #", "    #", "#   #", " ### "},
+	'6': {"  ## ", " #   ", "#    ", "#### ", "#   #", "#   #", " ### "},
+	'7': {"#####", "    #", "   # ", "  #  ", " #   ", " #   ", " #   "},
+	'8': {" ### ", "#   #", "#   #", " ### ", "#   #", "#   #", " ### "},
+	'9': {" ### ", "#   #", "#   #", " ####", "    #", "   # ", " ##  "},
+	'+': {"     ", "  #  ", "  #  ", "#####", "  #  ", "  #  ", "     "},
+	'-': {"     ", "     ", "     ", "#####", "     ", "     ", "     "},
+	'x': {"     ", "#   #", " # # ", "  #  ", " # # ", "#   #", "     "},
+	'=': {"     ", "     ", "#####", "     ", "#####", "     ", "     "},
+	'?': {" ### ", "#   #", "    #", "   # ", "  #  ", "     ", "  #  "},
+}
+
+// DigitGenerator 随机数字图片验证码
+type DigitGenerator struct {
+	Length int // 数字位数，默认 4
+}
+
+func (g DigitGenerator) Type() string {
+	return TypeImage
+}
+
+func (g DigitGenerator) Generate() (string, string, error) {
+	length := g.Length
+	if length <= 0 {
+		length = defaultDigitLength
+	}
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := randInt(10)
+		if err != nil {
+			return "", "", err
+		}
+		digits[i] = byte('0' + n)
+	}
+	data, err := renderPNG(string(digits))
+	if err != nil {
+		return "", "", err
+	}
+	return data, string(digits), nil
+}
+
+// ArithmeticGenerator 算术题图片验证码，答案为计算结果
+type ArithmeticGenerator struct{}
+
+func (g ArithmeticGenerator) Type() string {
+	return TypeArithmetic
+}
+
+func (g ArithmeticGenerator) Generate() (string, string, error) {
+	op, err := randInt(3)
+	if err != nil {
+		return "", "", err
+	}
+	a, err := randInt(20)
+	if err != nil {
+		return "", "", err
+	}
+	b, err := randInt(20)
+	if err != nil {
+		return "", "", err
+	}
+	var question string
+	var answer int
+	switch op {
+	case 0:
+		question, answer = fmt.Sprintf("%d+%d=?", a, b), a+b
+	case 1:
+		// 保证结果非负
+		if a < b {
+			a, b = b, a
+		}
+		question, answer = fmt.Sprintf("%d-%d=?", a, b), a-b
+	default:
+		a, b = a%9+1, b%9+1
+		question, answer = fmt.Sprintf("%dx%d=?", a, b), a*b
+	}
+	data, err := renderPNG(question)
+	if err != nil {
+		return "", "", err
+	}
+	return data, strconv.Itoa(answer), nil
+}
+
+// renderPNG 按点阵绘制文本并加入干扰线与噪点，返回 PNG 的 data URI
+func renderPNG(text string) (string, error) {
+	runes := []rune(text)
+	glyphW, glyphH := 5*glyphScale, 7*glyphScale
+	width := imagePadding*2 + len(runes)*(glyphW+glyphGap) - glyphGap
+	img := image.NewRGBA(image.Rect(0, 0, width, imageHeight))
+	bg := color.RGBA{R: 240, G: 243, B: 246, A: 255}
+	for y := 0; y < imageHeight; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, bg)
+		}
+	}
+
+	for i, r := range runes {
+		glyph, ok := glyphs[r]
+		if !ok {
+			return "", fmt.Errorf("captcha glyph %q not supported", r)
+		}
+		fg := randColor()
+		offsetX := imagePadding + i*(glyphW+glyphGap) + mrand.IntN(3) - 1
+		offsetY := (imageHeight-glyphH)/2 + mrand.IntN(9) - 4
+		shear := mrand.Float64()*0.4 - 0.2 // 随机倾斜
+		for row, line := range glyph {
+			for col, cell := range line {
+				if cell != '#' {
+					continue
+				}
+				for dy := 0; dy < glyphScale; dy++ {
+					y := offsetY + row*glyphScale + dy
+					dx0 := offsetX + col*glyphScale + int(shear*float64(glyphH/2-row*glyphScale))
+					for dx := 0; dx < glyphScale; dx++ {
+						img.Set(dx0+dx, y, fg)
+					}
+				}
+			}
+		}
+	}
+
+	// 干扰线
+	for i := 0; i < 3; i++ {
+		drawLine(img, mrand.IntN(width), mrand.IntN(imageHeight), mrand.IntN(width), mrand.IntN(imageHeight), randColor())
+	}
+	// 噪点
+	for i := 0; i < width*imageHeight/12; i++ {
+		img.Set(mrand.IntN(width), mrand.IntN(imageHeight), randColor())
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("encode captcha image error: %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// drawLine Bresenham 画线
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func randColor() color.RGBA {
+	return color.RGBA{R: uint8(mrand.IntN(150)), G: uint8(mrand.IntN(150)), B: uint8(mrand.IntN(150)), A: 255}
+}
+
+// randInt 答案使用 crypto/rand，避免被预测
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("generate captcha error: %w", err)
+	}
+	return int(v.Int64()), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
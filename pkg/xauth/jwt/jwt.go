@@ -1,9 +1,11 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"snowgo/pkg/xauth"
 	"strconv"
 	"time"
 
@@ -16,11 +18,13 @@ const (
 )
 
 var (
-	ErrTokenExpired     = errors.New("token has expired")
-	ErrInvalidTokenType = errors.New("invalid token type")
-	ErrInvalidToken     = errors.New("invalid token")
+	ErrTokenExpired     = xauth.ErrTokenExpired
+	ErrInvalidTokenType = xauth.ErrInvalidTokenType
+	ErrInvalidToken     = xauth.ErrInvalidToken
 )
 
+var _ xauth.TokenStrategy = (*Manager)(nil)
+
 type Token struct {
 	AccessToken   string    `json:"access_token"`
 	RefreshToken  string    `json:"refresh_token"`
@@ -33,17 +37,20 @@ type Config struct {
 	Issuer                string
 	AccessExpirationTime  time.Duration
 	RefreshExpirationTime time.Duration
+	// SigningKeys 非对称签名密钥(RS256/EdDSA)，配置后替代 JwtSecret 的 HS256 签名，按 ActiveFrom 轮换
+	SigningKeys []SigningKeyConfig
 }
 
 type Manager struct {
 	jwtConf *Config
+	keys    *keySet // 为 nil 时使用 HS256
 }
 
 func NewJwtManager(conf *Config) (*Manager, error) {
 	if conf == nil {
 		return nil, errors.New("jwt config is nil")
 	}
-	if len(conf.JwtSecret) < 32 {
+	if len(conf.SigningKeys) == 0 && len(conf.JwtSecret) < 32 {
 		return nil, errors.New("jwt secret must be at least 32 characters for HS256")
 	}
 	if conf.Issuer == "" {
@@ -55,7 +62,20 @@ func NewJwtManager(conf *Config) (*Manager, error) {
 	if conf.RefreshExpirationTime <= 0 {
 		return nil, errors.New("refresh expiration time must be > 0")
 	}
-	return &Manager{jwtConf: conf}, nil
+	m := &Manager{jwtConf: conf}
+	if len(conf.SigningKeys) > 0 {
+		// 旧密钥被替换后，需保留到其签发的最长 token 过期
+		grace := max(conf.AccessExpirationTime, conf.RefreshExpirationTime)
+		keys, err := newKeySet(conf.SigningKeys, grace)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = keys.current(time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		m.keys = keys
+	}
+	return m, nil
 }
 
 // Claims 自定义声明
@@ -64,12 +84,18 @@ type Claims struct {
 	UserId    int32  `json:"user_id"`
 	Username  string `json:"username"`
 	SessionId string `json:"session_id"`
+	FamilyId  string `json:"family_id,omitempty"` // refresh token 族ID，登录时生成，轮换时继承，用于检测重复使用后整族吊销
+	IssuedMs  int64  `json:"iat_ms,omitempty"`    // 毫秒精度签发时间，iat 只有秒级精度，吊销水位线按毫秒比较
 	//Role      string `json:"role"`
 	jwt.RegisteredClaims
 }
 
 // GenerateAccessToken 创建 access token
 func (m *Manager) GenerateAccessToken(userId int32, username, refreshJti string) (string, time.Time, error) {
+	return m.generateAccessToken(userId, username, refreshJti, "")
+}
+
+func (m *Manager) generateAccessToken(userId int32, username, refreshJti, familyId string) (string, time.Time, error) {
 	now := time.Now().UTC()
 	exp := now.Add(m.jwtConf.AccessExpirationTime)
 	exp = time.Unix(exp.Unix(), 0) // 去除纳秒
@@ -78,6 +104,8 @@ func (m *Manager) GenerateAccessToken(userId int32, username, refreshJti string)
 		UserId:    userId,
 		Username:  username,
 		SessionId: refreshJti, // refresh id，用于关联信息
+		FamilyId:  familyId,
+		IssuedMs:  now.UnixMilli(),
 		//Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(exp),
@@ -87,22 +115,31 @@ func (m *Manager) GenerateAccessToken(userId int32, username, refreshJti string)
 			ID:        uuid.NewString(), // jti
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	tokenString, err := token.SignedString([]byte(m.jwtConf.JwtSecret))
+	tokenString, err := m.sign(now, accessClaims)
 	return tokenString, exp, err
 }
 
-// GenerateRefreshToken 创建 refresh token
+// GenerateRefreshToken 创建 refresh token，开启新的令牌族
 func (m *Manager) GenerateRefreshToken(userId int32, username string) (string, string, time.Time, error) {
+	return m.generateRefreshToken(userId, username, "")
+}
+
+// generateRefreshToken familyId 为空时以本次 jti 作为新的族ID
+func (m *Manager) generateRefreshToken(userId int32, username, familyId string) (string, string, time.Time, error) {
 	now := time.Now().UTC()
 	exp := now.Add(m.jwtConf.RefreshExpirationTime)
 	exp = time.Unix(exp.Unix(), 0) // 去除纳秒
 	jti := uuid.New().String()
+	if familyId == "" {
+		familyId = jti
+	}
 	refreshClaims := Claims{
 		GrantType: refreshType,
 		UserId:    userId,
 		Username:  username,
 		SessionId: jti,
+		FamilyId:  familyId,
+		IssuedMs:  now.UnixMilli(),
 		//Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(exp),
@@ -112,18 +149,64 @@ func (m *Manager) GenerateRefreshToken(userId int32, username string) (string, s
 			ID:        jti, // jti
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	tokenString, err := token.SignedString([]byte(m.jwtConf.JwtSecret))
+	tokenString, err := m.sign(now, refreshClaims)
 	return tokenString, jti, exp, err
 }
 
+// sign 使用 HS256 或当前生效的非对称密钥签名，非对称签名在 header 写入 kid
+func (m *Manager) sign(now time.Time, claims Claims) (string, error) {
+	if m.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.jwtConf.JwtSecret))
+	}
+	key, err := m.keys.current(now)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
+}
+
+// keyFunc 根据 header 中的 kid 选择验签公钥
+func (m *Manager) keyFunc(token *jwt.Token) (any, error) {
+	if m.keys == nil {
+		return []byte(m.jwtConf.JwtSecret), nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, err := m.keys.lookup(kid, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.alg {
+		return nil, ErrInvalidToken
+	}
+	return key.public, nil
+}
+
+// validMethods 允许的签名算法
+func (m *Manager) validMethods() []string {
+	if m.keys == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+	return m.keys.methods()
+}
+
+// JWKS 导出验签公钥集合，HS256 模式下为空集合
+func (m *Manager) JWKS() *JWKS {
+	if m.keys == nil {
+		return &JWKS{Keys: []JWK{}}
+	}
+	return m.keys.jwks(time.Now().UTC())
+}
+
 // GenerateTokens 创建一对 access + refresh token
 func (m *Manager) GenerateTokens(userId int32, username string) (token *Token, err error) {
 	refreshToken, refreshJti, refreshExp, err := m.GenerateRefreshToken(userId, username)
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token error: %w", err)
 	}
-	accessToken, accessExp, err := m.GenerateAccessToken(userId, username, refreshJti)
+	accessToken, accessExp, err := m.generateAccessToken(userId, username, refreshJti, refreshJti)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token error: %w", err)
 	}
@@ -138,9 +221,8 @@ func (m *Manager) GenerateTokens(userId int32, username string) (token *Token, e
 // ParseToken 解析 JWT token
 func (m *Manager) ParseToken(tokenStr string) (*Claims, error) {
 	var claims Claims
-	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (any, error) {
-		return []byte(m.jwtConf.JwtSecret), nil
-	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, m.keyFunc,
+		jwt.WithValidMethods(m.validMethods()),
 		jwt.WithIssuer(m.jwtConf.Issuer),
 	)
 
@@ -169,13 +251,14 @@ func (m *Manager) RefreshTokens(refreshToken string) (token *Token, err error) {
 		return nil, ErrInvalidTokenType
 	}
 
-	// 生成新的刷新令牌
-	newRefreshToken, refreshJti, refreshExp, err := m.GenerateRefreshToken(claims.UserId, claims.Username)
+	// 生成新的刷新令牌，继承令牌族
+	familyId := claims.Family()
+	newRefreshToken, refreshJti, refreshExp, err := m.generateRefreshToken(claims.UserId, claims.Username, familyId)
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token error: %w", err)
 	}
 	// 生成新的访问令牌
-	accessToken, accessExp, err := m.GenerateAccessToken(claims.UserId, claims.Username, refreshJti)
+	accessToken, accessExp, err := m.generateAccessToken(claims.UserId, claims.Username, refreshJti, familyId)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token error: %w", err)
 	}
@@ -187,6 +270,87 @@ func (m *Manager) RefreshTokens(refreshToken string) (token *Token, err error) {
 	}, nil
 }
 
+// IssueTokens 登录签发令牌对，实现 xauth.TokenStrategy
+func (m *Manager) IssueTokens(_ context.Context, userId int32, username string) (*xauth.TokenPair, error) {
+	token, err := m.GenerateTokens(userId, username)
+	if err != nil {
+		return nil, err
+	}
+	return m.toTokenPair(token)
+}
+
+// ParseAccessToken 解析并校验 access token
+func (m *Manager) ParseAccessToken(_ context.Context, tokenStr string) (*xauth.TokenClaims, error) {
+	claims, err := m.ParseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := claims.ValidAccessToken(); err != nil {
+		return nil, err
+	}
+	return claims.TokenClaims(), nil
+}
+
+// ParseRefreshToken 解析并校验 refresh token
+func (m *Manager) ParseRefreshToken(_ context.Context, tokenStr string) (*xauth.TokenClaims, error) {
+	claims, err := m.ParseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.IsRefreshToken() {
+		return nil, ErrInvalidTokenType
+	}
+	return claims.TokenClaims(), nil
+}
+
+// RotateTokens 用已校验的 refresh token 身份签发新令牌对，继承令牌族
+func (m *Manager) RotateTokens(_ context.Context, refresh *xauth.TokenClaims) (*xauth.TokenPair, error) {
+	newRefreshToken, refreshJti, refreshExp, err := m.generateRefreshToken(refresh.UserId, refresh.Username, refresh.FamilyId)
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token error: %w", err)
+	}
+	accessToken, accessExp, err := m.generateAccessToken(refresh.UserId, refresh.Username, refreshJti, refresh.FamilyId)
+	if err != nil {
+		return nil, fmt.Errorf("generate access token error: %w", err)
+	}
+	return m.toTokenPair(&Token{
+		AccessToken:   accessToken,
+		RefreshToken:  newRefreshToken,
+		AccessExpire:  accessExp,
+		RefreshExpire: refreshExp,
+	})
+}
+
+// RevokeTokens JWT 无服务端状态，吊销由调用方写入吊销标记
+func (m *Manager) RevokeTokens(context.Context, string, string) error {
+	return nil
+}
+
+// toTokenPair 附带新 refresh token 的身份
+func (m *Manager) toTokenPair(token *Token) (*xauth.TokenPair, error) {
+	claims, err := m.ParseToken(token.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("parse refresh token error: %w", err)
+	}
+	return &xauth.TokenPair{
+		AccessToken:   token.AccessToken,
+		RefreshToken:  token.RefreshToken,
+		AccessExpire:  token.AccessExpire,
+		RefreshExpire: token.RefreshExpire,
+		Refresh:       claims.TokenClaims(),
+	}, nil
+}
+
+// AccessExpiration access token 有效期
+func (m *Manager) AccessExpiration() time.Duration {
+	return m.jwtConf.AccessExpirationTime
+}
+
+// RefreshExpiration refresh token 有效期
+func (m *Manager) RefreshExpiration() time.Duration {
+	return m.jwtConf.RefreshExpirationTime
+}
+
 // IsAccessToken 判断是否是 access token
 func (cm *Claims) IsAccessToken() bool {
 	return cm.GrantType == accessType
@@ -197,6 +361,41 @@ func (cm *Claims) IsRefreshToken() bool {
 	return cm.GrantType == refreshType
 }
 
+// IssuedTime 签发时间，优先使用毫秒精度的 iat_ms，未携带 iat 时返回零值
+func (cm *Claims) IssuedTime() time.Time {
+	if cm.IssuedMs > 0 {
+		return time.UnixMilli(cm.IssuedMs).UTC()
+	}
+	if cm.IssuedAt == nil {
+		return time.Time{}
+	}
+	return cm.IssuedAt.Time
+}
+
+// Family 令牌族ID，未携带 family_id 的旧 refresh token 以自身 jti 作为族ID
+func (cm *Claims) Family() string {
+	if cm.FamilyId == "" && cm.IsRefreshToken() {
+		return cm.ID
+	}
+	return cm.FamilyId
+}
+
+// TokenClaims 转换为与令牌策略无关的身份
+func (cm *Claims) TokenClaims() *xauth.TokenClaims {
+	tc := &xauth.TokenClaims{
+		UserId:    cm.UserId,
+		Username:  cm.Username,
+		TokenId:   cm.ID,
+		SessionId: cm.SessionId,
+		FamilyId:  cm.Family(),
+		IssuedAt:  cm.IssuedTime(),
+	}
+	if cm.ExpiresAt != nil {
+		tc.ExpireAt = cm.ExpiresAt.Time
+	}
+	return tc
+}
+
 // ValidAccessToken 校验 access token 的类型
 func (cm *Claims) ValidAccessToken() error {
 	// 检查令牌类型
@@ -1,7 +1,9 @@
 package jwt_test
 
 import (
+	"context"
 	"errors"
+	"snowgo/pkg/xauth"
 	"snowgo/pkg/xauth/jwt"
 	"strings"
 	"sync/atomic"
@@ -223,6 +225,39 @@ func TestJwt(t *testing.T) {
 		}
 	})
 
+	t.Run("refresh tokens keep family", func(t *testing.T) {
+		tokenPair, err := jwtManager.GenerateTokens(userId, username)
+		if err != nil {
+			t.Fatalf("GenerateTokens error: %v", err)
+		}
+		refreshClaims, _ := jwtManager.ParseToken(tokenPair.RefreshToken)
+		accessClaims, _ := jwtManager.ParseToken(tokenPair.AccessToken)
+		// 登录时以 refresh jti 作为新的族ID
+		if refreshClaims.FamilyId != refreshClaims.ID || accessClaims.FamilyId != refreshClaims.ID {
+			t.Fatalf("expected new family id %s, got refresh=%s access=%s", refreshClaims.ID, refreshClaims.FamilyId, accessClaims.FamilyId)
+		}
+
+		newPair, err := jwtManager.RefreshTokens(tokenPair.RefreshToken)
+		if err != nil {
+			t.Fatalf("RefreshTokens error: %v", err)
+		}
+		newRefreshClaims, _ := jwtManager.ParseToken(newPair.RefreshToken)
+		newAccessClaims, _ := jwtManager.ParseToken(newPair.AccessToken)
+		if newRefreshClaims.ID == refreshClaims.ID {
+			t.Fatal("expected rotated refresh jti")
+		}
+		if newRefreshClaims.Family() != refreshClaims.ID || newAccessClaims.Family() != refreshClaims.ID {
+			t.Fatalf("expected family inherited, got refresh=%s access=%s", newRefreshClaims.Family(), newAccessClaims.Family())
+		}
+
+		// 未携带 family_id 的 refresh token 以自身 jti 作为族ID
+		legacy := &jwt.Claims{GrantType: "refresh"}
+		legacy.ID = "legacy-jti"
+		if legacy.Family() != "legacy-jti" {
+			t.Fatalf("expected legacy refresh family fallback to jti, got %s", legacy.Family())
+		}
+	})
+
 	t.Run("claims type check", func(t *testing.T) {
 		tokenPair, err := jwtManager.GenerateTokens(userId, username)
 		if err != nil {
@@ -326,3 +361,58 @@ func BenchmarkRefreshTokens(b *testing.B) {
 		}
 	})
 }
+
+func TestManagerTokenStrategy(t *testing.T) {
+	ctx := context.Background()
+	jwtManager, err := jwt.NewJwtManager(&jwt.Config{
+		JwtSecret:             testJwtSecret,
+		Issuer:                "test-snow",
+		AccessExpirationTime:  10 * time.Minute,
+		RefreshExpirationTime: 30 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewJwtManager error: %v", err)
+	}
+
+	before := time.Now().UnixMilli()
+	pair, err := jwtManager.IssueTokens(ctx, 1, "test")
+	if err != nil {
+		t.Fatalf("IssueTokens error: %v", err)
+	}
+	if pair.Refresh == nil || pair.Refresh.SessionId == "" || pair.Refresh.FamilyId != pair.Refresh.SessionId {
+		t.Fatalf("unexpected refresh claims: %+v", pair.Refresh)
+	}
+
+	access, err := jwtManager.ParseAccessToken(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseAccessToken error: %v", err)
+	}
+	if access.UserId != 1 || access.SessionId != pair.Refresh.SessionId || access.FamilyId != pair.Refresh.FamilyId {
+		t.Fatalf("unexpected access claims: %+v", access)
+	}
+	// 签发时间保留毫秒精度，供吊销水位线比较
+	if ms := access.IssuedAt.UnixMilli(); ms < before || ms > time.Now().UnixMilli() {
+		t.Fatalf("expected millisecond issued at, got %v", access.IssuedAt)
+	}
+	if _, err := jwtManager.ParseAccessToken(ctx, pair.RefreshToken); !errors.Is(err, xauth.ErrInvalidTokenType) {
+		t.Fatalf("expected refresh token rejected as access token, got %v", err)
+	}
+	if _, err := jwtManager.ParseRefreshToken(ctx, pair.AccessToken); !errors.Is(err, xauth.ErrInvalidTokenType) {
+		t.Fatalf("expected access token rejected as refresh token, got %v", err)
+	}
+
+	refresh, err := jwtManager.ParseRefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken error: %v", err)
+	}
+	rotated, err := jwtManager.RotateTokens(ctx, refresh)
+	if err != nil {
+		t.Fatalf("RotateTokens error: %v", err)
+	}
+	if rotated.Refresh.SessionId == refresh.SessionId || rotated.Refresh.FamilyId != refresh.FamilyId {
+		t.Fatalf("expected new session in same family, got %+v", rotated.Refresh)
+	}
+	if err := jwtManager.RevokeTokens(ctx, access.TokenId, access.SessionId); err != nil {
+		t.Fatalf("RevokeTokens error: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// SigningKeyConfig 非对称签名密钥配置
+type SigningKeyConfig struct {
+	Kid           string    // 密钥ID，写入 token header 的 kid
+	PrivateKeyPEM []byte    // PKCS#1 / PKCS#8 PEM 私钥，支持 RSA(RS256) 与 Ed25519(EdDSA)
+	ActiveFrom    time.Time // 开始用于签名的时间，零值表示立即生效
+}
+
+// signingKey 解析后的签名密钥
+type signingKey struct {
+	kid        string
+	alg        string
+	method     jwt.SigningMethod
+	private    crypto.Signer
+	public     crypto.PublicKey
+	activeFrom time.Time
+	retireAt   time.Time // 被下一把密钥替换的时间，零值表示当前未被替换
+}
+
+// keySet 按 ActiveFrom 升序排列的密钥集合
+// 签名使用已生效的最新密钥；被替换的旧密钥在 retireAt + 最长 token 有效期内仍可验签
+type keySet struct {
+	keys  []*signingKey
+	byKid map[string]*signingKey
+	grace time.Duration
+}
+
+// JWK 公钥 JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS 公钥集合
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newKeySet(confs []SigningKeyConfig, grace time.Duration) (*keySet, error) {
+	ks := &keySet{
+		keys:  make([]*signingKey, 0, len(confs)),
+		byKid: make(map[string]*signingKey, len(confs)),
+		grace: grace,
+	}
+	for _, conf := range confs {
+		if conf.Kid == "" {
+			return nil, errors.New("jwt signing key kid required")
+		}
+		if _, ok := ks.byKid[conf.Kid]; ok {
+			return nil, fmt.Errorf("duplicate jwt signing key kid: %s", conf.Kid)
+		}
+		key, err := parseSigningKey(conf)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwt signing key %s error: %w", conf.Kid, err)
+		}
+		ks.keys = append(ks.keys, key)
+		ks.byKid[key.kid] = key
+	}
+	sort.SliceStable(ks.keys, func(i, j int) bool {
+		return ks.keys[i].activeFrom.Before(ks.keys[j].activeFrom)
+	})
+	for i := 0; i < len(ks.keys)-1; i++ {
+		ks.keys[i].retireAt = ks.keys[i+1].activeFrom
+	}
+	return ks, nil
+}
+
+func parseSigningKey(conf SigningKeyConfig) (*signingKey, error) {
+	block, _ := pem.Decode(conf.PrivateKeyPEM)
+	if block == nil {
+		return nil, errors.New("invalid pem private key")
+	}
+	var raw any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		raw, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		raw, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{kid: conf.Kid, activeFrom: conf.ActiveFrom.UTC()}
+	switch pk := raw.(type) {
+	case *rsa.PrivateKey:
+		if pk.N.BitLen() < 2048 {
+			return nil, errors.New("rsa key must be at least 2048 bits")
+		}
+		key.alg, key.method, key.private, key.public = AlgRS256, jwt.SigningMethodRS256, pk, &pk.PublicKey
+	case ed25519.PrivateKey:
+		key.alg, key.method, key.private, key.public = AlgEdDSA, jwt.SigningMethodEdDSA, pk, pk.Public()
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", raw)
+	}
+	return key, nil
+}
+
+// current 返回当前用于签名的密钥
+func (ks *keySet) current(now time.Time) (*signingKey, error) {
+	var cur *signingKey
+	for _, key := range ks.keys {
+		if key.activeFrom.After(now) {
+			break
+		}
+		cur = key
+	}
+	if cur == nil {
+		return nil, errors.New("no active jwt signing key")
+	}
+	return cur, nil
+}
+
+// verifiable 判断密钥在 now 时刻是否仍可用于验签
+func (key *signingKey) verifiable(now time.Time, grace time.Duration) bool {
+	return key.retireAt.IsZero() || now.Before(key.retireAt.Add(grace))
+}
+
+// lookup 根据 kid 查找可验签的密钥
+func (ks *keySet) lookup(kid string, now time.Time) (*signingKey, error) {
+	key, ok := ks.byKid[kid]
+	if !ok || !key.verifiable(now, ks.grace) {
+		return nil, ErrInvalidToken
+	}
+	return key, nil
+}
+
+// methods 密钥集合中出现的签名算法
+func (ks *keySet) methods() []string {
+	seen := make(map[string]struct{}, 2)
+	methods := make([]string, 0, 2)
+	for _, key := range ks.keys {
+		if _, ok := seen[key.alg]; ok {
+			continue
+		}
+		seen[key.alg] = struct{}{}
+		methods = append(methods, key.alg)
+	}
+	return methods
+}
+
+// jwks 导出仍可验签的公钥，包含已配置但尚未生效的密钥，便于验签方提前缓存
+func (ks *keySet) jwks(now time.Time) *JWKS {
+	set := &JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		if !key.verifiable(now, ks.grace) {
+			continue
+		}
+		jwk := JWK{Kid: key.kid, Use: "sig", Alg: key.alg}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,221 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"snowgo/pkg/xauth/jwt"
+)
+
+func rsaPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func ed25519PEM(t *testing.T) []byte {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal ed25519 key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func newKeyManager(t *testing.T, keys ...jwt.SigningKeyConfig) *jwt.Manager {
+	t.Helper()
+	mgr, err := jwt.NewJwtManager(&jwt.Config{
+		Issuer:                "test-snow",
+		AccessExpirationTime:  10 * time.Minute,
+		RefreshExpirationTime: 30 * time.Minute,
+		SigningKeys:           keys,
+	})
+	if err != nil {
+		t.Fatalf("NewJwtManager error: %v", err)
+	}
+	return mgr
+}
+
+func TestJwtSigningKeys(t *testing.T) {
+	rsaKey := rsaPEM(t)
+	edKey := ed25519PEM(t)
+	now := time.Now().UTC()
+
+	t.Run("key config validation errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			keys []jwt.SigningKeyConfig
+		}{
+			{"empty kid", []jwt.SigningKeyConfig{{PrivateKeyPEM: edKey}}},
+			{"duplicate kid", []jwt.SigningKeyConfig{{Kid: "k1", PrivateKeyPEM: edKey}, {Kid: "k1", PrivateKeyPEM: rsaKey}}},
+			{"invalid pem", []jwt.SigningKeyConfig{{Kid: "k1", PrivateKeyPEM: []byte("not a pem")}}},
+			{"no active key", []jwt.SigningKeyConfig{{Kid: "k1", PrivateKeyPEM: edKey, ActiveFrom: now.Add(time.Hour)}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := jwt.NewJwtManager(&jwt.Config{
+					Issuer:                "test",
+					AccessExpirationTime:  time.Minute,
+					RefreshExpirationTime: time.Minute,
+					SigningKeys:           tt.keys,
+				})
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+			})
+		}
+	})
+
+	t.Run("sign and parse with RS256 and EdDSA", func(t *testing.T) {
+		for _, key := range []jwt.SigningKeyConfig{
+			{Kid: "rsa-1", PrivateKeyPEM: rsaKey},
+			{Kid: "ed-1", PrivateKeyPEM: edKey},
+		} {
+			mgr := newKeyManager(t, key)
+			tokenPair, err := mgr.GenerateTokens(1, "test")
+			if err != nil {
+				t.Fatalf("%s GenerateTokens error: %v", key.Kid, err)
+			}
+			claims, err := mgr.ParseToken(tokenPair.AccessToken)
+			if err != nil {
+				t.Fatalf("%s ParseToken error: %v", key.Kid, err)
+			}
+			if claims.UserId != 1 || claims.Username != "test" {
+				t.Fatalf("%s claims mismatch: %+v", key.Kid, claims)
+			}
+			if _, err := mgr.RefreshTokens(tokenPair.RefreshToken); err != nil {
+				t.Fatalf("%s RefreshTokens error: %v", key.Kid, err)
+			}
+		}
+	})
+
+	t.Run("hs256 token rejected by key set manager", func(t *testing.T) {
+		hsMgr, err := jwt.NewJwtManager(&jwt.Config{
+			JwtSecret:             testJwtSecret,
+			Issuer:                "test-snow",
+			AccessExpirationTime:  time.Minute,
+			RefreshExpirationTime: time.Minute,
+		})
+		if err != nil {
+			t.Fatalf("NewJwtManager error: %v", err)
+		}
+		token, _, err := hsMgr.GenerateAccessToken(1, "test", "jti")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken error: %v", err)
+		}
+		mgr := newKeyManager(t, jwt.SigningKeyConfig{Kid: "ed-1", PrivateKeyPEM: edKey})
+		if _, err := mgr.ParseToken(token); !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got: %v", err)
+		}
+	})
+
+	t.Run("rotation keeps old key verifying", func(t *testing.T) {
+		oldMgr := newKeyManager(t, jwt.SigningKeyConfig{Kid: "old", PrivateKeyPEM: rsaKey, ActiveFrom: now.Add(-2 * time.Hour)})
+		oldToken, _, err := oldMgr.GenerateAccessToken(1, "test", "jti")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken error: %v", err)
+		}
+
+		// 新密钥 1 分钟前生效，旧密钥仍在宽限期内
+		mgr := newKeyManager(t,
+			jwt.SigningKeyConfig{Kid: "old", PrivateKeyPEM: rsaKey, ActiveFrom: now.Add(-2 * time.Hour)},
+			jwt.SigningKeyConfig{Kid: "new", PrivateKeyPEM: edKey, ActiveFrom: now.Add(-time.Minute)},
+		)
+		if _, err := mgr.ParseToken(oldToken); err != nil {
+			t.Fatalf("old key token should still verify, got: %v", err)
+		}
+		newToken, _, err := mgr.GenerateAccessToken(1, "test", "jti")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken error: %v", err)
+		}
+		if _, err := oldMgr.ParseToken(newToken); err == nil {
+			t.Fatal("token signed by new key should not verify with old-only manager")
+		}
+		if _, err := mgr.ParseToken(newToken); err != nil {
+			t.Fatalf("ParseToken error: %v", err)
+		}
+		if got := len(mgr.JWKS().Keys); got != 2 {
+			t.Fatalf("expected 2 jwks keys, got %d", got)
+		}
+	})
+
+	t.Run("retired key stops verifying after grace period", func(t *testing.T) {
+		oldMgr := newKeyManager(t, jwt.SigningKeyConfig{Kid: "old", PrivateKeyPEM: rsaKey})
+		oldToken, _, err := oldMgr.GenerateAccessToken(1, "test", "jti")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken error: %v", err)
+		}
+
+		// 新密钥 1 小时前生效，超过最长 token 有效期 30 分钟
+		mgr := newKeyManager(t,
+			jwt.SigningKeyConfig{Kid: "old", PrivateKeyPEM: rsaKey, ActiveFrom: now.Add(-2 * time.Hour)},
+			jwt.SigningKeyConfig{Kid: "new", PrivateKeyPEM: edKey, ActiveFrom: now.Add(-time.Hour)},
+		)
+		if _, err := mgr.ParseToken(oldToken); !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got: %v", err)
+		}
+		set := mgr.JWKS()
+		if len(set.Keys) != 1 || set.Keys[0].Kid != "new" {
+			t.Fatalf("expected only new key in jwks, got %+v", set.Keys)
+		}
+	})
+
+	t.Run("scheduled key published before activation", func(t *testing.T) {
+		mgr := newKeyManager(t,
+			jwt.SigningKeyConfig{Kid: "cur", PrivateKeyPEM: rsaKey},
+			jwt.SigningKeyConfig{Kid: "next", PrivateKeyPEM: edKey, ActiveFrom: now.Add(time.Hour)},
+		)
+		token, _, err := mgr.GenerateAccessToken(1, "test", "jti")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken error: %v", err)
+		}
+		curOnly := newKeyManager(t, jwt.SigningKeyConfig{Kid: "cur", PrivateKeyPEM: rsaKey})
+		if _, err := curOnly.ParseToken(token); err != nil {
+			t.Fatalf("token should be signed by current key, got: %v", err)
+		}
+		set := mgr.JWKS()
+		if len(set.Keys) != 2 {
+			t.Fatalf("expected 2 jwks keys, got %d", len(set.Keys))
+		}
+		for _, k := range set.Keys {
+			switch k.Kid {
+			case "cur":
+				if k.Kty != "RSA" || k.Alg != jwt.AlgRS256 || k.N == "" || k.E == "" {
+					t.Fatalf("unexpected rsa jwk: %+v", k)
+				}
+			case "next":
+				if k.Kty != "OKP" || k.Alg != jwt.AlgEdDSA || k.Crv != "Ed25519" || k.X == "" {
+					t.Fatalf("unexpected ed25519 jwk: %+v", k)
+				}
+			}
+		}
+	})
+
+	t.Run("hs256 manager exposes empty jwks", func(t *testing.T) {
+		mgr, err := jwt.NewJwtManager(&jwt.Config{
+			JwtSecret:             testJwtSecret,
+			Issuer:                "test-snow",
+			AccessExpirationTime:  time.Minute,
+			RefreshExpirationTime: time.Minute,
+		})
+		if err != nil {
+			t.Fatalf("NewJwtManager error: %v", err)
+		}
+		if set := mgr.JWKS(); set == nil || len(set.Keys) != 0 {
+			t.Fatalf("expected empty jwks, got %+v", set)
+		}
+	})
+}
@@ -0,0 +1,385 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"snowgo/pkg/xrequests"
+)
+
+const (
+	discoveryPath      = "/.well-known/openid-configuration"
+	jwksRefreshMinWait = time.Minute      // 遇到未知 kid 时重新拉取 JWKS 的最小间隔，防止被伪造 kid 刷接口
+	jwksCacheTTL       = 12 * time.Hour   // JWKS 缓存时长，IdP 轮换密钥后最迟该时长生效（未知 kid 会提前刷新）
+	clockSkew          = 30 * time.Second // 校验 ID Token 时间时允许的时钟误差
+	requestTimeout     = 10 * time.Second
+)
+
+// BindingCookieName 授权请求的浏览器绑定 Cookie，发起登录时写入，回调时比对
+const BindingCookieName = "snowgo_oidc"
+
+var (
+	ErrInvalidIDToken = errors.New("oidc id token invalid")
+	ErrNonceMismatch  = errors.New("oidc id token nonce mismatch")
+
+	supportedAlgs = []string{"RS256", "ES256", "EdDSA"}
+)
+
+// Config OIDC 客户端配置
+type Config struct {
+	Issuer       string   // IdP issuer，发现地址为 issuer + /.well-known/openid-configuration
+	ClientID     string   // 客户端ID
+	ClientSecret string   // 客户端密钥，公共客户端可为空（仅依赖 PKCE）
+	RedirectURL  string   // 授权回调地址，需与 IdP 注册的一致
+	Scopes       []string // 授权范围，始终包含 openid
+}
+
+// Token 授权码换取的令牌
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// IDTokenClaims ID Token 中使用的声明
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// discovery OIDC 发现文档中使用的字段
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// jwk IdP 公钥，兼容 RSA / EC P-256 / Ed25519
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Provider OIDC 授权码 + PKCE 客户端，发现文档与 JWKS 首次使用时拉取并缓存
+type Provider struct {
+	conf Config
+
+	mu            sync.Mutex
+	discovery     *discovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewProvider(conf Config) (*Provider, error) {
+	if conf.Issuer == "" || conf.ClientID == "" || conf.RedirectURL == "" {
+		return nil, errors.New("oidc issuer, client_id and redirect_url are required")
+	}
+	if !slices.Contains(conf.Scopes, "openid") {
+		conf.Scopes = append([]string{"openid"}, conf.Scopes...)
+	}
+	return &Provider{conf: conf}, nil
+}
+
+// Issuer IdP issuer
+func (p *Provider) Issuer() string {
+	return p.conf.Issuer
+}
+
+// AuthCodeURL 生成授权地址，使用 S256 PKCE
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.conf.ClientID)
+	params.Set("redirect_uri", p.conf.RedirectURL)
+	params.Set("scope", strings.Join(p.conf.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", CodeChallengeS256(codeVerifier))
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(disc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return disc.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange 使用授权码和 PKCE code_verifier 换取令牌
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          code,
+		"redirect_uri":  p.conf.RedirectURL,
+		"client_id":     p.conf.ClientID,
+		"code_verifier": codeVerifier,
+	}
+	header := map[string]string{"Accept": "application/json"}
+	if p.conf.ClientSecret != "" {
+		// client_secret_basic，RFC 6749 2.3.1 要求先做 form 编码
+		credential := url.QueryEscape(p.conf.ClientID) + ":" + url.QueryEscape(p.conf.ClientSecret)
+		header["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credential))
+	}
+	resp, err := xrequests.Post(disc.TokenEndpoint,
+		xrequests.WithCtx(ctx),
+		xrequests.WithHeader(header),
+		xrequests.WithFormData(form),
+		xrequests.WithTimeout(requestTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc token request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc token endpoint returned %d: %s", resp.StatusCode, truncate(resp.Text(), 200))
+	}
+	var token Token
+	if err := resp.Json(&token); err != nil {
+		return nil, fmt.Errorf("oidc token response decode failed: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oidc token response missing id_token")
+	}
+	return &token, nil
+}
+
+// VerifyIDToken 校验 ID Token 签名、issuer、audience、有效期和 nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	var claims IDTokenClaims
+	token, err := jwt.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	},
+		jwt.WithValidMethods(supportedAlgs),
+		jwt.WithIssuer(p.conf.Issuer),
+		jwt.WithAudience(p.conf.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	// OIDC Core 3.1.3.7：多个 audience 或携带 azp 时，azp 必须为本客户端
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != p.conf.ClientID {
+		return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	return &claims, nil
+}
+
+// getDiscovery 拉取并缓存发现文档
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	resp, err := xrequests.Get(strings.TrimRight(p.conf.Issuer, "/")+discoveryPath, xrequests.WithCtx(ctx), xrequests.WithTimeout(requestTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery returned %d", resp.StatusCode)
+	}
+	var disc discovery
+	if err := resp.Json(&disc); err != nil {
+		return nil, fmt.Errorf("oidc discovery decode failed: %w", err)
+	}
+	// OIDC Discovery 4.3：文档中的 issuer 必须与配置完全一致
+	if disc.Issuer != p.conf.Issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: %s", disc.Issuer)
+	}
+	if disc.AuthorizationEndpoint == "" || disc.TokenEndpoint == "" || disc.JwksURI == "" {
+		return nil, errors.New("oidc discovery missing endpoints")
+	}
+	p.discovery = &disc
+	return p.discovery, nil
+}
+
+// getKey 根据 kid 查找 IdP 公钥，未命中时按最小间隔刷新 JWKS
+func (p *Provider) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	expired := now.Sub(p.keysFetchedAt) > jwksCacheTTL
+	if key, ok := p.lookupKey(kid); ok && !expired {
+		return key, nil
+	}
+	if !expired && now.Sub(p.keysFetchedAt) < jwksRefreshMinWait {
+		return nil, fmt.Errorf("oidc signing key %q not found", kid)
+	}
+	keys, err := fetchJWKS(ctx, disc.JwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys, p.keysFetchedAt = keys, now
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc signing key %q not found", kid)
+}
+
+// lookupKey token 未携带 kid 且 IdP 只有一把密钥时直接使用
+func (p *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func fetchJWKS(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	resp, err := xrequests.Get(jwksURI, xrequests.WithCtx(ctx), xrequests.WithTimeout(requestTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("oidc jwks request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc jwks returned %d", resp.StatusCode)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(resp.Body, &set); err != nil {
+		return nil, fmt.Errorf("oidc jwks decode failed: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// 不支持的密钥类型直接跳过，不影响其它密钥
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported ec curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid ec public key")
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported okp curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+// BindingCookie 浏览器绑定 Cookie，HttpOnly 且 SameSite=Lax，maxAge<0 表示清除
+func BindingCookie(value, path string, maxAge int, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     BindingCookieName,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// RandomString 生成 URL 安全的随机串，用于 state / nonce / PKCE code_verifier
+func RandomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成随机串失败: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CodeChallengeS256 计算 PKCE code_challenge (RFC 7636 4.2)
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
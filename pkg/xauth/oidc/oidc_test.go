@@ -0,0 +1,143 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"snowgo/pkg/xauth/oidc"
+	"snowgo/pkg/xauth/oidc/oidctest"
+)
+
+const testRedirectURL = "https://admin.example.com/oidc/callback"
+
+func newTestProvider(t *testing.T) (*oidc.Provider, *oidctest.IdP) {
+	t.Helper()
+	idp, err := oidctest.NewIdP("snowgo-admin", "client-secret")
+	if err != nil {
+		t.Fatalf("start idp: %v", err)
+	}
+	t.Cleanup(idp.Close)
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "snowgo-admin",
+		ClientSecret: "client-secret",
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"profile", "email"},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+	return provider, idp
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+	verifier, _ := oidc.RandomString()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL error: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if q.Get("scope") != "openid profile email" || q.Get("redirect_uri") != testRedirectURL {
+		t.Fatalf("unexpected authorization params: %s", authURL)
+	}
+	if q.Get("code_challenge") != oidc.CodeChallengeS256(verifier) {
+		t.Fatal("code_challenge does not match verifier")
+	}
+
+	code, state, err := idp.Authorize(authURL, oidctest.Identity{Subject: "u-1", Email: "alice@example.com", PreferredUsername: "alice"})
+	if err != nil || state != "state-1" {
+		t.Fatalf("Authorize: state=%s err=%v", state, err)
+	}
+	token, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange error: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken error: %v", err)
+	}
+	if claims.Subject != "u-1" || claims.PreferredUsername != "alice" || claims.Email != "alice@example.com" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// 授权码只能使用一次
+	if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+		t.Fatal("expected reused code to fail")
+	}
+}
+
+func TestProvider_ExchangeRejectsWrongVerifier(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+	verifier, _ := oidc.RandomString()
+	authURL, _ := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	code, _, _ := idp.Authorize(authURL, oidctest.Identity{Subject: "u-1"})
+
+	other, _ := oidc.RandomString()
+	if _, err := provider.Exchange(ctx, code, other); err == nil {
+		t.Fatal("expected pkce verification failure")
+	}
+}
+
+func TestProvider_VerifyIDTokenRejects(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	now := time.Now()
+	valid := func() gojwt.MapClaims {
+		return gojwt.MapClaims{
+			"iss": idp.Issuer(), "sub": "u-1", "aud": "snowgo-admin", "nonce": "n",
+			"iat": now.Unix(), "exp": now.Add(time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(gojwt.MapClaims)
+		nonce  string
+		want   error
+	}{
+		{"wrong issuer", func(c gojwt.MapClaims) { c["iss"] = "https://evil.example.com" }, "n", oidc.ErrInvalidIDToken},
+		{"wrong audience", func(c gojwt.MapClaims) { c["aud"] = "other-client" }, "n", oidc.ErrInvalidIDToken},
+		{"expired", func(c gojwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() }, "n", oidc.ErrInvalidIDToken},
+		{"missing subject", func(c gojwt.MapClaims) { delete(c, "sub") }, "n", oidc.ErrInvalidIDToken},
+		{"foreign azp", func(c gojwt.MapClaims) { c["aud"] = []string{"snowgo-admin", "other"}; c["azp"] = "other" }, "n", oidc.ErrInvalidIDToken},
+		{"nonce mismatch", func(gojwt.MapClaims) {}, "other", oidc.ErrNonceMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+			raw, err := idp.SignIDToken(claims)
+			if err != nil {
+				t.Fatalf("sign id token: %v", err)
+			}
+			if _, err := provider.VerifyIDToken(context.Background(), raw, tt.nonce); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	idp, err := oidctest.NewIdP("snowgo-admin", "")
+	if err != nil {
+		t.Fatalf("start idp: %v", err)
+	}
+	defer idp.Close()
+	provider, _ := oidc.NewProvider(oidc.Config{
+		Issuer:      idp.Issuer() + "/",
+		ClientID:    "snowgo-admin",
+		RedirectURL: testRedirectURL,
+	})
+	if _, err := provider.AuthCodeURL(context.Background(), "s", "n", "v"); err == nil {
+		t.Fatal("expected issuer mismatch error")
+	}
+}
@@ -0,0 +1,194 @@
+// Package oidctest 提供进程内的 OIDC IdP，供单元测试模拟授权码 + PKCE 登录流程
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "oidctest-key"
+
+// Identity IdP 上登录的用户
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// authRequest 已授权、待换取令牌的授权码
+type authRequest struct {
+	identity      Identity
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// IdP 进程内 OIDC IdP，支持发现文档、JWKS 和授权码换取令牌
+type IdP struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]*authRequest
+}
+
+// NewIdP 启动 IdP，使用完需调用 Close
+func NewIdP(clientID, clientSecret string) (*IdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	idp := &IdP{ClientID: clientID, ClientSecret: clientSecret, key: key, codes: make(map[string]*authRequest)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.handleDiscovery)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.Server = httptest.NewServer(mux)
+	return idp, nil
+}
+
+// Issuer IdP issuer
+func (idp *IdP) Issuer() string {
+	return idp.Server.URL
+}
+
+// Close 关闭 IdP
+func (idp *IdP) Close() {
+	idp.Server.Close()
+}
+
+// Authorize 模拟用户在 IdP 登录并同意授权，返回回调携带的 code 与 state
+func (idp *IdP) Authorize(authURL string, identity Identity) (string, string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	q := u.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != idp.ClientID {
+		return "", "", errors.New("invalid authorization request")
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		return "", "", errors.New("pkce required")
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	code := hex.EncodeToString(buf)
+	idp.mu.Lock()
+	idp.codes[code] = &authRequest{
+		identity:      identity,
+		redirectURI:   q.Get("redirect_uri"),
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+	}
+	idp.mu.Unlock()
+	return code, q.Get("state"), nil
+}
+
+// SignIDToken 使用 IdP 密钥签发任意声明的 ID Token，用于构造异常 token
+func (idp *IdP) SignIDToken(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(idp.key)
+}
+
+func (idp *IdP) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 idp.Issuer(),
+		"authorization_endpoint": idp.Issuer() + "/authorize",
+		"token_endpoint":         idp.Issuer() + "/token",
+		"jwks_uri":               idp.Issuer() + "/jwks",
+	})
+}
+
+func (idp *IdP) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	pub := idp.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (idp *IdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if idp.ClientSecret != "" {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != idp.ClientID || secret != idp.ClientSecret {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+	}
+
+	code := r.PostForm.Get("code")
+	idp.mu.Lock()
+	req, ok := idp.codes[code]
+	delete(idp.codes, code)
+	idp.mu.Unlock()
+	if !ok || req.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != req.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+		return
+	}
+
+	now := time.Now()
+	idToken, err := idp.SignIDToken(jwt.MapClaims{
+		"iss":                idp.Issuer(),
+		"sub":                req.identity.Subject,
+		"aud":                idp.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"nonce":              req.nonce,
+		"email":              req.identity.Email,
+		"email_verified":     req.identity.EmailVerified,
+		"name":               req.identity.Name,
+		"preferred_username": req.identity.PreferredUsername,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": fmt.Sprintf("at-%s", code),
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+		"expires_in":   300,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,117 @@
+package perm
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	Separator = ":" // 权限标识分段分隔符，如 account:user:list
+	Wildcard  = "*" // 通配段：位于末尾时匹配剩余任意多段，位于中间时只匹配一段
+)
+
+var ErrInvalidPattern = errors.New("perm pattern invalid")
+
+// Validate 校验权限标识格式：分段不能为空，通配符必须独占一段
+func Validate(pattern string) error {
+	if pattern == "" {
+		return ErrInvalidPattern
+	}
+	for _, seg := range strings.Split(pattern, Separator) {
+		if seg == "" || (seg != Wildcard && strings.Contains(seg, Wildcard)) {
+			return ErrInvalidPattern
+		}
+	}
+	return nil
+}
+
+// node 前缀树节点，children 按分段索引
+type node struct {
+	children map[string]*node
+	terminal bool // 存在以该节点结尾的授权
+}
+
+func (n *node) child(seg string) *node {
+	if n.children == nil {
+		n.children = make(map[string]*node)
+	}
+	c, ok := n.children[seg]
+	if !ok {
+		c = &node{}
+		n.children[seg] = c
+	}
+	return c
+}
+
+// Matcher 由授权列表预编译的前缀树，编译后只读，可并发使用
+type Matcher struct {
+	root  node
+	empty bool
+}
+
+// Compile 编译授权列表，格式不合法的授权会被忽略
+func Compile(grants []string) *Matcher {
+	m := &Matcher{empty: true}
+	for _, g := range grants {
+		if Validate(g) != nil {
+			continue
+		}
+		n := &m.root
+		for _, seg := range strings.Split(g, Separator) {
+			n = n.child(seg)
+		}
+		n.terminal = true
+		m.empty = false
+	}
+	return m
+}
+
+// Match 判断授权是否覆盖 required，required 本身不应包含通配符
+func (m *Matcher) Match(required string) bool {
+	if m == nil || m.empty || required == "" {
+		return false
+	}
+	return match(&m.root, strings.Split(required, Separator))
+}
+
+func match(n *node, segs []string) bool {
+	if len(segs) == 0 {
+		return n.terminal
+	}
+	// 末尾通配授权覆盖其下所有层级
+	if w, ok := n.children[Wildcard]; ok {
+		if w.terminal {
+			return true
+		}
+		if match(w, segs[1:]) {
+			return true
+		}
+	}
+	if c, ok := n.children[segs[0]]; ok {
+		return match(c, segs[1:])
+	}
+	return false
+}
+
+// MatchAny 拥有任意一个权限即通过
+func (m *Matcher) MatchAny(required ...string) bool {
+	for _, r := range required {
+		if m.Match(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAll 必须拥有全部权限才通过，required 为空时不通过
+func (m *Matcher) MatchAll(required ...string) bool {
+	if len(required) == 0 {
+		return false
+	}
+	for _, r := range required {
+		if !m.Match(r) {
+			return false
+		}
+	}
+	return true
+}
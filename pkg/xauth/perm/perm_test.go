@@ -0,0 +1,72 @@
+package perm
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	for _, p := range []string{"*", "account:*", "account:user:list", "account:*:list"} {
+		if err := Validate(p); err != nil {
+			t.Fatalf("expected %q valid, got %v", p, err)
+		}
+	}
+	for _, p := range []string{"", ":", "account:", "account::list", "account:user*", "acc*:user"} {
+		if err := Validate(p); err == nil {
+			t.Fatalf("expected %q invalid", p)
+		}
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		grants   []string
+		required string
+		want     bool
+	}{
+		{"exact", []string{"account:user:list"}, "account:user:list", true},
+		{"exact mismatch", []string{"account:user:list"}, "account:user:create", false},
+		{"prefix is not a grant", []string{"account:user"}, "account:user:list", false},
+		{"trailing wildcard", []string{"account:user:*"}, "account:user:list", true},
+		{"trailing wildcard deeper", []string{"account:*"}, "account:user:list", true},
+		{"trailing wildcard needs segment", []string{"account:*"}, "account", false},
+		{"other module", []string{"account:*"}, "system:dict:list", false},
+		{"super admin", []string{"*"}, "system:dict:list", true},
+		{"middle wildcard", []string{"account:*:list"}, "account:role:list", true},
+		{"middle wildcard single segment", []string{"account:*:list"}, "account:role:create", false},
+		{"wildcard falls back to literal", []string{"account:*:list", "account:role:create"}, "account:role:create", true},
+		{"invalid grant ignored", []string{"account:user*"}, "account:user:list", false},
+		{"empty required", []string{"*"}, "", false},
+		{"no grants", nil, "account:user:list", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compile(tt.grants).Match(tt.required); got != tt.want {
+				t.Fatalf("Match(%q) with %v = %v, want %v", tt.required, tt.grants, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherAnyAll(t *testing.T) {
+	m := Compile([]string{"account:user:*", "system:dict:list"})
+
+	if !m.MatchAny("system:login-log:list", "account:user:delete") {
+		t.Fatalf("expected MatchAny to pass with one granted perm")
+	}
+	if m.MatchAny("system:login-log:list", "account:role:list") {
+		t.Fatalf("expected MatchAny to fail without granted perms")
+	}
+	if !m.MatchAll("account:user:list", "system:dict:list") {
+		t.Fatalf("expected MatchAll to pass with all perms granted")
+	}
+	if m.MatchAll("account:user:list", "system:dict:create") {
+		t.Fatalf("expected MatchAll to fail with a missing perm")
+	}
+	if m.MatchAll() || m.MatchAny() {
+		t.Fatalf("expected empty requirement to fail")
+	}
+
+	var nilMatcher *Matcher
+	if nilMatcher.Match("account:user:list") {
+		t.Fatalf("expected nil matcher to deny")
+	}
+}
@@ -0,0 +1,144 @@
+package perm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Def 权限点定义，Name 作为同步到菜单 Btn 节点时的名称
+type Def struct {
+	Code string
+	Name string
+}
+
+// Registry 权限注册表：权限点在此唯一定义，路由鉴权中间件登记引用
+// 用于对比菜单 Btn 节点，发现缺失、孤立、未使用的权限
+type Registry struct {
+	mu    sync.RWMutex
+	defs  []Def
+	index map[string]int
+	used  map[string]int // 权限标识 -> 引用的路由数
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		index: make(map[string]int),
+		used:  make(map[string]int),
+	}
+}
+
+// Define 定义权限点并返回权限标识；标识不合法、包含通配符或重复定义时 panic（均为编码错误）
+func (r *Registry) Define(code, name string) string {
+	if Validate(code) != nil || strings.Contains(code, Wildcard) {
+		panic(fmt.Sprintf("perm: invalid code %q", code))
+	}
+	if strings.TrimSpace(name) == "" {
+		panic(fmt.Sprintf("perm: code %q requires a name", code))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.index[code]; ok {
+		panic(fmt.Sprintf("perm: code %q defined twice", code))
+	}
+	r.index[code] = len(r.defs)
+	r.defs = append(r.defs, Def{Code: code, Name: name})
+	return code
+}
+
+// Use 登记路由对权限的引用，在注册路由时调用
+func (r *Registry) Use(codes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, code := range codes {
+		r.used[code]++
+	}
+}
+
+// Defs 按定义顺序返回全部权限点
+func (r *Registry) Defs() []Def {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]Def, len(r.defs))
+	copy(defs, r.defs)
+	return defs
+}
+
+// Drift 注册表与菜单 Btn 节点的差异
+type Drift struct {
+	Missing   []Def    // 已定义但没有对应 Btn 节点，除超级管理员外无法分配
+	Orphaned  []string // Btn 节点的权限不匹配任何已定义权限（含通配授权）
+	Unused    []Def    // 已定义但没有路由引用
+	Undefined []string // 路由引用了未定义的权限
+}
+
+// Empty 不存在任何差异
+func (d *Drift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Orphaned) == 0 && len(d.Unused) == 0 && len(d.Undefined) == 0
+}
+
+// Diff 对比菜单 Btn 节点的权限标识，menuPerms 允许包含通配授权，如 account:user:*
+func (r *Registry) Diff(menuPerms []string) *Drift {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	drift := &Drift{}
+	inMenu := make(map[string]struct{}, len(menuPerms))
+	for _, p := range menuPerms {
+		inMenu[p] = struct{}{}
+	}
+	for _, def := range r.defs {
+		if _, ok := inMenu[def.Code]; !ok {
+			drift.Missing = append(drift.Missing, def)
+		}
+		if r.used[def.Code] == 0 {
+			drift.Unused = append(drift.Unused, def)
+		}
+	}
+	for p := range inMenu {
+		if !r.matchAnyDefLocked(p) {
+			drift.Orphaned = append(drift.Orphaned, p)
+		}
+	}
+	for code := range r.used {
+		if _, ok := r.index[code]; !ok {
+			drift.Undefined = append(drift.Undefined, code)
+		}
+	}
+	sort.Strings(drift.Orphaned)
+	sort.Strings(drift.Undefined)
+	return drift
+}
+
+// matchAnyDefLocked 授权是否覆盖至少一个已定义权限，格式不合法的授权视为孤立
+func (r *Registry) matchAnyDefLocked(grant string) bool {
+	if _, ok := r.index[grant]; ok {
+		return true
+	}
+	m := Compile([]string{grant})
+	for _, def := range r.defs {
+		if m.Match(def.Code) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultRegistry 全局注册表，internal/constant 定义权限点，路由鉴权中间件登记引用
+var defaultRegistry = NewRegistry()
+
+// Default 返回全局注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Define 在全局注册表定义权限点
+func Define(code, name string) string {
+	return defaultRegistry.Define(code, name)
+}
+
+// Use 在全局注册表登记路由引用
+func Use(codes ...string) {
+	defaultRegistry.Use(codes...)
+}
@@ -0,0 +1,76 @@
+package perm
+
+import (
+	"slices"
+	"testing"
+)
+
+func expectPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("%s: expected panic", name)
+		}
+	}()
+	f()
+}
+
+func TestRegistryDefine(t *testing.T) {
+	r := NewRegistry()
+	if got := r.Define("account:user:list", "查看用户列表"); got != "account:user:list" {
+		t.Fatalf("expected code returned, got %q", got)
+	}
+	expectPanic(t, "duplicate", func() { r.Define("account:user:list", "重复") })
+	expectPanic(t, "invalid", func() { r.Define("account::list", "非法") })
+	expectPanic(t, "wildcard", func() { r.Define("account:user:*", "通配") })
+	expectPanic(t, "empty name", func() { r.Define("account:user:create", " ") })
+
+	defs := r.Defs()
+	if len(defs) != 1 || defs[0] != (Def{Code: "account:user:list", Name: "查看用户列表"}) {
+		t.Fatalf("unexpected defs: %+v", defs)
+	}
+}
+
+func TestRegistryDiff(t *testing.T) {
+	r := NewRegistry()
+	list := r.Define("account:user:list", "查看用户列表")
+	create := r.Define("account:user:create", "创建用户")
+	r.Define("system:dict:list", "查看字典列表")
+	r.Use(list)
+	r.Use(list, create)
+	r.Use("account:user:export")
+
+	drift := r.Diff([]string{"account:user:list", "account:role:*", "account:user:*", "system:user*", "legacy:perm"})
+
+	var missing, unused []string
+	for _, def := range drift.Missing {
+		missing = append(missing, def.Code)
+	}
+	for _, def := range drift.Unused {
+		unused = append(unused, def.Code)
+	}
+	if !slices.Equal(missing, []string{"account:user:create", "system:dict:list"}) {
+		t.Fatalf("unexpected missing: %v", missing)
+	}
+	if !slices.Equal(unused, []string{"system:dict:list"}) {
+		t.Fatalf("unexpected unused: %v", unused)
+	}
+	// account:user:* 覆盖已定义权限，不算孤立
+	if !slices.Equal(drift.Orphaned, []string{"account:role:*", "legacy:perm", "system:user*"}) {
+		t.Fatalf("unexpected orphaned: %v", drift.Orphaned)
+	}
+	if !slices.Equal(drift.Undefined, []string{"account:user:export"}) {
+		t.Fatalf("unexpected undefined: %v", drift.Undefined)
+	}
+	if drift.Empty() {
+		t.Fatalf("expected drift")
+	}
+}
+
+func TestRegistryDiffEmpty(t *testing.T) {
+	r := NewRegistry()
+	r.Use(r.Define("account:user:list", "查看用户列表"))
+	if drift := r.Diff([]string{"account:user:list"}); !drift.Empty() {
+		t.Fatalf("expected no drift, got %+v", drift)
+	}
+}
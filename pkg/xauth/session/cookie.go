@@ -0,0 +1,116 @@
+package session
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"snowgo/pkg/xauth"
+)
+
+const (
+	AccessCookieName  = "snowgo_access"  // access token，HttpOnly
+	RefreshCookieName = "snowgo_refresh" // refresh token，HttpOnly，仅发送到刷新接口路径
+	CsrfCookieName    = "snowgo_csrf"    // CSRF 令牌，前端可读，请求时通过 CsrfHeader 回传
+	CsrfHeader        = "X-CSRF-Token"
+)
+
+type CookieConfig struct {
+	Domain      string
+	Secure      bool
+	SameSite    http.SameSite
+	RefreshPath string // refresh token Cookie 的路径，限制为刷新与登出接口所在路径
+}
+
+// CookieTransport 通过 HttpOnly Cookie 传输令牌，并以双重提交 Cookie 防御 CSRF
+type CookieTransport struct {
+	conf CookieConfig
+}
+
+func NewCookieTransport(conf CookieConfig) (*CookieTransport, error) {
+	if conf.SameSite == http.SameSiteNoneMode && !conf.Secure {
+		return nil, errors.New("cookie with SameSite=None must be secure")
+	}
+	if conf.RefreshPath == "" {
+		conf.RefreshPath = "/"
+	}
+	return &CookieTransport{conf: conf}, nil
+}
+
+// ParseSameSite 解析 SameSite 配置：lax(默认) / strict / none
+func ParseSameSite(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "", "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, errors.New("cookie same_site must be lax, strict or none")
+	}
+}
+
+// WriteTokens 写入令牌 Cookie 并生成新的 CSRF 令牌，Cookie 与 refresh token 同时过期
+func (t *CookieTransport) WriteTokens(w http.ResponseWriter, pair *xauth.TokenPair) error {
+	csrfToken, err := newToken()
+	if err != nil {
+		return err
+	}
+	maxAge := max(int(time.Until(pair.RefreshExpire).Seconds()), 1)
+	http.SetCookie(w, t.cookie(AccessCookieName, pair.AccessToken, "/", maxAge, true))
+	http.SetCookie(w, t.cookie(RefreshCookieName, pair.RefreshToken, t.conf.RefreshPath, maxAge, true))
+	http.SetCookie(w, t.cookie(CsrfCookieName, csrfToken, "/", maxAge, false))
+	return nil
+}
+
+// Clear 清除令牌与 CSRF Cookie
+func (t *CookieTransport) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, t.cookie(AccessCookieName, "", "/", -1, true))
+	http.SetCookie(w, t.cookie(RefreshCookieName, "", t.conf.RefreshPath, -1, true))
+	http.SetCookie(w, t.cookie(CsrfCookieName, "", "/", -1, false))
+}
+
+// AccessToken 读取 Cookie 中的 access token
+func (t *CookieTransport) AccessToken(r *http.Request) string {
+	return cookieValue(r, AccessCookieName)
+}
+
+// RefreshToken 读取 Cookie 中的 refresh token
+func (t *CookieTransport) RefreshToken(r *http.Request) string {
+	return cookieValue(r, RefreshCookieName)
+}
+
+// VerifyCSRF 校验双重提交的 CSRF 令牌，GET/HEAD/OPTIONS 不校验
+func (t *CookieTransport) VerifyCSRF(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	expected := cookieValue(r, CsrfCookieName)
+	actual := r.Header.Get(CsrfHeader)
+	return expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+}
+
+func (t *CookieTransport) cookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   t.conf.Domain,
+		MaxAge:   maxAge,
+		Secure:   t.conf.Secure,
+		HttpOnly: httpOnly,
+		SameSite: t.conf.SameSite,
+	}
+}
+
+func cookieValue(r *http.Request, name string) string {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"snowgo/pkg/xauth"
+)
+
+func TestCookieTransport(t *testing.T) {
+	transport, err := NewCookieTransport(CookieConfig{Secure: true, SameSite: http.SameSiteLaxMode, RefreshPath: "/api/admin/auth"})
+	if err != nil {
+		t.Fatalf("NewCookieTransport error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	err = transport.WriteTokens(rec, &xauth.TokenPair{
+		AccessToken:   "access",
+		RefreshToken:  "refresh",
+		RefreshExpire: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("WriteTokens error: %v", err)
+	}
+	cookies := make(map[string]*http.Cookie)
+	for _, c := range rec.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	access, refresh, csrf := cookies[AccessCookieName], cookies[RefreshCookieName], cookies[CsrfCookieName]
+	if access == nil || !access.HttpOnly || !access.Secure || access.Value != "access" || access.Path != "/" {
+		t.Fatalf("unexpected access cookie: %+v", access)
+	}
+	if refresh == nil || !refresh.HttpOnly || refresh.Path != "/api/admin/auth" || refresh.MaxAge <= 0 {
+		t.Fatalf("unexpected refresh cookie: %+v", refresh)
+	}
+	if csrf == nil || csrf.HttpOnly || csrf.Value == "" {
+		t.Fatalf("unexpected csrf cookie: %+v", csrf)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/account/user", nil)
+	req.AddCookie(access)
+	req.AddCookie(csrf)
+	if transport.AccessToken(req) != "access" {
+		t.Fatalf("expected access token read from cookie")
+	}
+	if transport.VerifyCSRF(req) {
+		t.Fatalf("expected missing csrf header rejected")
+	}
+	req.Header.Set(CsrfHeader, "other")
+	if transport.VerifyCSRF(req) {
+		t.Fatalf("expected mismatched csrf header rejected")
+	}
+	req.Header.Set(CsrfHeader, csrf.Value)
+	if !transport.VerifyCSRF(req) {
+		t.Fatalf("expected matching csrf header accepted")
+	}
+	if !transport.VerifyCSRF(httptest.NewRequest(http.MethodGet, "/api/admin/account/user", nil)) {
+		t.Fatalf("expected safe method skip csrf check")
+	}
+
+	rec = httptest.NewRecorder()
+	transport.Clear(rec)
+	for _, c := range rec.Result().Cookies() {
+		if c.MaxAge >= 0 {
+			t.Fatalf("expected cookie %s cleared, got %+v", c.Name, c)
+		}
+	}
+}
+
+func TestNewCookieTransportSameSiteNone(t *testing.T) {
+	if _, err := NewCookieTransport(CookieConfig{SameSite: http.SameSiteNoneMode}); err == nil {
+		t.Fatalf("expected SameSite=None without Secure rejected")
+	}
+	if _, err := ParseSameSite("bogus"); err == nil {
+		t.Fatalf("expected invalid same_site rejected")
+	}
+}
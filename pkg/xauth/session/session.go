@@ -0,0 +1,233 @@
+// Package session 服务端不透明会话令牌，令牌为随机串，身份保存在 Redis 中，可随时删除吊销
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+)
+
+const (
+	tokenBytes    = 32 // 令牌随机字节数
+	accessSuffix  = "access:"
+	refreshSuffix = "refresh:"
+)
+
+var _ xauth.TokenStrategy = (*Manager)(nil)
+
+type Config struct {
+	KeyPrefix             string        // Redis key 前缀
+	IdleTimeout           time.Duration // access 会话空闲超时，每次校验滑动续期
+	AccessMaxLifetime     time.Duration // access 会话最长有效期，滑动续期不超过该时长
+	RefreshExpirationTime time.Duration // refresh token 有效期
+}
+
+// Manager 不透明会话令牌管理，实现 xauth.TokenStrategy
+// Redis 只保存令牌的 SHA-256 摘要，摘要同时作为令牌ID
+type Manager struct {
+	cache xcache.Cache
+	conf  Config
+}
+
+func NewManager(cache xcache.Cache, conf Config) (*Manager, error) {
+	if cache == nil {
+		return nil, errors.New("session cache is nil")
+	}
+	if conf.KeyPrefix == "" {
+		return nil, errors.New("session key prefix required")
+	}
+	if conf.IdleTimeout <= 0 {
+		return nil, errors.New("session idle timeout must be > 0")
+	}
+	if conf.AccessMaxLifetime < conf.IdleTimeout {
+		return nil, errors.New("session access max lifetime must be >= idle timeout")
+	}
+	if conf.RefreshExpirationTime <= 0 {
+		return nil, errors.New("session refresh expiration time must be > 0")
+	}
+	return &Manager{cache: cache, conf: conf}, nil
+}
+
+// record Redis 中保存的令牌身份
+type record struct {
+	UserId    int32  `json:"uid"`
+	Username  string `json:"username"`
+	SessionId string `json:"sid"`
+	FamilyId  string `json:"fid"`
+	IssuedAt  int64  `json:"iat"` // 签发时间（unix 毫秒），吊销水位线按毫秒比较
+	ExpireAt  int64  `json:"exp"` // 最长有效期（unix 秒）
+}
+
+// IssueTokens 登录签发令牌对，开启新的令牌族
+func (m *Manager) IssueTokens(ctx context.Context, userId int32, username string) (*xauth.TokenPair, error) {
+	return m.issue(ctx, userId, username, "")
+}
+
+// ParseAccessToken 校验 access token 并滑动续期，令牌不存在时按过期处理，客户端使用 refresh token 续期
+func (m *Manager) ParseAccessToken(ctx context.Context, token string) (*xauth.TokenClaims, error) {
+	tokenId := TokenId(token)
+	rec, err := m.load(ctx, accessSuffix+tokenId, xauth.ErrTokenExpired)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	remaining := time.Unix(rec.ExpireAt, 0).Sub(now)
+	if remaining <= 0 {
+		return nil, xauth.ErrTokenExpired
+	}
+	ttl := min(m.conf.IdleTimeout, remaining)
+	// 续期失败不影响本次请求，最多提前进入空闲超时
+	_ = m.cache.Expire(ctx, m.conf.KeyPrefix+accessSuffix+tokenId, ttl)
+	claims := rec.claims(tokenId)
+	claims.ExpireAt = now.Add(ttl)
+	return claims, nil
+}
+
+// ParseRefreshToken 校验 refresh token
+func (m *Manager) ParseRefreshToken(ctx context.Context, token string) (*xauth.TokenClaims, error) {
+	tokenId := TokenId(token)
+	rec, err := m.load(ctx, refreshSuffix+tokenId, xauth.ErrInvalidToken)
+	if err != nil {
+		return nil, err
+	}
+	return rec.claims(tokenId), nil
+}
+
+// RotateTokens 签发新令牌对并继承令牌族
+// 旧 refresh token 保留到过期，与 JWT 一致由调用方的一次性使用标记拒绝重复使用，以便识别重复使用并吊销整族
+func (m *Manager) RotateTokens(ctx context.Context, refresh *xauth.TokenClaims) (*xauth.TokenPair, error) {
+	return m.issue(ctx, refresh.UserId, refresh.Username, refresh.FamilyId)
+}
+
+// RevokeTokens 删除 access token 与会话对应的 refresh token
+func (m *Manager) RevokeTokens(ctx context.Context, tokenId, sessionId string) error {
+	keys := make([]string, 0, 2)
+	if tokenId != "" {
+		keys = append(keys, m.conf.KeyPrefix+accessSuffix+tokenId)
+	}
+	if sessionId != "" {
+		keys = append(keys, m.conf.KeyPrefix+refreshSuffix+sessionId)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if _, err := m.cache.Delete(ctx, keys...); err != nil {
+		return fmt.Errorf("delete session token error: %w", err)
+	}
+	return nil
+}
+
+// AccessExpiration access token 最长有效期
+func (m *Manager) AccessExpiration() time.Duration {
+	return m.conf.AccessMaxLifetime
+}
+
+// RefreshExpiration refresh token 有效期
+func (m *Manager) RefreshExpiration() time.Duration {
+	return m.conf.RefreshExpirationTime
+}
+
+// TokenId 令牌ID，即令牌的 SHA-256 摘要
+func TokenId(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issue familyId 为空时以本次 refresh token ID 作为新的族ID
+func (m *Manager) issue(ctx context.Context, userId int32, username, familyId string) (*xauth.TokenPair, error) {
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	sessionId := TokenId(refreshToken)
+	if familyId == "" {
+		familyId = sessionId
+	}
+	refreshExp := time.Unix(now.Add(m.conf.RefreshExpirationTime).Unix(), 0)
+	refreshRec := &record{
+		UserId:    userId,
+		Username:  username,
+		SessionId: sessionId,
+		FamilyId:  familyId,
+		IssuedAt:  now.UnixMilli(),
+		ExpireAt:  refreshExp.Unix(),
+	}
+	accessRec := *refreshRec
+	accessRec.ExpireAt = now.Add(m.conf.AccessMaxLifetime).Unix()
+
+	if err := m.save(ctx, refreshSuffix+sessionId, refreshRec, m.conf.RefreshExpirationTime); err != nil {
+		return nil, err
+	}
+	if err := m.save(ctx, accessSuffix+TokenId(accessToken), &accessRec, m.conf.IdleTimeout); err != nil {
+		return nil, err
+	}
+	return &xauth.TokenPair{
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		AccessExpire:  time.Unix(now.Add(m.conf.IdleTimeout).Unix(), 0),
+		RefreshExpire: refreshExp,
+		Refresh:       refreshRec.claims(sessionId),
+	}, nil
+}
+
+func (m *Manager) save(ctx context.Context, key string, rec *record, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal session token error: %w", err)
+	}
+	if err := m.cache.Set(ctx, m.conf.KeyPrefix+key, string(data), ttl); err != nil {
+		return fmt.Errorf("save session token error: %w", err)
+	}
+	return nil
+}
+
+// load 读取令牌身份，令牌不存在时返回 missingErr
+func (m *Manager) load(ctx context.Context, key string, missingErr error) (*record, error) {
+	raw, ok, err := m.cache.Get(ctx, m.conf.KeyPrefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("get session token error: %w", err)
+	}
+	if !ok {
+		return nil, missingErr
+	}
+	var rec record
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return nil, xauth.ErrInvalidToken
+	}
+	return &rec, nil
+}
+
+func (r *record) claims(tokenId string) *xauth.TokenClaims {
+	return &xauth.TokenClaims{
+		UserId:    r.UserId,
+		Username:  r.Username,
+		TokenId:   tokenId,
+		SessionId: r.SessionId,
+		FamilyId:  r.FamilyId,
+		IssuedAt:  time.UnixMilli(r.IssuedAt),
+		ExpireAt:  time.Unix(r.ExpireAt, 0),
+	}
+}
+
+// newToken 生成 base64url 编码的随机令牌
+func newToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate session token error: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
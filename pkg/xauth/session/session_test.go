@@ -0,0 +1,206 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"snowgo/pkg/xauth"
+	"snowgo/pkg/xcache"
+)
+
+// memCache 只实现会话令牌用到的方法
+type memCache struct {
+	xcache.Cache
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newMemCache() *memCache {
+	return &memCache{values: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (c *memCache) Get(_ context.Context, key string) (string, bool, error) {
+	v, ok := c.values[key]
+	return v, ok, nil
+}
+
+func (c *memCache) Set(_ context.Context, key string, value string, expiration time.Duration) error {
+	c.values[key] = value
+	c.ttls[key] = expiration
+	return nil
+}
+
+func (c *memCache) Delete(_ context.Context, keys ...string) (int64, error) {
+	var n int64
+	for _, key := range keys {
+		if _, ok := c.values[key]; ok {
+			delete(c.values, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (c *memCache) Expire(_ context.Context, key string, expiration time.Duration) error {
+	c.ttls[key] = expiration
+	return nil
+}
+
+func newTestManager(t *testing.T, cache *memCache) *Manager {
+	t.Helper()
+	m, err := NewManager(cache, Config{
+		KeyPrefix:             "auth:token:",
+		IdleTimeout:           30 * time.Minute,
+		AccessMaxLifetime:     12 * time.Hour,
+		RefreshExpirationTime: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager error: %v", err)
+	}
+	return m
+}
+
+func TestNewManagerValidation(t *testing.T) {
+	valid := Config{KeyPrefix: "p:", IdleTimeout: time.Minute, AccessMaxLifetime: time.Hour, RefreshExpirationTime: time.Hour}
+	if _, err := NewManager(newMemCache(), valid); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"empty prefix", func(c *Config) { c.KeyPrefix = "" }},
+		{"zero idle timeout", func(c *Config) { c.IdleTimeout = 0 }},
+		{"max lifetime below idle timeout", func(c *Config) { c.AccessMaxLifetime = time.Second }},
+		{"zero refresh expiration", func(c *Config) { c.RefreshExpirationTime = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := valid
+			tt.modify(&conf)
+			if _, err := NewManager(newMemCache(), conf); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestManagerIssueAndParse(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemCache()
+	m := newTestManager(t, cache)
+
+	before := time.Now().UnixMilli()
+	pair, err := m.IssueTokens(ctx, 7, "alice")
+	if err != nil {
+		t.Fatalf("IssueTokens error: %v", err)
+	}
+	if pair.Refresh.SessionId != TokenId(pair.RefreshToken) || pair.Refresh.FamilyId != pair.Refresh.SessionId {
+		t.Fatalf("unexpected refresh claims: %+v", pair.Refresh)
+	}
+	// Redis 中不保存令牌明文
+	for key, value := range cache.values {
+		if strings.Contains(key+value, pair.AccessToken) || strings.Contains(key+value, pair.RefreshToken) {
+			t.Fatalf("token stored in plaintext: %s=%s", key, value)
+		}
+	}
+	accessKey := "auth:token:access:" + TokenId(pair.AccessToken)
+	if cache.ttls[accessKey] != 30*time.Minute {
+		t.Fatalf("expected access ttl to be idle timeout, got %v", cache.ttls[accessKey])
+	}
+
+	cache.ttls[accessKey] = time.Minute
+	access, err := m.ParseAccessToken(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseAccessToken error: %v", err)
+	}
+	if access.UserId != 7 || access.Username != "alice" || access.SessionId != pair.Refresh.SessionId || access.TokenId != TokenId(pair.AccessToken) {
+		t.Fatalf("unexpected access claims: %+v", access)
+	}
+	if ms := access.IssuedAt.UnixMilli(); ms < before || ms > time.Now().UnixMilli() {
+		t.Fatalf("expected millisecond issued at, got %v", access.IssuedAt)
+	}
+	if cache.ttls[accessKey] != 30*time.Minute {
+		t.Fatalf("expected sliding expiry to reset ttl, got %v", cache.ttls[accessKey])
+	}
+
+	// access 与 refresh 不能混用
+	if _, err := m.ParseAccessToken(ctx, pair.RefreshToken); !errors.Is(err, xauth.ErrTokenExpired) {
+		t.Fatalf("expected refresh token rejected as access token, got %v", err)
+	}
+	if _, err := m.ParseRefreshToken(ctx, pair.AccessToken); !errors.Is(err, xauth.ErrInvalidToken) {
+		t.Fatalf("expected access token rejected as refresh token, got %v", err)
+	}
+
+	refresh, err := m.ParseRefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken error: %v", err)
+	}
+	rotated, err := m.RotateTokens(ctx, refresh)
+	if err != nil {
+		t.Fatalf("RotateTokens error: %v", err)
+	}
+	if rotated.Refresh.SessionId == refresh.SessionId || rotated.Refresh.FamilyId != refresh.FamilyId {
+		t.Fatalf("expected new session in same family, got %+v", rotated.Refresh)
+	}
+}
+
+func TestManagerSlidingExpiryCappedByMaxLifetime(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemCache()
+	m := newTestManager(t, cache)
+	pair, err := m.IssueTokens(ctx, 7, "alice")
+	if err != nil {
+		t.Fatalf("IssueTokens error: %v", err)
+	}
+	accessKey := "auth:token:access:" + TokenId(pair.AccessToken)
+
+	// 剩余最长有效期小于空闲超时时，续期不超过最长有效期
+	rec, err := m.load(ctx, accessSuffix+TokenId(pair.AccessToken), nil)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	rec.ExpireAt = time.Now().Add(10 * time.Minute).Unix()
+	if err := m.save(ctx, accessSuffix+TokenId(pair.AccessToken), rec, time.Minute); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	if _, err := m.ParseAccessToken(ctx, pair.AccessToken); err != nil {
+		t.Fatalf("ParseAccessToken error: %v", err)
+	}
+	if ttl := cache.ttls[accessKey]; ttl > 10*time.Minute || ttl < 9*time.Minute {
+		t.Fatalf("expected ttl capped by max lifetime, got %v", ttl)
+	}
+
+	rec.ExpireAt = time.Now().Add(-time.Second).Unix()
+	if err := m.save(ctx, accessSuffix+TokenId(pair.AccessToken), rec, time.Minute); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	if _, err := m.ParseAccessToken(ctx, pair.AccessToken); !errors.Is(err, xauth.ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired after max lifetime, got %v", err)
+	}
+}
+
+func TestManagerRevokeTokens(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, newMemCache())
+	pair, err := m.IssueTokens(ctx, 7, "alice")
+	if err != nil {
+		t.Fatalf("IssueTokens error: %v", err)
+	}
+	access, err := m.ParseAccessToken(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseAccessToken error: %v", err)
+	}
+	if err := m.RevokeTokens(ctx, access.TokenId, access.SessionId); err != nil {
+		t.Fatalf("RevokeTokens error: %v", err)
+	}
+	if _, err := m.ParseAccessToken(ctx, pair.AccessToken); !errors.Is(err, xauth.ErrTokenExpired) {
+		t.Fatalf("expected revoked access token rejected, got %v", err)
+	}
+	if _, err := m.ParseRefreshToken(ctx, pair.RefreshToken); !errors.Is(err, xauth.ErrInvalidToken) {
+		t.Fatalf("expected revoked refresh token rejected, got %v", err)
+	}
+}
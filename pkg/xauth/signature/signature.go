@@ -0,0 +1,175 @@
+// Package signature 开放接口 HMAC-SHA256 请求签名，服务端验签与客户端签名使用同一待签名串
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderAccessKey = "X-Access-Key" // 访问密钥ID
+	HeaderTimestamp = "X-Timestamp"  // 请求时间戳(unix 秒)
+	HeaderNonce     = "X-Nonce"      // 随机串，时间窗口内不可重复
+	HeaderSignature = "X-Signature"  // 十六进制 HMAC-SHA256 签名
+
+	nonceBytes      = 16 // 生成 nonce 的随机字节数（32位十六进制）
+	minNonceLen     = 16
+	maxNonceLen     = 64
+	maxAccessKeyLen = 64
+)
+
+var (
+	ErrMissingHeader    = errors.New("signature header missing")
+	ErrInvalidAccessKey = errors.New("signature access key invalid")
+	ErrInvalidTimestamp = errors.New("signature timestamp invalid")
+	ErrInvalidNonce     = errors.New("signature nonce invalid")
+)
+
+// Signed 请求携带的签名信息
+type Signed struct {
+	AccessKey string
+	Timestamp int64
+	Nonce     string
+	Signature string
+}
+
+// Time 签名时间
+func (s *Signed) Time() time.Time {
+	return time.Unix(s.Timestamp, 0)
+}
+
+// Parse 从请求头读取签名信息，只校验格式，不校验签名与时间窗口
+func Parse(header http.Header) (*Signed, error) {
+	s := &Signed{
+		AccessKey: header.Get(HeaderAccessKey),
+		Nonce:     header.Get(HeaderNonce),
+		Signature: header.Get(HeaderSignature),
+	}
+	rawTs := header.Get(HeaderTimestamp)
+	if s.AccessKey == "" || s.Nonce == "" || s.Signature == "" || rawTs == "" {
+		return nil, ErrMissingHeader
+	}
+	if len(s.AccessKey) > maxAccessKeyLen {
+		return nil, ErrInvalidAccessKey
+	}
+	ts, err := strconv.ParseInt(rawTs, 10, 64)
+	if err != nil || ts <= 0 {
+		return nil, ErrInvalidTimestamp
+	}
+	s.Timestamp = ts
+	if !validNonce(s.Nonce) {
+		return nil, ErrInvalidNonce
+	}
+	return s, nil
+}
+
+// Verify 使用密钥重新计算请求签名并常量时间比较
+func (s *Signed) Verify(secret string, r *http.Request, body []byte) bool {
+	expected := Compute(secret, StringToSign(r.Method, r.URL.EscapedPath(), r.URL.RawQuery, body,
+		s.AccessKey, s.Timestamp, s.Nonce))
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(s.Signature)))
+}
+
+// StringToSign 待签名串：方法、路径、规范化查询串、请求体 SHA-256、访问密钥ID、时间戳、nonce 以换行连接
+func StringToSign(method, path, rawQuery string, body []byte, accessKey string, timestamp int64, nonce string) string {
+	if path == "" {
+		path = "/"
+	}
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		canonicalQuery(rawQuery),
+		hex.EncodeToString(bodyHash[:]),
+		accessKey,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+	}, "\n")
+}
+
+// Compute 计算待签名串的十六进制 HMAC-SHA256 签名
+func Compute(secret, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign 为请求签名并写入签名请求头，body 需与实际发送的请求体一致；每次发送（含重试）都要重新签名
+func Sign(r *http.Request, body []byte, accessKey, secret string, now time.Time) error {
+	nonce, err := NewNonce()
+	if err != nil {
+		return err
+	}
+	ts := now.Unix()
+	r.Header.Set(HeaderAccessKey, accessKey)
+	r.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, Compute(secret, StringToSign(r.Method, r.URL.EscapedPath(), r.URL.RawQuery, body,
+		accessKey, ts, nonce)))
+	return nil
+}
+
+// NewNonce 生成随机 nonce
+func NewNonce() (string, error) {
+	buf := make([]byte, nonceBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成签名nonce失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// canonicalQuery 规范化查询串：按参数名、参数值排序后重新编码，与参数顺序和编码差异无关
+func canonicalQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// 无法解析时按原串签名，双方原样传输即可验签通过
+		return rawQuery
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		vs := values[k]
+		sort.Strings(vs)
+		for _, v := range vs {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
+
+// validNonce nonce 仅允许字母、数字、- 和 _，长度 16-64
+func validNonce(nonce string) bool {
+	if len(nonce) < minNonceLen || len(nonce) > maxNonceLen {
+		return false
+	}
+	for _, c := range nonce {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
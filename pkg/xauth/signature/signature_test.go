@@ -0,0 +1,100 @@
+package signature
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"order_no":"A1001"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/open/order?b=2&a=1&a=0", nil)
+	now := time.Unix(1760000000, 0)
+	if err := Sign(req, body, "ak_test", "secret", now); err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+
+	signed, err := Parse(req.Header)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if signed.AccessKey != "ak_test" || !signed.Time().Equal(now) || len(signed.Nonce) != nonceBytes*2 {
+		t.Fatalf("unexpected signed info: %+v", signed)
+	}
+	if !signed.Verify("secret", req, body) {
+		t.Fatalf("expected signature verified")
+	}
+
+	// 参数顺序不影响签名
+	reordered := httptest.NewRequest(http.MethodPost, "/api/open/order?a=0&b=2&a=1", nil)
+	if !signed.Verify("secret", reordered, body) {
+		t.Fatalf("expected signature independent of query order")
+	}
+
+	tampered := []struct {
+		name   string
+		req    *http.Request
+		body   []byte
+		secret string
+	}{
+		{"secret", req, body, "other"},
+		{"body", req, []byte(`{"order_no":"A1002"}`), "secret"},
+		{"method", httptest.NewRequest(http.MethodPut, "/api/open/order?a=0&a=1&b=2", nil), body, "secret"},
+		{"path", httptest.NewRequest(http.MethodPost, "/api/open/orders?a=0&a=1&b=2", nil), body, "secret"},
+		{"query", httptest.NewRequest(http.MethodPost, "/api/open/order?a=0&a=1&b=3", nil), body, "secret"},
+	}
+	for _, tt := range tampered {
+		t.Run(tt.name, func(t *testing.T) {
+			if signed.Verify(tt.secret, tt.req, tt.body) {
+				t.Fatalf("expected tampered %s rejected", tt.name)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	valid := func() http.Header {
+		h := http.Header{}
+		h.Set(HeaderAccessKey, "ak_test")
+		h.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+		h.Set(HeaderNonce, "0123456789abcdef")
+		h.Set(HeaderSignature, "deadbeef")
+		return h
+	}
+	if _, err := Parse(valid()); err != nil {
+		t.Fatalf("expected valid header, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(h http.Header)
+		want   error
+	}{
+		{"missing signature", func(h http.Header) { h.Del(HeaderSignature) }, ErrMissingHeader},
+		{"missing timestamp", func(h http.Header) { h.Del(HeaderTimestamp) }, ErrMissingHeader},
+		{"long access key", func(h http.Header) { h.Set(HeaderAccessKey, string(make([]byte, maxAccessKeyLen+1))) }, ErrInvalidAccessKey},
+		{"bad timestamp", func(h http.Header) { h.Set(HeaderTimestamp, "1760000000000x") }, ErrInvalidTimestamp},
+		{"short nonce", func(h http.Header) { h.Set(HeaderNonce, "abc") }, ErrInvalidNonce},
+		{"nonce charset", func(h http.Header) { h.Set(HeaderNonce, "0123456789abcdef:") }, ErrInvalidNonce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := valid()
+			tt.modify(h)
+			if _, err := Parse(h); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestStringToSign(t *testing.T) {
+	got := StringToSign("get", "", "b=2&a=1", nil, "ak_test", 1760000000, "0123456789abcdef")
+	want := "GET\n/\na=1&b=2\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855\nak_test\n1760000000\n0123456789abcdef"
+	if got != want {
+		t.Fatalf("unexpected string to sign:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package xauth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	TokenStrategyJwt     = "jwt"     // 自包含 JWT，access/refresh 通过响应体返回，请求头 Authorization 携带
+	TokenStrategySession = "session" // 服务端不透明会话，令牌存储于 Redis，通过 HttpOnly Cookie 传输
+)
+
+var (
+	ErrTokenExpired     = errors.New("token has expired")
+	ErrInvalidTokenType = errors.New("invalid token type")
+	ErrInvalidToken     = errors.New("invalid token")
+)
+
+// TokenClaims 令牌对应的登录身份，与令牌策略无关
+type TokenClaims struct {
+	UserId    int32
+	Username  string
+	TokenId   string // 令牌ID：JWT 为 jti，不透明会话为令牌摘要
+	SessionId string // 会话ID，即 refresh token 的令牌ID
+	FamilyId  string // refresh token 族ID，登录时生成，轮换时继承
+	IssuedAt  time.Time
+	ExpireAt  time.Time
+}
+
+// TokenPair 登录或刷新签发的 access + refresh 令牌
+type TokenPair struct {
+	AccessToken   string
+	RefreshToken  string
+	AccessExpire  time.Time
+	RefreshExpire time.Time
+	Refresh       *TokenClaims // 新 refresh token 的身份，用于登记会话与一次性使用标记
+}
+
+// TokenStrategy 登录令牌策略，按配置选择 JWT 或服务端不透明会话
+// 吊销、会话登记、refresh token 一次性使用与重复使用检测由调用方基于 TokenClaims 统一处理
+type TokenStrategy interface {
+	// IssueTokens 登录签发令牌对，开启新的令牌族
+	IssueTokens(ctx context.Context, userId int32, username string) (*TokenPair, error)
+	// ParseAccessToken 校验 access token 并返回身份
+	ParseAccessToken(ctx context.Context, token string) (*TokenClaims, error)
+	// ParseRefreshToken 校验 refresh token 并返回身份
+	ParseRefreshToken(ctx context.Context, token string) (*TokenClaims, error)
+	// RotateTokens 用已校验的 refresh token 身份签发新令牌对，继承令牌族
+	RotateTokens(ctx context.Context, refresh *TokenClaims) (*TokenPair, error)
+	// RevokeTokens 登出时删除服务端保存的 access token 与会话的 refresh token，无服务端状态的策略直接返回
+	RevokeTokens(ctx context.Context, tokenId, sessionId string) error
+	// AccessExpiration access token 最长有效期
+	AccessExpiration() time.Duration
+	// RefreshExpiration refresh token 最长有效期
+	RefreshExpiration() time.Duration
+}
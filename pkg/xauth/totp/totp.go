@@ -0,0 +1,113 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 — RFC 6238 默认算法，主流验证器 App 仅支持 SHA1
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Period     = 30 // 时间步长(秒)
+	Digits     = 6  // 验证码位数
+	Skew       = 1  // 允许前后偏移的时间步数，容忍客户端时钟误差
+	secretSize = 20 // 密钥字节数(160bit)，RFC 4226 推荐长度
+)
+
+var (
+	ErrInvalidSecret = errors.New("totp secret invalid")
+	encoding         = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// GenerateSecret 生成 base32 编码(无填充)的随机密钥
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成totp密钥失败: %w", err)
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// ProvisioningURI 生成验证器 App 扫码绑定使用的 otpauth URI
+func ProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", Digits))
+	params.Set("period", fmt.Sprintf("%d", Period))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Step 返回时间所在的时间步
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// GenerateCode 生成指定时间的验证码
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(Step(t)), Digits), nil // #nosec G115 — unix 时间步非负
+}
+
+// Validate 校验验证码，成功时返回匹配的时间步，调用方需记录已使用的时间步防止重放
+func Validate(secret, code string, t time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+	current := Step(t)
+	for offset := int64(-Skew); offset <= Skew; offset++ {
+		step := current + offset
+		if step < 0 {
+			continue
+		}
+		expected := hotp(key, uint64(step), Digits)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// hotp RFC 4226 HMAC-SHA1 一次性密码
+func hotp(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// 动态截断
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,93 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录B SHA1 测试密钥 "12345678901234567890"
+var rfc6238Secret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestHotpRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	key, err := decodeSecret(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	for _, tt := range tests {
+		got := hotp(key, uint64(tt.unix/Period), 8)
+		if got != tt.want {
+			t.Fatalf("hotp at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, err := GenerateCode(rfc6238Secret, now)
+	if err != nil {
+		t.Fatalf("GenerateCode error: %v", err)
+	}
+	if code != "081804" {
+		t.Fatalf("expected 6 digit code 081804, got %s", code)
+	}
+
+	step, ok := Validate(rfc6238Secret, code, now)
+	if !ok || step != Step(now) {
+		t.Fatalf("expected code valid at step %d, got %d %v", Step(now), step, ok)
+	}
+	// 容忍前后一个时间步的时钟误差
+	if _, ok := Validate(rfc6238Secret, code, now.Add(Period*time.Second)); !ok {
+		t.Fatal("expected code valid in next step")
+	}
+	if _, ok := Validate(rfc6238Secret, code, now.Add(-Period*time.Second)); !ok {
+		t.Fatal("expected code valid in previous step")
+	}
+	if _, ok := Validate(rfc6238Secret, code, now.Add(2*Period*time.Second)); ok {
+		t.Fatal("expected code invalid beyond skew")
+	}
+	if _, ok := Validate(rfc6238Secret, "12345", now); ok {
+		t.Fatal("expected short code invalid")
+	}
+	if _, ok := Validate("not-base32!", code, now); ok {
+		t.Fatal("expected invalid secret rejected")
+	}
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret error: %v", err)
+	}
+	if len(secret) != 32 || strings.Contains(secret, "=") {
+		t.Fatalf("expected 32 char unpadded base32 secret, got %q", secret)
+	}
+	code, err := GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("GenerateCode error: %v", err)
+	}
+	if _, ok := Validate(secret, code, time.Now()); !ok {
+		t.Fatal("expected generated code valid")
+	}
+
+	uri := ProvisioningURI("snow go", "admin", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/snow%20go:admin?") {
+		t.Fatalf("unexpected uri label: %s", uri)
+	}
+	for _, part := range []string{"secret=" + secret, "issuer=snow+go", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Fatalf("expected uri contains %q, got %s", part, uri)
+		}
+	}
+}
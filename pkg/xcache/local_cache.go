@@ -0,0 +1,105 @@
+package xcache
+
+import (
+	"sync"
+	"time"
+)
+
+type localItem[V any] struct {
+	value    V
+	expireAt time.Time
+}
+
+// LocalCache 进程内带过期时间的有界缓存，并发安全
+// 写满时先清理已过期条目，仍然写满则随机淘汰一个条目
+type LocalCache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]localItem[V]
+	size  int
+	ttl   time.Duration
+}
+
+// NewLocalCache size 为最大条目数，ttl 为条目有效期，二者都必须大于0
+func NewLocalCache[K comparable, V any](size int, ttl time.Duration) *LocalCache[K, V] {
+	if size <= 0 {
+		size = 1
+	}
+	return &LocalCache[K, V]{
+		items: make(map[K]localItem[V], size),
+		size:  size,
+		ttl:   ttl,
+	}
+}
+
+// Get 获取未过期的值
+func (c *LocalCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || !time.Now().Before(item.expireAt) {
+		var zero V
+		return zero, false
+	}
+	return item.value, true
+}
+
+// Set 写入值，有效期为创建时指定的 ttl
+func (c *LocalCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL 写入值，有效期取 ttl 与创建时指定 ttl 的较小值，ttl<=0 时不写入
+func (c *LocalCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	if ttl > c.ttl {
+		ttl = c.ttl
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; !ok && len(c.items) >= c.size {
+		c.evictLocked(now)
+	}
+	c.items[key] = localItem[V]{value: value, expireAt: now.Add(ttl)}
+}
+
+// Delete 删除一个或多个 key
+func (c *LocalCache[K, V]) Delete(keys ...K) {
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+}
+
+// Purge 清空全部条目
+func (c *LocalCache[K, V]) Purge() {
+	c.mu.Lock()
+	c.items = make(map[K]localItem[V], c.size)
+	c.mu.Unlock()
+}
+
+// Len 当前条目数（包含尚未清理的过期条目）
+func (c *LocalCache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+func (c *LocalCache[K, V]) evictLocked(now time.Time) {
+	for key, item := range c.items {
+		if !now.Before(item.expireAt) {
+			delete(c.items, key)
+		}
+	}
+	if len(c.items) < c.size {
+		return
+	}
+	// map 遍历顺序随机，删除第一个即随机淘汰
+	for key := range c.items {
+		delete(c.items, key)
+		return
+	}
+}
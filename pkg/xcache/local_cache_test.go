@@ -0,0 +1,72 @@
+package xcache_test
+
+import (
+	"testing"
+	"time"
+
+	"snowgo/pkg/xcache"
+)
+
+func TestLocalCache_GetSetDelete(t *testing.T) {
+	c := xcache.NewLocalCache[int32, string](4, time.Minute)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+	c.Set(1, "a")
+	c.Set(2, "b")
+	if v, ok := c.Get(1); !ok || v != "a" {
+		t.Fatalf("expected hit a, got %q %v", v, ok)
+	}
+	c.Delete(1)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("expected miss after delete")
+	}
+	c.Purge()
+	if _, ok := c.Get(2); ok || c.Len() != 0 {
+		t.Fatalf("expected empty cache after purge, len %d", c.Len())
+	}
+}
+
+func TestLocalCache_Expire(t *testing.T) {
+	c := xcache.NewLocalCache[string, int](4, 20*time.Millisecond)
+	c.Set("k", 1)
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+}
+
+func TestLocalCache_SetWithTTL(t *testing.T) {
+	c := xcache.NewLocalCache[string, int](4, time.Minute)
+	c.SetWithTTL("short", 1, 20*time.Millisecond)
+	c.SetWithTTL("long", 2, time.Hour)
+	c.SetWithTTL("past", 3, 0)
+	if _, ok := c.Get("past"); ok {
+		t.Fatal("expected non-positive ttl not to be stored")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("short"); ok {
+		t.Fatal("expected short entry to expire")
+	}
+	if v, ok := c.Get("long"); !ok || v != 2 {
+		t.Fatalf("expected long entry capped at cache ttl and still alive, got %d %v", v, ok)
+	}
+}
+
+func TestLocalCache_Bounded(t *testing.T) {
+	c := xcache.NewLocalCache[int, int](3, time.Minute)
+	for i := 0; i < 10; i++ {
+		c.Set(i, i)
+		if c.Len() > 3 {
+			t.Fatalf("expected at most 3 entries, got %d", c.Len())
+		}
+	}
+	if v, ok := c.Get(9); !ok || v != 9 {
+		t.Fatalf("expected latest entry kept, got %d %v", v, ok)
+	}
+	// 覆盖已存在的 key 不触发淘汰
+	c.Set(9, 90)
+	if v, _ := c.Get(9); v != 90 || c.Len() != 3 {
+		t.Fatalf("expected overwrite in place, got %d len %d", v, c.Len())
+	}
+}
@@ -0,0 +1,68 @@
+package xcache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// resubscribeDelay 订阅连接异常后的重试间隔
+const resubscribeDelay = time.Second
+
+// Notifier 跨实例广播消息，用于通知各实例清理进程内缓存
+type Notifier interface {
+	// Publish 向 channel 广播消息
+	Publish(ctx context.Context, channel string, message string) error
+
+	// Subscribe 阻塞订阅 channel 直到 ctx 取消
+	// 每次订阅（包括断线重连后的重新订阅）成功都会回调 onSubscribe，断线期间的消息会丢失，调用方应在此清理本地状态
+	Subscribe(ctx context.Context, channel string, onSubscribe func(), onMessage func(message string)) error
+}
+
+type RedisNotifier struct {
+	client *redis.Client
+}
+
+func NewRedisNotifier(client *redis.Client) (Notifier, error) {
+	if client == nil {
+		return nil, errors.New("redis client cannot be nil")
+	}
+	return &RedisNotifier{client: client}, nil
+}
+
+func (r *RedisNotifier) Publish(ctx context.Context, channel string, message string) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+func (r *RedisNotifier) Subscribe(ctx context.Context, channel string, onSubscribe func(), onMessage func(message string)) error {
+	ps := r.client.Subscribe(ctx, channel)
+	defer func() { _ = ps.Close() }()
+
+	for {
+		msg, err := ps.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// 连接异常时 go-redis 会在下次 Receive 时重连并重新订阅
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(resubscribeDelay):
+			}
+			continue
+		}
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" && onSubscribe != nil {
+				onSubscribe()
+			}
+		case *redis.Message:
+			if onMessage != nil {
+				onMessage(m.Payload)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package xcache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"snowgo/pkg/xcache"
+)
+
+func TestRedisNotifier_PublishSubscribe(t *testing.T) {
+	client := setupTestRedis(t)
+	notifier, err := xcache.NewRedisNotifier(client)
+	if err != nil {
+		t.Fatalf("NewRedisNotifier: %v", err)
+	}
+	channel := testKey("notifier", t.Name())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subscribed := make(chan struct{}, 1)
+	messages := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- notifier.Subscribe(ctx, channel, func() {
+			subscribed <- struct{}{}
+		}, func(message string) {
+			messages <- message
+		})
+	}()
+
+	select {
+	case <-subscribed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected subscribe callback")
+	}
+	if err := notifier.Publish(ctx, channel, "user:1"); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	select {
+	case msg := <-messages:
+		if msg != "user:1" {
+			t.Fatalf("expected user:1, got %q", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected published message")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil after cancel, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Subscribe to return after cancel")
+	}
+}
@@ -12,3 +12,10 @@ func TestNewRedisCache_NilClient(t *testing.T) {
 		t.Fatal("expected error for nil redis client")
 	}
 }
+
+func TestNewRedisNotifier_NilClient(t *testing.T) {
+	_, err := xcache.NewRedisNotifier(nil)
+	if err == nil {
+		t.Fatal("expected error for nil redis client")
+	}
+}
@@ -7,14 +7,18 @@ import (
 )
 
 const (
-	CategoryHttp      = "http"       // HTTP 协议级状态
-	CategorySystem    = "system"     // 基础设施级错误（限流、参数越权等）
-	CategoryAuth      = "admin_auth" // Admin 认证相关
-	CategoryAdminUser = "admin_user" // Admin 用户相关
-	CategoryAdminMenu = "admin_menu" // Admin 菜单相关
-	CategoryAdminRole = "admin_role" // Admin 角色相关
-	CategoryAdminDict = "admin_dict" // Admin 字典相关
-	CategoryAdminLog  = "admin_log"  // Admin 操作日志相关
+	CategoryHttp         = "http"          // HTTP 协议级状态
+	CategorySystem       = "system"        // 基础设施级错误（限流、参数越权等）
+	CategoryAuth         = "admin_auth"    // Admin 认证相关
+	CategoryAdminUser    = "admin_user"    // Admin 用户相关
+	CategoryAdminMenu    = "admin_menu"    // Admin 菜单相关
+	CategoryAdminRole    = "admin_role"    // Admin 角色相关
+	CategoryAdminDept    = "admin_dept"    // Admin 部门相关
+	CategoryAdminApiKey  = "admin_api_key" // Admin API Key 相关
+	CategoryAdminPwd     = "admin_pwd"     // Admin 密码策略相关
+	CategoryAdminDict    = "admin_dict"    // Admin 字典相关
+	CategoryAdminLog     = "admin_log"     // Admin 操作日志相关
+	CategoryAdminPartner = "admin_partner" // Admin 开放接口合作方相关
 )
 
 var (
@@ -50,6 +54,9 @@ var (
 	OffsetErrorRequests = NewCode(CategorySystem, 20103, "offset必须大于等于0")
 	LimitErrorRequests  = NewCode(CategorySystem, 20104, "limit必须大于0")
 	TimeFormatError     = NewCode(CategorySystem, 20105, "时间格式错误，应为yyyy-MM-dd HH:mm:ss")
+	IdempotencyKeyError = NewCode(CategorySystem, 20106, "Idempotency-Key格式错误，长度不超过128的可见字符")
+	IdempotencyBusy     = NewCode(CategorySystem, 20107, "相同请求正在处理中，请勿重复提交")
+	IdempotencyKeyReuse = NewCode(CategorySystem, 20108, "Idempotency-Key已用于其他请求，请更换后重试")
 )
 
 // auth相关  认证相关为101开头
@@ -63,11 +70,44 @@ var (
 	TokenUsedError       = NewCode(CategoryAuth, 10107, "token已被使用过，不能重复使用")
 	LoginLocked          = NewCode(CategoryAuth, 10108, "登录失败次数过多，请稍后再试")
 	AuthError            = NewCode(CategoryAuth, 10109, "用户名或密码错误，认证失败")
+	TokenRevoked         = NewCode(CategoryAuth, 10110, "token已失效，请重新登录")
+	SessionLimitExceeded = NewCode(CategoryAuth, 10111, "登录设备数已达上限，请先退出其他设备")
+	SessionNotFound      = NewCode(CategoryAuth, 10112, "会话不存在或已失效")
+	SessionListError     = NewCode(CategoryAuth, 10113, "会话列表获取失败")
+	SessionKickError     = NewCode(CategoryAuth, 10114, "踢出会话失败")
+	MfaChallengeInvalid  = NewCode(CategoryAuth, 10115, "两步验证已失效，请重新登录")
+	MfaCodeInvalid       = NewCode(CategoryAuth, 10116, "两步验证码错误")
+	MfaNotEnabled        = NewCode(CategoryAuth, 10117, "未开启两步验证")
+	MfaAlreadyEnabled    = NewCode(CategoryAuth, 10118, "已开启两步验证")
+	MfaNotEnrolled       = NewCode(CategoryAuth, 10119, "请先绑定验证器")
+	MfaRequiredByRole    = NewCode(CategoryAuth, 10120, "所属角色要求开启两步验证，不能关闭")
+	MfaUnavailable       = NewCode(CategoryAuth, 10121, "两步验证未配置，暂不可用")
+	MfaError             = NewCode(CategoryAuth, 10122, "两步验证操作失败")
+	ApiKeyInvalid        = NewCode(CategoryAuth, 10123, "API Key无效或已过期")
+	OidcUnavailable      = NewCode(CategoryAuth, 10124, "OIDC单点登录未配置，暂不可用")
+	OidcStateInvalid     = NewCode(CategoryAuth, 10125, "OIDC登录已失效，请重新发起登录")
+	OidcLoginError       = NewCode(CategoryAuth, 10126, "OIDC登录失败")
+	OidcUserNotLinked    = NewCode(CategoryAuth, 10127, "OIDC账号未关联系统用户，请联系管理员")
+	OidcUsernameConflict = NewCode(CategoryAuth, 10128, "OIDC用户名与已有用户冲突，请联系管理员关联")
+	OidcUserDisabled     = NewCode(CategoryAuth, 10129, "关联的系统用户已禁用或不允许登录")
+	PwdChangeRequired    = NewCode(CategoryAuth, 10130, "密码已过期或需要修改，请修改密码后重新登录")
+	PwdChangeInvalid     = NewCode(CategoryAuth, 10131, "修改密码已失效，请重新登录")
+	LoginLockedPermanent = NewCode(CategoryAuth, 10132, "登录失败次数过多，账号已锁定，请联系管理员解锁")
+	UserNotLocked        = NewCode(CategoryAuth, 10133, "账号未被锁定")
+	UserUnlockError      = NewCode(CategoryAuth, 10134, "解锁账号失败")
+	SignatureInvalid     = NewCode(CategoryAuth, 10135, "请求签名无效")
+	SignatureExpired     = NewCode(CategoryAuth, 10136, "请求时间戳超出允许范围，请校准时钟")
+	SignatureReplay      = NewCode(CategoryAuth, 10137, "请求已处理，nonce不能重复使用")
+	SignatureUnavailable = NewCode(CategoryAuth, 10138, "开放接口签名认证未配置，暂不可用")
+	CsrfTokenInvalid     = NewCode(CategoryAuth, 10139, "CSRF校验失败，请刷新页面后重试")
+	CaptchaRequired      = NewCode(CategoryAuth, 10140, "登录失败次数较多，请输入验证码")
+	CaptchaInvalid       = NewCode(CategoryAuth, 10141, "验证码错误或已过期，请重新获取")
+	CaptchaUnavailable   = NewCode(CategoryAuth, 10142, "登录验证码未启用")
 )
 
 // account相关 102开头
 var (
-	// UserNotFound 用户相关 102 01 - 102 19
+	// UserNotFound 用户相关 102 01 - 102 20
 	UserNotFound          = NewCode(CategoryAdminUser, 10201, "用户不存在")
 	UserCreateError       = NewCode(CategoryAdminUser, 10202, "用户创建失败")
 	UserUpdateError       = NewCode(CategoryAdminUser, 10203, "用户更新失败")
@@ -84,6 +124,10 @@ var (
 	PwdLengthError        = NewCode(CategoryAdminUser, 10214, "密码长度需为6-32位")
 	PwdInvalidCharError   = NewCode(CategoryAdminUser, 10215, "密码只能包含字母、数字或特殊字符(.!@#$%^&*?_~-)")
 	PwdComplexityError    = NewCode(CategoryAdminUser, 10216, "密码必须同时包含以下任意两类：字母、数字或特殊字符(.!@#$%^&*?_~-)")
+	UserTypeUnsupported   = NewCode(CategoryAdminUser, 10217, "服务账号不支持该操作")
+	UserTypeInvalid       = NewCode(CategoryAdminUser, 10218, "用户类型无效")
+	UserDeptNotExist      = NewCode(CategoryAdminUser, 10219, "设置的部门不存在")
+	UserRoleGrantInvalid  = NewCode(CategoryAdminUser, 10220, "角色授权有效期不合法或角色重复")
 
 	// MenuNotFound 菜单权限相关  102 21 - 102 39
 	MenuNotFound      = NewCode(CategoryAdminMenu, 10221, "菜单不存在")
@@ -98,6 +142,7 @@ var (
 	MenuHasChildren   = NewCode(CategoryAdminMenu, 10230, "存在子菜单，无法删除")
 	MenuUsedByRole    = NewCode(CategoryAdminMenu, 10231, "该菜单权限已被使用，无法删除")
 	MenuIDInvalid     = NewCode(CategoryAdminMenu, 10232, "菜单ID无效")
+	MenuPermsInvalid  = NewCode(CategoryAdminMenu, 10233, "权限标识格式错误，按 : 分段且通配符 * 必须独占一段")
 
 	// RoleNotFound 角色相关 102 41 - 102 59
 	RoleNotFound               = NewCode(CategoryAdminRole, 10241, "角色不存在")
@@ -112,6 +157,37 @@ var (
 	RoleMenuNotExist           = NewCode(CategoryAdminRole, 10250, "设置的菜单不存在")
 	RoleMenuNotAuthorized      = NewCode(CategoryAdminRole, 10251, "无权分配该菜单权限")
 	SuperAdminRoleCannotDelete = NewCode(CategoryAdminRole, 10252, "超级管理员角色不可删除")
+	RoleDataScopeInvalid       = NewCode(CategoryAdminRole, 10253, "自定义数据范围必须指定部门")
+	RoleParentInvalid          = NewCode(CategoryAdminRole, 10254, "继承的角色不存在或不可继承")
+	RoleParentCycle            = NewCode(CategoryAdminRole, 10255, "角色继承关系不能形成循环")
+	RoleInherited              = NewCode(CategoryAdminRole, 10256, "该角色已被其他角色继承，无法删除")
+
+	// ApiKeyNotFound 服务账号 API Key 相关 102 61 - 102 69
+	ApiKeyNotFound       = NewCode(CategoryAdminApiKey, 10261, "API Key不存在")
+	ApiKeyCreateError    = NewCode(CategoryAdminApiKey, 10262, "API Key创建失败")
+	ApiKeyRevokeError    = NewCode(CategoryAdminApiKey, 10263, "API Key吊销失败")
+	ApiKeyListError      = NewCode(CategoryAdminApiKey, 10264, "API Key列表获取失败")
+	ApiKeyExpireInvalid  = NewCode(CategoryAdminApiKey, 10265, "过期时间必须晚于当前时间")
+	ApiKeyUserNotService = NewCode(CategoryAdminApiKey, 10266, "仅服务账号可以创建API Key")
+
+	// DeptNotFound 部门相关 102 70 - 102 80
+	DeptNotFound      = NewCode(CategoryAdminDept, 10270, "部门不存在")
+	DeptCreateError   = NewCode(CategoryAdminDept, 10271, "部门创建失败")
+	DeptUpdateError   = NewCode(CategoryAdminDept, 10272, "部门更新失败")
+	DeptDeleteError   = NewCode(CategoryAdminDept, 10273, "部门删除失败")
+	DeptListError     = NewCode(CategoryAdminDept, 10274, "部门列表获取失败")
+	DeptNameExist     = NewCode(CategoryAdminDept, 10275, "同级部门名称已存在")
+	DeptParentInvalid = NewCode(CategoryAdminDept, 10276, "父级部门不存在")
+	DeptParentCycle   = NewCode(CategoryAdminDept, 10277, "父级部门不能是自己或下级部门")
+	DeptHasChildren   = NewCode(CategoryAdminDept, 10278, "存在下级部门，无法删除")
+	DeptHasUsers      = NewCode(CategoryAdminDept, 10279, "部门下存在用户，无法删除")
+	DeptIDInvalid     = NewCode(CategoryAdminDept, 10280, "部门ID无效")
+
+	// PwdReusedError 密码策略相关 102 81 - 102 99
+	PwdReusedError = NewCode(CategoryAdminPwd, 10281, "新密码不能与最近使用过的密码相同")
+	PwdBannedError = NewCode(CategoryAdminPwd, 10282, "密码过于常见，请更换")
+	PwdChangeError = NewCode(CategoryAdminPwd, 10283, "修改密码失败")
+	PwdOldError    = NewCode(CategoryAdminPwd, 10284, "原密码错误")
 )
 
 // 业务system相关 103开头
@@ -133,6 +209,16 @@ var (
 	DictItemCreateError    = NewCode(CategoryAdminDict, 10320, "字典枚举创建失败")
 	DictItemUpdateError    = NewCode(CategoryAdminDict, 10321, "字典枚举更新失败")
 	DictItemDeleteError    = NewCode(CategoryAdminDict, 10322, "字典枚举删除失败")
+
+	// PartnerNotFound 开放接口合作方相关 103 51 - 103 69
+	PartnerNotFound    = NewCode(CategoryAdminPartner, 10351, "合作方不存在")
+	PartnerCreateError = NewCode(CategoryAdminPartner, 10352, "合作方创建失败")
+	PartnerUpdateError = NewCode(CategoryAdminPartner, 10353, "合作方更新失败")
+	PartnerDeleteError = NewCode(CategoryAdminPartner, 10354, "合作方删除失败")
+	PartnerListError   = NewCode(CategoryAdminPartner, 10355, "合作方列表获取失败")
+	PartnerNameExist   = NewCode(CategoryAdminPartner, 10356, "合作方名称已存在")
+	PartnerStatusError = NewCode(CategoryAdminPartner, 10357, "合作方状态无效")
+	PartnerRotateError = NewCode(CategoryAdminPartner, 10358, "更换签名密钥失败")
 )
 
 // Code 错误码接口，错误码一旦创建即为不可变常量
@@ -55,6 +55,9 @@ func TestErrorCodes(t *testing.T) {
 		{xerror.HttpBadRequest, 400, "Bad Request"},
 		{xerror.TokenNotFound, 10101, "token不能为空"},
 		{xerror.UserNotFound, 10201, "用户不存在"},
+		{xerror.DeptNotFound, 10270, "部门不存在"},
+		{xerror.PwdReusedError, 10281, "新密码不能与最近使用过的密码相同"},
+		{xerror.PwdOldError, 10284, "原密码错误"},
 	}
 
 	for _, test := range tests {
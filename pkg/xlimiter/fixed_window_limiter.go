@@ -58,7 +58,7 @@ end
 return {allowed, cnt, t}
 `
 
-// Add 尝试增加一次计数（原子）
+// Add 尝试增加一次计数（原子），计数约定见 WindowLimiter
 // 返回：allowed 是否允许继续操作，count 当前计数（在做 incr 后的值），ttl 剩余窗口时间（time.Duration），err 错误
 func (f *FixedWindowLimiter) Add(ctx context.Context) (allowed bool, count int64, ttl time.Duration, err error) {
 	res, err := f.cache.Eval(ctx, fixedWindowScript, []string{f.key}, strconv.FormatInt(f.maxFails, 10), strconv.FormatInt(f.windowSec, 10))
@@ -0,0 +1,81 @@
+package xlimiter
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Result 一次限流判定结果，用于输出 X-RateLimit-* 与 Retry-After 响应头
+type Result struct {
+	Allowed    bool
+	Limit      int64         // 桶容量，即允许的最大突发请求数
+	Remaining  int64         // 本次判定后剩余可用次数
+	ResetAfter time.Duration // 桶恢复满所需时间
+	RetryAfter time.Duration // 被拒绝时距离下次可用的等待时间，放行时为 0
+	Degraded   bool          // 是否因 Redis 不可用降级为本地限流
+}
+
+// Limiter 按 key 限流，key 可以是 IP、用户 ID、路由等
+type Limiter interface {
+	Take(ctx context.Context, key string) (*Result, error)
+}
+
+// WindowLimiter 窗口计数限流（固定窗口、滑动窗口日志、滑动窗口计数）共同的约定：
+// 每次 Add 都计入窗口，包括被拒绝的调用；count 为计入本次后的窗口计数，超过上限即被拒绝，
+// 因此第一次被拒绝时 count == max+1，之后持续调用会继续增长，调用方可据此只在首次超限时触发锁定等动作
+type WindowLimiter interface {
+	Add(ctx context.Context) (allowed bool, count int64, ttl time.Duration, err error)
+	Reset(ctx context.Context) error
+}
+
+var (
+	_ WindowLimiter = (*FixedWindowLimiter)(nil)
+	_ WindowLimiter = (*SlidingWindowLogLimiter)(nil)
+	_ WindowLimiter = (*SlidingWindowCounterLimiter)(nil)
+)
+
+// LocalLimiter 基于进程内令牌桶的 Limiter，多副本部署时每个副本单独计数
+type LocalLimiter struct {
+	prefix string
+	limit  BucketLimit
+	burst  int
+}
+
+// NewLocalLimiter 创建本地限流器，prefix 用于区分不同业务的限流 key
+func NewLocalLimiter(prefix string, limit BucketLimit, burst int) *LocalLimiter {
+	if burst <= 0 {
+		panic("xlimiter: burst must be positive")
+	}
+	return &LocalLimiter{prefix: prefix, limit: limit, burst: burst}
+}
+
+func (l *LocalLimiter) Take(_ context.Context, key string) (*Result, error) {
+	bl, _ := NewTokenBucket(l.prefix+key, l.limit, l.burst)
+	return bl.take(time.Now()), nil
+}
+
+// take 非阻塞获取一个令牌，获取失败时取消预留并返回需要等待的时间
+func (bl *BucketLimiter) take(now time.Time) *Result {
+	res := &Result{Limit: int64(bl.limiter.Burst())}
+	r := bl.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return res
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		res.RetryAfter = delay
+	} else {
+		res.Allowed = true
+		bl.lastGetTime.Store(now.UnixNano())
+	}
+	tokens := bl.limiter.TokensAt(now)
+	if tokens > 0 {
+		res.Remaining = int64(math.Floor(tokens))
+	}
+	if limit := bl.limiter.Limit(); limit > 0 {
+		missing := float64(res.Limit) - tokens
+		res.ResetAfter = time.Duration(missing / float64(limit) * float64(time.Second))
+	}
+	return res
+}
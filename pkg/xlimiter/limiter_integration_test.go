@@ -160,3 +160,196 @@ func TestFixedWindowLimiter_Defaults(t *testing.T) {
 		}
 	})
 }
+
+func TestRedisLimiter_Integration_Take(t *testing.T) {
+	client := setupTestRedis(t)
+	cache, _ := xcache.NewRedisCache(client)
+	ctx := context.Background()
+
+	prefix := "test:gcra:"
+	_ = client.Del(ctx, prefix+"k").Err()
+	t.Cleanup(func() { _ = client.Del(ctx, prefix+"k").Err() })
+
+	// 每秒 2 个，突发 3 个
+	limiter, err := NewRedisLimiter(cache, prefix, 2, 3)
+	if err != nil {
+		t.Fatalf("NewRedisLimiter failed: %v", err)
+	}
+
+	t.Run("burst", func(t *testing.T) {
+		for i := int64(1); i <= 3; i++ {
+			res, err := limiter.Take(ctx, "k")
+			if err != nil || !res.Allowed || res.Degraded {
+				t.Fatalf("attempt %d should be allowed, got %+v %v", i, res, err)
+			}
+			if res.Remaining != 3-i {
+				t.Fatalf("attempt %d remaining mismatch: %+v", i, res)
+			}
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		res, err := limiter.Take(ctx, "k")
+		if err != nil || res.Allowed {
+			t.Fatalf("should be rejected after burst, got %+v %v", res, err)
+		}
+		if res.RetryAfter <= 0 || res.RetryAfter > 500*time.Millisecond {
+			t.Fatalf("retry after out of range: %v", res.RetryAfter)
+		}
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		time.Sleep(600 * time.Millisecond)
+		res, err := limiter.Take(ctx, "k")
+		if err != nil || !res.Allowed {
+			t.Fatalf("should be allowed after refill, got %+v %v", res, err)
+		}
+	})
+
+	t.Run("key expires when bucket full", func(t *testing.T) {
+		ttl, err := client.PTTL(ctx, prefix+"k").Result()
+		if err != nil {
+			t.Fatalf("PTTL failed: %v", err)
+		}
+		if ttl <= 0 || ttl > 1500*time.Millisecond {
+			t.Fatalf("ttl out of range: %v", ttl)
+		}
+	})
+}
+
+func TestSlidingWindowLogLimiter_Integration_Add(t *testing.T) {
+	client := setupTestRedis(t)
+	cache, _ := xcache.NewRedisCache(client)
+	ctx := context.Background()
+
+	limiter, err := NewSlidingWindowLogLimiter(cache, "test:swl:add", 2, 3)
+	if err != nil {
+		t.Fatalf("NewSlidingWindowLogLimiter failed: %v", err)
+	}
+	_ = limiter.Reset(ctx)
+	t.Cleanup(func() { _ = limiter.Reset(ctx) })
+
+	for i := int64(1); i <= 3; i++ {
+		allowed, count, ttl, err := limiter.Add(ctx)
+		if err != nil || !allowed || count != i {
+			t.Fatalf("attempt %d: allowed=%v count=%d err=%v", i, allowed, count, err)
+		}
+		if ttl <= 0 || ttl > 2*time.Second {
+			t.Fatalf("TTL out of range: %v", ttl)
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	// 被拒绝的请求也计入窗口，与固定窗口一致
+	allowed, count, ttl, err := limiter.Add(ctx)
+	if err != nil || allowed || count != 4 {
+		t.Fatalf("expected blocked with count=4, got allowed=%v count=%d err=%v", allowed, count, err)
+	}
+
+	// 最早一条记录移出窗口后，被拒绝的请求仍占用名额
+	time.Sleep(ttl + 50*time.Millisecond)
+	if allowed, count, _, _ := limiter.Add(ctx); allowed || count != 4 {
+		t.Fatalf("expected blocked: rejected attempts stay in the window, got allowed=%v count=%d", allowed, count)
+	}
+
+	// 停止调用一个完整窗口后恢复
+	time.Sleep(2*time.Second + 50*time.Millisecond)
+	if allowed, count, _, _ := limiter.Add(ctx); !allowed || count != 1 {
+		t.Fatalf("expected allowed after a quiet window, got allowed=%v count=%d", allowed, count)
+	}
+}
+
+func TestSlidingWindowCounterLimiter_Integration_Add(t *testing.T) {
+	client := setupTestRedis(t)
+	cache, _ := xcache.NewRedisCache(client)
+	ctx := context.Background()
+
+	limiter, err := NewSlidingWindowCounterLimiter(cache, "test:swc:add", 2, 4)
+	if err != nil {
+		t.Fatalf("NewSlidingWindowCounterLimiter failed: %v", err)
+	}
+	_ = limiter.Reset(ctx)
+	t.Cleanup(func() { _ = limiter.Reset(ctx) })
+
+	for i := int64(1); i <= 4; i++ {
+		allowed, _, _, err := limiter.Add(ctx)
+		if err != nil || !allowed {
+			t.Fatalf("attempt %d should be allowed, err=%v", i, err)
+		}
+	}
+	allowed, count, ttl, err := limiter.Add(ctx)
+	if err != nil || allowed || count != 5 {
+		t.Fatalf("expected blocked with count=5, got allowed=%v count=%d err=%v", allowed, count, err)
+	}
+	if ttl <= 0 || ttl > 4*time.Second {
+		t.Fatalf("TTL out of range: %v", ttl)
+	}
+
+	// 进入下一窗口后，上一窗口的计数按比例计入，不会立即放开全部额度
+	time.Sleep(ttl + 50*time.Millisecond)
+	allowed, _, _, err = limiter.Add(ctx)
+	if err != nil || !allowed {
+		t.Fatalf("expected allowed after ttl, err=%v", err)
+	}
+	if allowed, _, _, _ := limiter.Add(ctx); allowed {
+		t.Fatal("expected blocked: previous window still weighs in")
+	}
+
+	if err := limiter.Reset(ctx); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if allowed, count, _, _ := limiter.Add(ctx); !allowed || count != 1 {
+		t.Fatalf("expected count=1 after reset, got allowed=%v count=%d", allowed, count)
+	}
+}
+
+// TestWindowLimiter_Integration_Contract 三种窗口限流的 Add 计数约定一致（见 WindowLimiter）：
+// 被拒绝的调用也计数，第一次被拒绝时 count == max+1，之后继续增长
+func TestWindowLimiter_Integration_Contract(t *testing.T) {
+	client := setupTestRedis(t)
+	cache, _ := xcache.NewRedisCache(client)
+	ctx := context.Background()
+
+	const maxCount = 3
+	constructors := map[string]func(key string) (WindowLimiter, error){
+		"fixed": func(key string) (WindowLimiter, error) {
+			return NewFixedWindowLimiter(cache, key, 60, maxCount)
+		},
+		"sliding log": func(key string) (WindowLimiter, error) {
+			return NewSlidingWindowLogLimiter(cache, key, 60, maxCount)
+		},
+		"sliding counter": func(key string) (WindowLimiter, error) {
+			return NewSlidingWindowCounterLimiter(cache, key, 60, maxCount)
+		},
+	}
+	for name, newLimiter := range constructors {
+		t.Run(name, func(t *testing.T) {
+			limiter, err := newLimiter("test:window:contract:" + name)
+			if err != nil {
+				t.Fatalf("new limiter failed: %v", err)
+			}
+			_ = limiter.Reset(ctx)
+			t.Cleanup(func() { _ = limiter.Reset(ctx) })
+
+			for i := int64(1); i <= maxCount+2; i++ {
+				allowed, count, ttl, err := limiter.Add(ctx)
+				if err != nil {
+					t.Fatalf("attempt %d: Add failed: %v", i, err)
+				}
+				if allowed != (i <= maxCount) || count != i {
+					t.Fatalf("attempt %d: got allowed=%v count=%d, want allowed=%v count=%d", i, allowed, count, i <= maxCount, i)
+				}
+				if ttl <= 0 {
+					t.Fatalf("attempt %d: expected positive ttl, got %v", i, ttl)
+				}
+			}
+
+			if err := limiter.Reset(ctx); err != nil {
+				t.Fatalf("Reset failed: %v", err)
+			}
+			if allowed, count, _, _ := limiter.Add(ctx); !allowed || count != 1 {
+				t.Fatalf("expected count=1 after reset, got allowed=%v count=%d", allowed, count)
+			}
+		})
+	}
+}
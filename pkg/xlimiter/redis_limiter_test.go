@@ -0,0 +1,143 @@
+package xlimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// ============================================================
+// NewRedisLimiter
+// ============================================================
+
+func TestRedisLimiter_New(t *testing.T) {
+	cache := newMockCache()
+
+	t.Run("happy: emission interval from limit", func(t *testing.T) {
+		l, err := NewRedisLimiter(cache, "rate:", 10, 5)
+		if err != nil {
+			t.Fatalf("NewRedisLimiter failed: %v", err)
+		}
+		if l.emission != 100000 || l.burst != 5 {
+			t.Fatalf("unexpected emission=%d burst=%d", l.emission, l.burst)
+		}
+	})
+
+	t.Run("error: invalid parameters", func(t *testing.T) {
+		if _, err := NewRedisLimiter(nil, "rate:", 10, 5); err == nil {
+			t.Fatal("expected nil cache rejected")
+		}
+		if _, err := NewRedisLimiter(cache, "rate:", 0, 5); err == nil {
+			t.Fatal("expected zero limit rejected")
+		}
+		if _, err := NewRedisLimiter(cache, "rate:", 10, 0); err == nil {
+			t.Fatal("expected zero burst rejected")
+		}
+	})
+}
+
+// ============================================================
+// RedisLimiter.Take
+// ============================================================
+
+func TestRedisLimiter_Take(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("happy: parses eval result", func(t *testing.T) {
+		cache := newMockCache()
+		var gotKey string
+		var gotArgs []any
+		cache.evalFn = func(_ context.Context, _ string, keys []string, args ...any) (any, error) {
+			gotKey, gotArgs = keys[0], args
+			return []any{int64(1), int64(4), int64(0), "500000"}, nil
+		}
+		l, _ := NewRedisLimiter(cache, "rate:", 10, 5)
+		res, err := l.Take(ctx, "1.2.3.4")
+		if err != nil {
+			t.Fatalf("Take failed: %v", err)
+		}
+		if gotKey != "rate:1.2.3.4" || gotArgs[0] != "100000" || gotArgs[1] != "5" {
+			t.Fatalf("unexpected eval call key=%s args=%v", gotKey, gotArgs)
+		}
+		if !res.Allowed || res.Limit != 5 || res.Remaining != 4 || res.ResetAfter != 500*time.Millisecond || res.Degraded {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("happy: rejected with retry after", func(t *testing.T) {
+		cache := newMockCache()
+		cache.evalFn = func(context.Context, string, []string, ...any) (any, error) {
+			return []any{int64(0), int64(0), int64(80000), int64(480000)}, nil
+		}
+		l, _ := NewRedisLimiter(cache, "rate:", 10, 5)
+		res, _ := l.Take(ctx, "k")
+		if res.Allowed || res.RetryAfter != 80*time.Millisecond {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("error: falls back to local limiter", func(t *testing.T) {
+		cache := newMockCache()
+		cache.evalFn = func(context.Context, string, []string, ...any) (any, error) {
+			return nil, errors.New("connection refused")
+		}
+		l, _ := NewRedisLimiter(cache, "test:redis:fallback:", 0.001, 2)
+		for i := 0; i < 2; i++ {
+			res, err := l.Take(ctx, "k")
+			if err != nil || !res.Allowed || !res.Degraded {
+				t.Fatalf("attempt %d: expected degraded allow, got %+v %v", i+1, res, err)
+			}
+		}
+		res, _ := l.Take(ctx, "k")
+		if res.Allowed || res.RetryAfter <= 0 {
+			t.Fatalf("expected local limiter to reject after burst, got %+v", res)
+		}
+	})
+
+	t.Run("error: unexpected result type falls back", func(t *testing.T) {
+		cache := newMockCache()
+		cache.evalFn = func(context.Context, string, []string, ...any) (any, error) {
+			return "oops", nil
+		}
+		l, _ := NewRedisLimiter(cache, "test:redis:badtype:", 10, 2)
+		res, err := l.Take(ctx, "k")
+		if err != nil || !res.Degraded {
+			t.Fatalf("expected degraded result, got %+v %v", res, err)
+		}
+	})
+}
+
+// ============================================================
+// LocalLimiter
+// ============================================================
+
+func TestLocalLimiter_Take(t *testing.T) {
+	ctx := context.Background()
+	l := NewLocalLimiter("test:local:", 1, 3)
+
+	for i := int64(1); i <= 3; i++ {
+		res, err := l.Take(ctx, "k")
+		if err != nil || !res.Allowed {
+			t.Fatalf("attempt %d should be allowed, got %+v %v", i, res, err)
+		}
+		if res.Limit != 3 || res.Remaining != 3-i {
+			t.Fatalf("attempt %d unexpected counters: %+v", i, res)
+		}
+	}
+	res, _ := l.Take(ctx, "k")
+	if res.Allowed || res.Remaining != 0 {
+		t.Fatalf("expected rejected after burst, got %+v", res)
+	}
+	if res.RetryAfter <= 0 || res.RetryAfter > time.Second {
+		t.Fatalf("retry after out of range: %v", res.RetryAfter)
+	}
+	if res.ResetAfter <= 2*time.Second || res.ResetAfter > 3*time.Second {
+		t.Fatalf("reset after out of range: %v", res.ResetAfter)
+	}
+
+	// 不同 key 独立计数
+	if res, _ := l.Take(ctx, "other"); !res.Allowed {
+		t.Fatal("different key should have its own bucket")
+	}
+}
@@ -0,0 +1,170 @@
+package xlimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	common "snowgo/pkg"
+	"snowgo/pkg/xcache"
+)
+
+// slidingWindow 滑动窗口限流的公共参数
+type slidingWindow struct {
+	cache    xcache.Cache
+	windowMs int64  // 窗口长度，毫秒
+	maxCount int64  // 窗口内最大允许次数
+	key      string // 完整的 redis key（例如 "sms:send:13800000000"）
+}
+
+func newSlidingWindow(cache xcache.Cache, key string, windowSecond int64, maxCount int64) (slidingWindow, error) {
+	if cache == nil {
+		return slidingWindow{}, errors.New("cache cannot be nil")
+	}
+	if windowSecond <= 0 {
+		windowSecond = 60 // 默认 60s，避免 0 导致问题
+	}
+	if maxCount <= 0 {
+		maxCount = 1
+	}
+	return slidingWindow{
+		cache:    cache,
+		windowMs: windowSecond * 1000,
+		maxCount: maxCount,
+		key:      key,
+	}, nil
+}
+
+// eval 执行脚本并解析 {allowed, count, ttl(毫秒)}
+func (s *slidingWindow) eval(ctx context.Context, script string, args ...any) (allowed bool, count int64, ttl time.Duration, err error) {
+	args = append([]any{strconv.FormatInt(s.maxCount, 10), strconv.FormatInt(s.windowMs, 10)}, args...)
+	res, err := s.cache.Eval(ctx, script, []string{s.key}, args...)
+	if err != nil {
+		return false, 0, 0, err
+	}
+	arr, ok := res.([]any)
+	if !ok || len(arr) < 3 {
+		return false, 0, 0, fmt.Errorf("unexpected redis eval result type: %T", res)
+	}
+	allowedFlag, err := parseRedisInt(arr[0])
+	if err != nil {
+		return false, 0, 0, err
+	}
+	cnt, err := parseRedisInt(arr[1])
+	if err != nil {
+		return false, 0, 0, err
+	}
+	ttlMs, err := parseRedisInt(arr[2])
+	if err != nil {
+		return false, 0, 0, err
+	}
+	if ttlMs < 0 {
+		ttlMs = 0
+	}
+	return allowedFlag == 1, cnt, time.Duration(ttlMs) * time.Millisecond, nil
+}
+
+// Reset 删除计数，重置
+func (s *slidingWindow) Reset(ctx context.Context) error {
+	_, err := s.cache.Delete(ctx, s.key)
+	return err
+}
+
+// SlidingWindowLogLimiter 滑动窗口日志限流，每次请求的时间戳记录在有序集合中，计数精确，
+// 内存占用与窗口内请求数成正比，适用于短信配额、登录节流等次数较少但要求精确的场景
+type SlidingWindowLogLimiter struct {
+	slidingWindow
+}
+
+// NewSlidingWindowLogLimiter 创建滑动窗口日志限流，参数含义与 NewFixedWindowLimiter 一致
+func NewSlidingWindowLogLimiter(cache xcache.Cache, key string, windowSecond int64, maxCount int64) (*SlidingWindowLogLimiter, error) {
+	sw, err := newSlidingWindow(cache, key, windowSecond, maxCount)
+	if err != nil {
+		return nil, err
+	}
+	return &SlidingWindowLogLimiter{slidingWindow: sw}, nil
+}
+
+// slidingLogScript 清理窗口外的记录后记录本次请求并计数，与固定窗口一致被拒绝的请求也计入，持续超限调用会一直被拒绝
+// ttl 为最早一条记录移出窗口的剩余时间，即计数下一次减少的时间
+const slidingLogScript = `
+local key = KEYS[1]
+local maxCount = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local member = ARGV[3]
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+redis.call("ZADD", key, now, member)
+redis.call("PEXPIRE", key, window)
+local cnt = redis.call("ZCARD", key)
+local allowed = 0
+if cnt <= maxCount then
+    allowed = 1
+end
+local ttl = 0
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+if oldest[2] then
+    ttl = tonumber(oldest[2]) + window - now
+end
+return {allowed, cnt, ttl}
+`
+
+// Add 尝试增加一次计数（原子），计数约定见 WindowLimiter
+// 返回：allowed 是否允许继续操作，count 计入本次后的窗口内计数，ttl 距离计数下一次减少的时间，err 错误
+func (l *SlidingWindowLogLimiter) Add(ctx context.Context) (allowed bool, count int64, ttl time.Duration, err error) {
+	// member 需唯一，避免同一毫秒内的请求相互覆盖
+	return l.eval(ctx, slidingLogScript, common.GenerateID())
+}
+
+// SlidingWindowCounterLimiter 滑动窗口计数限流，按上一窗口计数的剩余权重加上当前窗口计数估算，
+// 只保存两个计数，开销与固定窗口相当，同时消除窗口边界处的 2 倍突发；计数为近似值
+type SlidingWindowCounterLimiter struct {
+	slidingWindow
+}
+
+// NewSlidingWindowCounterLimiter 创建滑动窗口计数限流，参数含义与 NewFixedWindowLimiter 一致
+func NewSlidingWindowCounterLimiter(cache xcache.Cache, key string, windowSecond int64, maxCount int64) (*SlidingWindowCounterLimiter, error) {
+	sw, err := newSlidingWindow(cache, key, windowSecond, maxCount)
+	if err != nil {
+		return nil, err
+	}
+	return &SlidingWindowCounterLimiter{slidingWindow: sw}, nil
+}
+
+// slidingCounterScript 两个窗口的计数保存在同一个 hash 中（field 为窗口序号），单 key 便于集群部署
+// 估算值 = floor(上一窗口计数 * 上一窗口在滑动窗口内的占比) + 当前窗口计数；与固定窗口一致被拒绝的请求也计数
+// ttl：放行时为当前窗口剩余时间，拒绝时为下一次调用可被放行所需时间
+const slidingCounterScript = `
+local key = KEYS[1]
+local maxCount = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local idx = math.floor(now / window)
+local elapsed = now - idx * window
+local cur = redis.call("HINCRBY", key, tostring(idx), 1)
+redis.call("HDEL", key, tostring(idx - 2))
+redis.call("PEXPIRE", key, window * 2)
+local prev = tonumber(redis.call("HGET", key, tostring(idx - 1)) or "0")
+local estimated = math.floor(prev * (window - elapsed) / window) + cur
+if estimated > maxCount then
+    local ttl = window - elapsed
+    if cur < maxCount and prev > 0 then
+        ttl = math.floor(window - window * (maxCount - cur) / prev) - elapsed + 1
+    elseif cur >= maxCount then
+        -- 当前窗口已超限，需等到下一窗口中本窗口计数的权重回落
+        ttl = window - elapsed + math.floor(window - window * maxCount / cur) + 1
+    end
+    return {0, estimated, ttl}
+end
+return {1, estimated, window - elapsed}
+`
+
+// Add 尝试增加一次计数（原子），计数约定见 WindowLimiter
+// 返回：allowed 是否允许继续操作，count 计入本次后估算的窗口内计数，ttl 见 slidingCounterScript，err 错误
+func (l *SlidingWindowCounterLimiter) Add(ctx context.Context) (allowed bool, count int64, ttl time.Duration, err error) {
+	return l.eval(ctx, slidingCounterScript)
+}
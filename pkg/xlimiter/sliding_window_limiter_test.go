@@ -0,0 +1,132 @@
+package xlimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var slidingConstructors = map[string]func(cache *mockCache, key string, windowSecond, maxCount int64) (WindowLimiter, error){
+	"log": func(cache *mockCache, key string, windowSecond, maxCount int64) (WindowLimiter, error) {
+		return NewSlidingWindowLogLimiter(cache, key, windowSecond, maxCount)
+	},
+	"counter": func(cache *mockCache, key string, windowSecond, maxCount int64) (WindowLimiter, error) {
+		return NewSlidingWindowCounterLimiter(cache, key, windowSecond, maxCount)
+	},
+}
+
+// ============================================================
+// NewSlidingWindow*Limiter
+// ============================================================
+
+func TestSlidingWindowLimiter_New(t *testing.T) {
+	t.Run("boundary: non-positive values use defaults", func(t *testing.T) {
+		l, err := NewSlidingWindowLogLimiter(newMockCache(), "test:swl", 0, -1)
+		if err != nil {
+			t.Fatalf("NewSlidingWindowLogLimiter failed: %v", err)
+		}
+		if l.windowMs != 60000 || l.maxCount != 1 {
+			t.Fatalf("expected defaults window=60000ms max=1, got %d %d", l.windowMs, l.maxCount)
+		}
+		c, _ := NewSlidingWindowCounterLimiter(newMockCache(), "test:swc", 5, 10)
+		if c.windowMs != 5000 || c.maxCount != 10 {
+			t.Fatalf("unexpected window=%d max=%d", c.windowMs, c.maxCount)
+		}
+	})
+
+	t.Run("error: nil cache returns error", func(t *testing.T) {
+		if _, err := NewSlidingWindowLogLimiter(nil, "k", 60, 5); err == nil {
+			t.Fatal("expected error for nil cache")
+		}
+		if _, err := NewSlidingWindowCounterLimiter(nil, "k", 60, 5); err == nil {
+			t.Fatal("expected error for nil cache")
+		}
+	})
+}
+
+// ============================================================
+// Add / Reset
+// ============================================================
+
+func TestSlidingWindowLimiter_Add(t *testing.T) {
+	for name, newLimiter := range slidingConstructors {
+		t.Run(name+": happy: passes arguments and parses result", func(t *testing.T) {
+			cache := newMockCache()
+			var gotKey string
+			var gotArgs []any
+			cache.evalFn = func(_ context.Context, _ string, keys []string, args ...any) (any, error) {
+				gotKey, gotArgs = keys[0], args
+				return []any{int64(1), "2", int64(1500)}, nil
+			}
+			l, _ := newLimiter(cache, "test:sw:"+name, 3, 5)
+			allowed, count, ttl, err := l.Add(context.Background())
+			if err != nil {
+				t.Fatalf("Add error: %v", err)
+			}
+			if !allowed || count != 2 || ttl != 1500*time.Millisecond {
+				t.Fatalf("unexpected result allowed=%v count=%d ttl=%v", allowed, count, ttl)
+			}
+			if gotKey != "test:sw:"+name || gotArgs[0] != "5" || gotArgs[1] != "3000" {
+				t.Fatalf("unexpected eval call key=%s args=%v", gotKey, gotArgs)
+			}
+		})
+
+		t.Run(name+": boundary: negative ttl normalized", func(t *testing.T) {
+			cache := newMockCache()
+			cache.evalFn = func(context.Context, string, []string, ...any) (any, error) {
+				return []any{int64(0), int64(5), int64(-1)}, nil
+			}
+			l, _ := newLimiter(cache, "test:sw:neg", 60, 5)
+			allowed, _, ttl, err := l.Add(context.Background())
+			if err != nil || allowed || ttl != 0 {
+				t.Fatalf("expected blocked with ttl=0, got allowed=%v ttl=%v err=%v", allowed, ttl, err)
+			}
+		})
+
+		t.Run(name+": error: eval failure and bad result", func(t *testing.T) {
+			cache := newMockCache()
+			cache.evalFn = func(context.Context, string, []string, ...any) (any, error) {
+				return nil, errors.New("redis down")
+			}
+			l, _ := newLimiter(cache, "test:sw:err", 60, 5)
+			if allowed, _, _, err := l.Add(context.Background()); err == nil || allowed {
+				t.Fatal("expected eval error returned and not allowed")
+			}
+			cache.evalFn = func(context.Context, string, []string, ...any) (any, error) {
+				return []any{int64(1)}, nil
+			}
+			if _, _, _, err := l.Add(context.Background()); err == nil {
+				t.Fatal("expected short result rejected")
+			}
+		})
+
+		t.Run(name+": happy: reset deletes key", func(t *testing.T) {
+			cache := newMockCache()
+			cache.data["test:sw:reset"] = "x"
+			l, _ := newLimiter(cache, "test:sw:reset", 60, 5)
+			if err := l.Reset(context.Background()); err != nil {
+				t.Fatalf("Reset error: %v", err)
+			}
+			if _, ok := cache.data["test:sw:reset"]; ok {
+				t.Fatal("expected key deleted")
+			}
+		})
+	}
+
+	t.Run("log: member unique per request", func(t *testing.T) {
+		cache := newMockCache()
+		members := map[any]bool{}
+		cache.evalFn = func(_ context.Context, _ string, _ []string, args ...any) (any, error) {
+			members[args[2]] = true
+			return []any{int64(1), int64(1), int64(0)}, nil
+		}
+		l, _ := NewSlidingWindowLogLimiter(cache, "test:swl:member", 60, 100)
+		for i := 0; i < 50; i++ {
+			_, _, _, _ = l.Add(context.Background())
+		}
+		if len(members) != 50 {
+			t.Fatalf("expected 50 unique members, got %d", len(members))
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package xmetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPMetrics HTTP 请求数与耗时
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewHTTPMetrics 创建并注册 HTTP 指标
+func NewHTTPMetrics(reg prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP 请求总数",
+		}, []string{"method", "path", "status", "biz_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP 请求耗时",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"method", "path", "status"}),
+	}
+	reg.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// Observe 记录一次请求，path 使用路由模板避免标签基数膨胀
+func (m *HTTPMetrics) Observe(method, path, status, bizCode string, duration time.Duration) {
+	m.requests.WithLabelValues(method, path, status, bizCode).Inc()
+	m.duration.WithLabelValues(method, path, status).Observe(duration.Seconds())
+}
+
+var defaultHTTP = sync.OnceValue(func() *HTTPMetrics {
+	return NewHTTPMetrics(registry)
+})
+
+// HTTP 返回注册在默认 Registry 上的 HTTP 指标
+func HTTP() *HTTPMetrics {
+	return defaultHTTP()
+}
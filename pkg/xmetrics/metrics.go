@@ -0,0 +1,70 @@
+// Package xmetrics Prometheus 指标，统一注册到独立的 Registry 并通过 Handler 暴露
+package xmetrics
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "snowgo"
+
+// registry 不使用 prometheus 默认的全局 Registry，避免三方库注册的指标混入
+var registry = prometheus.NewRegistry()
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Registry 返回指标注册表
+func Registry() *prometheus.Registry {
+	return registry
+}
+
+// Register 注册采集器，重复注册（如 HTTP 服务重启后重新初始化路由）时忽略
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if errors.As(err, &are) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler 指标抓取接口
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// DefaultPath 默认抓取路径
+const DefaultPath = "/metrics"
+
+// PathOrDefault 未配置抓取路径时使用 DefaultPath
+func PathOrDefault(path string) string {
+	if path == "" {
+		return DefaultPath
+	}
+	return path
+}
+
+// NewServer 创建独立监听的指标服务，用于与业务端口隔离
+func NewServer(addr, path string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(PathOrDefault(path), Handler())
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
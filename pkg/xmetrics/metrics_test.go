@@ -0,0 +1,114 @@
+package xmetrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"snowgo/pkg/xmq"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewHTTPMetrics(reg)
+	m.Observe("GET", "/api/user/:id", "200", "0", 20*time.Millisecond)
+	m.Observe("GET", "/api/user/:id", "200", "0", 30*time.Millisecond)
+	m.Observe("GET", "/api/user/:id", "200", "10001", time.Millisecond)
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/api/user/:id", "200", "0")); got != 2 {
+		t.Fatalf("expected 2 requests, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.duration); got != 1 {
+		t.Fatalf("expected single histogram series without biz_code label, got %d", got)
+	}
+}
+
+func TestMQMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	var m xmq.Metrics = NewMQMetrics(reg)
+	m.PublishResult("ex", xmq.PublishConfirmed)
+	m.PublishResult("ex", xmq.PublishNack)
+	m.HandlerDone("q", time.Millisecond, errors.New("boom"))
+	m.ConsumeRetry("q")
+	m.HandlerDone("q", time.Millisecond, nil)
+	m.ConsumeDone("q", xmq.ConsumeAck)
+
+	mq := m.(*MQMetrics)
+	if got := testutil.ToFloat64(mq.published.WithLabelValues("ex", xmq.PublishNack)); got != 1 {
+		t.Fatalf("expected 1 nack, got %v", got)
+	}
+	if got := testutil.ToFloat64(mq.retries.WithLabelValues("q")); got != 1 {
+		t.Fatalf("expected 1 retry, got %v", got)
+	}
+	if got := testutil.ToFloat64(mq.consumed.WithLabelValues("q", xmq.ConsumeAck)); got != 1 {
+		t.Fatalf("expected 1 ack, got %v", got)
+	}
+	if got := testutil.CollectAndCount(mq.handled); got != 2 {
+		t.Fatalf("expected success and fail handler series, got %d", got)
+	}
+}
+
+type fakeRedis struct{}
+
+func (fakeRedis) PoolStats() *redis.PoolStats {
+	return &redis.PoolStats{Hits: 5, Misses: 1, TotalConns: 3, IdleConns: 2}
+}
+
+type fakeProducer struct{}
+
+func (fakeProducer) Stats() xmq.ProducerStats {
+	return xmq.ProducerStats{Inflight: 4, PoolSize: 8, IdleChannels: 6}
+}
+
+func TestPoolCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewRedisCollector("default", fakeRedis{}), NewProducerCollector(fakeProducer{}))
+
+	expected := `
+# HELP snowgo_mq_producer_inflight 正在等待 broker confirm 的消息数
+# TYPE snowgo_mq_producer_inflight gauge
+snowgo_mq_producer_inflight 4
+# HELP snowgo_redis_pool_idle_conns 连接池中的空闲连接数
+# TYPE snowgo_redis_pool_idle_conns gauge
+snowgo_redis_pool_idle_conns{client="default"} 2
+# HELP snowgo_redis_pool_hits_total 从连接池获取到空闲连接的次数
+# TYPE snowgo_redis_pool_hits_total counter
+snowgo_redis_pool_hits_total{client="default"} 5
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"snowgo_mq_producer_inflight", "snowgo_redis_pool_idle_conns", "snowgo_redis_pool_hits_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterIgnoresDuplicate(t *testing.T) {
+	c := NewProducerCollector(fakeProducer{})
+	if err := Register(c); err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	t.Cleanup(func() { registry.Unregister(c) })
+	if err := Register(NewProducerCollector(fakeProducer{})); err != nil {
+		t.Fatalf("expected duplicate registration ignored, got %v", err)
+	}
+}
+
+func TestServer(t *testing.T) {
+	HTTP().Observe("GET", "/healthz", "200", "0", time.Millisecond)
+	srv := NewServer("127.0.0.1:0", "")
+	w := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DefaultPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	for _, name := range []string{"snowgo_http_requests_total", "go_goroutines", "process_"} {
+		if !strings.Contains(w.Body.String(), name) {
+			t.Fatalf("expected %s in output", name)
+		}
+	}
+}
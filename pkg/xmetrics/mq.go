@@ -0,0 +1,117 @@
+package xmetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"snowgo/pkg/xmq"
+)
+
+// MQMetrics 实现 xmq.Metrics，记录发布结果与消费情况
+type MQMetrics struct {
+	published *prometheus.CounterVec
+	handled   *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+	consumed  *prometheus.CounterVec
+}
+
+var _ xmq.Metrics = (*MQMetrics)(nil)
+
+// NewMQMetrics 创建并注册 MQ 指标
+func NewMQMetrics(reg prometheus.Registerer) *MQMetrics {
+	m := &MQMetrics{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "mq",
+			Name:      "publish_total",
+			Help:      "消息发布次数，result: confirmed/nack/timeout/error",
+		}, []string{"exchange", "result"}),
+		handled: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "mq",
+			Name:      "handler_duration_seconds",
+			Help:      "消费 handler 单次执行耗时，每次重试单独记录",
+			Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"queue", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "mq",
+			Name:      "consume_retries_total",
+			Help:      "消费进程内重试次数",
+		}, []string{"queue"}),
+		consumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "mq",
+			Name:      "consume_total",
+			Help:      "消息最终消费结果，result: ack/nack",
+		}, []string{"queue", "result"}),
+	}
+	reg.MustRegister(m.published, m.handled, m.retries, m.consumed)
+	return m
+}
+
+func (m *MQMetrics) PublishResult(exchange, result string) {
+	m.published.WithLabelValues(exchange, result).Inc()
+}
+
+func (m *MQMetrics) HandlerDone(queue string, duration time.Duration, err error) {
+	status := "success"
+	if err != nil {
+		status = "fail"
+	}
+	m.handled.WithLabelValues(queue, status).Observe(duration.Seconds())
+}
+
+func (m *MQMetrics) ConsumeRetry(queue string) {
+	m.retries.WithLabelValues(queue).Inc()
+}
+
+func (m *MQMetrics) ConsumeDone(queue, result string) {
+	m.consumed.WithLabelValues(queue, result).Inc()
+}
+
+var defaultMQ = sync.OnceValue(func() *MQMetrics {
+	return NewMQMetrics(registry)
+})
+
+// MQ 返回注册在默认 Registry 上的 MQ 指标
+func MQ() *MQMetrics {
+	return defaultMQ()
+}
+
+// ProducerStater 提供运行状态的生产者
+type ProducerStater interface {
+	Stats() xmq.ProducerStats
+}
+
+type producerCollector struct {
+	producer                      ProducerStater
+	inflight, poolSize, idleChans *prometheus.Desc
+}
+
+// NewProducerCollector 采集生产者 inflight 与 channel 池状态
+func NewProducerCollector(producer ProducerStater) prometheus.Collector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "mq_producer", metric), help, nil, nil)
+	}
+	return &producerCollector{
+		producer:  producer,
+		inflight:  desc("inflight", "正在等待 broker confirm 的消息数"),
+		poolSize:  desc("channel_pool_size", "channel 池大小"),
+		idleChans: desc("idle_channels", "池中空闲的 channel 数"),
+	}
+}
+
+func (c *producerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.inflight
+	ch <- c.poolSize
+	ch <- c.idleChans
+}
+
+func (c *producerCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.producer.Stats()
+	ch <- prometheus.MustNewConstMetric(c.inflight, prometheus.GaugeValue, float64(s.Inflight))
+	ch <- prometheus.MustNewConstMetric(c.poolSize, prometheus.GaugeValue, float64(s.PoolSize))
+	ch <- prometheus.MustNewConstMetric(c.idleChans, prometheus.GaugeValue, float64(s.IdleChannels))
+}
@@ -0,0 +1,74 @@
+package xmetrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// NewDBCollectors 为每个数据库连接池创建采集器，标签 db_name 为 DbMap 中的名称
+func NewDBCollectors(dbMap map[string]*gorm.DB) ([]prometheus.Collector, error) {
+	cs := make([]prometheus.Collector, 0, len(dbMap))
+	for name, db := range dbMap {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("get sql db %s error: %w", name, err)
+		}
+		cs = append(cs, collectors.NewDBStatsCollector(sqlDB, name))
+	}
+	return cs, nil
+}
+
+// RedisPoolStater 提供连接池状态的 Redis 客户端
+type RedisPoolStater interface {
+	PoolStats() *redis.PoolStats
+}
+
+type redisCollector struct {
+	client RedisPoolStater
+
+	hits, misses, timeouts, waits    *prometheus.Desc
+	totalConns, idleConns, staleConn *prometheus.Desc
+}
+
+// NewRedisCollector 采集 go-redis 连接池状态，name 用于区分多个客户端
+func NewRedisCollector(name string, client RedisPoolStater) prometheus.Collector {
+	labels := prometheus.Labels{"client": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", metric), help, nil, labels)
+	}
+	return &redisCollector{
+		client:     client,
+		hits:       desc("hits_total", "从连接池获取到空闲连接的次数"),
+		misses:     desc("misses_total", "连接池中没有空闲连接的次数"),
+		timeouts:   desc("timeouts_total", "等待连接超时的次数"),
+		waits:      desc("waits_total", "等待连接的次数"),
+		totalConns: desc("conns", "连接池中的连接总数"),
+		idleConns:  desc("idle_conns", "连接池中的空闲连接数"),
+		staleConn:  desc("stale_conns_total", "被回收的过期连接数"),
+	}
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.waits
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConn
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(s.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.waits, prometheus.CounterValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConn, prometheus.CounterValue, float64(s.StaleConns))
+}
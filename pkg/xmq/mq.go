@@ -59,6 +59,37 @@ type Logger interface {
 	Error(ctx context.Context, msg string, fields ...zap.Field)
 }
 
+const (
+	// PublishConfirmed 等发布结果
+	PublishConfirmed = "confirmed" // broker 已确认
+	PublishNack      = "nack"      // broker 拒绝
+	PublishTimeout   = "timeout"   // 等待确认超时
+	PublishError     = "error"     // 获取 channel、发送等其他失败
+
+	// ConsumeAck 等消费最终结果
+	ConsumeAck  = "ack"
+	ConsumeNack = "nack"
+)
+
+// Metrics 指标采集接口，由调用方注入（如 Prometheus 实现），默认不采集
+type Metrics interface {
+	// PublishResult 记录一次发布结果
+	PublishResult(exchange, result string)
+	// HandlerDone 记录一次 handler 执行，每次重试单独记录
+	HandlerDone(queue string, duration time.Duration, err error)
+	// ConsumeRetry 记录一次进程内重试
+	ConsumeRetry(queue string)
+	// ConsumeDone 记录一条消息的最终结果：ConsumeAck / ConsumeNack
+	ConsumeDone(queue, result string)
+}
+
+// ProducerStats 生产者运行状态
+type ProducerStats struct {
+	Inflight     int64 // 正在等待 confirm 的消息数
+	PoolSize     int   // channel 池大小
+	IdleChannels int   // 池中空闲的 channel 数
+}
+
 // Producer 生产者
 type Producer interface {
 	// Publish 同步发布，成功返回 nil；失败返回 error（带确认超时、nack）。
@@ -2,10 +2,12 @@ package rabbitmq
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
+	"snowgo/pkg/xmq"
 )
 
 // Dummy URL for config-only unit tests (never connects to RabbitMQ).
@@ -253,3 +255,68 @@ func TestLoggerOptions(t *testing.T) {
 		}
 	})
 }
+
+// ============================================================
+// Metrics options
+// ============================================================
+
+type recordMetrics struct {
+	nopMetrics
+	published []string
+}
+
+func (m *recordMetrics) PublishResult(exchange, result string) {
+	m.published = append(m.published, exchange+":"+result)
+}
+
+func TestMetricsOptions(t *testing.T) {
+	// === Happy path ===
+	t.Run("happy: default nop metrics", func(t *testing.T) {
+		if NewProducerConnConfig(testURL).Metrics == nil {
+			t.Fatal("default producer metrics should not be nil (nopMetrics)")
+		}
+		if NewConsumerConnConfig(testURL).Metrics == nil {
+			t.Fatal("default consumer metrics should not be nil (nopMetrics)")
+		}
+	})
+
+	t.Run("happy: custom metrics applied", func(t *testing.T) {
+		m := &recordMetrics{}
+		cfg := NewProducerConnConfig(testURL, WithProducerMetrics(m))
+		cfg.Metrics.PublishResult("ex", publishResult(nil))
+		if len(m.published) != 1 || m.published[0] != "ex:confirmed" {
+			t.Fatalf("expected custom metrics called, got %v", m.published)
+		}
+		if NewConsumerConnConfig(testURL, WithConsumerMetrics(m)).Metrics != m {
+			t.Fatal("expected custom consumer metrics applied")
+		}
+	})
+
+	// === Boundary values ===
+	t.Run("boundary: nil metrics option keeps default", func(t *testing.T) {
+		if NewProducerConnConfig(testURL, WithProducerMetrics(nil)).Metrics == nil {
+			t.Fatal("nil metrics option should keep nopMetrics")
+		}
+		if NewConsumerConnConfig(testURL, WithConsumerMetrics(nil)).Metrics == nil {
+			t.Fatal("nil consumer metrics option should keep nopMetrics")
+		}
+	})
+
+	t.Run("boundary: publish result classification", func(t *testing.T) {
+		tests := map[error]string{
+			nil:                   xmq.PublishConfirmed,
+			xmq.ErrPublishNack:    xmq.PublishNack,
+			xmq.ErrPublishTimeout: xmq.PublishTimeout,
+			xmq.ErrClosed:         xmq.PublishError,
+		}
+		for err, want := range tests {
+			if got := publishResult(err); got != want {
+				t.Fatalf("publishResult(%v)=%s, want %s", err, got, want)
+			}
+		}
+		wrapped := fmt.Errorf("publish: %w", xmq.ErrPublishNack)
+		if publishResult(wrapped) != xmq.PublishNack {
+			t.Fatal("wrapped nack should be classified as nack")
+		}
+	})
+}
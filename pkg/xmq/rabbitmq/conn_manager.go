@@ -523,6 +523,15 @@ func newProducerConnManager(_ context.Context, cfg *ProducerConnConfig) *produce
 	return m
 }
 
+// Stats 当前 inflight 与 channel 池状态
+func (m *producerConnManager) Stats() xmq.ProducerStats {
+	return xmq.ProducerStats{
+		Inflight:     atomic.LoadInt64(&m.inflight),
+		PoolSize:     m.cfg.ProducerChannelPoolSize,
+		IdleChannels: len(m.pool),
+	}
+}
+
 // Start  建立连接并刷新 pool，启动 reconnect watcher
 func (m *producerConnManager) Start(ctx context.Context) error {
 	if atomic.LoadInt32(&m.closed) == 1 {
@@ -34,9 +34,10 @@ func defaultMeta() *xmq.ConsumerMeta {
 
 // Consumer 结构
 type Consumer struct {
-	cm     *consumerConnManager
-	cfg    *ConsumerConnConfig
-	logger xmq.Logger
+	cm      *consumerConnManager
+	cfg     *ConsumerConnConfig
+	logger  xmq.Logger
+	metrics xmq.Metrics
 
 	stopped    int32
 	wg         sync.WaitGroup
@@ -74,6 +75,9 @@ func NewConsumer(ctx context.Context, cfg *ConsumerConnConfig) (*Consumer, error
 	if cfg.Logger == nil {
 		cfg.Logger = &nopLogger{}
 	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = &nopMetrics{}
+	}
 	cm := newConsumerConnManager(ctx, cfg)
 	if err := cm.Start(ctx); err != nil {
 		cm.logger.Error(ctx,
@@ -89,6 +93,7 @@ func NewConsumer(ctx context.Context, cfg *ConsumerConnConfig) (*Consumer, error
 		cm:         cm,
 		cfg:        cfg,
 		logger:     cfg.Logger,
+		metrics:    cfg.Metrics,
 		instanceID: instanceID,
 	}, nil
 }
@@ -244,6 +249,7 @@ func (c *Consumer) workerLoop(ctx context.Context, unit *consumerUnit, workerID
 				func(delivery amqp.Delivery) {
 					defer func() {
 						if r := recover(); r != nil {
+							c.metrics.ConsumeDone(unit.queue, xmq.ConsumeNack)
 							if ne := delivery.Nack(false, false); ne != nil {
 								c.logger.Warn(ctx,
 									fmt.Sprintf("d.Nack(false,false) failed after panic queue=%s message_id=%s err=%v", unit.queue, delivery.MessageId, ne),
@@ -293,6 +299,7 @@ func (c *Consumer) handleDeliveryInProcess(parentCtx context.Context, _ *amqp.Ch
 		lastErr = unit.handler(handlerCtx, msg)
 		cancel()
 		duration := time.Since(startTime)
+		c.metrics.HandlerDone(unit.queue, duration, lastErr)
 		if lastErr == nil {
 			c.logger.Info(handlerCtx,
 				"consumer msg success",
@@ -304,6 +311,7 @@ func (c *Consumer) handleDeliveryInProcess(parentCtx context.Context, _ *amqp.Ch
 				zap.String("status", "success"),
 				zap.Int("attempt", attempt+1),
 			)
+			c.metrics.ConsumeDone(unit.queue, xmq.ConsumeAck)
 			if ackErr := d.Ack(false); ackErr != nil {
 				c.logger.Warn(handlerCtx,
 					fmt.Sprintf("ack failed queue=%s message_id=%s err=%v", unit.queue, d.MessageId, ackErr),
@@ -324,6 +332,9 @@ func (c *Consumer) handleDeliveryInProcess(parentCtx context.Context, _ *amqp.Ch
 				zap.Int("attempt", attempt+1),
 				zap.Error(lastErr),
 			)
+			if attempt+1 < meta.RetryLimit {
+				c.metrics.ConsumeRetry(unit.queue)
+			}
 			// short backoff between in-process attempts
 			sleepWithContext(handlerCtx, time.Duration(attempt+1)*100*time.Millisecond)
 			continue
@@ -334,6 +345,7 @@ func (c *Consumer) handleDeliveryInProcess(parentCtx context.Context, _ *amqp.Ch
 	// WHY: 使用 Nack 替代 Ack，避免消息被静默丢弃；如果配置了死信队列，消息会被路由到 DLX
 	ackCtx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
+	c.metrics.ConsumeDone(unit.queue, xmq.ConsumeNack)
 	if ackErr := d.Nack(false, false); ackErr != nil {
 		c.logger.Warn(ackCtx,
 			fmt.Sprintf("ack failed queue=%s message_id=%s err=%v", unit.queue, d.MessageId, ackErr),
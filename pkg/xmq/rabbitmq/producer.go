@@ -2,6 +2,7 @@ package rabbitmq
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	common "snowgo/pkg"
 	"snowgo/pkg/xmq"
@@ -29,6 +30,9 @@ func NewProducer(ctx context.Context, cfg *ProducerConnConfig) (*Producer, error
 	if cfg.Logger == nil {
 		cfg.Logger = &nopLogger{}
 	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = &nopMetrics{}
+	}
 	cm := newProducerConnManager(ctx, cfg)
 	if err := cm.Start(ctx); err != nil {
 		cm.logger.Error(
@@ -75,6 +79,7 @@ func (p *Producer) Publish(ctx context.Context, exchange, routingKey string, msg
 	if err != nil {
 		status = "fail"
 	}
+	p.cfg.Metrics.PublishResult(exchange, publishResult(err))
 	// 只记录 body 前128字节
 	truncatedBody := string(msg.Body)
 	if len(truncatedBody) > 128 {
@@ -115,6 +120,28 @@ func (p *Producer) PublishDelayed(ctx context.Context, delayedExchange, routingK
 	// 降级 TTL+DLX，根据情况实现
 }
 
+// Stats 生产者运行状态，用于指标采集
+func (p *Producer) Stats() xmq.ProducerStats {
+	if p == nil || p.cm == nil {
+		return xmq.ProducerStats{}
+	}
+	return p.cm.Stats()
+}
+
+// publishResult 发布结果分类
+func publishResult(err error) string {
+	switch {
+	case err == nil:
+		return xmq.PublishConfirmed
+	case errors.Is(err, xmq.ErrPublishNack):
+		return xmq.PublishNack
+	case errors.Is(err, xmq.ErrPublishTimeout):
+		return xmq.PublishTimeout
+	default:
+		return xmq.PublishError
+	}
+}
+
 // Close 优雅关闭 producer
 // 会等待所有 inflight 消息完成
 func (p *Producer) Close(ctx context.Context) error {
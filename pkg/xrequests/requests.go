@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	common "snowgo/pkg"
+	"snowgo/pkg/xauth/signature"
 	"time"
 )
 
@@ -49,6 +50,13 @@ type requestOptions struct {
 	formData   map[string]string // 表单数据
 	json       any               // 支持直接传入JSON对象
 	timeout    time.Duration     // 支持单独设置超时
+	signer     *signer           // 开放接口 HMAC 签名
+}
+
+// signer 开放接口签名凭证
+type signer struct {
+	accessKey string
+	secret    string
 }
 
 // Response 响应结构体
@@ -274,6 +282,12 @@ func Request(method, rawURL string, opts ...Option) (*Response, error) {
 		if err != nil {
 			return nil, fmt.Errorf("create http request failed: %w", err)
 		}
+		// 每次尝试重新签名，重试不会复用时间戳与 nonce 被判定为重放
+		if reqOpts.signer != nil {
+			if err := signature.Sign(httpReq, reqOpts.body, reqOpts.signer.accessKey, reqOpts.signer.secret, time.Now()); err != nil {
+				return nil, fmt.Errorf("sign http request failed: %w", err)
+			}
+		}
 
 		resp, err := client.Do(httpReq)
 		if err != nil {
@@ -430,3 +444,8 @@ func WithJSON(jsonBody any) Option {
 func WithTimeout(timeout time.Duration) Option {
 	return func(o *requestOptions) { o.timeout = timeout }
 }
+
+// WithSigner 按开放接口规范对请求做 HMAC-SHA256 签名，签名覆盖的请求体为 WithBody/WithJSON/WithFormData 设置的内容
+func WithSigner(accessKey, secret string) Option {
+	return func(o *requestOptions) { o.signer = &signer{accessKey: accessKey, secret: secret} }
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"snowgo/pkg/xauth/signature"
 	"snowgo/pkg/xrequests"
 	"testing"
 	"time"
@@ -278,6 +279,47 @@ func TestWithMaxRetries(t *testing.T) {
 	}
 }
 
+func TestWithSigner(t *testing.T) {
+	count := 0
+	nonces := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		body, _ := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		signed, err := signature.Parse(r.Header)
+		if err != nil || !signed.Verify("partner-secret", r, body) || nonces[signed.Nonce] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		nonces[signed.Nonce] = true
+		if count == 1 {
+			// 首次请求断开连接触发重试，重试需重新签名
+			hj, _ := w.(http.Hijacker)
+			conn, _, _ := hj.Hijack()
+			_ = conn.Close()
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":1}`))
+	}))
+	defer server.Close()
+
+	resp, err := xrequests.Post(server.URL+"/api/open/ping",
+		xrequests.WithQuery(map[string]string{"b": "2", "a": "1"}),
+		xrequests.WithJSON(map[string]string{"order_no": "A1001"}),
+		xrequests.WithMaxRetries(1),
+		xrequests.WithSigner("ak_test", "partner-secret"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected signed request accepted, got %d", resp.StatusCode)
+	}
+	if count != 2 || len(nonces) != 2 {
+		t.Fatalf("expected retry signed with new nonce, got %d requests %d nonces", count, len(nonces))
+	}
+}
+
 func TestWithClientAndCtx(t *testing.T) {
 	server := mockServerForTest()
 	defer server.Close()